@@ -326,6 +326,8 @@ Gitea or set your environment appropriately.`, "")
 	repoName := os.Getenv(models.EnvRepoName)
 	pusherID, _ := strconv.ParseInt(os.Getenv(models.EnvPusherID), 10, 64)
 	pusherName := os.Getenv(models.EnvPusherName)
+	prID, _ := strconv.ParseInt(os.Getenv(models.EnvPRID), 10, 64)
+	isDeployKey, _ := strconv.ParseBool(os.Getenv(models.EnvIsDeployKey))
 
 	hookOptions := private.HookOptions{
 		UserName:                        pusherName,
@@ -334,6 +336,8 @@ Gitea or set your environment appropriately.`, "")
 		GitObjectDirectory:              os.Getenv(private.GitObjectDirectory),
 		GitQuarantinePath:               os.Getenv(private.GitQuarantinePath),
 		GitPushOptions:                  pushOptions(),
+		ProtectedBranchID:               prID,
+		IsDeployKey:                     isDeployKey,
 	}
 	oldCommitIDs := make([]string, hookBatchSize)
 	newCommitIDs := make([]string, hookBatchSize)
@@ -435,7 +439,10 @@ func hookPrintResults(results []private.HookPostReceiveBranchResult) {
 		}
 
 		fmt.Fprintln(os.Stderr, "")
-		if res.Create {
+		if res.PushToPullRequest {
+			fmt.Fprintf(os.Stderr, "Branch '%s' is protected, your push has been turned into a pull request:\n", res.Branch)
+			fmt.Fprintf(os.Stderr, "  %s\n", res.URL)
+		} else if res.Create {
 			fmt.Fprintf(os.Stderr, "Create a new pull request for '%s':\n", res.Branch)
 			fmt.Fprintf(os.Stderr, "  %s\n", res.URL)
 		} else {
@@ -455,6 +462,9 @@ func pushOptions() map[string]string {
 			kv := strings.SplitN(opt, "=", 2)
 			if len(kv) == 2 {
 				opts[kv[0]] = kv[1]
+			} else if len(kv[0]) > 0 {
+				// options without value are flags, e.g. "git push -o pull-request"
+				opts[kv[0]] = "true"
 			}
 		}
 	}
@@ -0,0 +1,84 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/urfave/cli"
+)
+
+// CmdMergeChecker represents the available merge-checker sub-command.
+var CmdMergeChecker = cli.Command{
+	Name:  "merge-checker",
+	Usage: "Check pull requests for conflicts on behalf of the running gitea process",
+	Description: `Checks pull requests of a shard for conflicts and changed protected files, requires
+EXTERNAL_MERGE_CHECKER to be enabled in the [repository.pull-request] section. Pull requests are
+sharded by their base repository, every shard must be served by exactly one merge checker.`,
+	Action: runMergeChecker,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "shard",
+			Value: 0,
+			Usage: "Shard of the pull requests to check, between 0 and the number of shards minus one",
+		},
+		cli.Int64Flag{
+			Name:  "shards",
+			Value: 1,
+			Usage: "Number of shards",
+		},
+		cli.DurationFlag{
+			Name:  "interval",
+			Value: 10 * time.Second,
+			Usage: "Time to wait before asking for new pull requests once all are checked",
+		},
+	},
+}
+
+func runMergeChecker(c *cli.Context) error {
+	shard, shards := c.Int64("shard"), c.Int64("shards")
+	if shards < 1 || shard < 0 || shard >= shards {
+		return fmt.Errorf("invalid shard %d of %d shards", shard, shards)
+	}
+
+	setting.NewContext()
+	if !setting.Repository.PullRequest.ExternalMergeChecker {
+		return fmt.Errorf("EXTERNAL_MERGE_CHECKER is not enabled in the [repository.pull-request] section")
+	}
+
+	ctx := graceful.GetManager().ShutdownContext()
+	if err := git.Init(ctx); err != nil {
+		return err
+	}
+
+	log.Info("Checking pull requests of shard %d/%d", shard, shards)
+	for ctx.Err() == nil {
+		task, err := private.ClaimPullCheck(shard, shards)
+		if err != nil {
+			log.Error("ClaimPullCheck: %v", err)
+		} else if task != nil {
+			log.Trace("Checking PR ID %d", task.PullID)
+			if err := private.SubmitPullCheck(task.PullID, pull_service.RunExternalCheck(task)); err != nil {
+				log.Error("SubmitPullCheck[%d]: %v", task.PullID, err)
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(c.Duration("interval")):
+		}
+	}
+	log.Info("Stopped checking pull requests of shard %d/%d", shard, shards)
+	return nil
+}
@@ -199,6 +199,8 @@ func runServ(c *cli.Context) error {
 		} else {
 			fail("Unknown LFS verb", "Unknown lfs verb %s", lfsVerb)
 		}
+	} else if requestedMode == models.AccessModeWrite && setting.Replication.IsReplica {
+		fail("This server is a read replica, please push to "+setting.Replication.PrimaryURL, "Push to read replica denied: %s", repoPath)
 	}
 
 	results, err := private.ServCommand(keyID, username, reponame, requestedMode, verb, lfsVerb)
@@ -0,0 +1,75 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportAbuse(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user4")
+	req := NewRequest(t, "GET", "/user/report_abuse?type=issue&id=1")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	setting.Moderation.Enabled = true
+	defer func() {
+		setting.Moderation.Enabled = false
+	}()
+
+	// the issue of a private repository cannot be reported by users who cannot see it
+	req = NewRequest(t, "GET", "/user/report_abuse?type=issue&id=4")
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestWithValues(t, "POST", "/user/report_abuse", map[string]string{
+		"_csrf":  GetCSRF(t, session, "/user/report_abuse?type=issue&id=1"),
+		"type":   "issue",
+		"id":     "1",
+		"reason": "spam",
+		"remark": "advertisement",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	report := models.AssertExistsAndLoadBean(t, &models.AbuseReport{
+		ReporterID:  4,
+		ContentType: models.AbuseReportContentTypeIssue,
+		ContentID:   1,
+	}).(*models.AbuseReport)
+	assert.Equal(t, models.AbuseReportReasonSpam, report.Reason)
+	assert.Equal(t, models.AbuseReportStatusOpen, report.Status)
+
+	adminSession := loginUser(t, "user1")
+	req = NewRequest(t, "GET", "/admin/abuse_reports")
+	resp := adminSession.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), fmt.Sprintf("/admin/abuse_reports/%d", report.ID))
+
+	link := fmt.Sprintf("/admin/abuse_reports/%d", report.ID)
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":   GetCSRF(t, adminSession, link),
+		"action":  "hide",
+		"comment": "hidden as spam",
+	})
+	adminSession.MakeRequest(t, req, http.StatusFound)
+	assert.True(t, models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue).IsHidden)
+	models.AssertExistsAndLoadBean(t, &models.AbuseReportLog{ReportID: report.ID, DoerID: 1, Action: models.AbuseReportActionHide})
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.NotContains(t, resp.Body.String(), "content for the first issue")
+
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":  GetCSRF(t, adminSession, link),
+		"action": "resolve",
+	})
+	adminSession.MakeRequest(t, req, http.StatusFound)
+	models.AssertExistsAndLoadBean(t, &models.AbuseReport{ID: report.ID, Status: models.AbuseReportStatusResolved})
+}
@@ -193,3 +193,21 @@ func TestAPIEditUser(t *testing.T) {
 	json.Unmarshal(resp.Body.Bytes(), &errMap)
 	assert.EqualValues(t, "email is not allowed to be empty string", errMap["message"].(string))
 }
+
+func TestAPIListDormantUsers(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	user4 := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	assert.NoError(t, models.FlagDormantUser(user4))
+
+	req := NewRequestf(t, "GET", "/api/v1/admin/users/dormant?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var users []*api.DormantUser
+	DecodeJSON(t, resp, &users)
+	assert.Len(t, users, 1)
+	assert.EqualValues(t, "user4", users[0].User.UserName)
+	assert.NotNil(t, users[0].Deadline)
+	assert.False(t, users[0].Deactivated)
+}
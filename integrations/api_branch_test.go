@@ -193,3 +193,48 @@ func TestAPIBranchProtection(t *testing.T) {
 	testAPIDeleteBranch(t, "master", http.StatusForbidden)
 	testAPIDeleteBranch(t, "branch2", http.StatusNoContent)
 }
+
+func TestAPIBranchProtectionStatusCheckContexts(t *testing.T) {
+	defer prepareTestEnv(t)()
+	testAPICreateBranchProtection(t, "master", http.StatusCreated)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	link := "/api/v1/repos/user2/repo1/branch_protections/master/status_check_contexts?token=" + token
+
+	req := NewRequestWithJSON(t, "POST", link, &api.StatusCheckContextsOption{
+		Contexts: []string{"ci/build (linux)", "ci/build (windows)"},
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var bp api.BranchProtection
+	DecodeJSON(t, resp, &bp)
+	assert.True(t, bp.EnableStatusCheck)
+	assert.EqualValues(t, []string{"ci/build (linux)", "ci/build (windows)"}, bp.StatusCheckContexts)
+
+	req = NewRequestWithJSON(t, "POST", link, &api.StatusCheckContextsOption{
+		Contexts: []string{" "},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "DELETE", link, &api.StatusCheckContextsOption{
+		Contexts: []string{"ci/build (windows)"},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &bp)
+	assert.EqualValues(t, []string{"ci/build (linux)"}, bp.StatusCheckContexts)
+
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/branch_protections/master/status_check_contexts/changes?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var changes []*api.StatusCheckContextChange
+	DecodeJSON(t, resp, &changes)
+	if assert.Len(t, changes, 3) {
+		assert.Equal(t, "ci/build (windows)", changes[0].Context)
+		assert.Equal(t, "unregistered", changes[0].Action)
+		assert.Equal(t, "user2", changes[0].Doer.UserName)
+	}
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/branch_protections/branch2/status_check_contexts?token="+token, &api.StatusCheckContextsOption{
+		Contexts: []string{"ci/build"},
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -8,7 +8,10 @@ import (
 	"net/http"
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateForkNoLogin(t *testing.T) {
@@ -16,3 +19,39 @@ func TestCreateForkNoLogin(t *testing.T) {
 	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/forks", &api.CreateForkOption{})
 	MakeRequest(t, req, http.StatusUnauthorized)
 }
+
+func TestAPIForkNetwork(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user20/big_test_public_fork_7/forks/network")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var nodes []*api.ForkNetworkRepository
+	DecodeJSON(t, resp, &nodes)
+	if assert.Len(t, nodes, 2) {
+		assert.EqualValues(t, 27, nodes[0].Repository.ID)
+		assert.EqualValues(t, 0, nodes[0].ParentID)
+		assert.EqualValues(t, 0, nodes[0].Depth)
+		assert.EqualValues(t, 29, nodes[1].Repository.ID)
+		assert.EqualValues(t, 27, nodes[1].ParentID)
+		assert.EqualValues(t, 1, nodes[1].Depth)
+	}
+
+	// only site administrators may detach forks
+	session := loginUser(t, "user20")
+	token := getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "POST", "/api/v1/repos/user20/big_test_public_fork_7/forks/detach?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	session = loginUser(t, "user1")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "POST", "/api/v1/repos/user19/big_test_public_mirror_6/forks/detach?token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "POST", "/api/v1/repos/user20/big_test_public_fork_7/forks/detach?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var repo api.Repository
+	DecodeJSON(t, resp, &repo)
+	assert.False(t, repo.Fork)
+	models.AssertExistsAndLoadBean(t, &models.Repository{ID: 29, IsFork: false, ForkID: 0})
+	models.AssertExistsAndLoadBean(t, &models.Repository{ID: 27, NumForks: 0})
+}
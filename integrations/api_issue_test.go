@@ -266,3 +266,26 @@ func TestAPISearchIssuesWithLabels(t *testing.T) {
 	DecodeJSON(t, resp, &apiIssues)
 	assert.Len(t, apiIssues, 2)
 }
+
+func TestAPISuggestIssueReferences(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	link, _ := url.Parse("/api/v1/repos/user2/repo1/issues/suggestions")
+	link.RawQuery = url.Values{"q": {"user2/repo2#"}}.Encode()
+	req := NewRequest(t, "GET", link.String())
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var suggestions []*api.IssueReferenceSuggestion
+	DecodeJSON(t, resp, &suggestions)
+	assert.Len(t, suggestions, 2)
+	for _, suggestion := range suggestions {
+		assert.Equal(t, "user2/repo2", suggestion.Repo.FullName)
+		assert.Equal(t, fmt.Sprintf("user2/repo2#%d", suggestion.Index), suggestion.Ref)
+	}
+
+	// anonymous users can not see the issues of private repositories
+	req = NewRequest(t, "GET", link.String())
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &suggestions)
+	assert.Empty(t, suggestions)
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIUserNotificationRules(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/user/notification_rules?token="+token, &api.CreateNotificationRuleOption{
+		RepoPattern: "user3/*",
+		Event:       "pull_request",
+		Action:      "none",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var rule api.NotificationRule
+	DecodeJSON(t, resp, &rule)
+	assert.Equal(t, "user3/*", rule.RepoPattern)
+	assert.Equal(t, "pull_request", rule.Event)
+	assert.Equal(t, "none", rule.Action)
+	models.AssertExistsAndLoadBean(t, &models.NotificationRule{ID: rule.ID, OwnerID: 2})
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/user/notification_rules?token="+token, &api.CreateNotificationRuleOption{
+		Action: "sms",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", "/api/v1/user/notification_rules?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var rules []*api.NotificationRule
+	DecodeJSON(t, resp, &rules)
+	assert.Len(t, rules, 1)
+
+	// rules of other users can not be deleted
+	token4 := getTokenForLoggedInUser(t, loginUser(t, "user4"))
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/user/notification_rules/%d?token=%s", rule.ID, token4))
+	MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/user/notification_rules/%d?token=%s", rule.ID, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.NotificationRule{ID: rule.ID})
+}
+
+func TestAPIOrgNotificationRules(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// user2 owns the organization user3, user4 is a member only
+	token4 := getTokenForLoggedInUser(t, loginUser(t, "user4"))
+	req := NewRequest(t, "GET", "/api/v1/orgs/user3/notification_rules?token="+token4)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/notification_rules?token="+token, &api.CreateNotificationRuleOption{
+		Event:  "release",
+		Action: "web",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var rule api.NotificationRule
+	DecodeJSON(t, resp, &rule)
+	models.AssertExistsAndLoadBean(t, &models.NotificationRule{ID: rule.ID, OwnerID: 3, Event: models.NotificationRuleEventRelease})
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/orgs/user3/notification_rules/%d?token=%s", rule.ID, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgRepoPresets(t *testing.T) {
+	onGiteaRun(t, func(*testing.T, *url.URL) {
+		// user2 owns org3
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repo_presets?token="+token, &api.CreateRepoPresetOption{
+			Name:                 "Service",
+			Units:                []string{"repo.code", "repo.issues"},
+			Topics:               []string{"backend"},
+			ProtectDefaultBranch: true,
+			RequiredApprovals:    1,
+			Webhooks: []*api.CreateRepoPresetWebhookOption{{
+				URL:         "https://example.com/hook",
+				ContentType: "json",
+				Secret:      "secret",
+			}},
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var preset api.RepoPreset
+		DecodeJSON(t, resp, &preset)
+		assert.EqualValues(t, "Service", preset.Name)
+		assert.EqualValues(t, []string{"repo.code", "repo.issues"}, preset.Units)
+		assert.Len(t, preset.Webhooks, 1)
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repo_presets?token="+token, &api.CreateRepoPresetOption{
+			Name:   "Invalid",
+			Topics: []string{"not a topic"},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequest(t, "GET", "/api/v1/orgs/user3/repo_presets?token="+token)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		var presets []*api.RepoPreset
+		DecodeJSON(t, resp, &presets)
+		assert.Len(t, presets, 1)
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos?token="+token, &api.CreateRepoOption{
+			Name:     "preset-repo",
+			PresetID: preset.ID,
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var repo api.Repository
+		DecodeJSON(t, resp, &repo)
+		assert.False(t, repo.HasPullRequests)
+		assert.True(t, repo.HasIssues)
+		models.AssertExistsAndLoadBean(t, &models.ProtectedBranch{RepoID: repo.ID, BranchName: repo.DefaultBranch, RequiredApprovals: 1})
+		models.AssertExistsAndLoadBean(t, &models.Webhook{RepoID: repo.ID, URL: "https://example.com/hook"})
+
+		// presets of organizations can not be used for repositories of users
+		req = NewRequestWithJSON(t, "POST", "/api/v1/user/repos?token="+token, &api.CreateRepoOption{
+			Name:     "preset-repo",
+			PresetID: preset.ID,
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/orgs/user3/repo_presets/%d?token=%s", preset.ID, token), &api.EditRepoPresetOption{
+			Topics: &[]string{"frontend"},
+		})
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &preset)
+		assert.EqualValues(t, []string{"frontend"}, preset.Topics)
+
+		// user4 is a member of org3 but can not create repositories
+		session4 := loginUser(t, "user4")
+		token4 := getTokenForLoggedInUser(t, session4)
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/user3/repo_presets/%d?token=%s", preset.ID, token4))
+		session4.MakeRequest(t, req, http.StatusNotFound)
+		req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/orgs/user3/repo_presets/%d?token=%s", preset.ID, token4))
+		session4.MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/orgs/user3/repo_presets/%d?token=%s", preset.ID, token))
+		session.MakeRequest(t, req, http.StatusNoContent)
+		models.AssertNotExistsBean(t, &models.RepoPreset{ID: preset.ID})
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/setting"
@@ -150,3 +151,78 @@ func TestAPIGetAll(t *testing.T) {
 	assert.Equal(t, "org25", apiOrgList[0].FullName)
 	assert.Equal(t, "public", apiOrgList[0].Visibility)
 }
+
+func TestAPIOrgOutsideCollaborators(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	expires := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	req := NewRequestWithJSON(t, "PUT", "/api/v1/repos/user3/repo3/collaborators/user5?token="+token, &api.AddCollaboratorOption{
+		ExpiresAt: &expires,
+	})
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	past := time.Now().Add(-time.Hour)
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user3/repo3/collaborators/user5?token="+token, &api.AddCollaboratorOption{
+		ExpiresAt: &past,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "GET", "/api/v1/orgs/user3/outside_collaborators?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var collaborators []*api.OutsideCollaborator
+	DecodeJSON(t, resp, &collaborators)
+	if assert.Len(t, collaborators, 1) {
+		assert.Equal(t, "user5", collaborators[0].User.UserName)
+		assert.Equal(t, "repo3", collaborators[0].Repository)
+		assert.Equal(t, "write", collaborators[0].Permission)
+		if assert.NotNil(t, collaborators[0].ExpiresAt) {
+			assert.True(t, expires.Equal(*collaborators[0].ExpiresAt))
+		}
+	}
+
+	// only owners of the organization may list its outside collaborators
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/orgs/user3/outside_collaborators?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIOrgTokenUsage(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/user3/repo3/issues?token=%s", token)
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequestf(t, "GET", "/api/v1/repos/user3/repo3/issues?token=%s", token)
+	MakeRequest(t, req, http.StatusOK)
+	// repositories of users are not recorded
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1?token=%s", token)
+	MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequestf(t, "GET", "/api/v1/orgs/user3/token_usage?token=%s", token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var usages []*api.TokenUsage
+	DecodeJSON(t, resp, &usages)
+	assert.Len(t, usages, 2)
+	for _, u := range usages {
+		assert.Equal(t, "user2", u.User.UserName)
+		assert.Equal(t, "read", u.Access)
+		switch u.Endpoint {
+		case "repos/issues":
+			assert.EqualValues(t, 2, u.Count)
+		case "orgs/token_usage":
+			assert.EqualValues(t, 1, u.Count)
+		default:
+			assert.Fail(t, "unexpected endpoint", u.Endpoint)
+		}
+	}
+
+	// only owners of the organization may list the token usage
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/orgs/user3/token_usage?token=%s", token)
+	MakeRequest(t, req, http.StatusForbidden)
+}
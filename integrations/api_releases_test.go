@@ -9,9 +9,12 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
@@ -177,3 +180,41 @@ func TestAPIDeleteTagByName(t *testing.T) {
 	req = NewRequestf(t, http.MethodDelete, urlStr)
 	_ = session.MakeRequest(t, req, http.StatusConflict)
 }
+
+func TestAPIReleaseAttachmentSignedURL(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// attachment 11 belongs to release 2 of a repository of an organization which is only visible to signed in users
+	attach := models.AssertExistsAndLoadBean(t, &models.Attachment{ID: 11}).(*models.Attachment)
+	_, err := storage.Attachments.Save(attach.RelativePath(), strings.NewReader("hello world"))
+	assert.NoError(t, err)
+
+	req := NewRequest(t, "GET", "/attachments/"+attach.UUID)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/repos/limited_org/public_repo_on_private_org/releases/2/assets/%d/signed_url?token=%s", attach.ID, token)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateSignedURLOption{ExpiresIn: 1000})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateSignedURLOption{ExpiresIn: 5})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var signedURL api.SignedURL
+	DecodeJSON(t, resp, &signedURL)
+	assert.True(t, strings.HasPrefix(signedURL.URL, attach.DownloadURL()+"?token="))
+	assert.True(t, signedURL.Expires.After(time.Now().Add(3*time.Minute)))
+
+	req = NewRequest(t, "GET", strings.TrimPrefix(signedURL.URL, setting.AppURL[:len(setting.AppURL)-1]))
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "hello world", resp.Body.String())
+
+	req = NewRequest(t, "GET", "/attachments/"+attach.UUID+"?token=invalid")
+	MakeRequest(t, req, http.StatusForbidden)
+
+	// the release does not belong to the repository
+	urlStr = fmt.Sprintf("/api/v1/repos/user2/repo1/releases/2/assets/%d/signed_url?token=%s", attach.ID, token)
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateSignedURLOption{})
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoAttachments(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/attachments?token=%s", user.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var attachments []*api.RepoAttachment
+	DecodeJSON(t, resp, &attachments)
+	assert.Len(t, attachments, 7)
+	assert.Equal(t, "7", resp.Header().Get("X-Total-Count"))
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/attachments?orphaned=true&token=%s", user.Name, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &attachments)
+	if assert.Len(t, attachments, 1) {
+		assert.EqualValues(t, 10, attachments[0].ID)
+		assert.True(t, attachments[0].Orphaned)
+	}
+
+	// attachment 2 belongs to another repository
+	req = NewRequestWithJSON(t, "DELETE", "/api/v1/repos/"+user.Name+"/repo1/attachments?token="+token, &api.DeleteAttachmentsOption{
+		IDs: []int64{2, 10},
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+	models.AssertExistsAndLoadBean(t, &models.Attachment{ID: 10})
+
+	req = NewRequestWithJSON(t, "DELETE", "/api/v1/repos/"+user.Name+"/repo1/attachments?token="+token, &api.DeleteAttachmentsOption{
+		IDs: []int64{10},
+	})
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.Attachment{ID: 10})
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPICompareBranches(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/master...branch2?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var threeDot api.Compare
+	DecodeJSON(t, resp, &threeDot)
+	assert.False(t, threeDot.DirectComparison)
+	assert.Len(t, threeDot.Commits, threeDot.TotalCommits)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/master..branch2?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var twoDot api.Compare
+	DecodeJSON(t, resp, &twoDot)
+	assert.True(t, twoDot.DirectComparison)
+	assert.Equal(t, threeDot.MergeBase, twoDot.MergeBase)
+	assert.Equal(t, threeDot.TotalCommits, twoDot.TotalCommits)
+
+	// the base has to be a reference of the repository
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/user2:master...branch2?token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/compare/master...not-exist?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -325,5 +325,54 @@ func TestAPIRepoEdit(t *testing.T) {
 		url = fmt.Sprintf("/api/v1/repos/%s/%s?token=%s", user2.Name, repo1.Name, token4)
 		req = NewRequestWithJSON(t, "PATCH", url, &repoEditOption)
 		session.MakeRequest(t, req, http.StatusForbidden)
+
+		// Test that all invalid fields are reported and nothing is changed
+		repo1 = models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		description := "not saved"
+		website := "htp://www.somewebsite.com"
+		trustModel := "nobody"
+		retentionDays := -1
+		interval := "1h"
+		repoEditOption = &api.EditRepoOption{
+			Description:             &description,
+			Website:                 &website,
+			TrustModel:              &trustModel,
+			AttachmentRetentionDays: &retentionDays,
+			MirrorInterval:          &interval,
+		}
+		url = fmt.Sprintf("/api/v1/repos/%s/%s?token=%s", user2.Name, repo1.Name, token2)
+		req = NewRequestWithJSON(t, "PATCH", url, &repoEditOption)
+		resp = session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+		var validationErr api.APIFieldValidationError
+		DecodeJSON(t, resp, &validationErr)
+		assert.Len(t, validationErr.Errors, 4)
+		for _, field := range []string{"website", "trust_model", "attachment_retention_days", "mirror_interval"} {
+			assert.Contains(t, validationErr.Errors, field)
+		}
+		repo1edited = models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		assert.Equal(t, repo1.Description, repo1edited.Description)
+
+		// Test changing the signing settings
+		trustModel = "collaborator"
+		repoEditOption = &api.EditRepoOption{TrustModel: &trustModel}
+		req = NewRequestWithJSON(t, "PATCH", url, &repoEditOption)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &repo)
+		assert.Equal(t, trustModel, repo.TrustModel)
+		repo1edited = models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		assert.Equal(t, models.CollaboratorTrustModel, repo1edited.TrustModel)
+
+		// Test that only site administrators can change the admin settings
+		enableHealthCheck := !repo1.IsFsckEnabled
+		repoEditOption = &api.EditRepoOption{EnableHealthCheck: &enableHealthCheck}
+		req = NewRequestWithJSON(t, "PATCH", url, &repoEditOption)
+		session.MakeRequest(t, req, http.StatusForbidden)
+		session = loginUser(t, "user1")
+		token1 := getTokenForLoggedInUser(t, session)
+		url = fmt.Sprintf("/api/v1/repos/%s/%s?token=%s", user2.Name, repo1.Name, token1)
+		req = NewRequestWithJSON(t, "PATCH", url, &repoEditOption)
+		session.MakeRequest(t, req, http.StatusOK)
+		repo1edited = models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		assert.Equal(t, enableHealthCheck, repo1edited.IsFsckEnabled)
 	})
 }
@@ -0,0 +1,65 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoGitCredential(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo2/git_credentials?token="+token, &api.CreateGitCredentialOption{})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var credential api.GitCredential
+	DecodeJSON(t, resp, &credential)
+	assert.Equal(t, "user2", credential.Username)
+	assert.Equal(t, "read", credential.Operation)
+
+	req = NewRequest(t, "GET", "/user2/repo2.git/info/refs?service=git-upload-pack")
+	req.SetBasicAuth(credential.Username, credential.Password)
+	MakeRequest(t, req, http.StatusOK)
+
+	// the credential does not allow to push
+	req = NewRequest(t, "GET", "/user2/repo2.git/info/refs?service=git-receive-pack")
+	req.SetBasicAuth(credential.Username, credential.Password)
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// nor to access other repositories
+	req = NewRequest(t, "GET", "/user2/repo16.git/info/refs?service=git-upload-pack")
+	req.SetBasicAuth(credential.Username, credential.Password)
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo2/git_credentials?token="+token, &api.CreateGitCredentialOption{
+		Operation: "write",
+		ExpiresIn: 5,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &credential)
+	req = NewRequest(t, "GET", "/user2/repo2.git/info/refs?service=git-receive-pack")
+	req.SetBasicAuth(credential.Username, credential.Password)
+	MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo2/git_credentials?token="+token, &api.CreateGitCredentialOption{
+		ExpiresIn: 24 * 60,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// user4 may only read repo1
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/git_credentials?token="+token, &api.CreateGitCredentialOption{
+		Operation: "write",
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
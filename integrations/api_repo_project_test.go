@@ -0,0 +1,35 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoProjectAnalytics(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1/projects/1/analytics?token="+token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var analytics api.ProjectAnalytics
+	DecodeJSON(t, resp, &analytics)
+	if assert.Len(t, analytics.Boards, 4) {
+		assert.EqualValues(t, 0, analytics.Boards[0].ID)
+		assert.EqualValues(t, 1, analytics.Boards[1].ID)
+		assert.EqualValues(t, 4, analytics.Boards[0].Stays)
+	}
+
+	// project of another repository
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/projects/3/analytics?token="+token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
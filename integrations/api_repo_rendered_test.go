@@ -0,0 +1,38 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIReposRendered(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/rendered/README.md?ref=v1.1&token="+token, user.Name)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var rendered api.RenderedFileResponse
+	DecodeJSON(t, resp, &rendered)
+	assert.Equal(t, "README.md", rendered.Name)
+	assert.Equal(t, "markdown", rendered.Type)
+	assert.Contains(t, rendered.HTML, `<h1 id="user-content-repo1">repo1</h1>`)
+	if assert.Len(t, rendered.TOC, 1) {
+		assert.Equal(t, 1, rendered.TOC[0].Level)
+		assert.Equal(t, "repo1", rendered.TOC[0].Text)
+		assert.Equal(t, "user-content-repo1", rendered.TOC[0].ID)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/rendered/missing.md?token="+token, user.Name)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -21,6 +21,7 @@ import (
 	_ "code.gitea.io/gitea/modules/markup/csv"
 	_ "code.gitea.io/gitea/modules/markup/markdown"
 	_ "code.gitea.io/gitea/modules/markup/orgmode"
+	_ "code.gitea.io/gitea/modules/markup/stl"
 
 	"github.com/urfave/cli"
 )
@@ -71,6 +72,7 @@ arguments - which can alternatively be run by running the subcommand web.`
 		cmd.CmdManager,
 		cmd.Cmdembedded,
 		cmd.CmdMigrateStorage,
+		cmd.CmdMergeChecker,
 		cmd.CmdDocs,
 	}
 	// Now adjust these commands to add our global configuration options
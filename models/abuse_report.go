@@ -0,0 +1,491 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// AbuseReportContentType is the kind of content an abuse report is about
+type AbuseReportContentType int
+
+// Kinds of reported content
+const (
+	AbuseReportContentTypeIssue AbuseReportContentType = iota + 1
+	AbuseReportContentTypeComment
+	AbuseReportContentTypeRepo
+	AbuseReportContentTypeUser
+)
+
+var abuseReportContentTypeNames = map[AbuseReportContentType]string{
+	AbuseReportContentTypeIssue:   "issue",
+	AbuseReportContentTypeComment: "comment",
+	AbuseReportContentTypeRepo:    "repo",
+	AbuseReportContentTypeUser:    "user",
+}
+
+// Name returns the name of the content type used in URLs and locales
+func (t AbuseReportContentType) Name() string {
+	return abuseReportContentTypeNames[t]
+}
+
+// ParseAbuseReportContentType returns the content type of the name, 0 if the name is unknown
+func ParseAbuseReportContentType(name string) AbuseReportContentType {
+	for t, n := range abuseReportContentTypeNames {
+		if n == name {
+			return t
+		}
+	}
+	return 0
+}
+
+// AbuseReportReason is the reason content is reported for
+type AbuseReportReason string
+
+// Reasons of abuse reports
+const (
+	AbuseReportReasonSpam    AbuseReportReason = "spam"
+	AbuseReportReasonAbuse   AbuseReportReason = "abuse"
+	AbuseReportReasonIllegal AbuseReportReason = "illegal"
+	AbuseReportReasonOther   AbuseReportReason = "other"
+)
+
+// AbuseReportReasons are the valid reasons of abuse reports
+var AbuseReportReasons = []AbuseReportReason{
+	AbuseReportReasonSpam,
+	AbuseReportReasonAbuse,
+	AbuseReportReasonIllegal,
+	AbuseReportReasonOther,
+}
+
+// AbuseReportStatus is the state of an abuse report in the moderation queue
+type AbuseReportStatus int
+
+// States of abuse reports
+const (
+	AbuseReportStatusOpen AbuseReportStatus = iota
+	AbuseReportStatusResolved
+	AbuseReportStatusDismissed
+)
+
+var abuseReportStatusNames = map[AbuseReportStatus]string{
+	AbuseReportStatusOpen:      "open",
+	AbuseReportStatusResolved:  "resolved",
+	AbuseReportStatusDismissed: "dismissed",
+}
+
+// Name returns the name of the status used in URLs and locales
+func (s AbuseReportStatus) Name() string {
+	return abuseReportStatusNames[s]
+}
+
+// ParseAbuseReportStatus returns the status of the name, -1 if the name is unknown
+func ParseAbuseReportStatus(name string) AbuseReportStatus {
+	for s, n := range abuseReportStatusNames {
+		if n == name {
+			return s
+		}
+	}
+	return -1
+}
+
+// AbuseReport is a report of an issue, a comment, a repository or a user to the site administrators
+type AbuseReport struct {
+	ID          int64                  `xorm:"pk autoincr"`
+	ReporterID  int64                  `xorm:"INDEX NOT NULL"`
+	Reporter    *User                  `xorm:"-"`
+	ContentType AbuseReportContentType `xorm:"INDEX(s) NOT NULL"`
+	ContentID   int64                  `xorm:"INDEX(s) NOT NULL"`
+	// ContentOwnerID is the user who posted the reported issue or comment, the owner
+	// of the reported repository or the reported user
+	ContentOwnerID int64 `xorm:"INDEX"`
+	ContentOwner   *User `xorm:"-"`
+	// RepoID is the repository of the reported issue, comment or repository
+	RepoID int64             `xorm:"INDEX"`
+	Reason AbuseReportReason `xorm:"VARCHAR(20) NOT NULL"`
+	Remark string            `xorm:"TEXT"`
+	Status AbuseReportStatus `xorm:"INDEX NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+
+	// the reported content, depending on the content type
+	Repo    *Repository `xorm:"-"`
+	Issue   *Issue      `xorm:"-"`
+	Comment *Comment    `xorm:"-"`
+}
+
+// AbuseReportAction is an action of a site administrator on an abuse report
+type AbuseReportAction string
+
+// Actions on abuse reports
+const (
+	// AbuseReportActionHide hides the reported issue or comment or makes the reported repository private
+	AbuseReportActionHide AbuseReportAction = "hide"
+	// AbuseReportActionShow reverts hiding the reported issue or comment
+	AbuseReportActionShow AbuseReportAction = "show"
+	// AbuseReportActionLock locks the issue of the reported issue or comment or archives the reported repository
+	AbuseReportActionLock AbuseReportAction = "lock"
+	// AbuseReportActionBlockUser prohibits the owner of the reported content to sign in
+	AbuseReportActionBlockUser AbuseReportAction = "block_user"
+	AbuseReportActionResolve   AbuseReportAction = "resolve"
+	AbuseReportActionDismiss   AbuseReportAction = "dismiss"
+	AbuseReportActionReopen    AbuseReportAction = "reopen"
+	// AbuseReportActionComment only records a comment of the site administrator
+	AbuseReportActionComment AbuseReportAction = "comment"
+)
+
+// AbuseReportLog records an action on an abuse report, all logs of a report are its audit trail
+type AbuseReportLog struct {
+	ID       int64             `xorm:"pk autoincr"`
+	ReportID int64             `xorm:"INDEX NOT NULL"`
+	DoerID   int64             `xorm:"NOT NULL"`
+	Doer     *User             `xorm:"-"`
+	Action   AbuseReportAction `xorm:"VARCHAR(20) NOT NULL"`
+	Comment  string            `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// ErrAbuseReportNotExist represents a "AbuseReportNotExist" kind of error.
+type ErrAbuseReportNotExist struct {
+	ID int64
+}
+
+// IsErrAbuseReportNotExist checks if an error is a ErrAbuseReportNotExist.
+func IsErrAbuseReportNotExist(err error) bool {
+	_, ok := err.(ErrAbuseReportNotExist)
+	return ok
+}
+
+func (err ErrAbuseReportNotExist) Error() string {
+	return fmt.Sprintf("abuse report does not exist [id: %d]", err.ID)
+}
+
+// ErrAbuseReportAlreadyExist represents a "AbuseReportAlreadyExist" kind of error.
+type ErrAbuseReportAlreadyExist struct {
+	ReporterID  int64
+	ContentType AbuseReportContentType
+	ContentID   int64
+}
+
+// IsErrAbuseReportAlreadyExist checks if an error is a ErrAbuseReportAlreadyExist.
+func IsErrAbuseReportAlreadyExist(err error) bool {
+	_, ok := err.(ErrAbuseReportAlreadyExist)
+	return ok
+}
+
+func (err ErrAbuseReportAlreadyExist) Error() string {
+	return fmt.Sprintf("open abuse report already exists [reporter_id: %d, content_type: %s, content_id: %d]", err.ReporterID, err.ContentType.Name(), err.ContentID)
+}
+
+// ErrAbuseReportReasonInvalid represents a "AbuseReportReasonInvalid" kind of error.
+type ErrAbuseReportReasonInvalid struct {
+	Reason AbuseReportReason
+}
+
+// IsErrAbuseReportReasonInvalid checks if an error is a ErrAbuseReportReasonInvalid.
+func IsErrAbuseReportReasonInvalid(err error) bool {
+	_, ok := err.(ErrAbuseReportReasonInvalid)
+	return ok
+}
+
+func (err ErrAbuseReportReasonInvalid) Error() string {
+	return fmt.Sprintf("abuse report reason is invalid [reason: %s]", err.Reason)
+}
+
+// ErrAbuseReportContentNotExist represents a "AbuseReportContentNotExist" kind of error.
+type ErrAbuseReportContentNotExist struct {
+	ContentType AbuseReportContentType
+	ContentID   int64
+}
+
+// IsErrAbuseReportContentNotExist checks if an error is a ErrAbuseReportContentNotExist.
+func IsErrAbuseReportContentNotExist(err error) bool {
+	_, ok := err.(ErrAbuseReportContentNotExist)
+	return ok
+}
+
+func (err ErrAbuseReportContentNotExist) Error() string {
+	return fmt.Sprintf("reported content does not exist [content_type: %s, content_id: %d]", err.ContentType.Name(), err.ContentID)
+}
+
+// ErrAbuseReportActionNotApplicable represents an error for an action which cannot be applied to the reported content
+type ErrAbuseReportActionNotApplicable struct {
+	Action      AbuseReportAction
+	ContentType AbuseReportContentType
+}
+
+// IsErrAbuseReportActionNotApplicable checks if an error is a ErrAbuseReportActionNotApplicable.
+func IsErrAbuseReportActionNotApplicable(err error) bool {
+	_, ok := err.(ErrAbuseReportActionNotApplicable)
+	return ok
+}
+
+func (err ErrAbuseReportActionNotApplicable) Error() string {
+	return fmt.Sprintf("action cannot be applied to the reported content [action: %s, content_type: %s]", err.Action, err.ContentType.Name())
+}
+
+// LoadContent loads the reported content and sets the owner and the repository of the content
+func (r *AbuseReport) LoadContent() error {
+	notExist := ErrAbuseReportContentNotExist{r.ContentType, r.ContentID}
+	var err error
+	switch r.ContentType {
+	case AbuseReportContentTypeComment:
+		if r.Comment, err = GetCommentByID(r.ContentID); err != nil {
+			if IsErrCommentNotExist(err) {
+				return notExist
+			}
+			return err
+		}
+		r.ContentOwnerID = r.Comment.PosterID
+		if r.Issue, err = GetIssueByID(r.Comment.IssueID); err != nil {
+			if IsErrIssueNotExist(err) {
+				return notExist
+			}
+			return err
+		}
+		r.Comment.Issue = r.Issue
+		r.RepoID = r.Issue.RepoID
+	case AbuseReportContentTypeIssue:
+		if r.Issue, err = GetIssueByID(r.ContentID); err != nil {
+			if IsErrIssueNotExist(err) {
+				return notExist
+			}
+			return err
+		}
+		r.ContentOwnerID = r.Issue.PosterID
+		r.RepoID = r.Issue.RepoID
+	case AbuseReportContentTypeRepo:
+		r.RepoID = r.ContentID
+	case AbuseReportContentTypeUser:
+		r.ContentOwnerID = r.ContentID
+	default:
+		return notExist
+	}
+
+	if r.RepoID > 0 {
+		if r.Repo, err = GetRepositoryByID(r.RepoID); err != nil {
+			if IsErrRepoNotExist(err) {
+				return notExist
+			}
+			return err
+		}
+		if r.Issue != nil {
+			r.Issue.Repo = r.Repo
+		}
+		if r.ContentType == AbuseReportContentTypeRepo {
+			r.ContentOwnerID = r.Repo.OwnerID
+		}
+	}
+
+	if r.ContentOwner, err = GetUserByID(r.ContentOwnerID); err != nil {
+		if IsErrUserNotExist(err) && r.ContentType == AbuseReportContentTypeUser {
+			return notExist
+		} else if !IsErrUserNotExist(err) {
+			return err
+		}
+		r.ContentOwner = NewGhostUser()
+	}
+	return nil
+}
+
+// LoadAttributes loads the reporter and the reported content
+func (r *AbuseReport) LoadAttributes() error {
+	if r.Reporter == nil {
+		var err error
+		if r.Reporter, err = GetUserByID(r.ReporterID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return err
+			}
+			r.Reporter = NewGhostUser()
+		}
+	}
+	if err := r.LoadContent(); err != nil && !IsErrAbuseReportContentNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// HTMLURL returns the URL of the reported content, empty if the content does not exist anymore
+func (r *AbuseReport) HTMLURL() string {
+	switch {
+	case r.Comment != nil:
+		return r.Comment.HTMLURL()
+	case r.Issue != nil:
+		return r.Issue.HTMLURL()
+	case r.Repo != nil:
+		return r.Repo.HTMLURL()
+	case r.ContentType == AbuseReportContentTypeUser && r.ContentOwner != nil && r.ContentOwner.ID > 0:
+		return r.ContentOwner.HTMLURL()
+	}
+	return ""
+}
+
+// IsHidden returns whether the reported issue or comment is hidden or the reported repository is private
+func (r *AbuseReport) IsHidden() bool {
+	switch {
+	case r.Comment != nil:
+		return r.Comment.IsHidden
+	case r.Issue != nil:
+		return r.Issue.IsHidden
+	case r.Repo != nil:
+		return r.Repo.IsPrivate
+	}
+	return false
+}
+
+// CreateAbuseReport creates an abuse report of the content, a reporter can only
+// have one open report of the same content
+func CreateAbuseReport(r *AbuseReport) error {
+	validReason := false
+	for _, reason := range AbuseReportReasons {
+		validReason = validReason || r.Reason == reason
+	}
+	if !validReason {
+		return ErrAbuseReportReasonInvalid{r.Reason}
+	}
+	if err := r.LoadContent(); err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	has, err := sess.Where(builder.Eq{
+		"reporter_id":  r.ReporterID,
+		"content_type": r.ContentType,
+		"content_id":   r.ContentID,
+		"status":       AbuseReportStatusOpen,
+	}).Exist(new(AbuseReport))
+	if err != nil {
+		return err
+	} else if has {
+		return ErrAbuseReportAlreadyExist{r.ReporterID, r.ContentType, r.ContentID}
+	}
+
+	r.Status = AbuseReportStatusOpen
+	if _, err = sess.Insert(r); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetAbuseReportByID returns the abuse report by its id
+func GetAbuseReportByID(id int64) (*AbuseReport, error) {
+	r := new(AbuseReport)
+	has, err := x.ID(id).Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrAbuseReportNotExist{id}
+	}
+	return r, nil
+}
+
+// FindAbuseReportsOptions represents the options to find abuse reports
+type FindAbuseReportsOptions struct {
+	ListOptions
+	Status AbuseReportStatus
+}
+
+// FindAbuseReports returns the abuse reports with the status, oldest first, and their total count
+func FindAbuseReports(opts FindAbuseReportsOptions) ([]*AbuseReport, int64, error) {
+	cond := builder.Eq{"status": opts.Status}
+	count, err := x.Where(cond).Count(new(AbuseReport))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sess := x.Where(cond).Asc("created_unix", "id")
+	if opts.Page > 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	reports := make([]*AbuseReport, 0, opts.PageSize)
+	return reports, count, sess.Find(&reports)
+}
+
+// CountOpenAbuseReports returns the number of abuse reports waiting for a site administrator
+func CountOpenAbuseReports() (int64, error) {
+	return x.Where("status = ?", AbuseReportStatusOpen).Count(new(AbuseReport))
+}
+
+// GetOtherReportsCount returns the number of the other reports of the same content
+func (r *AbuseReport) GetOtherReportsCount() (int64, error) {
+	return x.Where("content_type = ? AND content_id = ? AND id != ?", r.ContentType, r.ContentID, r.ID).Count(new(AbuseReport))
+}
+
+// GetLogs returns the audit trail of the abuse report, oldest first
+func (r *AbuseReport) GetLogs() ([]*AbuseReportLog, error) {
+	logs := make([]*AbuseReportLog, 0, 5)
+	if err := x.Where("report_id = ?", r.ID).Asc("id").Find(&logs); err != nil {
+		return nil, err
+	}
+	for _, l := range logs {
+		var err error
+		if l.Doer, err = GetUserByID(l.DoerID); err != nil {
+			if !IsErrUserNotExist(err) {
+				return nil, err
+			}
+			l.Doer = NewGhostUser()
+		}
+	}
+	return logs, nil
+}
+
+// AddAbuseReportLog records the action of the doer on the abuse report and updates the status of the report
+func AddAbuseReportLog(r *AbuseReport, doer *User, action AbuseReportAction, comment string, status AbuseReportStatus) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Insert(&AbuseReportLog{
+		ReportID: r.ID,
+		DoerID:   doer.ID,
+		Action:   action,
+		Comment:  comment,
+	}); err != nil {
+		return err
+	}
+
+	r.Status = status
+	if _, err := sess.ID(r.ID).Cols("status").Update(r); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetModerators returns the site administrators who handle abuse reports
+func GetModerators() ([]*User, error) {
+	users := make([]*User, 0, 5)
+	return users, x.Where(builder.Eq{
+		"type":           UserTypeIndividual,
+		"is_admin":       true,
+		"is_active":      true,
+		"prohibit_login": false,
+	}).Asc("id").Find(&users)
+}
+
+// SetIssueHidden hides or shows the content of the issue
+func SetIssueHidden(issue *Issue, hidden bool) error {
+	issue.IsHidden = hidden
+	return updateIssueCols(x, issue, "is_hidden")
+}
+
+// SetCommentHidden hides or shows the content of the comment
+func SetCommentHidden(c *Comment, hidden bool) error {
+	c.IsHidden = hidden
+	_, err := x.ID(c.ID).Cols("is_hidden").NoAutoTime().Update(c)
+	return err
+}
@@ -0,0 +1,120 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAbuseReport(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	r := &AbuseReport{
+		ReporterID:  2,
+		ContentType: AbuseReportContentTypeIssue,
+		ContentID:   1,
+		Reason:      AbuseReportReasonSpam,
+	}
+	assert.NoError(t, CreateAbuseReport(r))
+	assert.EqualValues(t, 1, r.ContentOwnerID)
+	assert.EqualValues(t, 1, r.RepoID)
+	AssertExistsAndLoadBean(t, &AbuseReport{ID: r.ID, ReporterID: 2, ContentID: 1})
+
+	// a reporter can only have one open report of the same content
+	err := CreateAbuseReport(&AbuseReport{
+		ReporterID:  2,
+		ContentType: AbuseReportContentTypeIssue,
+		ContentID:   1,
+		Reason:      AbuseReportReasonAbuse,
+	})
+	assert.True(t, IsErrAbuseReportAlreadyExist(err))
+
+	// other reporters can still report it
+	other := &AbuseReport{
+		ReporterID:  3,
+		ContentType: AbuseReportContentTypeIssue,
+		ContentID:   1,
+		Reason:      AbuseReportReasonAbuse,
+	}
+	assert.NoError(t, CreateAbuseReport(other))
+	count, err := other.GetOtherReportsCount()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	err = CreateAbuseReport(&AbuseReport{
+		ReporterID:  2,
+		ContentType: AbuseReportContentTypeComment,
+		ContentID:   1000,
+		Reason:      AbuseReportReasonSpam,
+	})
+	assert.True(t, IsErrAbuseReportContentNotExist(err))
+
+	err = CreateAbuseReport(&AbuseReport{
+		ReporterID:  2,
+		ContentType: AbuseReportContentTypeUser,
+		ContentID:   4,
+		Reason:      "unknown",
+	})
+	assert.True(t, IsErrAbuseReportReasonInvalid(err))
+}
+
+func TestFindAbuseReports(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	first := &AbuseReport{ReporterID: 2, ContentType: AbuseReportContentTypeRepo, ContentID: 3, Reason: AbuseReportReasonSpam}
+	assert.NoError(t, CreateAbuseReport(first))
+	assert.EqualValues(t, 3, first.ContentOwnerID)
+	second := &AbuseReport{ReporterID: 2, ContentType: AbuseReportContentTypeUser, ContentID: 4, Reason: AbuseReportReasonOther}
+	assert.NoError(t, CreateAbuseReport(second))
+
+	reports, count, err := FindAbuseReports(FindAbuseReportsOptions{Status: AbuseReportStatusOpen})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, reports, 2) {
+		assert.EqualValues(t, first.ID, reports[0].ID)
+		assert.EqualValues(t, second.ID, reports[1].ID)
+	}
+
+	admin := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	assert.NoError(t, AddAbuseReportLog(first, admin, AbuseReportActionResolve, "handled", AbuseReportStatusResolved))
+
+	open, err := CountOpenAbuseReports()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, open)
+
+	reports, count, err = FindAbuseReports(FindAbuseReportsOptions{Status: AbuseReportStatusResolved})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, reports, 1) {
+		assert.EqualValues(t, first.ID, reports[0].ID)
+	}
+
+	logs, err := first.GetLogs()
+	assert.NoError(t, err)
+	if assert.Len(t, logs, 1) {
+		assert.Equal(t, AbuseReportActionResolve, logs[0].Action)
+		assert.Equal(t, "handled", logs[0].Comment)
+		assert.EqualValues(t, 1, logs[0].Doer.ID)
+	}
+
+	// a resolved report does not prevent a new report of the same content
+	assert.NoError(t, CreateAbuseReport(&AbuseReport{ReporterID: 2, ContentType: AbuseReportContentTypeRepo, ContentID: 3, Reason: AbuseReportReasonSpam}))
+}
+
+func TestSetIssueAndCommentHidden(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.NoError(t, SetIssueHidden(issue, true))
+	assert.True(t, AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue).IsHidden)
+
+	comment := AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment)
+	assert.NoError(t, SetCommentHidden(comment, true))
+	assert.True(t, AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment).IsHidden)
+	assert.NoError(t, SetCommentHidden(comment, false))
+	assert.False(t, AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment).IsHidden)
+}
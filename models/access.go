@@ -97,6 +97,18 @@ func accessLevel(e Engine, user *User, repo *Repository) (AccessMode, error) {
 	if has, err := e.Get(a); !has || err != nil {
 		return mode, err
 	}
+
+	// the access of an expired collaboration is only removed by the cron task,
+	// until then the user only keeps the access of their teams
+	if expired, err := repo.hasExpiredCollaboration(e, userID); err != nil {
+		return mode, err
+	} else if expired {
+		teamMode, err := repo.getTeamAccessMode(e, userID)
+		if err != nil {
+			return mode, err
+		}
+		return maxAccessMode(mode, teamMode), nil
+	}
 	return a.Mode, nil
 }
 
@@ -225,6 +237,9 @@ func (repo *Repository) refreshCollaboratorAccesses(e Engine, accessMap map[int6
 		return fmt.Errorf("getCollaborations: %v", err)
 	}
 	for _, c := range collaborators {
+		if c.Collaboration.IsExpired() {
+			continue
+		}
 		updateUserAccess(accessMap, c.User, c.Collaboration.Mode)
 	}
 	return nil
@@ -286,31 +301,15 @@ func (repo *Repository) recalculateUserAccess(e Engine, uid int64) (err error) {
 	collaborator, err := repo.getCollaboration(e, uid)
 	if err != nil {
 		return err
-	} else if collaborator != nil {
+	} else if collaborator != nil && !collaborator.IsExpired() {
 		accessMode = collaborator.Mode
 	}
 
-	if err = repo.getOwner(e); err != nil {
+	teamMode, err := repo.getTeamAccessMode(e, uid)
+	if err != nil {
 		return err
-	} else if repo.Owner.IsOrganization() {
-		var teams []Team
-		if err := e.Join("INNER", "team_repo", "team_repo.team_id = team.id").
-			Join("INNER", "team_user", "team_user.team_id = team.id").
-			Where("team.org_id = ?", repo.OwnerID).
-			And("team_repo.repo_id=?", repo.ID).
-			And("team_user.uid=?", uid).
-			Find(&teams); err != nil {
-			return err
-		}
-
-		for _, t := range teams {
-			if t.IsOwnerTeam() {
-				t.Authorize = AccessModeOwner
-			}
-
-			accessMode = maxAccessMode(accessMode, t.Authorize)
-		}
 	}
+	accessMode = maxAccessMode(accessMode, teamMode)
 
 	// Delete old user accesses and insert new one for repository.
 	if _, err = e.Delete(&Access{RepoID: repo.ID, UserID: uid}); err != nil {
@@ -323,6 +322,34 @@ func (repo *Repository) recalculateUserAccess(e Engine, uid int64) (err error) {
 	return nil
 }
 
+// getTeamAccessMode returns the highest access mode the teams of the user grant to the repository of an organization
+func (repo *Repository) getTeamAccessMode(e Engine, uid int64) (AccessMode, error) {
+	if err := repo.getOwner(e); err != nil {
+		return AccessModeNone, err
+	} else if !repo.Owner.IsOrganization() {
+		return AccessModeNone, nil
+	}
+
+	var teams []Team
+	if err := e.Join("INNER", "team_repo", "team_repo.team_id = team.id").
+		Join("INNER", "team_user", "team_user.team_id = team.id").
+		Where("team.org_id = ?", repo.OwnerID).
+		And("team_repo.repo_id=?", repo.ID).
+		And("team_user.uid=?", uid).
+		Find(&teams); err != nil {
+		return AccessModeNone, err
+	}
+
+	accessMode := AccessModeNone
+	for _, t := range teams {
+		if t.IsOwnerTeam() {
+			t.Authorize = AccessModeOwner
+		}
+		accessMode = maxAccessMode(accessMode, t.Authorize)
+	}
+	return accessMode, nil
+}
+
 func (repo *Repository) recalculateAccesses(e Engine) error {
 	if repo.Owner.IsOrganization() {
 		return repo.recalculateTeamAccesses(e, 0)
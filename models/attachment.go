@@ -6,15 +6,22 @@ package models
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"path"
+	"time"
 
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 
 	gouuid "github.com/google/uuid"
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -22,6 +29,7 @@ import (
 type Attachment struct {
 	ID            int64  `xorm:"pk autoincr"`
 	UUID          string `xorm:"uuid UNIQUE"`
+	RepoID        int64  `xorm:"INDEX"` // this should not be zero
 	IssueID       int64  `xorm:"INDEX"`
 	ReleaseID     int64  `xorm:"INDEX"`
 	UploaderID    int64  `xorm:"INDEX DEFAULT 0"` // Notice: will be zero before this column added
@@ -30,6 +38,10 @@ type Attachment struct {
 	DownloadCount int64              `xorm:"DEFAULT 0"`
 	Size          int64              `xorm:"DEFAULT 0"`
 	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+
+	Uploader *User    `xorm:"-"`
+	Issue    *Issue   `xorm:"-"`
+	Release  *Release `xorm:"-"`
 }
 
 // IncreaseDownloadCount is update download count + 1
@@ -42,6 +54,12 @@ func (a *Attachment) IncreaseDownloadCount() error {
 	return nil
 }
 
+// IsOrphaned returns true if the attachment is neither linked to an issue, a comment nor a release,
+// e.g. because it was uploaded but the issue or comment was never posted
+func (a *Attachment) IsOrphaned() bool {
+	return a.IssueID == 0 && a.ReleaseID == 0
+}
+
 // AttachmentRelativePath returns the relative path
 func AttachmentRelativePath(uuid string) string {
 	return path.Join(uuid[0:1], uuid[1:2], uuid)
@@ -57,6 +75,29 @@ func (a *Attachment) DownloadURL() string {
 	return fmt.Sprintf("%sattachments/%s", setting.AppURL, a.UUID)
 }
 
+// downloadTokenData returns the data a download token of the attachment is created from
+func (a *Attachment) downloadTokenData() string {
+	return "attachment:" + a.UUID
+}
+
+// CreateDownloadToken creates a token which allows to download the attachment without
+// signing in for the given minutes and returns it with the time it expires at
+func (a *Attachment) CreateDownloadToken(minutes int) (string, time.Time) {
+	token := base.CreateTimeLimitCode(a.downloadTokenData(), minutes, nil)
+	start, _ := time.ParseInLocation("200601021504", token[:12], time.Local)
+	return token, start.Add(time.Duration(minutes) * time.Minute)
+}
+
+// VerifyDownloadToken returns whether the token has been created for the attachment and has not expired yet
+func (a *Attachment) VerifyDownloadToken(token string) bool {
+	return base.VerifyTimeLimitCode(a.downloadTokenData(), 0, token)
+}
+
+// SignedDownloadURL returns the download url of the attachment including a download token
+func (a *Attachment) SignedDownloadURL(token string) string {
+	return a.DownloadURL() + "?token=" + url.QueryEscape(token)
+}
+
 // LinkedRepository returns the linked repo if any
 func (a *Attachment) LinkedRepository() (*Repository, UnitType, error) {
 	if a.IssueID != 0 {
@@ -279,3 +320,164 @@ func IterateAttachment(f func(attach *Attachment) error) error {
 		}
 	}
 }
+
+// AttachmentList defines a list of attachments
+type AttachmentList []*Attachment
+
+// LoadAttributes loads the uploaders, issues and releases of the attachments,
+// attachments of deleted users get the ghost user
+func (attachments AttachmentList) LoadAttributes() error {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	userIDs := make([]int64, 0, len(attachments))
+	issueIDs := make([]int64, 0, len(attachments))
+	releaseIDs := make([]int64, 0, len(attachments))
+	for _, a := range attachments {
+		if a.UploaderID > 0 {
+			userIDs = append(userIDs, a.UploaderID)
+		}
+		if a.IssueID > 0 {
+			issueIDs = append(issueIDs, a.IssueID)
+		}
+		if a.ReleaseID > 0 {
+			releaseIDs = append(releaseIDs, a.ReleaseID)
+		}
+	}
+
+	users := make(map[int64]*User, len(userIDs))
+	if len(userIDs) > 0 {
+		if err := x.In("id", userIDs).Find(&users); err != nil {
+			return fmt.Errorf("find uploaders: %v", err)
+		}
+	}
+	issues := make(map[int64]*Issue, len(issueIDs))
+	if len(issueIDs) > 0 {
+		if err := x.In("id", issueIDs).Find(&issues); err != nil {
+			return fmt.Errorf("find issues: %v", err)
+		}
+	}
+	releases := make(map[int64]*Release, len(releaseIDs))
+	if len(releaseIDs) > 0 {
+		if err := x.In("id", releaseIDs).Find(&releases); err != nil {
+			return fmt.Errorf("find releases: %v", err)
+		}
+	}
+
+	for _, a := range attachments {
+		if a.Uploader = users[a.UploaderID]; a.Uploader == nil {
+			a.Uploader = NewGhostUser()
+		}
+		a.Issue = issues[a.IssueID]
+		a.Release = releases[a.ReleaseID]
+	}
+	return nil
+}
+
+// FindRepoAttachmentsOptions represents the options to find the attachments of a repository
+type FindRepoAttachmentsOptions struct {
+	ListOptions
+	RepoID   int64
+	Orphaned util.OptionalBool
+	// OlderThan only returns attachments created before this time if set
+	OlderThan timeutil.TimeStamp
+}
+
+func (opts *FindRepoAttachmentsOptions) toConds() builder.Cond {
+	cond := builder.NewCond().And(builder.Eq{"repo_id": opts.RepoID})
+	switch opts.Orphaned {
+	case util.OptionalBoolTrue:
+		cond = cond.And(builder.Eq{"issue_id": 0, "release_id": 0})
+	case util.OptionalBoolFalse:
+		cond = cond.And(builder.Or(builder.Neq{"issue_id": 0}, builder.Neq{"release_id": 0}))
+	}
+	if opts.OlderThan > 0 {
+		cond = cond.And(builder.Lt{"created_unix": opts.OlderThan})
+	}
+	return cond
+}
+
+// FindRepoAttachments returns the attachments of a repository matching the options, newest first
+func FindRepoAttachments(opts *FindRepoAttachmentsOptions) (AttachmentList, error) {
+	sess := x.Where(opts.toConds()).Desc("created_unix").Desc("id")
+	if opts.Page != 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	attachments := make(AttachmentList, 0, opts.PageSize)
+	return attachments, sess.Find(&attachments)
+}
+
+// CountRepoAttachments returns the number and the total size of the attachments of a repository matching the options
+func CountRepoAttachments(opts *FindRepoAttachmentsOptions) (count, size int64, err error) {
+	count, err = x.Where(opts.toConds()).Count(new(Attachment))
+	if err != nil {
+		return 0, 0, err
+	}
+	size, err = x.Where(opts.toConds()).SumInt(new(Attachment), "size")
+	return count, size, err
+}
+
+// SumRepoReleaseAttachmentsSize returns the total size of the attachments of the releases of a repository
+func SumRepoReleaseAttachmentsSize(repoID int64) (int64, error) {
+	return x.Where("repo_id = ? AND release_id > 0", repoID).SumInt(new(Attachment), "size")
+}
+
+// GetRepoAttachmentsByIDs returns the attachments of a repository with the given ids,
+// ids of attachments of other repositories are silently dropped
+func GetRepoAttachmentsByIDs(repoID int64, ids []int64) (AttachmentList, error) {
+	attachments := make(AttachmentList, 0, len(ids))
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+	return attachments, x.Where("repo_id = ?", repoID).In("id", ids).Find(&attachments)
+}
+
+// DeleteExpiredOrphanedAttachments deletes the orphaned attachments of all repositories
+// which are older than the attachment retention period configured for their repository,
+// as well as the attachments older than a day which do not belong to any repository
+func DeleteExpiredOrphanedAttachments(ctx context.Context) error {
+	attachments, err := FindRepoAttachments(&FindRepoAttachmentsOptions{
+		RepoID:    0,
+		OlderThan: timeutil.TimeStamp(time.Now().AddDate(0, 0, -1).Unix()),
+	})
+	if err != nil {
+		return err
+	}
+	if len(attachments) > 0 {
+		if _, err := DeleteAttachments(attachments, true); err != nil {
+			return fmt.Errorf("delete attachments without repository: %v", err)
+		}
+		log.Trace("Deleted %d attachments without repository", len(attachments))
+	}
+
+	repos := make([]*Repository, 0, 10)
+	if err := x.Where("attachment_retention_days > 0").Cols("id", "owner_name", "name", "attachment_retention_days").Find(&repos); err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		select {
+		case <-ctx.Done():
+			return ErrCancelledf("before deleting orphaned attachments of %s/%s", repo.OwnerName, repo.Name)
+		default:
+		}
+
+		attachments, err := FindRepoAttachments(&FindRepoAttachmentsOptions{
+			RepoID:    repo.ID,
+			Orphaned:  util.OptionalBoolTrue,
+			OlderThan: timeutil.TimeStamp(time.Now().AddDate(0, 0, -repo.AttachmentRetentionDays).Unix()),
+		})
+		if err != nil {
+			return err
+		}
+		if len(attachments) == 0 {
+			continue
+		}
+		if _, err := DeleteAttachments(attachments, true); err != nil {
+			return fmt.Errorf("delete orphaned attachments of %s/%s: %v", repo.OwnerName, repo.Name, err)
+		}
+		log.Trace("Deleted %d orphaned attachments of %s/%s", len(attachments), repo.OwnerName, repo.Name)
+	}
+	return nil
+}
@@ -5,9 +5,14 @@
 package models
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -104,6 +109,22 @@ func TestAttachment_DownloadURL(t *testing.T) {
 	assert.Equal(t, "https://try.gitea.io/attachments/a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", attach.DownloadURL())
 }
 
+func TestAttachment_DownloadToken(t *testing.T) {
+	attach := &Attachment{UUID: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"}
+	other := &Attachment{UUID: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12"}
+
+	token, expires := attach.CreateDownloadToken(5)
+	assert.True(t, expires.After(time.Now().Add(4*time.Minute)))
+	assert.True(t, attach.VerifyDownloadToken(token))
+	assert.False(t, other.VerifyDownloadToken(token))
+	assert.False(t, attach.VerifyDownloadToken("invalid"))
+	assert.Equal(t, "https://try.gitea.io/attachments/a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11?token="+token, attach.SignedDownloadURL(token))
+
+	// expired tokens are rejected
+	token, _ = attach.CreateDownloadToken(0)
+	assert.False(t, attach.VerifyDownloadToken(token))
+}
+
 func TestUpdateAttachment(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 
@@ -156,3 +177,71 @@ func TestLinkedRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestFindRepoAttachments(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	attachments, err := FindRepoAttachments(&FindRepoAttachmentsOptions{RepoID: 1})
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 7)
+
+	count, size, err := CountRepoAttachments(&FindRepoAttachmentsOptions{RepoID: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 7, count)
+	assert.EqualValues(t, 0, size)
+
+	attachments, err = FindRepoAttachments(&FindRepoAttachmentsOptions{RepoID: 1, Orphaned: util.OptionalBoolTrue})
+	assert.NoError(t, err)
+	if assert.Len(t, attachments, 1) {
+		assert.EqualValues(t, 10, attachments[0].ID)
+		assert.True(t, attachments[0].IsOrphaned())
+	}
+
+	attachments, err = FindRepoAttachments(&FindRepoAttachmentsOptions{RepoID: 1, Orphaned: util.OptionalBoolFalse})
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 6)
+	assert.NoError(t, attachments.LoadAttributes())
+	for _, attachment := range attachments {
+		assert.False(t, attachment.IsOrphaned())
+		assert.NotNil(t, attachment.Uploader)
+		assert.True(t, attachment.Issue != nil || attachment.Release != nil)
+	}
+
+	attachments, err = FindRepoAttachments(&FindRepoAttachmentsOptions{
+		ListOptions: ListOptions{Page: 1, PageSize: 2},
+		RepoID:      1,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 2)
+}
+
+func TestGetRepoAttachmentsByIDs(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	attachments, err := GetRepoAttachmentsByIDs(1, []int64{1, 2, 10})
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 2)
+}
+
+func TestDeleteExpiredOrphanedAttachments(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// attachments without repository are deleted after a day
+	fresh := &Attachment{UUID: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a99", Name: "fresh.txt", CreatedUnix: timeutil.TimeStampNow()}
+	stale := &Attachment{UUID: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a98", Name: "stale.txt", CreatedUnix: timeutil.TimeStamp(time.Now().AddDate(0, 0, -2).Unix())}
+	_, err := x.NoAutoTime().Insert(fresh, stale)
+	assert.NoError(t, err)
+
+	// no retention configured
+	assert.NoError(t, DeleteExpiredOrphanedAttachments(context.Background()))
+	AssertExistsAndLoadBean(t, &Attachment{ID: 10})
+	AssertExistsAndLoadBean(t, &Attachment{ID: fresh.ID})
+	AssertNotExistsBean(t, &Attachment{ID: stale.ID})
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	repo.AttachmentRetentionDays = 1
+	assert.NoError(t, UpdateRepositoryCols(repo, "attachment_retention_days"))
+	assert.NoError(t, DeleteExpiredOrphanedAttachments(context.Background()))
+	AssertNotExistsBean(t, &Attachment{ID: 10})
+	AssertExistsAndLoadBean(t, &Attachment{ID: 9})
+}
@@ -26,29 +26,75 @@ type ProtectedBranch struct {
 	BranchName                    string `xorm:"UNIQUE(s)"`
 	CanPush                       bool   `xorm:"NOT NULL DEFAULT false"`
 	EnableWhitelist               bool
-	WhitelistUserIDs              []int64  `xorm:"JSON TEXT"`
-	WhitelistTeamIDs              []int64  `xorm:"JSON TEXT"`
-	EnableMergeWhitelist          bool     `xorm:"NOT NULL DEFAULT false"`
-	WhitelistDeployKeys           bool     `xorm:"NOT NULL DEFAULT false"`
-	MergeWhitelistUserIDs         []int64  `xorm:"JSON TEXT"`
-	MergeWhitelistTeamIDs         []int64  `xorm:"JSON TEXT"`
-	EnableStatusCheck             bool     `xorm:"NOT NULL DEFAULT false"`
-	StatusCheckContexts           []string `xorm:"JSON TEXT"`
-	EnableApprovalsWhitelist      bool     `xorm:"NOT NULL DEFAULT false"`
-	ApprovalsWhitelistUserIDs     []int64  `xorm:"JSON TEXT"`
-	ApprovalsWhitelistTeamIDs     []int64  `xorm:"JSON TEXT"`
-	RequiredApprovals             int64    `xorm:"NOT NULL DEFAULT 0"`
-	BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
-	BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
-	BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
-	DismissStaleApprovals         bool     `xorm:"NOT NULL DEFAULT false"`
-	RequireSignedCommits          bool     `xorm:"NOT NULL DEFAULT false"`
-	ProtectedFilePatterns         string   `xorm:"TEXT"`
+	WhitelistUserIDs              []int64               `xorm:"JSON TEXT"`
+	WhitelistTeamIDs              []int64               `xorm:"JSON TEXT"`
+	EnableMergeWhitelist          bool                  `xorm:"NOT NULL DEFAULT false"`
+	WhitelistDeployKeys           bool                  `xorm:"NOT NULL DEFAULT false"`
+	MergeWhitelistUserIDs         []int64               `xorm:"JSON TEXT"`
+	MergeWhitelistTeamIDs         []int64               `xorm:"JSON TEXT"`
+	EnableStatusCheck             bool                  `xorm:"NOT NULL DEFAULT false"`
+	StatusCheckContexts           []string              `xorm:"JSON TEXT"`
+	EnableApprovalsWhitelist      bool                  `xorm:"NOT NULL DEFAULT false"`
+	ApprovalsWhitelistUserIDs     []int64               `xorm:"JSON TEXT"`
+	ApprovalsWhitelistTeamIDs     []int64               `xorm:"JSON TEXT"`
+	RequiredApprovals             int64                 `xorm:"NOT NULL DEFAULT 0"`
+	BlockOnRejectedReviews        bool                  `xorm:"NOT NULL DEFAULT false"`
+	BlockOnOfficialReviewRequests bool                  `xorm:"NOT NULL DEFAULT false"`
+	BlockOnOutdatedBranch         bool                  `xorm:"NOT NULL DEFAULT false"`
+	DismissStaleApprovals         bool                  `xorm:"NOT NULL DEFAULT false"`
+	RequireSignedCommits          bool                  `xorm:"NOT NULL DEFAULT false"`
+	ProtectedFilePatterns         string                `xorm:"TEXT"`
+	PushToPullRequest             PushToPullRequestMode `xorm:"NOT NULL DEFAULT 0"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
 }
 
+// PushToPullRequestMode defines whether direct pushes to a protected branch are turned into pull requests
+type PushToPullRequestMode int
+
+const (
+	// PushToPullRequestDisabled pushes are checked against the protection rules as usual
+	PushToPullRequestDisabled PushToPullRequestMode = iota
+	// PushToPullRequestOnOption only pushes with the "pull-request" push option are turned into pull requests
+	PushToPullRequestOnOption
+	// PushToPullRequestAlways all pushes by users are turned into pull requests
+	PushToPullRequestAlways
+)
+
+// String converts a PushToPullRequestMode to a string
+func (m PushToPullRequestMode) String() string {
+	switch m {
+	case PushToPullRequestOnOption:
+		return "option"
+	case PushToPullRequestAlways:
+		return "always"
+	}
+	return "disabled"
+}
+
+// ToPushToPullRequestMode converts a string to a PushToPullRequestMode
+func ToPushToPullRequestMode(mode string) PushToPullRequestMode {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "option":
+		return PushToPullRequestOnOption
+	case "always":
+		return PushToPullRequestAlways
+	}
+	return PushToPullRequestDisabled
+}
+
+// IsPushToPullRequest returns true if a push to this branch should be turned into a pull request
+func (protectBranch *ProtectedBranch) IsPushToPullRequest(pushOptionSet bool) bool {
+	switch protectBranch.PushToPullRequest {
+	case PushToPullRequestAlways:
+		return true
+	case PushToPullRequestOnOption:
+		return pushOptionSet
+	}
+	return false
+}
+
 // IsProtected returns if the branch is protected
 func (protectBranch *ProtectedBranch) IsProtected() bool {
 	return protectBranch.ID > 0
@@ -510,6 +556,10 @@ func (repo *Repository) DeleteProtectedBranch(id int64) (err error) {
 		return fmt.Errorf("delete protected branch ID(%v) failed", id)
 	}
 
+	if _, err = sess.Delete(&StatusCheckContextChange{ProtectedBranchID: id}); err != nil {
+		return err
+	}
+
 	return sess.Commit()
 }
 
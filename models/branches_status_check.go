@@ -0,0 +1,149 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// StatusCheckContextChange records the registration or removal of a required status check context
+// of a protected branch through the API, so changes made by CI systems can be audited
+type StatusCheckContextChange struct {
+	ID                int64              `xorm:"pk autoincr"`
+	RepoID            int64              `xorm:"INDEX NOT NULL"`
+	ProtectedBranchID int64              `xorm:"INDEX NOT NULL"`
+	DoerID            int64              `xorm:"NOT NULL"`
+	Doer              *User              `xorm:"-"`
+	Context           string             `xorm:"NOT NULL"`
+	IsRegistered      bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix       timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// LoadDoer loads the user who made the change, falling back to the ghost user if they were deleted
+func (change *StatusCheckContextChange) LoadDoer() error {
+	if change.Doer != nil {
+		return nil
+	}
+	user, err := GetUserByID(change.DoerID)
+	if err != nil {
+		if !IsErrUserNotExist(err) {
+			return err
+		}
+		user = NewGhostUser()
+	}
+	change.Doer = user
+	return nil
+}
+
+func (protectBranch *ProtectedBranch) changeStatusCheckContexts(doer *User, contexts []string, register bool) ([]string, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	// reload and lock the protection inside the transaction, so concurrent CI jobs do not overwrite each other's contexts
+	current := &ProtectedBranch{ID: protectBranch.ID}
+	if has, err := sess.ForUpdate().Get(current); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("protected branch ID(%d) does not exist", protectBranch.ID)
+	}
+
+	required := make(map[string]bool, len(current.StatusCheckContexts))
+	for _, context := range current.StatusCheckContexts {
+		required[context] = true
+	}
+
+	changed := make([]string, 0, len(contexts))
+	for _, context := range contexts {
+		if required[context] == register {
+			continue
+		}
+		required[context] = register
+		changed = append(changed, context)
+		if register {
+			current.StatusCheckContexts = append(current.StatusCheckContexts, context)
+		}
+	}
+	if !register {
+		remaining := make([]string, 0, len(current.StatusCheckContexts))
+		for _, context := range current.StatusCheckContexts {
+			if required[context] {
+				remaining = append(remaining, context)
+			}
+		}
+		current.StatusCheckContexts = remaining
+	}
+
+	if register && !current.EnableStatusCheck {
+		current.EnableStatusCheck = true
+	} else if len(changed) == 0 {
+		*protectBranch = *current
+		return changed, nil
+	}
+
+	if _, err := sess.ID(current.ID).Cols("enable_status_check", "status_check_contexts").Update(current); err != nil {
+		return nil, err
+	}
+
+	for _, context := range changed {
+		if _, err := sess.Insert(&StatusCheckContextChange{
+			RepoID:            current.RepoID,
+			ProtectedBranchID: current.ID,
+			DoerID:            doer.ID,
+			Context:           context,
+			IsRegistered:      register,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := sess.Commit(); err != nil {
+		return nil, err
+	}
+	*protectBranch = *current
+	return changed, nil
+}
+
+// RegisterStatusCheckContexts adds contexts to the required status checks of the protected branch
+// and enables status checks. It returns the contexts which were not required before.
+func (protectBranch *ProtectedBranch) RegisterStatusCheckContexts(doer *User, contexts []string) ([]string, error) {
+	return protectBranch.changeStatusCheckContexts(doer, contexts, true)
+}
+
+// UnregisterStatusCheckContexts removes contexts from the required status checks of the protected branch.
+// It returns the contexts which were required before.
+func (protectBranch *ProtectedBranch) UnregisterStatusCheckContexts(doer *User, contexts []string) ([]string, error) {
+	return protectBranch.changeStatusCheckContexts(doer, contexts, false)
+}
+
+// GetStatusCheckContextChanges returns the recorded changes of the required status check contexts
+// of the protected branch, the latest first
+func (protectBranch *ProtectedBranch) GetStatusCheckContextChanges(listOptions ListOptions) ([]*StatusCheckContextChange, error) {
+	sess := x.Where("protected_branch_id = ?", protectBranch.ID).Desc("created_unix", "id")
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+
+	changes := make([]*StatusCheckContextChange, 0, 10)
+	if err := sess.Find(&changes); err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		if err := change.LoadDoer(); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// CountStatusCheckContextChanges returns the number of recorded changes of the required status check contexts
+// of the protected branch
+func (protectBranch *ProtectedBranch) CountStatusCheckContextChanges() (int64, error) {
+	return x.Where("protected_branch_id = ?", protectBranch.ID).Count(new(StatusCheckContextChange))
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectedBranch_RegisterStatusCheckContexts(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	protectBranch := &ProtectedBranch{
+		RepoID:              repo.ID,
+		BranchName:          "master",
+		StatusCheckContexts: []string{"ci/lint"},
+	}
+	assert.NoError(t, UpdateProtectBranch(repo, protectBranch, WhitelistOptions{}))
+
+	added, err := protectBranch.RegisterStatusCheckContexts(doer, []string{"ci/lint", "ci/test (go1.14)", "ci/test (go1.15)", "ci/test (go1.15)"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"ci/test (go1.14)", "ci/test (go1.15)"}, added)
+	assert.True(t, protectBranch.EnableStatusCheck)
+	assert.EqualValues(t, []string{"ci/lint", "ci/test (go1.14)", "ci/test (go1.15)"}, protectBranch.StatusCheckContexts)
+
+	removed, err := protectBranch.UnregisterStatusCheckContexts(doer, []string{"ci/test (go1.14)", "ci/unknown"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"ci/test (go1.14)"}, removed)
+
+	protectBranch = AssertExistsAndLoadBean(t, &ProtectedBranch{ID: protectBranch.ID}).(*ProtectedBranch)
+	assert.True(t, protectBranch.EnableStatusCheck)
+	assert.EqualValues(t, []string{"ci/lint", "ci/test (go1.15)"}, protectBranch.StatusCheckContexts)
+
+	count, err := protectBranch.CountStatusCheckContextChanges()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+
+	changes, err := protectBranch.GetStatusCheckContextChanges(ListOptions{Page: 1, PageSize: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, changes, 1) {
+		assert.Equal(t, "ci/test (go1.14)", changes[0].Context)
+		assert.False(t, changes[0].IsRegistered)
+		assert.EqualValues(t, doer.ID, changes[0].Doer.ID)
+	}
+
+	assert.NoError(t, repo.DeleteProtectedBranch(protectBranch.ID))
+	AssertNotExistsBean(t, &StatusCheckContextChange{ProtectedBranchID: protectBranch.ID})
+}
@@ -78,3 +78,20 @@ func getDeletedBranch(t *testing.T, branch *DeletedBranch) *DeletedBranch {
 
 	return deletedBranch
 }
+
+func TestProtectedBranchIsPushToPullRequest(t *testing.T) {
+	for _, mode := range []PushToPullRequestMode{PushToPullRequestDisabled, PushToPullRequestOnOption, PushToPullRequestAlways} {
+		assert.Equal(t, mode, ToPushToPullRequestMode(mode.String()))
+	}
+	assert.Equal(t, PushToPullRequestDisabled, ToPushToPullRequestMode("unknown"))
+
+	protectBranch := &ProtectedBranch{PushToPullRequest: PushToPullRequestDisabled}
+	assert.False(t, protectBranch.IsPushToPullRequest(true))
+
+	protectBranch.PushToPullRequest = PushToPullRequestOnOption
+	assert.False(t, protectBranch.IsPushToPullRequest(false))
+	assert.True(t, protectBranch.IsPushToPullRequest(true))
+
+	protectBranch.PushToPullRequest = PushToPullRequestAlways
+	assert.True(t, protectBranch.IsPushToPullRequest(false))
+}
@@ -0,0 +1,258 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// FeatureFlag gates a feature of the instance which is rolled out gradually.
+// A flag is on for the users and organizations it targets and for a percentage of all users,
+// as long as the flag itself is enabled.
+type FeatureFlag struct {
+	ID          int64  `xorm:"pk autoincr"`
+	Name        string `xorm:"UNIQUE NOT NULL"`
+	Description string `xorm:"TEXT"`
+	// Enabled is the switch of the flag, a disabled flag is off for everyone
+	Enabled bool `xorm:"NOT NULL DEFAULT false"`
+	// Percentage is the percentage of all users the flag is on for
+	Percentage int `xorm:"NOT NULL DEFAULT 0"`
+	// TargetIDs are the IDs of the users and organizations the flag is on for
+	TargetIDs []int64 `xorm:"JSON TEXT"`
+	Targets   []*User `xorm:"-"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// ErrFeatureFlagNotExist represents a "FeatureFlagNotExist" kind of error.
+type ErrFeatureFlagNotExist struct {
+	ID   int64
+	Name string
+}
+
+// IsErrFeatureFlagNotExist checks if an error is a ErrFeatureFlagNotExist.
+func IsErrFeatureFlagNotExist(err error) bool {
+	_, ok := err.(ErrFeatureFlagNotExist)
+	return ok
+}
+
+func (err ErrFeatureFlagNotExist) Error() string {
+	return fmt.Sprintf("feature flag does not exist [id: %d, name: %s]", err.ID, err.Name)
+}
+
+// ErrFeatureFlagAlreadyExist represents a "FeatureFlagAlreadyExist" kind of error.
+type ErrFeatureFlagAlreadyExist struct {
+	Name string
+}
+
+// IsErrFeatureFlagAlreadyExist checks if an error is a ErrFeatureFlagAlreadyExist.
+func IsErrFeatureFlagAlreadyExist(err error) bool {
+	_, ok := err.(ErrFeatureFlagAlreadyExist)
+	return ok
+}
+
+func (err ErrFeatureFlagAlreadyExist) Error() string {
+	return fmt.Sprintf("feature flag already exists [name: %s]", err.Name)
+}
+
+// ErrInvalidFeatureFlag represents an error for a feature flag with invalid settings
+type ErrInvalidFeatureFlag struct {
+	Reason string
+}
+
+// IsErrInvalidFeatureFlag checks if an error is a ErrInvalidFeatureFlag.
+func IsErrInvalidFeatureFlag(err error) bool {
+	_, ok := err.(ErrInvalidFeatureFlag)
+	return ok
+}
+
+func (err ErrInvalidFeatureFlag) Error() string {
+	return fmt.Sprintf("invalid feature flag: %s", err.Reason)
+}
+
+var featureFlagNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,49}$`)
+
+// Validate checks the settings of the feature flag
+func (f *FeatureFlag) Validate() error {
+	f.Name = strings.ToLower(strings.TrimSpace(f.Name))
+	if !featureFlagNamePattern.MatchString(f.Name) {
+		return ErrInvalidFeatureFlag{"name must consist of up to 50 lower case letters, digits, dashes, dots and underscores"}
+	}
+	if f.Percentage < 0 || f.Percentage > 100 {
+		return ErrInvalidFeatureFlag{"percentage must be between 0 and 100"}
+	}
+	return nil
+}
+
+// LoadTargets loads the users and organizations the flag is on for
+func (f *FeatureFlag) LoadTargets() (err error) {
+	if f.Targets != nil {
+		return nil
+	}
+	f.Targets, err = GetUsersByIDs(f.TargetIDs)
+	return err
+}
+
+// IsTarget returns true if the flag targets the user or organization
+func (f *FeatureFlag) IsTarget(u *User) bool {
+	for _, id := range f.TargetIDs {
+		if id == u.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// inPercentage returns true if the user falls into the percentage of users the flag is on for.
+// Every user is assigned to the same bucket of a flag every time, but to different buckets of different flags.
+func (f *FeatureFlag) inPercentage(u *User) bool {
+	if f.Percentage >= 100 {
+		return true
+	} else if f.Percentage <= 0 || u == nil {
+		return false
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(f.Name + ":" + strconv.FormatInt(u.ID, 10)))
+	return int(h.Sum32()%100) < f.Percentage
+}
+
+// IsOnFor returns true if the flag is on for the user, nil for anonymous users, or for
+// one of the owners, the users or organizations owning what the user is working on.
+func (f *FeatureFlag) IsOnFor(u *User, owners ...*User) bool {
+	if !f.Enabled {
+		return false
+	}
+	if u != nil && f.IsTarget(u) {
+		return true
+	}
+	for _, owner := range owners {
+		if owner != nil && f.IsTarget(owner) {
+			return true
+		}
+	}
+	return f.inPercentage(u)
+}
+
+func isFeatureFlagNameExist(e Engine, id int64, name string) (bool, error) {
+	return e.Where("id != ? AND name = ?", id, name).Exist(new(FeatureFlag))
+}
+
+// CreateFeatureFlag creates a new feature flag
+func CreateFeatureFlag(f *FeatureFlag) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if exist, err := isFeatureFlagNameExist(sess, 0, f.Name); err != nil {
+		return err
+	} else if exist {
+		return ErrFeatureFlagAlreadyExist{f.Name}
+	}
+	if _, err := sess.Insert(f); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// UpdateFeatureFlag updates the settings of a feature flag
+func UpdateFeatureFlag(f *FeatureFlag) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if exist, err := isFeatureFlagNameExist(sess, f.ID, f.Name); err != nil {
+		return err
+	} else if exist {
+		return ErrFeatureFlagAlreadyExist{f.Name}
+	}
+	if _, err := sess.ID(f.ID).AllCols().Update(f); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// DeleteFeatureFlag deletes a feature flag
+func DeleteFeatureFlag(id int64) error {
+	n, err := x.ID(id).Delete(new(FeatureFlag))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrFeatureFlagNotExist{ID: id}
+	}
+	return nil
+}
+
+// GetFeatureFlagByID returns the feature flag by its ID
+func GetFeatureFlagByID(id int64) (*FeatureFlag, error) {
+	f := new(FeatureFlag)
+	has, err := x.ID(id).Get(f)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrFeatureFlagNotExist{ID: id}
+	}
+	return f, nil
+}
+
+// GetFeatureFlagByName returns the feature flag by its name
+func GetFeatureFlagByName(name string) (*FeatureFlag, error) {
+	f := &FeatureFlag{Name: strings.ToLower(name)}
+	has, err := x.Get(f)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrFeatureFlagNotExist{Name: name}
+	}
+	return f, nil
+}
+
+// GetFeatureFlags returns all feature flags
+func GetFeatureFlags() ([]*FeatureFlag, error) {
+	flags := make([]*FeatureFlag, 0, 10)
+	return flags, x.Asc("name").Find(&flags)
+}
+
+// removeFeatureFlagTarget removes a deleted user or organization from the targets of all feature flags
+func removeFeatureFlagTarget(e Engine, userID int64) error {
+	flags := make([]*FeatureFlag, 0, 10)
+	if err := e.Find(&flags); err != nil {
+		return err
+	}
+	for _, f := range flags {
+		if !f.IsTarget(&User{ID: userID}) {
+			continue
+		}
+		targetIDs := make([]int64, 0, len(f.TargetIDs))
+		for _, id := range f.TargetIDs {
+			if id != userID {
+				targetIDs = append(targetIDs, id)
+			}
+		}
+		f.TargetIDs = targetIDs
+		if _, err := e.ID(f.ID).Cols("target_i_ds").Update(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
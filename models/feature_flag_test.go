@@ -0,0 +1,108 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateFeatureFlag(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	f := &FeatureFlag{Name: " New-UI ", Enabled: true, Percentage: 10, TargetIDs: []int64{4}}
+	assert.NoError(t, CreateFeatureFlag(f))
+	AssertExistsAndLoadBean(t, &FeatureFlag{ID: f.ID, Name: "new-ui"})
+
+	assert.True(t, IsErrFeatureFlagAlreadyExist(CreateFeatureFlag(&FeatureFlag{Name: "Merge-Queue"})))
+	assert.True(t, IsErrInvalidFeatureFlag(CreateFeatureFlag(&FeatureFlag{Name: "not a name"})))
+	assert.True(t, IsErrInvalidFeatureFlag(CreateFeatureFlag(&FeatureFlag{Name: "too-much", Percentage: 101})))
+
+	f.Name = "packages"
+	assert.True(t, IsErrFeatureFlagAlreadyExist(UpdateFeatureFlag(f)))
+	f.Name = "new-ui.v2"
+	f.Enabled = false
+	assert.NoError(t, UpdateFeatureFlag(f))
+	assert.False(t, AssertExistsAndLoadBean(t, &FeatureFlag{ID: f.ID, Name: "new-ui.v2"}).(*FeatureFlag).Enabled)
+
+	flags, err := GetFeatureFlags()
+	assert.NoError(t, err)
+	if assert.Len(t, flags, 3) {
+		assert.EqualValues(t, "merge-queue", flags[0].Name)
+		assert.EqualValues(t, "new-ui.v2", flags[1].Name)
+		assert.EqualValues(t, []int64{4}, flags[1].TargetIDs)
+	}
+
+	assert.NoError(t, DeleteFeatureFlag(f.ID))
+	AssertNotExistsBean(t, &FeatureFlag{ID: f.ID})
+	assert.True(t, IsErrFeatureFlagNotExist(DeleteFeatureFlag(f.ID)))
+}
+
+func TestGetFeatureFlagByName(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	f, err := GetFeatureFlagByName("Merge-Queue")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, f.ID)
+	assert.EqualValues(t, []int64{2, 3}, f.TargetIDs)
+
+	assert.NoError(t, f.LoadTargets())
+	if assert.Len(t, f.Targets, 2) {
+		assert.EqualValues(t, "user2", f.Targets[0].Name)
+		assert.EqualValues(t, "user3", f.Targets[1].Name)
+	}
+
+	_, err = GetFeatureFlagByName("unknown")
+	assert.True(t, IsErrFeatureFlagNotExist(err))
+}
+
+func TestFeatureFlag_IsOnFor(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	org3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	f := AssertExistsAndLoadBean(t, &FeatureFlag{ID: 1}).(*FeatureFlag)
+	assert.True(t, f.IsOnFor(user2))
+	assert.False(t, f.IsOnFor(user4))
+	assert.True(t, f.IsOnFor(user4, org3))
+	assert.False(t, f.IsOnFor(nil))
+	assert.True(t, f.IsOnFor(nil, org3))
+
+	f.Enabled = false
+	assert.False(t, f.IsOnFor(user2))
+
+	// disabled flags are off for everyone
+	f = AssertExistsAndLoadBean(t, &FeatureFlag{ID: 2}).(*FeatureFlag)
+	assert.False(t, f.IsOnFor(user2))
+	f.Enabled = true
+	assert.True(t, f.IsOnFor(user2))
+	assert.True(t, f.IsOnFor(nil))
+
+	// users stay in the percentage when it is raised
+	on := 0
+	for id := int64(1); id <= 1000; id++ {
+		f.Percentage = 30
+		isOn := f.IsOnFor(&User{ID: id})
+		if isOn {
+			on++
+		}
+		f.Percentage = 60
+		if isOn {
+			assert.True(t, f.IsOnFor(&User{ID: id}))
+		}
+	}
+	assert.InDelta(t, 300, on, 60)
+}
+
+func TestRemoveFeatureFlagTarget(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, removeFeatureFlagTarget(x, 2))
+	f := AssertExistsAndLoadBean(t, &FeatureFlag{ID: 1}).(*FeatureFlag)
+	assert.EqualValues(t, []int64{3}, f.TargetIDs)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/base"
+)
+
+// GitCredentialPrefix is the prefix of the passwords of short-lived git credentials
+const GitCredentialPrefix = "gtc_"
+
+// GitCredentialOperation is the git operation a short-lived git credential allows
+type GitCredentialOperation string
+
+// Operations of short-lived git credentials
+const (
+	// GitCredentialOperationRead allows to clone and fetch the repository
+	GitCredentialOperationRead GitCredentialOperation = "read"
+	// GitCredentialOperationWrite allows to push to the repository in addition to reading it
+	GitCredentialOperationWrite GitCredentialOperation = "write"
+)
+
+// IsValid returns whether the operation is known
+func (op GitCredentialOperation) IsValid() bool {
+	return op == GitCredentialOperationRead || op == GitCredentialOperationWrite
+}
+
+// AccessMode returns the access mode to the code of the repository the operation needs
+func (op GitCredentialOperation) AccessMode() AccessMode {
+	if op == GitCredentialOperationWrite {
+		return AccessModeWrite
+	}
+	return AccessModeRead
+}
+
+// gitCredentialData returns the data signed by a git credential. It contains the password hash
+// and salt of the user, so changing the password revokes all the git credentials of the user.
+func gitCredentialData(u *User, repo *Repository, op GitCredentialOperation) string {
+	return fmt.Sprintf("git_credential:%d:%s:%s:%d:%s", u.ID, u.Passwd, u.Rands, repo.ID, op)
+}
+
+// CreateGitCredential creates a password which allows the user to perform the git operation
+// over HTTP on the repository for the given minutes and returns it with the time it expires at
+func CreateGitCredential(u *User, repo *Repository, op GitCredentialOperation, minutes int) (string, time.Time) {
+	code := base.CreateTimeLimitCode(gitCredentialData(u, repo, op), minutes, nil)
+	start, _ := time.ParseInLocation("200601021504", code[:12], time.Local)
+	return GitCredentialPrefix + code, start.Add(time.Duration(minutes) * time.Minute)
+}
+
+// VerifyGitCredential returns whether the password is a git credential of the user which allows
+// the git operation on the repository and has not expired yet. Write credentials also allow to read.
+func VerifyGitCredential(u *User, repo *Repository, op GitCredentialOperation, password string) bool {
+	if !strings.HasPrefix(password, GitCredentialPrefix) {
+		return false
+	}
+	code := strings.TrimPrefix(password, GitCredentialPrefix)
+	if base.VerifyTimeLimitCode(gitCredentialData(u, repo, op), 0, code) {
+		return true
+	}
+	return op == GitCredentialOperationRead &&
+		base.VerifyTimeLimitCode(gitCredentialData(u, repo, GitCredentialOperationWrite), 0, code)
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitCredential(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo1 := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	repo2 := AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+
+	password, expires := CreateGitCredential(user, repo1, GitCredentialOperationRead, 10)
+	assert.True(t, expires.After(time.Now().Add(8*time.Minute)))
+	assert.True(t, VerifyGitCredential(user, repo1, GitCredentialOperationRead, password))
+	assert.False(t, VerifyGitCredential(user, repo1, GitCredentialOperationWrite, password))
+	assert.False(t, VerifyGitCredential(user, repo2, GitCredentialOperationRead, password))
+	assert.False(t, VerifyGitCredential(user, repo1, GitCredentialOperationRead, password[len(GitCredentialPrefix):]))
+
+	other := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	assert.False(t, VerifyGitCredential(other, repo1, GitCredentialOperationRead, password))
+
+	password, _ = CreateGitCredential(user, repo1, GitCredentialOperationWrite, 10)
+	assert.True(t, VerifyGitCredential(user, repo1, GitCredentialOperationWrite, password))
+	assert.True(t, VerifyGitCredential(user, repo1, GitCredentialOperationRead, password))
+
+	// changing the password revokes the credential
+	user.Rands = "new-salt"
+	assert.False(t, VerifyGitCredential(user, repo1, GitCredentialOperationWrite, password))
+}
@@ -68,6 +68,10 @@ type Issue struct {
 	// with write access
 	IsLocked bool `xorm:"NOT NULL DEFAULT false"`
 
+	// IsHidden hides the content of the issue from everyone but the site administrators,
+	// it is set by the site administrators handling an abuse report
+	IsHidden bool `xorm:"NOT NULL DEFAULT false"`
+
 	// For view issue page.
 	ShowTag CommentTag `xorm:"-"`
 }
@@ -2032,6 +2036,16 @@ func deleteIssuesByRepoID(sess Engine, repoID int64) (attachmentPaths []string,
 		return
 	}
 
+	if _, err = sess.In("issue_id", deleteCond).
+		Delete(&ProjectIssueTransition{}); err != nil {
+		return
+	}
+
+	if _, err = sess.In("issue_id", deleteCond).
+		Delete(&IssueContentTranslation{}); err != nil {
+		return
+	}
+
 	if _, err = sess.In("dependent_issue_id", deleteCond).
 		Delete(&Comment{}); err != nil {
 		return
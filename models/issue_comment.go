@@ -154,6 +154,9 @@ type Comment struct {
 	TreePath        string
 	Content         string `xorm:"TEXT"`
 	RenderedContent string `xorm:"-"`
+	// IsHidden hides the content of the comment from everyone but the site administrators,
+	// it is set by the site administrators handling an abuse report
+	IsHidden bool `xorm:"NOT NULL DEFAULT false"`
 
 	// Path represents the 4 lines of code cemented by this comment
 	Patch       string `xorm:"-"`
@@ -1059,6 +1062,12 @@ func DeleteComment(comment *Comment, doer *User) error {
 	if _, err := sess.Where("comment_id = ?", comment.ID).Cols("is_deleted").Update(&Action{IsDeleted: true}); err != nil {
 		return err
 	}
+	if _, err := sess.Delete(&IssueContentTranslation{CommentID: comment.ID}); err != nil {
+		return err
+	}
+	if _, err := sess.Delete(&IssueSyncLink{CommentID: comment.ID}); err != nil {
+		return err
+	}
 
 	if err := comment.neuterCrossReferences(sess); err != nil {
 		return err
@@ -1138,6 +1147,10 @@ func fetchCodeCommentsByReview(e Engine, issue *Issue, currentUser *User, review
 			comment.Review = re
 		}
 
+		if comment.IsHidden && (currentUser == nil || !currentUser.IsAdmin) {
+			// the content hidden by the site administrators is not shown to anyone else
+			comment.Content = ""
+		}
 		comment.RenderedContent = string(markdown.Render([]byte(comment.Content), issue.Repo.Link(),
 			issue.Repo.ComposeMetas()))
 		if pathToLineToComment[comment.TreePath] == nil {
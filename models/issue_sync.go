@@ -0,0 +1,220 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IssueSync represents the two-way mirroring of the issues and comments of a repository
+// migrated from GitHub with the upstream repository it was migrated from
+type IssueSync struct {
+	ID     int64       `xorm:"pk autoincr"`
+	RepoID int64       `xorm:"UNIQUE NOT NULL"`
+	Repo   *Repository `xorm:"-"`
+	// DoerID is the user who set up the sync, upstream issues and comments of users without
+	// a linked account are posted as this user on behalf of their original author
+	DoerID int64 `xorm:"NOT NULL"`
+	// PullEnabled mirrors new upstream issues and comments to the repository
+	PullEnabled bool `xorm:"NOT NULL DEFAULT false"`
+	// PushEnabled mirrors new issues and comments of the repository upstream
+	PushEnabled bool `xorm:"NOT NULL DEFAULT false"`
+	// EncryptedToken is the encrypted access token for the upstream repository
+	EncryptedToken string `xorm:"TEXT"`
+	// PulledUnix is the time of the last pull, the next pull mirrors what was created upstream since then
+	PulledUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	LastError  string             `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ErrIssueSyncNotExist represents a "IssueSyncNotExist" kind of error.
+type ErrIssueSyncNotExist struct {
+	RepoID int64
+}
+
+// IsErrIssueSyncNotExist checks if an error is a ErrIssueSyncNotExist.
+func IsErrIssueSyncNotExist(err error) bool {
+	_, ok := err.(ErrIssueSyncNotExist)
+	return ok
+}
+
+func (err ErrIssueSyncNotExist) Error() string {
+	return fmt.Sprintf("issue sync does not exist [repo_id: %d]", err.RepoID)
+}
+
+// LoadRepo loads the repository of the sync
+func (s *IssueSync) LoadRepo() (err error) {
+	if s.Repo == nil {
+		s.Repo, err = GetRepositoryByID(s.RepoID)
+	}
+	return err
+}
+
+// SetToken encrypts and sets the access token for the upstream repository
+func (s *IssueSync) SetToken(token string) (err error) {
+	if len(token) == 0 {
+		s.EncryptedToken = ""
+		return nil
+	}
+	s.EncryptedToken, err = secret.EncryptSecret(setting.SecretKey, token)
+	return err
+}
+
+// Token returns the decrypted access token for the upstream repository
+func (s *IssueSync) Token() (string, error) {
+	if len(s.EncryptedToken) == 0 {
+		return "", nil
+	}
+	return secret.DecryptSecret(setting.SecretKey, s.EncryptedToken)
+}
+
+// GetIssueSyncByRepoID returns the issue sync of the repository
+func GetIssueSyncByRepoID(repoID int64) (*IssueSync, error) {
+	s := &IssueSync{RepoID: repoID}
+	has, err := x.Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueSyncNotExist{repoID}
+	}
+	return s, nil
+}
+
+// FindPullIssueSyncs returns the issue syncs which mirror upstream issues and comments
+func FindPullIssueSyncs() ([]*IssueSync, error) {
+	syncs := make([]*IssueSync, 0, 10)
+	return syncs, x.Where("pull_enabled = ?", true).Asc("id").Find(&syncs)
+}
+
+// SaveIssueSync creates or updates the issue sync of a repository
+func SaveIssueSync(s *IssueSync) error {
+	if s.ID == 0 {
+		_, err := x.Insert(s)
+		return err
+	}
+	_, err := x.ID(s.ID).AllCols().Update(s)
+	return err
+}
+
+// UpdateIssueSyncCols updates the given columns of an issue sync
+func UpdateIssueSyncCols(s *IssueSync, cols ...string) error {
+	_, err := x.ID(s.ID).Cols(cols...).Update(s)
+	return err
+}
+
+// DeleteIssueSync stops mirroring the issues of a repository. The links of the mirrored issues
+// and comments are kept, so they are not mirrored again if the sync is set up again.
+func DeleteIssueSync(repoID int64) error {
+	_, err := x.Delete(&IssueSync{RepoID: repoID})
+	return err
+}
+
+// IssueSyncLink links an issue or comment of a repository to its counterpart in the upstream repository
+type IssueSyncLink struct {
+	ID      int64 `xorm:"pk autoincr"`
+	RepoID  int64 `xorm:"UNIQUE(upstream) NOT NULL"`
+	IssueID int64 `xorm:"UNIQUE(local) NOT NULL"`
+	// CommentID is 0 for the link of an issue
+	CommentID      int64 `xorm:"UNIQUE(local) NOT NULL DEFAULT 0"`
+	UpstreamNumber int64 `xorm:"UNIQUE(upstream) NOT NULL"`
+	// UpstreamCommentID is 0 for the link of an issue
+	UpstreamCommentID int64 `xorm:"UNIQUE(upstream) NOT NULL DEFAULT 0"`
+	// IsPushed is true for issues and comments created in the repository and mirrored upstream
+	IsPushed    bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// GetIssueSyncLink returns the link of an issue, or of a comment if commentID is not 0, nil if there is none
+func GetIssueSyncLink(issueID, commentID int64) (*IssueSyncLink, error) {
+	link := new(IssueSyncLink)
+	has, err := x.Where("issue_id = ? AND comment_id = ?", issueID, commentID).Get(link)
+	if err != nil || !has {
+		return nil, err
+	}
+	return link, nil
+}
+
+// GetIssueSyncLinkByUpstream returns the link of an upstream issue, or of an upstream comment
+// if upstreamCommentID is not 0, nil if there is none
+func GetIssueSyncLinkByUpstream(repoID, upstreamNumber, upstreamCommentID int64) (*IssueSyncLink, error) {
+	link := new(IssueSyncLink)
+	has, err := x.Where("repo_id = ? AND upstream_number = ? AND upstream_comment_id = ?", repoID, upstreamNumber, upstreamCommentID).Get(link)
+	if err != nil || !has {
+		return nil, err
+	}
+	return link, nil
+}
+
+// InsertIssueSyncLink inserts the link of an issue or comment mirrored upstream
+func InsertIssueSyncLink(link *IssueSyncLink) error {
+	_, err := x.Insert(link)
+	return err
+}
+
+// InsertUpstreamIssue inserts an issue mirrored from the upstream repository together with its link.
+// Unlike NewIssue no notifications are sent, in particular the issue is not mirrored back upstream.
+func InsertUpstreamIssue(repo *Repository, issue *Issue, link *IssueSyncLink) (err error) {
+	for i := 0; i < issueMaxDupIndexAttempts; i++ {
+		if err = insertUpstreamIssueAttempt(repo, issue, link); err == nil || !IsErrNewIssueInsert(err) {
+			return err
+		}
+		log.Error("InsertUpstreamIssue: error attempting to insert the new issue; will retry. Original error: %v", err)
+	}
+	return fmt.Errorf("InsertUpstreamIssue: too many errors attempting to insert the new issue. Last error was: %v", err)
+}
+
+func insertUpstreamIssueAttempt(repo *Repository, issue *Issue, link *IssueSyncLink) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if err := newIssue(sess, issue.Poster, NewIssueOptions{
+		Repo:  repo,
+		Issue: issue,
+	}); err != nil {
+		return err
+	}
+
+	link.RepoID = repo.ID
+	link.IssueID = issue.ID
+	if _, err := sess.Insert(link); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// InsertUpstreamComment inserts a comment mirrored from the upstream repository together with its link.
+// Unlike CreateComment no notifications are sent, in particular the comment is not mirrored back upstream.
+func InsertUpstreamComment(comment *Comment, link *IssueSyncLink) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	comment.Type = CommentTypeComment
+	if _, err := sess.Insert(comment); err != nil {
+		return err
+	}
+	if _, err := sess.Exec("UPDATE `issue` SET num_comments = num_comments + 1 WHERE id = ?", comment.IssueID); err != nil {
+		return err
+	}
+
+	link.IssueID = comment.IssueID
+	link.CommentID = comment.ID
+	if _, err := sess.Insert(link); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
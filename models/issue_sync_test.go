@@ -0,0 +1,67 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueSync_Token(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	s := &IssueSync{RepoID: 1, DoerID: 2, PushEnabled: true}
+	assert.NoError(t, s.SetToken("secret token"))
+	assert.NotContains(t, s.EncryptedToken, "secret token")
+	assert.NoError(t, SaveIssueSync(s))
+
+	s, err := GetIssueSyncByRepoID(1)
+	assert.NoError(t, err)
+	token, err := s.Token()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "secret token", token)
+
+	_, err = GetIssueSyncByRepoID(2)
+	assert.True(t, IsErrIssueSyncNotExist(err))
+}
+
+func TestInsertUpstreamIssue(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue := &Issue{
+		RepoID:           repo.ID,
+		Title:            "upstream issue",
+		Poster:           doer,
+		PosterID:         doer.ID,
+		OriginalAuthor:   "octocat",
+		OriginalAuthorID: 42,
+	}
+	assert.NoError(t, InsertUpstreamIssue(repo, issue, &IssueSyncLink{UpstreamNumber: 30}))
+
+	link, err := GetIssueSyncLinkByUpstream(repo.ID, 30, 0)
+	assert.NoError(t, err)
+	if assert.NotNil(t, link) {
+		assert.EqualValues(t, issue.ID, link.IssueID)
+	}
+
+	comment := &Comment{IssueID: issue.ID, PosterID: doer.ID, Content: "upstream comment"}
+	assert.NoError(t, InsertUpstreamComment(comment, &IssueSyncLink{RepoID: repo.ID, UpstreamNumber: 30, UpstreamCommentID: 7}))
+	link, err = GetIssueSyncLink(issue.ID, comment.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, link) {
+		assert.EqualValues(t, 7, link.UpstreamCommentID)
+	}
+	issue = AssertExistsAndLoadBean(t, &Issue{ID: issue.ID}).(*Issue)
+	assert.EqualValues(t, 1, issue.NumComments)
+
+	// deleting the comment removes its link
+	assert.NoError(t, DeleteComment(comment, doer))
+	link, err = GetIssueSyncLink(issue.ID, comment.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, link)
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IssueContentTranslation is the cached machine translation of the content of an issue or a comment.
+// Only the translation of the latest revision of the content is kept.
+type IssueContentTranslation struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"INDEX NOT NULL"`
+	// IssueID is the issue the content belongs to
+	IssueID int64 `xorm:"UNIQUE(s) NOT NULL"`
+	// CommentID is the comment the content belongs to, zero for the content of the issue itself
+	CommentID int64  `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	Language  string `xorm:"UNIQUE(s) VARCHAR(20) NOT NULL"`
+	// ContentHash is the SHA256 hash of the revision of the content which was translated
+	ContentHash    string `xorm:"VARCHAR(64) NOT NULL"`
+	SourceLanguage string `xorm:"VARCHAR(20)"`
+	Content        string `xorm:"LONGTEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// ErrIssueContentTranslationNotExist represents a "IssueContentTranslationNotExist" kind of error.
+type ErrIssueContentTranslationNotExist struct {
+	IssueID   int64
+	CommentID int64
+	Language  string
+}
+
+// IsErrIssueContentTranslationNotExist checks if an error is a ErrIssueContentTranslationNotExist.
+func IsErrIssueContentTranslationNotExist(err error) bool {
+	_, ok := err.(ErrIssueContentTranslationNotExist)
+	return ok
+}
+
+func (err ErrIssueContentTranslationNotExist) Error() string {
+	return fmt.Sprintf("issue content translation does not exist [issue_id: %d, comment_id: %d, language: %s]", err.IssueID, err.CommentID, err.Language)
+}
+
+// GetIssueContentTranslation returns the cached translation of the content of an issue or,
+// if commentID is not zero, of a comment of the issue
+func GetIssueContentTranslation(issueID, commentID int64, language string) (*IssueContentTranslation, error) {
+	t := new(IssueContentTranslation)
+	has, err := x.
+		Where("issue_id = ? AND comment_id = ? AND language = ?", issueID, commentID, language).
+		Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrIssueContentTranslationNotExist{issueID, commentID, language}
+	}
+	return t, nil
+}
+
+// SaveIssueContentTranslation stores a translation, replacing the translation of a former revision of the content
+func SaveIssueContentTranslation(t *IssueContentTranslation) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.
+		Where("issue_id = ? AND comment_id = ? AND language = ?", t.IssueID, t.CommentID, t.Language).
+		Delete(new(IssueContentTranslation)); err != nil {
+		return err
+	}
+	if _, err := sess.Insert(t); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
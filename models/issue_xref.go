@@ -144,7 +144,11 @@ func (issue *Issue) getCrossReferences(e *xorm.Session, ctx *crossReferencesCont
 		err       error
 	)
 
-	allrefs := append(references.FindAllIssueReferences(plaincontent), references.FindAllIssueReferencesMarkdown(mdcontent)...)
+	if err := ctx.OrigIssue.loadRepo(e); err != nil {
+		return nil, err
+	}
+	keywords := ctx.OrigIssue.Repo.issueKeywords(e)
+	allrefs := append(keywords.FindAllIssueReferences(plaincontent), keywords.FindAllIssueReferencesMarkdown(mdcontent)...)
 
 	for _, ref := range allrefs {
 		if ref.Owner == "" && ref.Name == "" {
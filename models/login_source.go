@@ -724,8 +724,8 @@ func ExternalUserLogin(user *User, login, password string, source *LoginSource)
 
 	// WARN: DON'T check user.IsActive, that will be checked on reqSign so that
 	// user could be hint to resend confirm email.
-	if user.ProhibitLogin {
-		return nil, ErrUserProhibitLogin{user.ID, user.Name}
+	if err := checkUserProhibitLogin(user); err != nil {
+		return nil, err
 	}
 
 	return user, nil
@@ -775,8 +775,8 @@ func UserSignIn(username, password string) (*User, error) {
 
 				// WARN: DON'T check user.IsActive, that will be checked on reqSign so that
 				// user could be hint to resend confirm email.
-				if user.ProhibitLogin {
-					return nil, ErrUserProhibitLogin{user.ID, user.Name}
+				if err := checkUserProhibitLogin(user); err != nil {
+					return nil, err
 				}
 
 				return user, nil
@@ -256,6 +256,46 @@ var migrations = []Migration{
 	NewMigration("update reactions constraint", updateReactionConstraint),
 	// v160 -> v161
 	NewMigration("Add block on official review requests branch protection", addBlockOnOfficialReviewRequests),
+	// v161 -> v162
+	NewMigration("Add push to pull request mode to protected branch", addPushToPullRequestToProtectedBranch),
+	// v162 -> v163
+	NewMigration("Add license to repository", addLicenseToRepository),
+	// v163 -> v164
+	NewMigration("Add repository to attachments and attachment retention to repository", addRepoIDToAttachment),
+	// v164 -> v165
+	NewMigration("Add two-factor authentication requirement to organizations", addRequireTwoFactorToOrganization),
+	// v165 -> v166
+	NewMigration("Add expiry to collaborations", addExpiresUnixToCollaboration),
+	// v166 -> v167
+	NewMigration("Add table to record changes of required status check contexts", addStatusCheckContextChangeTable),
+	// v167 -> v168
+	NewMigration("Add release assets mirroring to mirrors", addEnableReleaseAssetsToMirror),
+	// v168 -> v169
+	NewMigration("Add repo_preset table", addRepoPresetTable),
+	// v169 -> v170
+	NewMigration("Add dormant user columns to user", addDormantUserColumns),
+	// v170 -> v171
+	NewMigration("Add notification_rule table", addNotificationRuleTable),
+	// v171 -> v172
+	NewMigration("Add abuse report tables and hidden issues and comments", addAbuseReportTables),
+	// v172 -> v173
+	NewMigration("Add scheduled post table", addScheduledPostTable),
+	// v173 -> v174
+	NewMigration("Add code frequency table", addCodeFrequencyTable),
+	// v174 -> v175
+	NewMigration("Add token usage table", addTokenUsageTable),
+	// v175 -> v176
+	NewMigration("Add project issue transition table", addProjectIssueTransitionTable),
+	// v176 -> v177
+	NewMigration("Add release channel table", addReleaseChannelTable),
+	// v177 -> v178
+	NewMigration("Add issue content translation table", addIssueContentTranslationTable),
+	// v178 -> v179
+	NewMigration("Add feature flag table", addFeatureFlagTable),
+	// v179 -> v180
+	NewMigration("Add issue sync tables", addIssueSyncTables),
+	// v180 -> v181
+	NewMigration("Add publish state to scheduled posts", addScheduledPostPublishState),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,24 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"xorm.io/xorm"
+)
+
+func addLicenseToRepository(x *xorm.Engine) error {
+	type Repository struct {
+		License      string   `xorm:"VARCHAR(255) INDEX"`
+		FileLicenses []string `xorm:"TEXT JSON"`
+	}
+
+	if err := x.Sync2(new(Repository)); err != nil {
+		return err
+	}
+
+	// Licenses are detected by the stats indexer, so make it index all repositories again
+	_, err := x.Exec("DELETE FROM repo_indexer_status WHERE indexer_type = ?", 1)
+	return err
+}
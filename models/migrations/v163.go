@@ -0,0 +1,52 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/log"
+
+	"xorm.io/xorm"
+)
+
+func addRepoIDToAttachment(x *xorm.Engine) error {
+	type Attachment struct {
+		RepoID int64 `xorm:"INDEX"`
+	}
+	if err := x.Sync2(new(Attachment)); err != nil {
+		return err
+	}
+
+	type Repository struct {
+		AttachmentRetentionDays int `xorm:"NOT NULL DEFAULT 0"`
+	}
+	if err := x.Sync2(new(Repository)); err != nil {
+		return err
+	}
+
+	// attachments of comments are linked to the issue of their comment, otherwise they would count as orphaned
+	if _, err := x.Exec("UPDATE `attachment` SET issue_id = (SELECT issue_id FROM `comment` WHERE `comment`.id = `attachment`.comment_id) " +
+		"WHERE `attachment`.comment_id > 0 AND `attachment`.issue_id = 0 AND EXISTS (SELECT 1 FROM `comment` WHERE `comment`.id = `attachment`.comment_id)"); err != nil {
+		return err
+	}
+	if _, err := x.Exec("UPDATE `attachment` SET repo_id = (SELECT repo_id FROM `issue` WHERE `issue`.id = `attachment`.issue_id) " +
+		"WHERE `attachment`.issue_id > 0 AND EXISTS (SELECT 1 FROM `issue` WHERE `issue`.id = `attachment`.issue_id)"); err != nil {
+		return err
+	}
+	if _, err := x.Exec("UPDATE `attachment` SET repo_id = (SELECT repo_id FROM `release` WHERE `release`.id = `attachment`.release_id) " +
+		"WHERE `attachment`.release_id > 0 AND EXISTS (SELECT 1 FROM `release` WHERE `release`.id = `attachment`.release_id)"); err != nil {
+		return err
+	}
+
+	// the rest was never posted or belongs to deleted issues, comments or releases and cannot be
+	// attributed to a repository, it is deleted by the orphaned attachments cleanup
+	res, err := x.Exec("UPDATE `attachment` SET repo_id = 0 WHERE repo_id IS NULL")
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		log.Info("%d attachments are not linked to any repository and are left to the orphaned attachments cleanup", n)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRequireTwoFactorToOrganization(x *xorm.Engine) error {
+	type User struct {
+		RequireTwoFactor      bool               `xorm:"NOT NULL DEFAULT false"`
+		TwoFactorGraceDays    int                `xorm:"NOT NULL DEFAULT 0"`
+		TwoFactorRequiredUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync2(new(User))
+}
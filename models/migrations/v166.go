@@ -0,0 +1,25 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addStatusCheckContextChangeTable(x *xorm.Engine) error {
+	type StatusCheckContextChange struct {
+		ID                int64              `xorm:"pk autoincr"`
+		RepoID            int64              `xorm:"INDEX NOT NULL"`
+		ProtectedBranchID int64              `xorm:"INDEX NOT NULL"`
+		DoerID            int64              `xorm:"NOT NULL"`
+		Context           string             `xorm:"NOT NULL"`
+		IsRegistered      bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix       timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync2(new(StatusCheckContextChange))
+}
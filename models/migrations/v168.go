@@ -0,0 +1,40 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoPresetTable(x *xorm.Engine) error {
+	type RepoPresetWebhook struct {
+		URL            string
+		ContentType    int
+		Secret         string
+		SendEverything bool
+	}
+
+	type RepoPreset struct {
+		ID                   int64                `xorm:"pk autoincr"`
+		OrgID                int64                `xorm:"UNIQUE(s) NOT NULL"`
+		TeamID               int64                `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Name                 string               `xorm:"UNIQUE(s) NOT NULL"`
+		Description          string               `xorm:"TEXT"`
+		Units                []int                `xorm:"JSON TEXT"`
+		IssueLabels          string               `xorm:"NOT NULL DEFAULT ''"`
+		Topics               []string             `xorm:"JSON TEXT"`
+		ProtectDefaultBranch bool                 `xorm:"NOT NULL DEFAULT false"`
+		RequiredApprovals    int64                `xorm:"NOT NULL DEFAULT 0"`
+		StatusCheckContexts  []string             `xorm:"JSON TEXT"`
+		Webhooks             []*RepoPresetWebhook `xorm:"JSON TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync2(new(RepoPreset))
+}
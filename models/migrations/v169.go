@@ -0,0 +1,20 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addDormantUserColumns(x *xorm.Engine) error {
+	type User struct {
+		DormantFlaggedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		IsDormant          bool               `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	return x.Sync2(new(User))
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addNotificationRuleTable(x *xorm.Engine) error {
+	type NotificationRule struct {
+		ID          int64  `xorm:"pk autoincr"`
+		OwnerID     int64  `xorm:"INDEX NOT NULL"`
+		RepoPattern string `xorm:"NOT NULL DEFAULT ''"`
+		Event       string `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+		Action      string `xorm:"VARCHAR(20) NOT NULL"`
+		Priority    int    `xorm:"NOT NULL DEFAULT 0"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync2(new(NotificationRule))
+}
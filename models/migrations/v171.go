@@ -0,0 +1,48 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addAbuseReportTables(x *xorm.Engine) error {
+	type Issue struct {
+		IsHidden bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type Comment struct {
+		IsHidden bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type AbuseReport struct {
+		ID             int64  `xorm:"pk autoincr"`
+		ReporterID     int64  `xorm:"INDEX NOT NULL"`
+		ContentType    int    `xorm:"INDEX(s) NOT NULL"`
+		ContentID      int64  `xorm:"INDEX(s) NOT NULL"`
+		ContentOwnerID int64  `xorm:"INDEX"`
+		RepoID         int64  `xorm:"INDEX"`
+		Reason         string `xorm:"VARCHAR(20) NOT NULL"`
+		Remark         string `xorm:"TEXT"`
+		Status         int    `xorm:"INDEX NOT NULL DEFAULT 0"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type AbuseReportLog struct {
+		ID       int64  `xorm:"pk autoincr"`
+		ReportID int64  `xorm:"INDEX NOT NULL"`
+		DoerID   int64  `xorm:"NOT NULL"`
+		Action   string `xorm:"VARCHAR(20) NOT NULL"`
+		Comment  string `xorm:"TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync2(new(Issue), new(Comment), new(AbuseReport), new(AbuseReportLog))
+}
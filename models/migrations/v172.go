@@ -0,0 +1,32 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addScheduledPostTable(x *xorm.Engine) error {
+	type ScheduledPost struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"INDEX NOT NULL"`
+		PosterID    int64  `xorm:"INDEX NOT NULL"`
+		IssueID     int64  `xorm:"INDEX"`
+		Title       string `xorm:"name"`
+		Content     string `xorm:"LONGTEXT"`
+		Ref         string
+		MilestoneID int64
+		ProjectID   int64
+		LabelIDs    []int64 `xorm:"TEXT JSON"`
+		AssigneeIDs []int64 `xorm:"TEXT JSON"`
+
+		PostUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync2(new(ScheduledPost))
+}
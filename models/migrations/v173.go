@@ -0,0 +1,25 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addCodeFrequencyTable(x *xorm.Engine) error {
+	type CodeFrequency struct {
+		ID        int64              `xorm:"pk autoincr"`
+		RepoID    int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Directory string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+		WeekUnix  timeutil.TimeStamp `xorm:"UNIQUE(s) NOT NULL"`
+		Additions int64
+		Deletions int64
+		Commits   int64
+	}
+
+	return x.Sync2(new(CodeFrequency))
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addTokenUsageTable(x *xorm.Engine) error {
+	type TokenUsage struct {
+		ID            int64              `xorm:"pk autoincr"`
+		TokenID       int64              `xorm:"UNIQUE(s) NOT NULL"`
+		UID           int64              `xorm:"INDEX NOT NULL"`
+		OrgID         int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Endpoint      string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+		IsWrite       bool               `xorm:"UNIQUE(s) NOT NULL DEFAULT false"`
+		IP            string             `xorm:"UNIQUE(s) VARCHAR(64) NOT NULL"`
+		Count         int64              `xorm:"NOT NULL DEFAULT 0"`
+		FirstUsedUnix timeutil.TimeStamp `xorm:"created"`
+		LastUsedUnix  timeutil.TimeStamp `xorm:"INDEX"`
+	}
+
+	return x.Sync2(new(TokenUsage))
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addProjectIssueTransitionTable(x *xorm.Engine) error {
+	type ProjectIssueTransition struct {
+		ID          int64              `xorm:"pk autoincr"`
+		ProjectID   int64              `xorm:"INDEX NOT NULL"`
+		IssueID     int64              `xorm:"INDEX NOT NULL"`
+		FromBoardID int64              `xorm:"NOT NULL"`
+		ToBoardID   int64              `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	type ProjectIssue struct {
+		ID             int64 `xorm:"pk autoincr"`
+		IssueID        int64 `xorm:"INDEX"`
+		ProjectID      int64 `xorm:"INDEX"`
+		ProjectBoardID int64 `xorm:"INDEX"`
+	}
+
+	if err := x.Sync2(new(ProjectIssueTransition)); err != nil {
+		return err
+	}
+
+	// The history of issues already on a project is unknown, so they start on their current board now.
+	const batchSize = 100
+	for start := 0; ; start += batchSize {
+		projectIssues := make([]*ProjectIssue, 0, batchSize)
+		if err := x.Where("project_id > 0").Asc("id").Limit(batchSize, start).Find(&projectIssues); err != nil {
+			return err
+		}
+		if len(projectIssues) == 0 {
+			return nil
+		}
+
+		transitions := make([]*ProjectIssueTransition, 0, len(projectIssues))
+		for _, pi := range projectIssues {
+			transitions = append(transitions, &ProjectIssueTransition{
+				ProjectID:   pi.ProjectID,
+				IssueID:     pi.IssueID,
+				FromBoardID: -1,
+				ToBoardID:   pi.ProjectBoardID,
+			})
+		}
+		if _, err := x.Insert(&transitions); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueContentTranslationTable(x *xorm.Engine) error {
+	type IssueContentTranslation struct {
+		ID             int64              `xorm:"pk autoincr"`
+		RepoID         int64              `xorm:"INDEX NOT NULL"`
+		IssueID        int64              `xorm:"UNIQUE(s) NOT NULL"`
+		CommentID      int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		Language       string             `xorm:"UNIQUE(s) VARCHAR(20) NOT NULL"`
+		ContentHash    string             `xorm:"VARCHAR(64) NOT NULL"`
+		SourceLanguage string             `xorm:"VARCHAR(20)"`
+		Content        string             `xorm:"LONGTEXT"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync2(new(IssueContentTranslation))
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addFeatureFlagTable(x *xorm.Engine) error {
+	type FeatureFlag struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Name        string             `xorm:"UNIQUE NOT NULL"`
+		Description string             `xorm:"TEXT"`
+		Enabled     bool               `xorm:"NOT NULL DEFAULT false"`
+		Percentage  int                `xorm:"NOT NULL DEFAULT 0"`
+		TargetIDs   []int64            `xorm:"JSON TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	return x.Sync2(new(FeatureFlag))
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueSyncTables(x *xorm.Engine) error {
+	type IssueSync struct {
+		ID             int64              `xorm:"pk autoincr"`
+		RepoID         int64              `xorm:"UNIQUE NOT NULL"`
+		DoerID         int64              `xorm:"NOT NULL"`
+		PullEnabled    bool               `xorm:"NOT NULL DEFAULT false"`
+		PushEnabled    bool               `xorm:"NOT NULL DEFAULT false"`
+		EncryptedToken string             `xorm:"TEXT"`
+		PulledUnix     timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		LastError      string             `xorm:"TEXT"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type IssueSyncLink struct {
+		ID                int64              `xorm:"pk autoincr"`
+		RepoID            int64              `xorm:"UNIQUE(upstream) NOT NULL"`
+		IssueID           int64              `xorm:"UNIQUE(local) NOT NULL"`
+		CommentID         int64              `xorm:"UNIQUE(local) NOT NULL DEFAULT 0"`
+		UpstreamNumber    int64              `xorm:"UNIQUE(upstream) NOT NULL"`
+		UpstreamCommentID int64              `xorm:"UNIQUE(upstream) NOT NULL DEFAULT 0"`
+		IsPushed          bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix       timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync2(new(IssueSync), new(IssueSyncLink))
+}
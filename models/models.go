@@ -131,6 +131,20 @@ func init() {
 		new(Project),
 		new(ProjectBoard),
 		new(ProjectIssue),
+		new(StatusCheckContextChange),
+		new(RepoPreset),
+		new(NotificationRule),
+		new(AbuseReport),
+		new(AbuseReportLog),
+		new(ScheduledPost),
+		new(CodeFrequency),
+		new(TokenUsage),
+		new(ProjectIssueTransition),
+		new(ReleaseChannel),
+		new(IssueContentTranslation),
+		new(FeatureFlag),
+		new(IssueSync),
+		new(IssueSyncLink),
 	)
 
 	gonicNames := []string{"SSL", "UID"}
@@ -7,8 +7,10 @@ package models
 import (
 	"fmt"
 	"path"
+	"strings"
 
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/references"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -196,7 +198,8 @@ func createOrUpdateIssueNotifications(e Engine, issueID, commentID, notification
 		return err
 	}
 
-	// notify
+	users := make([]*User, 0, len(toNotify))
+	lowerNames := make(map[int64]string, len(toNotify))
 	for userID := range toNotify {
 		issue.Repo.Units = nil
 		user, err := getUserByID(e, userID)
@@ -213,20 +216,64 @@ func createOrUpdateIssueNotifications(e Engine, issueID, commentID, notification
 		if !issue.IsPull && !issue.Repo.checkUnitUser(e, user, UnitTypeIssues) {
 			continue
 		}
+		users = append(users, user)
+		lowerNames[user.ID] = user.LowerName
+	}
+
+	mentioned, err := getNotificationMentions(e, issue, commentID)
+	if err != nil {
+		return err
+	}
+	actions, err := getNotificationActions(e, users, issue.Repo, NotificationRuleEventOfIssue(issue), func(userID int64) bool {
+		return mentioned[lowerNames[userID]]
+	})
+	if err != nil {
+		return err
+	}
+
+	// notify
+	for _, user := range users {
+		// the notification rules of the user may mute notifications in the web interface
+		if actions[user.ID] == NotificationRuleActionNone {
+			continue
+		}
 
-		if notificationExists(notifications, issue.ID, userID) {
-			if err = updateIssueNotification(e, userID, issue.ID, commentID, notificationAuthorID); err != nil {
+		if notificationExists(notifications, issue.ID, user.ID) {
+			if err = updateIssueNotification(e, user.ID, issue.ID, commentID, notificationAuthorID); err != nil {
 				return err
 			}
 			continue
 		}
-		if err = createIssueNotification(e, userID, issue, commentID, notificationAuthorID); err != nil {
+		if err = createIssueNotification(e, user.ID, issue, commentID, notificationAuthorID); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// getNotificationMentions returns the lower names of the users mentioned by the comment,
+// or by the issue itself if the notification is not about a comment
+func getNotificationMentions(e Engine, issue *Issue, commentID int64) (map[string]bool, error) {
+	content := issue.Content
+	if commentID > 0 {
+		comment, err := getCommentByID(e, commentID)
+		if err != nil {
+			if IsErrCommentNotExist(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		content = comment.Content
+	}
+
+	mentions := references.FindAllMentionsMarkdown(content)
+	mentioned := make(map[string]bool, len(mentions))
+	for _, name := range mentions {
+		mentioned[strings.ToLower(name)] = true
+	}
+	return mentioned, nil
+}
+
 func getNotificationsByIssueID(e Engine, issueID int64) (notifications []*Notification, err error) {
 	err = e.
 		Where("issue_id = ?", issueID).
@@ -0,0 +1,349 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
+)
+
+// NotificationRuleEvent is the kind of notification a notification rule applies to
+type NotificationRuleEvent string
+
+// Events of notification rules
+const (
+	NotificationRuleEventAny         NotificationRuleEvent = ""
+	NotificationRuleEventIssue       NotificationRuleEvent = "issue"
+	NotificationRuleEventPullRequest NotificationRuleEvent = "pull_request"
+	NotificationRuleEventRelease     NotificationRuleEvent = "release"
+	// NotificationRuleEventMention applies to notifications of issues and pull requests mentioning the user
+	NotificationRuleEventMention NotificationRuleEvent = "mention"
+)
+
+// NotificationRuleEvents are the valid events of notification rules
+var NotificationRuleEvents = []NotificationRuleEvent{
+	NotificationRuleEventAny,
+	NotificationRuleEventIssue,
+	NotificationRuleEventPullRequest,
+	NotificationRuleEventRelease,
+	NotificationRuleEventMention,
+}
+
+// NotificationRuleAction is how a notification matched by a notification rule is delivered
+type NotificationRuleAction string
+
+// Actions of notification rules
+const (
+	// NotificationRuleActionEmail delivers the notification by email and in the web interface
+	NotificationRuleActionEmail NotificationRuleAction = "email"
+	// NotificationRuleActionWeb delivers the notification only in the web interface
+	NotificationRuleActionWeb NotificationRuleAction = "web"
+	// NotificationRuleActionNone does not deliver the notification at all
+	NotificationRuleActionNone NotificationRuleAction = "none"
+)
+
+// NotificationRuleActions are the valid actions of notification rules
+var NotificationRuleActions = []NotificationRuleAction{
+	NotificationRuleActionEmail,
+	NotificationRuleActionWeb,
+	NotificationRuleActionNone,
+}
+
+// NotificationRule decides how notifications about the repositories matching its pattern are delivered.
+// Rules of a user are evaluated before the rules of the organization owning the repository,
+// the rules of an organization only apply to its members. If no rule matches, the email
+// notifications preference of the user applies.
+type NotificationRule struct {
+	ID      int64 `xorm:"pk autoincr"`
+	OwnerID int64 `xorm:"INDEX NOT NULL"`
+	// RepoPattern is a glob matched against the full name of the repository, empty matches all repositories
+	RepoPattern string                 `xorm:"NOT NULL DEFAULT ''"`
+	Event       NotificationRuleEvent  `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+	Action      NotificationRuleAction `xorm:"VARCHAR(20) NOT NULL"`
+	// Priority orders the rules of an owner, rules with a higher priority are evaluated first
+	Priority int `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+
+	repoGlob glob.Glob `xorm:"-"`
+}
+
+// ErrNotificationRuleNotExist represents a "NotificationRuleNotExist" kind of error.
+type ErrNotificationRuleNotExist struct {
+	ID      int64
+	OwnerID int64
+}
+
+// IsErrNotificationRuleNotExist checks if an error is a ErrNotificationRuleNotExist.
+func IsErrNotificationRuleNotExist(err error) bool {
+	_, ok := err.(ErrNotificationRuleNotExist)
+	return ok
+}
+
+func (err ErrNotificationRuleNotExist) Error() string {
+	return fmt.Sprintf("notification rule does not exist [id: %d, owner_id: %d]", err.ID, err.OwnerID)
+}
+
+// ErrInvalidNotificationRule represents an error for a notification rule with invalid settings
+type ErrInvalidNotificationRule struct {
+	Reason string
+}
+
+// IsErrInvalidNotificationRule checks if an error is a ErrInvalidNotificationRule.
+func IsErrInvalidNotificationRule(err error) bool {
+	_, ok := err.(ErrInvalidNotificationRule)
+	return ok
+}
+
+func (err ErrInvalidNotificationRule) Error() string {
+	return fmt.Sprintf("invalid notification rule: %s", err.Reason)
+}
+
+// Validate checks the event, the action and the repository pattern of the rule
+func (r *NotificationRule) Validate() error {
+	validEvent := false
+	for _, event := range NotificationRuleEvents {
+		validEvent = validEvent || r.Event == event
+	}
+	if !validEvent {
+		return ErrInvalidNotificationRule{fmt.Sprintf("unknown event %q", r.Event)}
+	}
+
+	validAction := false
+	for _, action := range NotificationRuleActions {
+		validAction = validAction || r.Action == action
+	}
+	if !validAction {
+		return ErrInvalidNotificationRule{fmt.Sprintf("unknown action %q", r.Action)}
+	}
+
+	r.RepoPattern = strings.TrimSpace(r.RepoPattern)
+	r.repoGlob = nil
+	if _, err := r.getRepoGlob(); err != nil {
+		return ErrInvalidNotificationRule{fmt.Sprintf("invalid repository pattern %q: %v", r.RepoPattern, err)}
+	}
+	return nil
+}
+
+func (r *NotificationRule) getRepoGlob() (glob.Glob, error) {
+	if r.repoGlob == nil && len(r.RepoPattern) > 0 {
+		g, err := glob.Compile(strings.ToLower(r.RepoPattern), '/')
+		if err != nil {
+			return nil, err
+		}
+		r.repoGlob = g
+	}
+	return r.repoGlob, nil
+}
+
+// Match returns whether the rule applies to a notification of the event about the repository
+func (r *NotificationRule) Match(repo *Repository, event NotificationRuleEvent, isMention bool) bool {
+	switch r.Event {
+	case NotificationRuleEventAny, event:
+	case NotificationRuleEventMention:
+		if !isMention {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if len(r.RepoPattern) == 0 {
+		return true
+	}
+	g, err := r.getRepoGlob()
+	if err != nil {
+		return false
+	}
+	return g.Match(strings.ToLower(repo.FullName()))
+}
+
+// CreateNotificationRule creates a notification rule
+func CreateNotificationRule(r *NotificationRule) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	_, err := x.Insert(r)
+	return err
+}
+
+// UpdateNotificationRule updates a notification rule
+func UpdateNotificationRule(r *NotificationRule) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	_, err := x.ID(r.ID).Cols("repo_pattern", "event", "action", "priority").Update(r)
+	return err
+}
+
+// DeleteNotificationRule deletes a notification rule of the owner
+func DeleteNotificationRule(ownerID, id int64) error {
+	deleted, err := x.Delete(&NotificationRule{ID: id, OwnerID: ownerID})
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrNotificationRuleNotExist{ID: id, OwnerID: ownerID}
+	}
+	return nil
+}
+
+// GetNotificationRuleByID returns the notification rule of the owner by its id
+func GetNotificationRuleByID(ownerID, id int64) (*NotificationRule, error) {
+	r := new(NotificationRule)
+	has, err := x.ID(id).Where("owner_id = ?", ownerID).Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrNotificationRuleNotExist{ID: id, OwnerID: ownerID}
+	}
+	return r, nil
+}
+
+func getNotificationRules(e Engine, ownerIDs ...int64) ([]*NotificationRule, error) {
+	rules := make([]*NotificationRule, 0, 10)
+	return rules, e.In("owner_id", ownerIDs).Desc("priority").Asc("id").Find(&rules)
+}
+
+// GetNotificationRules returns the notification rules of the user or organization in the order they are evaluated
+func (u *User) GetNotificationRules() ([]*NotificationRule, error) {
+	return getNotificationRules(x, u.ID)
+}
+
+// defaultNotificationAction returns how a notification is delivered to the user if no rule matches.
+// The email notification preference of the user acts as the last rule, it never mutes web notifications.
+func (u *User) defaultNotificationAction(isMention bool) NotificationRuleAction {
+	switch u.EmailNotifications() {
+	case EmailNotificationsEnabled:
+		return NotificationRuleActionEmail
+	case EmailNotificationsOnMention:
+		if isMention {
+			return NotificationRuleActionEmail
+		}
+	}
+	return NotificationRuleActionWeb
+}
+
+// allMentioned returns a mention check which treats all or none of the users as mentioned
+func allMentioned(isMention bool) func(int64) bool {
+	return func(int64) bool {
+		return isMention
+	}
+}
+
+func getNotificationActions(e Engine, users []*User, repo *Repository, event NotificationRuleEvent, isMentioned func(userID int64) bool) (map[int64]NotificationRuleAction, error) {
+	actions := make(map[int64]NotificationRuleAction, len(users))
+	if len(users) == 0 {
+		return actions, nil
+	}
+
+	if err := repo.getOwner(e); err != nil {
+		return nil, err
+	}
+	ownerIDs := make([]int64, 0, len(users)+1)
+	for _, u := range users {
+		ownerIDs = append(ownerIDs, u.ID)
+	}
+	if repo.Owner.IsOrganization() {
+		ownerIDs = append(ownerIDs, repo.OwnerID)
+	}
+	rules, err := getNotificationRules(e, ownerIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	rulesByOwner := make(map[int64][]*NotificationRule, len(ownerIDs))
+	for _, r := range rules {
+		rulesByOwner[r.OwnerID] = append(rulesByOwner[r.OwnerID], r)
+	}
+
+	// the rules of the organization only apply to its members
+	orgRules := rulesByOwner[repo.OwnerID]
+	members := make(map[int64]bool)
+	if repo.Owner.IsOrganization() && len(orgRules) > 0 {
+		memberIDs := make([]int64, 0, len(users))
+		if err := e.Table("org_user").
+			Where(builder.Eq{"org_id": repo.OwnerID}.And(builder.In("uid", ownerIDs[:len(users)]))).
+			Cols("uid").
+			Find(&memberIDs); err != nil {
+			return nil, err
+		}
+		for _, id := range memberIDs {
+			members[id] = true
+		}
+	}
+
+	for _, u := range users {
+		isMention := isMentioned(u.ID)
+		actions[u.ID] = u.defaultNotificationAction(isMention)
+
+		candidates := rulesByOwner[u.ID]
+		if members[u.ID] {
+			candidates = append(candidates[:len(candidates):len(candidates)], orgRules...)
+		}
+		for _, r := range candidates {
+			if r.Match(repo, event, isMention) {
+				actions[u.ID] = r.Action
+				break
+			}
+		}
+	}
+	return actions, nil
+}
+
+// GetNotificationActions returns how a notification of the event about the repository is delivered to each of the users
+func GetNotificationActions(users []*User, repo *Repository, event NotificationRuleEvent, isMention bool) (map[int64]NotificationRuleAction, error) {
+	return getNotificationActions(x, users, repo, event, allMentioned(isMention))
+}
+
+// GetNotificationAction returns how a notification of the event about the repository is delivered to the user
+func (u *User) GetNotificationAction(repo *Repository, event NotificationRuleEvent, isMention bool) (NotificationRuleAction, error) {
+	actions, err := getNotificationActions(x, []*User{u}, repo, event, allMentioned(isMention))
+	if err != nil {
+		return "", err
+	}
+	return actions[u.ID], nil
+}
+
+// GetMailableNotificationRecipients gets the users from ids who can receive mails and whose
+// notification rules deliver a notification of the event about the repository by email
+func GetMailableNotificationRecipients(ids []int64, repo *Repository, event NotificationRuleEvent, isMention bool) ([]*User, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	users := make([]*User, 0, len(ids))
+	if err := x.In("id", ids).
+		Where("`type` = ?", UserTypeIndividual).
+		And("`prohibit_login` = ?", false).
+		And("`is_active` = ?", true).
+		Find(&users); err != nil {
+		return nil, err
+	}
+
+	actions, err := getNotificationActions(x, users, repo, event, allMentioned(isMention))
+	if err != nil {
+		return nil, err
+	}
+	recipients := make([]*User, 0, len(users))
+	for _, u := range users {
+		if actions[u.ID] == NotificationRuleActionEmail {
+			recipients = append(recipients, u)
+		}
+	}
+	return recipients, nil
+}
+
+// NotificationRuleEventOfIssue returns the event of notifications about the issue or pull request
+func NotificationRuleEventOfIssue(issue *Issue) NotificationRuleEvent {
+	if issue.IsPull {
+		return NotificationRuleEventPullRequest
+	}
+	return NotificationRuleEventIssue
+}
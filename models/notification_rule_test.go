@@ -0,0 +1,90 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationRule_Validate(t *testing.T) {
+	assert.NoError(t, (&NotificationRule{Action: NotificationRuleActionWeb}).Validate())
+	assert.NoError(t, (&NotificationRule{RepoPattern: " user3/* ", Event: NotificationRuleEventMention, Action: NotificationRuleActionNone}).Validate())
+	assert.True(t, IsErrInvalidNotificationRule((&NotificationRule{Action: "sms"}).Validate()))
+	assert.True(t, IsErrInvalidNotificationRule((&NotificationRule{Event: "push", Action: NotificationRuleActionWeb}).Validate()))
+	assert.True(t, IsErrInvalidNotificationRule((&NotificationRule{RepoPattern: "user3/[", Action: NotificationRuleActionWeb}).Validate()))
+}
+
+func TestNotificationRule_Match(t *testing.T) {
+	repo := &Repository{OwnerName: "user3", Name: "Repo3"}
+
+	assert.True(t, (&NotificationRule{}).Match(repo, NotificationRuleEventIssue, false))
+	assert.True(t, (&NotificationRule{RepoPattern: "user3/*"}).Match(repo, NotificationRuleEventIssue, false))
+	assert.True(t, (&NotificationRule{RepoPattern: "USER3/repo3"}).Match(repo, NotificationRuleEventIssue, false))
+	assert.False(t, (&NotificationRule{RepoPattern: "user2/*"}).Match(repo, NotificationRuleEventIssue, false))
+	assert.False(t, (&NotificationRule{RepoPattern: "*"}).Match(repo, NotificationRuleEventIssue, false))
+
+	assert.True(t, (&NotificationRule{Event: NotificationRuleEventIssue}).Match(repo, NotificationRuleEventIssue, false))
+	assert.False(t, (&NotificationRule{Event: NotificationRuleEventPullRequest}).Match(repo, NotificationRuleEventIssue, false))
+	assert.False(t, (&NotificationRule{Event: NotificationRuleEventMention}).Match(repo, NotificationRuleEventIssue, false))
+	assert.True(t, (&NotificationRule{Event: NotificationRuleEventMention}).Match(repo, NotificationRuleEventIssue, true))
+}
+
+func TestGetNotificationActions(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// repo3 belongs to the organization user3, user2 and user4 are members, user5 is not
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	users := []*User{user2, user4, user5}
+
+	// without rules the email notifications preference applies
+	actions, err := GetNotificationActions(users, repo, NotificationRuleEventIssue, false)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]NotificationRuleAction{
+		2: NotificationRuleActionEmail,
+		4: NotificationRuleActionWeb,
+		5: NotificationRuleActionEmail,
+	}, actions)
+
+	actions, err = GetNotificationActions(users, repo, NotificationRuleEventIssue, true)
+	assert.NoError(t, err)
+	assert.Equal(t, NotificationRuleActionEmail, actions[4])
+
+	assert.NoError(t, CreateNotificationRule(&NotificationRule{OwnerID: 3, Event: NotificationRuleEventIssue, Action: NotificationRuleActionNone}))
+	assert.NoError(t, CreateNotificationRule(&NotificationRule{OwnerID: 2, RepoPattern: "user3/*", Action: NotificationRuleActionWeb}))
+	assert.NoError(t, CreateNotificationRule(&NotificationRule{OwnerID: 2, RepoPattern: "user3/repo3", Event: NotificationRuleEventPullRequest, Action: NotificationRuleActionEmail, Priority: 1}))
+
+	// rules of the user are evaluated first, rules of the organization only apply to its members
+	actions, err = GetNotificationActions(users, repo, NotificationRuleEventIssue, false)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]NotificationRuleAction{
+		2: NotificationRuleActionWeb,
+		4: NotificationRuleActionNone,
+		5: NotificationRuleActionEmail,
+	}, actions)
+
+	// rules with a higher priority are evaluated first
+	action, err := user2.GetNotificationAction(repo, NotificationRuleEventPullRequest, false)
+	assert.NoError(t, err)
+	assert.Equal(t, NotificationRuleActionEmail, action)
+
+	recipients, err := GetMailableNotificationRecipients([]int64{2, 4, 5}, repo, NotificationRuleEventIssue, false)
+	assert.NoError(t, err)
+	if assert.Len(t, recipients, 1) {
+		assert.EqualValues(t, 5, recipients[0].ID)
+	}
+
+	rules, err := user2.GetNotificationRules()
+	assert.NoError(t, err)
+	if assert.Len(t, rules, 2) {
+		assert.Equal(t, 1, rules[0].Priority)
+		assert.NoError(t, DeleteNotificationRule(2, rules[0].ID))
+	}
+	assert.True(t, IsErrNotificationRuleNotExist(DeleteNotificationRule(3, rules[1].ID)))
+}
@@ -25,6 +25,28 @@ func TestCreateOrUpdateIssueNotifications(t *testing.T) {
 	assert.Equal(t, NotificationStatusUnread, notf.Status)
 }
 
+func TestCreateOrUpdateIssueNotifications_Mention(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+
+	// user4 is only notified in the web interface when mentioned
+	assert.NoError(t, CreateNotificationRule(&NotificationRule{OwnerID: 4, Event: NotificationRuleEventMention, Action: NotificationRuleActionWeb, Priority: 1}))
+	assert.NoError(t, CreateNotificationRule(&NotificationRule{OwnerID: 4, Action: NotificationRuleActionNone}))
+
+	comment := &Comment{Type: CommentTypeComment, PosterID: 2, IssueID: issue.ID, Content: "no mention"}
+	_, err := x.Insert(comment)
+	assert.NoError(t, err)
+	assert.NoError(t, CreateOrUpdateIssueNotifications(issue.ID, comment.ID, 2, 0))
+	AssertExistsAndLoadBean(t, &Notification{UserID: 1, IssueID: issue.ID})
+	AssertNotExistsBean(t, &Notification{UserID: 4, IssueID: issue.ID})
+
+	comment = &Comment{Type: CommentTypeComment, PosterID: 2, IssueID: issue.ID, Content: "what do you think @User4?"}
+	_, err = x.Insert(comment)
+	assert.NoError(t, err)
+	assert.NoError(t, CreateOrUpdateIssueNotifications(issue.ID, comment.ID, 2, 0))
+	AssertExistsAndLoadBean(t, &Notification{UserID: 4, IssueID: issue.ID, CommentID: comment.ID})
+}
+
 func TestNotificationsForUser(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
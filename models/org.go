@@ -292,10 +292,17 @@ func deleteOrg(e *xorm.Session, u *User) error {
 		&OrgUser{OrgID: u.ID},
 		&TeamUser{OrgID: u.ID},
 		&TeamUnit{OrgID: u.ID},
+		&RepoPreset{OrgID: u.ID},
+		&NotificationRule{OwnerID: u.ID},
+		&TokenUsage{OrgID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err := removeFeatureFlagTarget(e, u.ID); err != nil {
+		return fmt.Errorf("removeFeatureFlagTarget: %v", err)
+	}
+
 	if _, err = e.ID(u.ID).Delete(new(User)); err != nil {
 		return fmt.Errorf("Delete: %v", err)
 	}
@@ -436,6 +443,15 @@ func hasOrgVisible(e Engine, org *User, user *User) bool {
 	if (org.Visibility == structs.VisibleTypePrivate || user.IsRestricted) && !org.hasMemberWithUserID(e, user.ID) {
 		return false
 	}
+
+	if org.Visibility == structs.VisibleTypePrivate {
+		blocked, err := org.isBlockedByTwoFactor(e, user)
+		if err != nil {
+			log.Error("isBlockedByTwoFactor: %v", err)
+			return false
+		}
+		return !blocked
+	}
 	return true
 }
 
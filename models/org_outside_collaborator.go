@@ -0,0 +1,73 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"xorm.io/builder"
+)
+
+// OutsideCollaboration represents the access of a user who is not a member of an organization
+// to one of the repositories of the organization
+type OutsideCollaboration struct {
+	User          *User
+	Repo          *Repository
+	Collaboration *Collaboration
+}
+
+func (org *User) outsideCollaborationsCond() builder.Cond {
+	return builder.In("collaboration.repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": org.ID})).
+		And(builder.NotIn("collaboration.user_id", builder.Select("uid").From("org_user").Where(builder.Eq{"org_id": org.ID})))
+}
+
+// GetOutsideCollaborations returns all accesses of users who are collaborators on repositories
+// of the organization without being a member of it, ordered by user and repository
+func (org *User) GetOutsideCollaborations(listOptions ListOptions) ([]*OutsideCollaboration, error) {
+	sess := x.
+		Join("INNER", "`user`", "`user`.id = collaboration.user_id").
+		Join("INNER", "repository", "repository.id = collaboration.repo_id").
+		Where(org.outsideCollaborationsCond()).
+		Asc("`user`.lower_name", "repository.lower_name")
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+
+	collaborations := make([]*Collaboration, 0, 10)
+	if err := sess.Find(&collaborations); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, 0, len(collaborations))
+	repoIDs := make([]int64, 0, len(collaborations))
+	for _, c := range collaborations {
+		userIDs = append(userIDs, c.UserID)
+		repoIDs = append(repoIDs, c.RepoID)
+	}
+	users := make(map[int64]*User, len(userIDs))
+	if err := x.In("id", userIDs).Find(&users); err != nil {
+		return nil, err
+	}
+	repos := make(map[int64]*Repository, len(repoIDs))
+	if err := x.In("id", repoIDs).Find(&repos); err != nil {
+		return nil, err
+	}
+
+	result := make([]*OutsideCollaboration, 0, len(collaborations))
+	for _, c := range collaborations {
+		repo := repos[c.RepoID]
+		repo.Owner = org
+		result = append(result, &OutsideCollaboration{
+			User:          users[c.UserID],
+			Repo:          repo,
+			Collaboration: c,
+		})
+	}
+	return result, nil
+}
+
+// CountOutsideCollaborations returns the number of accesses of users who are collaborators on repositories
+// of the organization without being a member of it
+func (org *User) CountOutsideCollaborations() (int64, error) {
+	return x.Where(org.outsideCollaborationsCond()).Count(new(Collaboration))
+}
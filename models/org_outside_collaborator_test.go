@@ -0,0 +1,43 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_GetOutsideCollaborations(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// user2 is a collaborator on repo3 but also a member of org3
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	collaborations, err := org.GetOutsideCollaborations(ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, collaborations, 0)
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	assert.NoError(t, repo.AddCollaborator(AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)))
+
+	collaborations, err = org.GetOutsideCollaborations(ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, collaborations, 1) {
+		assert.EqualValues(t, 5, collaborations[0].User.ID)
+		assert.EqualValues(t, 3, collaborations[0].Repo.ID)
+		assert.EqualValues(t, AccessModeWrite, collaborations[0].Collaboration.Mode)
+	}
+	count, err := org.CountOutsideCollaborations()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	org = AssertExistsAndLoadBean(t, &User{ID: 23}).(*User)
+	collaborations, err = org.GetOutsideCollaborations(ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, collaborations, 1) {
+		assert.EqualValues(t, 4, collaborations[0].User.ID)
+		assert.EqualValues(t, 40, collaborations[0].Repo.ID)
+	}
+}
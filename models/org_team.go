@@ -720,6 +720,13 @@ func DeleteTeam(t *Team) error {
 		return err
 	}
 
+	// Delete repository presets of the team.
+	if _, err := sess.
+		Where("team_id=?", t.ID).
+		Delete(new(RepoPreset)); err != nil {
+		return err
+	}
+
 	// Delete team.
 	if _, err := sess.ID(t.ID).Delete(new(Team)); err != nil {
 		return err
@@ -0,0 +1,127 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// TwoFactorDeadline returns the end of the grace period of the two-factor authentication requirement of the organization
+func (org *User) TwoFactorDeadline() timeutil.TimeStamp {
+	return org.TwoFactorRequiredUnix.Add(int64(org.TwoFactorGraceDays) * 24 * 60 * 60)
+}
+
+// IsTwoFactorEnforced returns true if the organization requires two-factor authentication
+// and the grace period for its members to enroll has ended
+func (org *User) IsTwoFactorEnforced() bool {
+	return org.RequireTwoFactor && org.TwoFactorDeadline() <= timeutil.TimeStampNow()
+}
+
+// SetTwoFactorRequirement updates the two-factor authentication requirement of the organization.
+// The grace period starts when the requirement is enabled, doer has to be enrolled to enable it.
+func (org *User) SetTwoFactorRequirement(doer *User, require bool, graceDays int) error {
+	if require && !org.RequireTwoFactor {
+		has, err := HasTwoFactorByUID(doer.ID)
+		if err != nil {
+			return err
+		} else if !has {
+			return ErrTwoFactorNotEnrolled{doer.ID}
+		}
+		org.TwoFactorRequiredUnix = timeutil.TimeStampNow()
+	} else if !require {
+		org.TwoFactorRequiredUnix = 0
+	}
+	org.RequireTwoFactor = require
+	org.TwoFactorGraceDays = graceDays
+
+	_, err := x.ID(org.ID).Cols("require_two_factor", "two_factor_grace_days", "two_factor_required_unix").Update(org)
+	return err
+}
+
+// isBlockedByTwoFactor returns true if user is a member of the organization who lost access
+// to its private resources, because the organization enforces two-factor authentication
+// and the user has not enrolled in it
+func (org *User) isBlockedByTwoFactor(e Engine, user *User) (bool, error) {
+	if user == nil || user.IsAdmin || !org.IsTwoFactorEnforced() {
+		return false, nil
+	}
+	if isMember, err := isOrganizationMember(e, org.ID, user.ID); err != nil || !isMember {
+		return false, err
+	}
+	has, err := hasTwoFactorByUID(e, user.ID)
+	return !has, err
+}
+
+// IsBlockedByTwoFactor returns true if user is a member of the organization who lost access
+// to its private resources because of its two-factor authentication requirement
+func (org *User) IsBlockedByTwoFactor(user *User) (bool, error) {
+	return org.isBlockedByTwoFactor(x, user)
+}
+
+// GetMembersWithoutTwoFactor returns the members of the organization who are not enrolled in two-factor authentication
+func (org *User) GetMembersWithoutTwoFactor() (UserList, error) {
+	users := make(UserList, 0, 10)
+	return users, x.
+		Join("INNER", "org_user", "`org_user`.uid = `user`.id").
+		Where("`org_user`.org_id = ?", org.ID).
+		And(builder.NotIn("`user`.id", builder.Select("uid").From("two_factor"))).
+		Asc("`user`.name").
+		Find(&users)
+}
+
+// OrgMemberTwoFactorStatus represents the two-factor authentication status of a member of an organization
+type OrgMemberTwoFactorStatus struct {
+	User         *User
+	IsOwner      bool
+	HasTwoFactor bool
+}
+
+// GetMembersTwoFactorStatus returns the two-factor authentication status of all members of the organization
+func (org *User) GetMembersTwoFactorStatus() ([]*OrgMemberTwoFactorStatus, error) {
+	if err := org.GetMembers(); err != nil {
+		return nil, err
+	}
+	withoutTwoFactor, err := org.GetMembersWithoutTwoFactor()
+	if err != nil {
+		return nil, err
+	}
+	missing := make(map[int64]bool, len(withoutTwoFactor))
+	for _, u := range withoutTwoFactor {
+		missing[u.ID] = true
+	}
+
+	ownerTeam, err := org.GetOwnerTeam()
+	if err != nil {
+		return nil, err
+	}
+	if err := ownerTeam.GetMembers(&SearchMembersOptions{}); err != nil {
+		return nil, err
+	}
+	owners := make(map[int64]bool, len(ownerTeam.Members))
+	for _, u := range ownerTeam.Members {
+		owners[u.ID] = true
+	}
+
+	statuses := make([]*OrgMemberTwoFactorStatus, 0, len(org.Members))
+	for _, u := range org.Members {
+		statuses = append(statuses, &OrgMemberTwoFactorStatus{
+			User:         u,
+			IsOwner:      owners[u.ID],
+			HasTwoFactor: !missing[u.ID],
+		})
+	}
+	return statuses, nil
+}
+
+// GetOrgsRequiringTwoFactor returns all organizations which require two-factor authentication of their members
+func GetOrgsRequiringTwoFactor() ([]*User, error) {
+	orgs := make([]*User, 0, 10)
+	return orgs, x.
+		Where("type = ?", UserTypeOrganization).
+		And("require_two_factor = ?", true).
+		Find(&orgs)
+}
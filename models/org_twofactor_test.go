@@ -0,0 +1,88 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgSetTwoFactorRequirement(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+
+	// the doer has to be enrolled
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	err := org.SetTwoFactorRequirement(user2, true, 7)
+	assert.True(t, IsErrTwoFactorNotEnrolled(err))
+	assert.False(t, org.RequireTwoFactor)
+
+	user24 := AssertExistsAndLoadBean(t, &User{ID: 24}).(*User)
+	assert.NoError(t, org.SetTwoFactorRequirement(user24, true, 7))
+	org = AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	assert.True(t, org.RequireTwoFactor)
+	assert.EqualValues(t, 7, org.TwoFactorGraceDays)
+	assert.NotZero(t, org.TwoFactorRequiredUnix)
+	assert.False(t, org.IsTwoFactorEnforced())
+
+	assert.NoError(t, org.SetTwoFactorRequirement(user2, false, 7))
+	org = AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	assert.False(t, org.RequireTwoFactor)
+	assert.Zero(t, org.TwoFactorRequiredUnix)
+}
+
+func TestOrgTwoFactorEnforcement(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	user24 := AssertExistsAndLoadBean(t, &User{ID: 24}).(*User)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+
+	perm, err := GetUserRepoPermission(repo, user4)
+	assert.NoError(t, err)
+	assert.True(t, perm.CanWrite(UnitTypeCode))
+
+	// members keep their access during the grace period
+	assert.NoError(t, org.SetTwoFactorRequirement(user24, true, 7))
+	repo.Owner = nil
+	perm, err = GetUserRepoPermission(repo, user4)
+	assert.NoError(t, err)
+	assert.True(t, perm.CanWrite(UnitTypeCode))
+
+	assert.NoError(t, org.SetTwoFactorRequirement(user24, true, 0))
+	blocked, err := org.IsBlockedByTwoFactor(user4)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+	repo.Owner = nil
+	perm, err = GetUserRepoPermission(repo, user4)
+	assert.NoError(t, err)
+	assert.False(t, perm.CanRead(UnitTypeCode))
+
+	// blocked members keep the access they have as collaborators, but not the one of their teams
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	AssertExistsAndLoadBean(t, &Collaboration{RepoID: repo.ID, UserID: user2.ID, Mode: AccessModeWrite})
+	blocked, err = org.IsBlockedByTwoFactor(user2)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+	repo.Owner = nil
+	perm, err = GetUserRepoPermission(repo, user2)
+	assert.NoError(t, err)
+	assert.True(t, perm.CanWrite(UnitTypeCode))
+	assert.False(t, perm.IsAdmin())
+
+	// non-members are not affected
+	blocked, err = org.IsBlockedByTwoFactor(user24)
+	assert.NoError(t, err)
+	assert.False(t, blocked)
+
+	statuses, err := org.GetMembersTwoFactorStatus()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, statuses)
+	for _, status := range statuses {
+		assert.False(t, status.HasTwoFactor)
+		assert.Equal(t, status.User.ID == 2, status.IsOwner)
+	}
+}
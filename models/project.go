@@ -299,6 +299,10 @@ func deleteProjectByID(e Engine, id int64) error {
 		return err
 	}
 
+	if err := deleteProjectIssueTransitionsByProjectID(e, id); err != nil {
+		return err
+	}
+
 	if _, err = e.ID(p.ID).Delete(new(Project)); err != nil {
 		return err
 	}
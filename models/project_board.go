@@ -171,10 +171,13 @@ func updateProjectBoard(e Engine, board *ProjectBoard) error {
 
 // GetProjectBoards fetches all boards related to a project
 func GetProjectBoards(projectID int64) ([]*ProjectBoard, error) {
+	return getProjectBoards(x, projectID)
+}
 
+func getProjectBoards(e Engine, projectID int64) ([]*ProjectBoard, error) {
 	var boards = make([]*ProjectBoard, 0, 5)
 
-	sess := x.Where("project_id=?", projectID)
+	sess := e.Where("project_id=?", projectID)
 	return boards, sess.Find(&boards)
 }
 
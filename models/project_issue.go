@@ -141,6 +141,7 @@ func ChangeProjectAssign(issue *Issue, doer *User, newProjectID int64) error {
 func addUpdateIssueProject(e *xorm.Session, issue *Issue, doer *User, newProjectID int64) error {
 
 	oldProjectID := issue.projectID(e)
+	oldProjectBoardID := issue.projectBoardID(e)
 
 	if _, err := e.Where("project_issue.issue_id=?", issue.ID).Delete(&ProjectIssue{}); err != nil {
 		return err
@@ -163,6 +164,17 @@ func addUpdateIssueProject(e *xorm.Session, issue *Issue, doer *User, newProject
 		}
 	}
 
+	if oldProjectID > 0 {
+		if err := insertProjectIssueTransition(e, oldProjectID, issue.ID, oldProjectBoardID, ProjectBoardOutside); err != nil {
+			return err
+		}
+	}
+	if newProjectID > 0 {
+		if err := insertProjectIssueTransition(e, newProjectID, issue.ID, ProjectBoardOutside, 0); err != nil {
+			return err
+		}
+	}
+
 	_, err := e.Insert(&ProjectIssue{
 		IssueID:   issue.ID,
 		ProjectID: newProjectID,
@@ -196,6 +208,14 @@ func MoveIssueAcrossProjectBoards(issue *Issue, board *ProjectBoard) error {
 		return fmt.Errorf("issue has to be added to a project first")
 	}
 
+	if pis.ProjectBoardID == board.ID {
+		return nil
+	}
+
+	if err := insertProjectIssueTransition(sess, pis.ProjectID, issue.ID, pis.ProjectBoardID, board.ID); err != nil {
+		return err
+	}
+
 	pis.ProjectBoardID = board.ID
 	if _, err := sess.ID(pis.ID).Cols("project_board_id").Update(&pis); err != nil {
 		return err
@@ -0,0 +1,196 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ProjectBoardOutside is the board ID used in a transition for an issue which is
+// added to or removed from a project.
+const ProjectBoardOutside int64 = -1
+
+// ProjectIssueTransition records an issue moving between the boards of a project.
+// Board ID 0 represents the uncategorized board.
+type ProjectIssueTransition struct {
+	ID          int64              `xorm:"pk autoincr"`
+	ProjectID   int64              `xorm:"INDEX NOT NULL"`
+	IssueID     int64              `xorm:"INDEX NOT NULL"`
+	FromBoardID int64              `xorm:"NOT NULL"`
+	ToBoardID   int64              `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func insertProjectIssueTransition(e Engine, projectID, issueID, fromBoardID, toBoardID int64) error {
+	_, err := e.Insert(&ProjectIssueTransition{
+		ProjectID:   projectID,
+		IssueID:     issueID,
+		FromBoardID: fromBoardID,
+		ToBoardID:   toBoardID,
+	})
+	return err
+}
+
+func deleteProjectIssueTransitionsByProjectID(e Engine, projectID int64) error {
+	_, err := e.Where("project_id=?", projectID).Delete(&ProjectIssueTransition{})
+	return err
+}
+
+// ProjectBoardStats represents how long issues stay on a board of a project
+type ProjectBoardStats struct {
+	Board *ProjectBoard
+	// NumIssues is the number of issues currently on the board
+	NumIssues int
+	// NumStays is the number of times an issue entered the board
+	NumStays        int
+	AverageDuration int64
+	MedianDuration  int64
+}
+
+// ProjectAnalytics represents the time issues spend on the boards of a project.
+// Lead time is measured from adding an issue to the project until it is closed,
+// cycle time from its first move between boards until it is closed.
+type ProjectAnalytics struct {
+	Boards           []*ProjectBoardStats
+	NumLeadTimes     int
+	AverageLeadTime  int64
+	MedianLeadTime   int64
+	NumCycleTimes    int
+	AverageCycleTime int64
+	MedianCycleTime  int64
+}
+
+// durationStats returns the average and median of the durations
+func durationStats(durations []int64) (average, median int64) {
+	if len(durations) == 0 {
+		return 0, 0
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var sum int64
+	for _, d := range durations {
+		sum += d
+	}
+	return sum / int64(len(durations)), durations[len(durations)/2]
+}
+
+// GetProjectAnalytics computes the time-in-board, lead time and cycle time statistics
+// of a project from the recorded board transitions of its issues.
+func GetProjectAnalytics(project *Project) (*ProjectAnalytics, error) {
+	return getProjectAnalytics(x, project, timeutil.TimeStampNow())
+}
+
+func getProjectAnalytics(e Engine, project *Project, now timeutil.TimeStamp) (*ProjectAnalytics, error) {
+	transitions := make([]*ProjectIssueTransition, 0, 50)
+	if err := e.Where("project_id=?", project.ID).
+		Asc("issue_id", "created_unix", "id").
+		Find(&transitions); err != nil {
+		return nil, err
+	}
+
+	issueIDs := make([]int64, 0, 10)
+	for i, t := range transitions {
+		if i == 0 || transitions[i-1].IssueID != t.IssueID {
+			issueIDs = append(issueIDs, t.IssueID)
+		}
+	}
+	closedUnix := make(map[int64]timeutil.TimeStamp, len(issueIDs))
+	if len(issueIDs) > 0 {
+		issues := make([]*Issue, 0, len(issueIDs))
+		if err := e.In("id", issueIDs).
+			And("is_closed=?", true).
+			Cols("id", "closed_unix").
+			Find(&issues); err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			closedUnix[issue.ID] = issue.ClosedUnix
+		}
+	}
+
+	boards, err := getProjectBoards(e, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	uncategorized, err := GetUncategorizedBoard(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	stays := make(map[int64][]int64, len(boards)+1)
+	numIssues := make(map[int64]int, len(boards)+1)
+	var leadTimes, cycleTimes []int64
+
+	for i := 0; i < len(transitions); {
+		issueID := transitions[i].IssueID
+		closed, isClosed := closedUnix[issueID]
+
+		// stayUntil returns when a stay starting at since ends if the issue is not moved anymore,
+		// closing an issue ends its stay on a board.
+		stayUntil := func(since timeutil.TimeStamp) timeutil.TimeStamp {
+			if !isClosed {
+				return now
+			} else if closed >= since {
+				return closed
+			}
+			return since
+		}
+
+		boardID := ProjectBoardOutside
+		var since, addedUnix, startedUnix timeutil.TimeStamp
+		for ; i < len(transitions) && transitions[i].IssueID == issueID; i++ {
+			t := transitions[i]
+			if boardID != ProjectBoardOutside {
+				end := t.CreatedUnix
+				if until := stayUntil(since); until < end {
+					end = until
+				}
+				stays[boardID] = append(stays[boardID], int64(end-since))
+			}
+
+			switch {
+			case t.FromBoardID == ProjectBoardOutside:
+				addedUnix, startedUnix = t.CreatedUnix, 0
+			case t.ToBoardID != ProjectBoardOutside && startedUnix == 0:
+				startedUnix = t.CreatedUnix
+			}
+			boardID, since = t.ToBoardID, t.CreatedUnix
+		}
+
+		if boardID == ProjectBoardOutside {
+			continue
+		}
+		stays[boardID] = append(stays[boardID], int64(stayUntil(since)-since))
+		numIssues[boardID]++
+
+		if isClosed && closed >= addedUnix {
+			leadTimes = append(leadTimes, int64(closed-addedUnix))
+			if startedUnix > 0 && closed >= startedUnix {
+				cycleTimes = append(cycleTimes, int64(closed-startedUnix))
+			}
+		}
+	}
+
+	analytics := &ProjectAnalytics{
+		Boards:        make([]*ProjectBoardStats, 0, len(boards)+1),
+		NumLeadTimes:  len(leadTimes),
+		NumCycleTimes: len(cycleTimes),
+	}
+	analytics.AverageLeadTime, analytics.MedianLeadTime = durationStats(leadTimes)
+	analytics.AverageCycleTime, analytics.MedianCycleTime = durationStats(cycleTimes)
+
+	for _, board := range append([]*ProjectBoard{uncategorized}, boards...) {
+		stats := &ProjectBoardStats{
+			Board:     board,
+			NumIssues: numIssues[board.ID],
+			NumStays:  len(stays[board.ID]),
+		}
+		stats.AverageDuration, stats.MedianDuration = durationStats(stays[board.ID])
+		analytics.Boards = append(analytics.Boards, stats)
+	}
+	return analytics, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProjectAnalytics(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	_, err := x.ID(5).Cols("closed_unix").Update(&Issue{ClosedUnix: 1900})
+	assert.NoError(t, err)
+
+	project := AssertExistsAndLoadBean(t, &Project{ID: 1}).(*Project)
+	analytics, err := getProjectAnalytics(x, project, 2000)
+	assert.NoError(t, err)
+
+	expected := []struct {
+		boardID         int64
+		numIssues       int
+		numStays        int
+		averageDuration int64
+		medianDuration  int64
+	}{
+		{0, 1, 4, 400, 300},
+		{1, 1, 3, 500, 300},
+		{2, 1, 2, 300, 500},
+		{3, 1, 1, 200, 200},
+	}
+	if assert.Len(t, analytics.Boards, len(expected)) {
+		for i, e := range expected {
+			stats := analytics.Boards[i]
+			assert.EqualValues(t, e.boardID, stats.Board.ID)
+			assert.EqualValues(t, e.numIssues, stats.NumIssues)
+			assert.EqualValues(t, e.numStays, stats.NumStays)
+			assert.EqualValues(t, e.averageDuration, stats.AverageDuration)
+			assert.EqualValues(t, e.medianDuration, stats.MedianDuration)
+		}
+	}
+
+	assert.EqualValues(t, 1, analytics.NumLeadTimes)
+	assert.EqualValues(t, 900, analytics.AverageLeadTime)
+	assert.EqualValues(t, 1, analytics.NumCycleTimes)
+	assert.EqualValues(t, 600, analytics.MedianCycleTime)
+}
+
+func TestMoveIssueAcrossProjectBoardsRecordsTransition(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	board := AssertExistsAndLoadBean(t, &ProjectBoard{ID: 2}).(*ProjectBoard)
+	assert.NoError(t, MoveIssueAcrossProjectBoards(issue, board))
+	AssertExistsAndLoadBean(t, &ProjectIssueTransition{ProjectID: 1, IssueID: 1, FromBoardID: 1, ToBoardID: 2})
+
+	// moving onto the current board is not a transition
+	assert.NoError(t, MoveIssueAcrossProjectBoards(issue, board))
+	AssertCount(t, &ProjectIssueTransition{IssueID: 1, ToBoardID: 2}, 1)
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, ChangeProjectAssign(issue, doer, 0))
+	AssertExistsAndLoadBean(t, &ProjectIssueTransition{ProjectID: 1, IssueID: 1, FromBoardID: 2, ToBoardID: ProjectBoardOutside})
+}
@@ -77,6 +77,18 @@ func GetPullRequestIDsByCheckStatus(status PullRequestStatus) ([]int64, error) {
 		Find(&prs)
 }
 
+// GetPullRequestIDsByCheckStatusInShard returns the IDs of the unmerged pull requests with the check status
+// whose base repository ID modulo the number of shards is the shard, oldest first.
+func GetPullRequestIDsByCheckStatusInShard(status PullRequestStatus, shard, shards int64) ([]int64, error) {
+	prs := make([]int64, 0, 10)
+	return prs, x.Table("pull_request").
+		Where("status=? AND has_merged=?", status, false).
+		And("base_repo_id % ? = ?", shards, shard).
+		Cols("pull_request.id").
+		Asc("pull_request.id").
+		Find(&prs)
+}
+
 // PullRequests returns all pull requests for a base Repo by the given conditions
 func PullRequests(baseRepoID int64, opts *PullRequestsOptions) ([]*PullRequest, int64, error) {
 	if opts.Page <= 0 {
@@ -0,0 +1,241 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gobwas/glob"
+)
+
+// ReleaseChannel groups the releases of a repository whose tags match a pattern,
+// e.g. a stable, beta or nightly channel.
+type ReleaseChannel struct {
+	ID         int64  `xorm:"pk autoincr"`
+	RepoID     int64  `xorm:"INDEX UNIQUE(s) NOT NULL"`
+	Name       string `xorm:"NOT NULL"`
+	LowerName  string `xorm:"UNIQUE(s) NOT NULL"`
+	TagPattern string `xorm:"NOT NULL"`
+	// KeepLast is the number of releases the channel keeps, older releases are deleted
+	// together with their tags. Zero keeps all releases.
+	KeepLast int `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// ErrReleaseChannelNotExist represents a "ReleaseChannelNotExist" kind of error.
+type ErrReleaseChannelNotExist struct {
+	RepoID int64
+	Name   string
+}
+
+// IsErrReleaseChannelNotExist checks if an error is a ErrReleaseChannelNotExist.
+func IsErrReleaseChannelNotExist(err error) bool {
+	_, ok := err.(ErrReleaseChannelNotExist)
+	return ok
+}
+
+func (err ErrReleaseChannelNotExist) Error() string {
+	return fmt.Sprintf("release channel does not exist [repo_id: %d, name: %s]", err.RepoID, err.Name)
+}
+
+// ErrReleaseChannelAlreadyExist represents a "ReleaseChannelAlreadyExist" kind of error.
+type ErrReleaseChannelAlreadyExist struct {
+	RepoID int64
+	Name   string
+}
+
+// IsErrReleaseChannelAlreadyExist checks if an error is a ErrReleaseChannelAlreadyExist.
+func IsErrReleaseChannelAlreadyExist(err error) bool {
+	_, ok := err.(ErrReleaseChannelAlreadyExist)
+	return ok
+}
+
+func (err ErrReleaseChannelAlreadyExist) Error() string {
+	return fmt.Sprintf("release channel already exists [repo_id: %d, name: %s]", err.RepoID, err.Name)
+}
+
+// ErrInvalidReleaseChannel represents an error for a release channel with invalid settings
+type ErrInvalidReleaseChannel struct {
+	Reason string
+}
+
+// IsErrInvalidReleaseChannel checks if an error is a ErrInvalidReleaseChannel.
+func IsErrInvalidReleaseChannel(err error) bool {
+	_, ok := err.(ErrInvalidReleaseChannel)
+	return ok
+}
+
+func (err ErrInvalidReleaseChannel) Error() string {
+	return fmt.Sprintf("invalid release channel: %s", err.Reason)
+}
+
+var releaseChannelNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,50}$`)
+
+// Validate checks the settings of the release channel
+func (c *ReleaseChannel) Validate() error {
+	if !releaseChannelNamePattern.MatchString(c.Name) {
+		return ErrInvalidReleaseChannel{"name must consist of up to 50 letters, digits, dashes, dots and underscores"}
+	}
+	if len(c.TagPattern) == 0 {
+		return ErrInvalidReleaseChannel{"tag pattern is empty"}
+	}
+	if _, err := glob.Compile(c.TagPattern); err != nil {
+		return ErrInvalidReleaseChannel{fmt.Sprintf("invalid tag pattern: %v", err)}
+	}
+	if c.KeepLast < 0 {
+		return ErrInvalidReleaseChannel{"number of releases to keep is negative"}
+	}
+	c.LowerName = strings.ToLower(c.Name)
+	return nil
+}
+
+// Match returns true if releases with the tag belong to the channel
+func (c *ReleaseChannel) Match(tagName string) bool {
+	g, err := glob.Compile(c.TagPattern)
+	if err != nil {
+		return false
+	}
+	return g.Match(tagName)
+}
+
+// GetReleases returns the published releases of the channel, newest first.
+// At most limit releases are returned unless limit is zero.
+func (c *ReleaseChannel) GetReleases(limit int) ([]*Release, error) {
+	g, err := glob.Compile(c.TagPattern)
+	if err != nil {
+		return nil, ErrInvalidReleaseChannel{fmt.Sprintf("invalid tag pattern: %v", err)}
+	}
+
+	rels := make([]*Release, 0, 10)
+	return rels, x.
+		Where("repo_id = ? AND is_draft = ? AND is_tag = ?", c.RepoID, false, false).
+		Desc("created_unix", "id").
+		Iterate(new(Release), func(idx int, bean interface{}) error {
+			rel := bean.(*Release)
+			if g.Match(rel.TagName) && (limit == 0 || len(rels) < limit) {
+				rels = append(rels, rel)
+			}
+			return nil
+		})
+}
+
+// GetLatestRelease returns the latest published release of the channel
+func (c *ReleaseChannel) GetLatestRelease() (*Release, error) {
+	rels, err := c.GetReleases(1)
+	if err != nil {
+		return nil, err
+	} else if len(rels) == 0 {
+		return nil, ErrReleaseNotExist{0, "latest"}
+	}
+	return rels[0], nil
+}
+
+// GetExpiredReleases returns the releases of the channel beyond the number of releases it keeps
+func (c *ReleaseChannel) GetExpiredReleases() ([]*Release, error) {
+	if c.KeepLast == 0 {
+		return []*Release{}, nil
+	}
+
+	rels, err := c.GetReleases(0)
+	if err != nil || len(rels) <= c.KeepLast {
+		return []*Release{}, err
+	}
+	return rels[c.KeepLast:], nil
+}
+
+func isReleaseChannelNameExist(e Engine, repoID, channelID int64, name string) (bool, error) {
+	return e.
+		Where("repo_id = ? AND id != ?", repoID, channelID).
+		And("lower_name = ?", strings.ToLower(name)).
+		Exist(new(ReleaseChannel))
+}
+
+// CreateReleaseChannel creates a new release channel of a repository
+func CreateReleaseChannel(c *ReleaseChannel) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if exist, err := isReleaseChannelNameExist(sess, c.RepoID, 0, c.Name); err != nil {
+		return err
+	} else if exist {
+		return ErrReleaseChannelAlreadyExist{c.RepoID, c.Name}
+	}
+	if _, err := sess.Insert(c); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// UpdateReleaseChannel updates the settings of a release channel
+func UpdateReleaseChannel(c *ReleaseChannel) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if exist, err := isReleaseChannelNameExist(sess, c.RepoID, c.ID, c.Name); err != nil {
+		return err
+	} else if exist {
+		return ErrReleaseChannelAlreadyExist{c.RepoID, c.Name}
+	}
+	if _, err := sess.ID(c.ID).AllCols().Update(c); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// DeleteReleaseChannel deletes a release channel of a repository, its releases are kept
+func DeleteReleaseChannel(repoID, id int64) error {
+	n, err := x.Delete(&ReleaseChannel{ID: id, RepoID: repoID})
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrReleaseChannelNotExist{repoID, fmt.Sprintf("#%d", id)}
+	}
+	return nil
+}
+
+// GetReleaseChannelByName returns the release channel of a repository by its name
+func GetReleaseChannelByName(repoID int64, name string) (*ReleaseChannel, error) {
+	c := &ReleaseChannel{RepoID: repoID, LowerName: strings.ToLower(name)}
+	has, err := x.Get(c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrReleaseChannelNotExist{repoID, name}
+	}
+	return c, nil
+}
+
+// GetReleaseChannels returns all release channels of a repository
+func GetReleaseChannels(repoID int64) ([]*ReleaseChannel, error) {
+	channels := make([]*ReleaseChannel, 0, 3)
+	return channels, x.Where("repo_id = ?", repoID).Asc("lower_name").Find(&channels)
+}
+
+// GetReleaseChannelsWithRetention returns the release channels of all repositories
+// which only keep a limited number of releases
+func GetReleaseChannelsWithRetention() ([]*ReleaseChannel, error) {
+	channels := make([]*ReleaseChannel, 0, 10)
+	return channels, x.Where("keep_last > 0").Asc("id").Find(&channels)
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateReleaseChannel(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	channel := &ReleaseChannel{RepoID: 1, Name: "Beta", TagPattern: "v*-beta*", KeepLast: 5}
+	assert.NoError(t, CreateReleaseChannel(channel))
+	AssertExistsAndLoadBean(t, &ReleaseChannel{ID: channel.ID, LowerName: "beta"})
+
+	err := CreateReleaseChannel(&ReleaseChannel{RepoID: 1, Name: "STABLE", TagPattern: "*"})
+	assert.True(t, IsErrReleaseChannelAlreadyExist(err))
+
+	err = CreateReleaseChannel(&ReleaseChannel{RepoID: 1, Name: "not a name", TagPattern: "*"})
+	assert.True(t, IsErrInvalidReleaseChannel(err))
+
+	err = CreateReleaseChannel(&ReleaseChannel{RepoID: 1, Name: "broken", TagPattern: "v[1"})
+	assert.True(t, IsErrInvalidReleaseChannel(err))
+
+	err = CreateReleaseChannel(&ReleaseChannel{RepoID: 1, Name: "negative", TagPattern: "*", KeepLast: -1})
+	assert.True(t, IsErrInvalidReleaseChannel(err))
+
+	channel.Name = "nightly"
+	assert.True(t, IsErrReleaseChannelAlreadyExist(UpdateReleaseChannel(channel)))
+	channel.Name = "Preview"
+	assert.NoError(t, UpdateReleaseChannel(channel))
+	AssertExistsAndLoadBean(t, &ReleaseChannel{ID: channel.ID, LowerName: "preview"})
+
+	channels, err := GetReleaseChannels(1)
+	assert.NoError(t, err)
+	if assert.Len(t, channels, 3) {
+		assert.EqualValues(t, "nightly", channels[0].LowerName)
+		assert.EqualValues(t, "preview", channels[1].LowerName)
+		assert.EqualValues(t, "stable", channels[2].LowerName)
+	}
+
+	assert.NoError(t, DeleteReleaseChannel(1, channel.ID))
+	AssertNotExistsBean(t, &ReleaseChannel{ID: channel.ID})
+	assert.True(t, IsErrReleaseChannelNotExist(DeleteReleaseChannel(1, channel.ID)))
+}
+
+func TestReleaseChannel_GetReleases(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	for i, tag := range []string{"nightly-1", "nightly-2", "nightly-3", "v1.2"} {
+		_, err := x.Insert(&Release{
+			RepoID:       1,
+			PublisherID:  2,
+			TagName:      tag,
+			LowerTagName: tag,
+			Target:       "master",
+			CreatedUnix:  timeutil.TimeStamp(946684801 + i),
+		})
+		assert.NoError(t, err)
+	}
+	_, err := x.Insert(&Release{RepoID: 1, PublisherID: 2, TagName: "nightly-4", LowerTagName: "nightly-4", IsDraft: true})
+	assert.NoError(t, err)
+
+	stable, err := GetReleaseChannelByName(1, "Stable")
+	assert.NoError(t, err)
+	assert.True(t, stable.Match("v1.1"))
+	assert.False(t, stable.Match("nightly-1"))
+
+	latest, err := stable.GetLatestRelease()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "v1.2", latest.TagName)
+
+	nightly, err := GetReleaseChannelByName(1, "nightly")
+	assert.NoError(t, err)
+	releases, err := nightly.GetReleases(0)
+	assert.NoError(t, err)
+	var tags []string
+	for _, rel := range releases {
+		tags = append(tags, rel.TagName)
+	}
+	assert.EqualValues(t, []string{"nightly-3", "nightly-2", "nightly-1"}, tags)
+
+	expired, err := nightly.GetExpiredReleases()
+	assert.NoError(t, err)
+	if assert.Len(t, expired, 1) {
+		assert.EqualValues(t, "nightly-1", expired[0].TagName)
+	}
+
+	expired, err = stable.GetExpiredReleases()
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+
+	_, err = GetReleaseChannelByName(2, "stable")
+	assert.True(t, IsErrReleaseChannelNotExist(err))
+
+	channels, err := GetReleaseChannelsWithRetention()
+	assert.NoError(t, err)
+	if assert.Len(t, channels, 1) {
+		assert.EqualValues(t, nightly.ID, channels[0].ID)
+	}
+}
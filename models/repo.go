@@ -239,6 +239,9 @@ type Repository struct {
 	IsFsckEnabled                   bool               `xorm:"NOT NULL DEFAULT true"`
 	CloseIssuesViaCommitInAnyBranch bool               `xorm:"NOT NULL DEFAULT false"`
 	Topics                          []string           `xorm:"TEXT JSON"`
+	License                         string             `xorm:"VARCHAR(255) INDEX"`
+	FileLicenses                    []string           `xorm:"TEXT JSON"`
+	AttachmentRetentionDays         int                `xorm:"NOT NULL DEFAULT 0"`
 
 	TrustModel TrustModelType
 
@@ -368,6 +371,10 @@ func (repo *Repository) innerAPIFormat(e Engine, mode AccessMode, isParent bool)
 			EnableTimeTracker:                config.EnableTimetracker,
 			AllowOnlyContributorsToTrackTime: config.AllowOnlyContributorsToTrackTime,
 			EnableIssueDependencies:          config.EnableDependencies,
+			DisableCloseKeywords:             config.DisableCloseKeywords,
+			CloseKeywords:                    config.CloseKeywords,
+			ReopenKeywords:                   config.ReopenKeywords,
+			CloseTrigger:                     string(config.CloseTrigger),
 		}
 	} else if unit, err := repo.getUnit(e, UnitTypeExternalTracker); err == nil {
 		config := unit.ExternalTrackerConfig()
@@ -432,6 +439,9 @@ func (repo *Repository) innerAPIFormat(e Engine, mode AccessMode, isParent bool)
 		Template:                  repo.IsTemplate,
 		Empty:                     repo.IsEmpty,
 		Archived:                  repo.IsArchived,
+		License:                   repo.License,
+		FileLicenses:              repo.FileLicenses,
+		AttachmentRetentionDays:   repo.AttachmentRetentionDays,
 		Size:                      int(repo.Size / 1024),
 		Fork:                      repo.IsFork,
 		Parent:                    parent,
@@ -464,6 +474,7 @@ func (repo *Repository) innerAPIFormat(e Engine, mode AccessMode, isParent bool)
 		AllowSquash:               allowSquash,
 		AvatarURL:                 repo.avatarLink(e),
 		Internal:                  !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate,
+		TrustModel:                repo.TrustModel.String(),
 	}
 }
 
@@ -618,6 +629,11 @@ func (repo *Repository) ComposeMetas() map[string]string {
 			default:
 				metas["style"] = markup.IssueNameStyleNumeric
 			}
+		} else if unit, err := repo.GetUnit(UnitTypeIssues); err == nil {
+			if closeKeywords, reopenKeywords, custom := unit.IssuesConfig().Keywords(); custom {
+				metas["closeKeywords"] = strings.Join(closeKeywords, ",")
+				metas["reopenKeywords"] = strings.Join(reopenKeywords, ",")
+			}
 		}
 
 		repo.MustOwner()
@@ -1114,6 +1130,7 @@ type CreateRepoOptions struct {
 	AutoInit       bool
 	Status         RepositoryStatus
 	TrustModel     TrustModelType
+	Preset         *RepoPreset
 }
 
 // GetRepoInitFile returns repository init files
@@ -1709,6 +1726,16 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		releaseAttachments = append(releaseAttachments, attachments[i].RelativePath())
 	}
 
+	orphanedAttachments := make([]*Attachment, 0, 10)
+	if err = sess.Where("repo_id = ? AND issue_id = 0 AND release_id = 0", repoID).
+		Find(&orphanedAttachments); err != nil {
+		return err
+	}
+	if _, err = sess.Where("repo_id = ? AND issue_id = 0 AND release_id = 0", repoID).
+		Delete(new(Attachment)); err != nil {
+		return err
+	}
+
 	if _, err = sess.Exec("UPDATE `user` SET num_stars=num_stars-1 WHERE id IN (SELECT `uid` FROM `star` WHERE repo_id = ?)", repo.ID); err != nil {
 		return err
 	}
@@ -1733,6 +1760,12 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		&LanguageStat{RepoID: repoID},
 		&Comment{RefRepoID: repoID},
 		&Task{RepoID: repoID},
+		&StatusCheckContextChange{RepoID: repoID},
+		&ScheduledPost{RepoID: repoID},
+		&CodeFrequency{RepoID: repoID},
+		&ReleaseChannel{RepoID: repoID},
+		&IssueSync{RepoID: repoID},
+		&IssueSyncLink{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -1747,6 +1780,13 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return err
 	}
 
+	// the oldest fork takes the place of the repository in its fork network
+	if repo.NumForks > 0 {
+		if err = reRootForkNetworkOnDelete(sess, repo); err != nil {
+			return fmt.Errorf("reRootForkNetworkOnDelete: %v", err)
+		}
+	}
+
 	if repo.IsFork {
 		if _, err = sess.Exec("UPDATE `repository` SET num_forks=num_forks-1 WHERE id=?", repo.ForkID); err != nil {
 			return fmt.Errorf("decrease fork count: %v", err)
@@ -1806,12 +1846,6 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return err
 	}
 
-	if repo.NumForks > 0 {
-		if _, err = sess.Exec("UPDATE `repository` SET fork_id=0,is_fork=? WHERE fork_id=?", false, repo.ID); err != nil {
-			log.Error("reset 'fork_id' and 'is_fork': %v", err)
-		}
-	}
-
 	if err = sess.Commit(); err != nil {
 		sess.Close()
 		if len(deployKeys) > 0 {
@@ -1838,6 +1872,11 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		RemoveStorageWithNotice(storage.Attachments, "Delete release attachment", releaseAttachments[i])
 	}
 
+	// Remove orphaned attachment files.
+	for i := range orphanedAttachments {
+		RemoveStorageWithNotice(storage.Attachments, "Delete orphaned attachment", orphanedAttachments[i].RelativePath())
+	}
+
 	if len(repo.Avatar) > 0 {
 		if err := storage.RepoAvatars.Delete(repo.CustomAvatarRelativePath()); err != nil {
 			return fmt.Errorf("Failed to remove %s: %v", repo.Avatar, err)
@@ -1925,6 +1964,10 @@ func GetUserRepositories(opts *SearchRepoOptions) ([]*Repository, int64, error)
 		cond = cond.And(builder.In("lower_name", opts.LowerNames))
 	}
 
+	if opts.License != "" {
+		cond = cond.And(builder.Eq{"license": opts.License})
+	}
+
 	sess := x.NewSession()
 	defer sess.Close()
 
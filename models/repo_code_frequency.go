@@ -0,0 +1,102 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// CodeFrequencyWeeks is the number of weeks the code frequency of repositories is computed for
+const CodeFrequencyWeeks = 52
+
+// CodeFrequency represents the lines added and deleted in a top level directory of a repository during a week
+type CodeFrequency struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"UNIQUE(s) NOT NULL"`
+	// Directory is the top level directory, empty for the files in the root of the repository
+	Directory string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+	WeekUnix  timeutil.TimeStamp `xorm:"UNIQUE(s) NOT NULL"`
+	Additions int64
+	Deletions int64
+	Commits   int64
+}
+
+// DirectoryCodeFrequency represents the code frequency of a top level directory of a repository
+type DirectoryCodeFrequency struct {
+	Directory string
+	Additions int64
+	Deletions int64
+	Commits   int64
+	// Weeks are the weeks with changes in the directory, the oldest first
+	Weeks []*CodeFrequency
+}
+
+// Churn returns the number of changed lines
+func (f *DirectoryCodeFrequency) Churn() int64 {
+	return f.Additions + f.Deletions
+}
+
+// GetCodeFrequencies returns the code frequency of the repository since the given week, the oldest week first
+func (repo *Repository) GetCodeFrequencies(since timeutil.TimeStamp) ([]*CodeFrequency, error) {
+	frequencies := make([]*CodeFrequency, 0, 10)
+	return frequencies, x.
+		Where("repo_id = ? AND week_unix >= ?", repo.ID, since).
+		Asc("week_unix", "directory").
+		Find(&frequencies)
+}
+
+// UpdateCodeFrequencies replaces the code frequency of the repository by the one computed at the commit
+func (repo *Repository) UpdateCodeFrequencies(commitID string, frequencies []*CodeFrequency) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Delete(&CodeFrequency{RepoID: repo.ID}); err != nil {
+		return err
+	}
+	for _, f := range frequencies {
+		f.ID = 0
+		f.RepoID = repo.ID
+	}
+	if len(frequencies) > 0 {
+		if _, err := sess.Insert(&frequencies); err != nil {
+			return err
+		}
+	}
+	if err := repo.updateIndexerStatus(sess, RepoIndexerTypeCodeFrequency, commitID); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GroupCodeFrequenciesByDirectory sums up the weekly code frequencies per directory,
+// the directory with the most changed lines first
+func GroupCodeFrequenciesByDirectory(frequencies []*CodeFrequency) []*DirectoryCodeFrequency {
+	directories := make(map[string]*DirectoryCodeFrequency)
+	result := make([]*DirectoryCodeFrequency, 0, 10)
+	for _, f := range frequencies {
+		d, ok := directories[f.Directory]
+		if !ok {
+			d = &DirectoryCodeFrequency{Directory: f.Directory}
+			directories[f.Directory] = d
+			result = append(result, d)
+		}
+		d.Additions += f.Additions
+		d.Deletions += f.Deletions
+		d.Commits += f.Commits
+		d.Weeks = append(d.Weeks, f)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Churn() != result[j].Churn() {
+			return result[i].Churn() > result[j].Churn()
+		}
+		return result[i].Directory < result[j].Directory
+	})
+	return result
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateCodeFrequencies(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+
+	assert.NoError(t, repo.UpdateCodeFrequencies("1234", []*CodeFrequency{
+		{Directory: "docs", WeekUnix: 1000, Additions: 5, Deletions: 1, Commits: 1},
+		{Directory: "", WeekUnix: 1000, Additions: 1, Commits: 1},
+		{Directory: "docs", WeekUnix: 2000, Additions: 2, Deletions: 2, Commits: 2},
+		{Directory: "src", WeekUnix: 2000, Additions: 20, Deletions: 10, Commits: 3},
+	}))
+	AssertExistsAndLoadBean(t, &RepoIndexerStatus{RepoID: 1, IndexerType: RepoIndexerTypeCodeFrequency, CommitSha: "1234"})
+
+	frequencies, err := repo.GetCodeFrequencies(2000)
+	assert.NoError(t, err)
+	assert.Len(t, frequencies, 2)
+
+	frequencies, err = repo.GetCodeFrequencies(0)
+	assert.NoError(t, err)
+	directories := GroupCodeFrequenciesByDirectory(frequencies)
+	if assert.Len(t, directories, 3) {
+		assert.Equal(t, "src", directories[0].Directory)
+		assert.EqualValues(t, 30, directories[0].Churn())
+		assert.Equal(t, "docs", directories[1].Directory)
+		assert.EqualValues(t, 7, directories[1].Additions)
+		assert.EqualValues(t, 3, directories[1].Commits)
+		assert.Len(t, directories[1].Weeks, 2)
+		assert.Equal(t, "", directories[2].Directory)
+	}
+
+	// the code frequency is replaced
+	assert.NoError(t, repo.UpdateCodeFrequencies("5678", nil))
+	AssertNotExistsBean(t, &CodeFrequency{RepoID: 1})
+	AssertExistsAndLoadBean(t, &RepoIndexerStatus{RepoID: 1, IndexerType: RepoIndexerTypeCodeFrequency, CommitSha: "5678"})
+}
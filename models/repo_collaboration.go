@@ -6,8 +6,10 @@
 package models
 
 import (
+	"context"
 	"fmt"
 
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/timeutil"
 
 	"xorm.io/builder"
@@ -21,6 +23,28 @@ type Collaboration struct {
 	Mode        AccessMode         `xorm:"DEFAULT 2 NOT NULL"`
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+}
+
+// HasExpiry returns true if the collaboration is removed automatically at some point
+func (c *Collaboration) HasExpiry() bool {
+	return c.ExpiresUnix > 0
+}
+
+// IsExpired returns true if the collaboration has an expiry date which has passed
+func (c *Collaboration) IsExpired() bool {
+	return c.HasExpiry() && c.ExpiresUnix <= timeutil.TimeStampNow()
+}
+
+// expiredCollaborationCond returns the condition of the collaborations whose expiry date has passed.
+// They are only deleted by the cron task, until then they must not grant any access.
+func expiredCollaborationCond() builder.Cond {
+	return builder.Gt{"`collaboration`.expires_unix": 0}.And(builder.Lte{"`collaboration`.expires_unix": timeutil.TimeStampNow()})
+}
+
+func (repo *Repository) hasExpiredCollaboration(e Engine, userID int64) (bool, error) {
+	return e.Where(builder.Eq{"`collaboration`.repo_id": repo.ID, "`collaboration`.user_id": userID}.And(expiredCollaborationCond())).
+		Exist(new(Collaboration))
 }
 
 func (repo *Repository) addCollaborator(e Engine, u *User) error {
@@ -115,7 +139,7 @@ func (repo *Repository) getCollaboration(e Engine, uid int64) (*Collaboration, e
 }
 
 func (repo *Repository) isCollaborator(e Engine, userID int64) (bool, error) {
-	return e.Get(&Collaboration{RepoID: repo.ID, UserID: userID})
+	return e.Where(builder.Not{expiredCollaborationCond()}).Get(&Collaboration{RepoID: repo.ID, UserID: userID})
 }
 
 // IsCollaborator check if a user is a collaborator of a repository
@@ -172,6 +196,15 @@ func (repo *Repository) ChangeCollaborationAccessMode(uid int64, mode AccessMode
 	return sess.Commit()
 }
 
+// ChangeCollaborationExpiry sets the date at which the collaboration is removed, 0 means never.
+func (repo *Repository) ChangeCollaborationExpiry(uid int64, expires timeutil.TimeStamp) error {
+	_, err := x.
+		Where("repo_id = ? AND user_id = ?", repo.ID, uid).
+		Cols("expires_unix").
+		Update(&Collaboration{ExpiresUnix: expires})
+	return err
+}
+
 // DeleteCollaboration removes collaboration relation between the user and repository.
 func (repo *Repository) DeleteCollaboration(uid int64) (err error) {
 	collaboration := &Collaboration{
@@ -271,3 +304,34 @@ func (repo *Repository) IsOwnerMemberCollaborator(userID int64) (bool, error) {
 
 	return x.Get(&Collaboration{RepoID: repo.ID, UserID: userID})
 }
+
+// DeleteExpiredCollaborations removes all collaborations whose expiry date has passed
+func DeleteExpiredCollaborations(ctx context.Context) error {
+	collaborations := make([]*Collaboration, 0, 10)
+	if err := x.
+		Where("expires_unix > 0 AND expires_unix <= ?", timeutil.TimeStampNow()).
+		Find(&collaborations); err != nil {
+		return err
+	}
+
+	for _, c := range collaborations {
+		select {
+		case <-ctx.Done():
+			return ErrCancelledf("before removing expired collaboration of user %d on repository %d", c.UserID, c.RepoID)
+		default:
+		}
+
+		repo, err := GetRepositoryByID(c.RepoID)
+		if err != nil {
+			return fmt.Errorf("GetRepositoryByID[%d]: %v", c.RepoID, err)
+		}
+		if err := repo.GetOwner(); err != nil {
+			return fmt.Errorf("GetOwner[%d]: %v", repo.OwnerID, err)
+		}
+		if err := repo.DeleteCollaboration(c.UserID); err != nil {
+			return fmt.Errorf("delete expired collaboration of user %d on %s: %v", c.UserID, repo.FullName(), err)
+		}
+		log.Trace("Removed expired collaboration of user %d on %s", c.UserID, repo.FullName())
+	}
+	return nil
+}
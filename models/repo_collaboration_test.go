@@ -5,8 +5,12 @@
 package models
 
 import (
+	"context"
 	"testing"
 
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -92,3 +96,50 @@ func TestRepository_DeleteCollaboration(t *testing.T) {
 
 	CheckConsistencyFor(t, &Repository{ID: repo.ID})
 }
+
+func TestDeleteExpiredCollaborations(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 4}).(*Repository)
+	assert.NoError(t, repo.ChangeCollaborationExpiry(4, timeutil.TimeStampNow().Add(-60)))
+	assert.NoError(t, repo.ChangeCollaborationExpiry(29, timeutil.TimeStampNow().Add(3600)))
+
+	collaboration := AssertExistsAndLoadBean(t, &Collaboration{RepoID: repo.ID, UserID: 4}).(*Collaboration)
+	assert.True(t, collaboration.IsExpired())
+
+	assert.NoError(t, DeleteExpiredCollaborations(context.Background()))
+	AssertNotExistsBean(t, &Collaboration{RepoID: repo.ID, UserID: 4})
+	AssertExistsAndLoadBean(t, &Collaboration{RepoID: repo.ID, UserID: 29})
+	AssertExistsAndLoadBean(t, &Collaboration{RepoID: 21, UserID: 15})
+
+	CheckConsistencyFor(t, &Repository{ID: repo.ID})
+}
+
+func TestExpiredCollaborationGrantsNoAccess(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 4}).(*Repository)
+	user := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	assert.NoError(t, repo.ChangeCollaborationExpiry(user.ID, timeutil.TimeStampNow().Add(3600)))
+
+	perm, err := GetUserRepoPermission(repo, user)
+	assert.NoError(t, err)
+	assert.True(t, perm.CanWrite(UnitTypeCode))
+
+	// the collaboration expires before the cron task deletes it
+	_, err = x.Exec("UPDATE collaboration SET expires_unix = ? WHERE repo_id = ? AND user_id = ?", timeutil.TimeStampNow().Add(-60), repo.ID, user.ID)
+	assert.NoError(t, err)
+
+	perm, err = GetUserRepoPermission(repo, user)
+	assert.NoError(t, err)
+	assert.False(t, perm.CanWrite(UnitTypeCode))
+	isCollaborator, err := repo.IsCollaborator(user.ID)
+	assert.NoError(t, err)
+	assert.False(t, isCollaborator)
+
+	repos, _, err := SearchRepository(&SearchRepoOptions{Actor: user, OwnerID: user.ID, Collaborate: util.OptionalBoolTrue, Private: true})
+	assert.NoError(t, err)
+	for _, r := range repos {
+		assert.NotEqual(t, repo.ID, r.ID)
+	}
+}
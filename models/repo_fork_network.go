@@ -0,0 +1,198 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"xorm.io/builder"
+)
+
+// ErrRepoNotFork represents a "RepoNotFork" kind of error.
+type ErrRepoNotFork struct {
+	RepoID int64
+}
+
+// IsErrRepoNotFork checks if an error is a ErrRepoNotFork.
+func IsErrRepoNotFork(err error) bool {
+	_, ok := err.(ErrRepoNotFork)
+	return ok
+}
+
+func (err ErrRepoNotFork) Error() string {
+	return fmt.Sprintf("repository is not a fork [id: %d]", err.RepoID)
+}
+
+// ForkNetworkNode is a repository of a fork network
+type ForkNetworkNode struct {
+	Repo *Repository
+	// ParentID is the id of the repository the repository was forked from, 0 for the root of the network
+	ParentID int64
+	// Depth is the number of forks between the root of the network and the repository
+	Depth int
+}
+
+func (repo *Repository) getForkNetworkRoot(e Engine) (*Repository, error) {
+	root := repo
+	visited := map[int64]bool{repo.ID: true}
+	for root.IsFork && root.ForkID > 0 && !visited[root.ForkID] {
+		parent, err := getRepositoryByID(e, root.ForkID)
+		if err != nil {
+			if IsErrRepoNotExist(err) {
+				break
+			}
+			return nil, err
+		}
+		visited[parent.ID] = true
+		root = parent
+	}
+	return root, nil
+}
+
+// GetForkNetworkRoot returns the repository the fork network of the repository starts with
+func (repo *Repository) GetForkNetworkRoot() (*Repository, error) {
+	return repo.getForkNetworkRoot(x)
+}
+
+// GetForkNetwork returns the repositories of the fork network of the repository, including forks of forks,
+// starting with the root of the network and each fork following the repository it was forked from.
+// Only the repositories the doer has access to are returned.
+func (repo *Repository) GetForkNetwork(doer *User) ([]*ForkNetworkNode, error) {
+	root, err := repo.getForkNetworkRoot(x)
+	if err != nil {
+		return nil, err
+	}
+
+	forksByParent := make(map[int64][]*Repository)
+	ids := []int64{root.ID}
+	parentIDs := []int64{root.ID}
+	for len(parentIDs) > 0 {
+		forks := make([]*Repository, 0, 10)
+		if err := x.In("fork_id", parentIDs).And(builder.NotIn("id", ids)).Asc("id").Find(&forks); err != nil {
+			return nil, err
+		}
+		parentIDs = make([]int64, 0, len(forks))
+		for _, fork := range forks {
+			forksByParent[fork.ForkID] = append(forksByParent[fork.ForkID], fork)
+			ids = append(ids, fork.ID)
+			parentIDs = append(parentIDs, fork.ID)
+		}
+	}
+
+	accessibleIDs := make([]int64, 0, len(ids))
+	if err := x.Table("repository").
+		Where(builder.In("id", ids).And(accessibleRepositoryCondition(doer))).
+		Cols("id").
+		Find(&accessibleIDs); err != nil {
+		return nil, err
+	}
+	accessible := make(map[int64]bool, len(accessibleIDs))
+	for _, id := range accessibleIDs {
+		accessible[id] = true
+	}
+
+	nodes := make([]*ForkNetworkNode, 0, len(accessibleIDs))
+	var walk func(r *Repository, parentID int64, depth int)
+	walk = func(r *Repository, parentID int64, depth int) {
+		if accessible[r.ID] {
+			nodes = append(nodes, &ForkNetworkNode{Repo: r, ParentID: parentID, Depth: depth})
+		}
+		for _, fork := range forksByParent[r.ID] {
+			walk(fork, r.ID, depth+1)
+		}
+	}
+	walk(root, 0, 0)
+
+	for _, node := range nodes {
+		if err := node.Repo.GetOwner(); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// DetachFork makes the fork a standalone repository, the forks of the fork remain its forks
+func DetachFork(repo *Repository) error {
+	if !repo.IsFork {
+		return ErrRepoNotFork{RepoID: repo.ID}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Exec("UPDATE `repository` SET num_forks=num_forks-1 WHERE id=? AND num_forks > 0", repo.ForkID); err != nil {
+		return err
+	}
+	repo.IsFork = false
+	repo.ForkID = 0
+	repo.BaseRepo = nil
+	if _, err := sess.ID(repo.ID).Cols("is_fork", "fork_id").Update(repo); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// ReRootForkNetwork replaces the repository in its fork network by one of its forks, e.g. to hand the
+// network over to a fork which is maintained instead. The other forks of the repository become forks
+// of the new root and the repository becomes a standalone repository. Deleting a repository hands its
+// network over to its oldest fork the same way.
+func ReRootForkNetwork(repo, newRoot *Repository) error {
+	if !newRoot.IsFork || newRoot.ForkID != repo.ID {
+		return ErrRepoNotFork{RepoID: newRoot.ID}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	if err := reRootForkNetwork(sess, repo, newRoot); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// reRootForkNetworkOnDelete makes the oldest fork of the repository, which is about to be
+// deleted, the new root of its forks
+func reRootForkNetworkOnDelete(e Engine, repo *Repository) error {
+	newRoot := new(Repository)
+	has, err := e.Where("fork_id=?", repo.ID).Asc("id").Get(newRoot)
+	if err != nil || !has {
+		return err
+	}
+	return reRootForkNetwork(e, repo, newRoot)
+}
+
+func reRootForkNetwork(e Engine, repo, newRoot *Repository) error {
+	if _, err := e.Exec("UPDATE `repository` SET fork_id=? WHERE fork_id=? AND id!=?", newRoot.ID, repo.ID, newRoot.ID); err != nil {
+		return err
+	}
+
+	numForks, err := e.Where("fork_id=?", newRoot.ID).Count(new(Repository))
+	if err != nil {
+		return err
+	}
+
+	// the new root takes the place of the repository in the network it was forked from
+	newRoot.IsFork = repo.IsFork
+	newRoot.ForkID = repo.ForkID
+	newRoot.BaseRepo = nil
+	newRoot.NumForks = int(numForks)
+	if _, err := e.ID(newRoot.ID).Cols("is_fork", "fork_id", "num_forks").Update(newRoot); err != nil {
+		return err
+	}
+
+	repo.IsFork = false
+	repo.ForkID = 0
+	repo.BaseRepo = nil
+	repo.NumForks = 0
+	if _, err := e.ID(repo.ID).Cols("is_fork", "fork_id", "num_forks").Update(repo); err != nil {
+		return err
+	}
+	return nil
+}
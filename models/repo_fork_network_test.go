@@ -0,0 +1,111 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func prepareForkNetwork(t *testing.T) (repo27, repo29, repo30 *Repository) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	// repo29 is a fork of repo27, make the private repo30 a fork of repo29 instead of repo28
+	repo27 = AssertExistsAndLoadBean(t, &Repository{ID: 27}).(*Repository)
+	repo28 := AssertExistsAndLoadBean(t, &Repository{ID: 28}).(*Repository)
+	repo29 = AssertExistsAndLoadBean(t, &Repository{ID: 29}).(*Repository)
+	repo30 = AssertExistsAndLoadBean(t, &Repository{ID: 30}).(*Repository)
+	repo30.ForkID = repo29.ID
+	repo29.NumForks = 1
+	repo28.NumForks = 0
+	assert.NoError(t, UpdateRepositoryCols(repo30, "fork_id"))
+	assert.NoError(t, UpdateRepositoryCols(repo29, "num_forks"))
+	assert.NoError(t, UpdateRepositoryCols(repo28, "num_forks"))
+	return
+}
+
+func forkNetworkIDs(t *testing.T, repo *Repository, doer *User) []int64 {
+	nodes, err := repo.GetForkNetwork(doer)
+	assert.NoError(t, err)
+	ids := make([]int64, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.Repo.ID)
+	}
+	return ids
+}
+
+func TestGetForkNetwork(t *testing.T) {
+	repo27, _, repo30 := prepareForkNetwork(t)
+	user20 := AssertExistsAndLoadBean(t, &User{ID: 20}).(*User)
+
+	root, err := repo30.GetForkNetworkRoot()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 27, root.ID)
+
+	nodes, err := repo27.GetForkNetwork(user20)
+	assert.NoError(t, err)
+	if assert.Len(t, nodes, 3) {
+		assert.EqualValues(t, 27, nodes[0].Repo.ID)
+		assert.EqualValues(t, 0, nodes[0].ParentID)
+		assert.EqualValues(t, 0, nodes[0].Depth)
+		assert.EqualValues(t, 29, nodes[1].Repo.ID)
+		assert.EqualValues(t, 27, nodes[1].ParentID)
+		assert.EqualValues(t, 1, nodes[1].Depth)
+		assert.EqualValues(t, 30, nodes[2].Repo.ID)
+		assert.EqualValues(t, 29, nodes[2].ParentID)
+		assert.EqualValues(t, 2, nodes[2].Depth)
+		assert.Equal(t, "user20", nodes[2].Repo.Owner.Name)
+	}
+
+	// the private fork is not visible to anonymous users
+	assert.Equal(t, []int64{27, 29}, forkNetworkIDs(t, repo30, nil))
+}
+
+func TestDetachFork(t *testing.T) {
+	repo27, repo29, repo30 := prepareForkNetwork(t)
+
+	assert.True(t, IsErrRepoNotFork(DetachFork(repo27)))
+	assert.NoError(t, DetachFork(repo29))
+
+	repo29 = AssertExistsAndLoadBean(t, &Repository{ID: 29}).(*Repository)
+	assert.False(t, repo29.IsFork)
+	assert.EqualValues(t, 0, repo29.ForkID)
+	assert.Equal(t, []int64{27}, forkNetworkIDs(t, repo27, nil))
+	assert.Equal(t, []int64{29, 30}, forkNetworkIDs(t, repo30, &User{ID: 20}))
+	CheckConsistencyFor(t, &Repository{})
+}
+
+func TestReRootForkNetwork(t *testing.T) {
+	repo27, repo29, repo30 := prepareForkNetwork(t)
+
+	assert.True(t, IsErrRepoNotFork(ReRootForkNetwork(repo27, repo30)))
+	assert.NoError(t, ReRootForkNetwork(repo29, repo30))
+
+	// repo30 took the place of repo29 in the network
+	repo30 = AssertExistsAndLoadBean(t, &Repository{ID: 30}).(*Repository)
+	assert.True(t, repo30.IsFork)
+	assert.EqualValues(t, 27, repo30.ForkID)
+	repo29 = AssertExistsAndLoadBean(t, &Repository{ID: 29}).(*Repository)
+	assert.False(t, repo29.IsFork)
+	assert.EqualValues(t, 0, repo29.NumForks)
+	assert.Equal(t, []int64{27, 30}, forkNetworkIDs(t, repo27, &User{ID: 20}))
+	CheckConsistencyFor(t, &Repository{})
+}
+
+func TestDeleteRepositoryReRootsForkNetwork(t *testing.T) {
+	repo27, repo29, _ := prepareForkNetwork(t)
+
+	assert.NoError(t, DeleteRepository(&User{ID: 1}, repo29.OwnerID, repo29.ID))
+
+	// the oldest fork of the deleted repository takes its place in the network
+	repo30 := AssertExistsAndLoadBean(t, &Repository{ID: 30}).(*Repository)
+	assert.True(t, repo30.IsFork)
+	assert.EqualValues(t, 27, repo30.ForkID)
+	repo27 = AssertExistsAndLoadBean(t, &Repository{ID: 27}).(*Repository)
+	assert.EqualValues(t, 1, repo27.NumForks)
+	assert.Equal(t, []int64{27, 30}, forkNetworkIDs(t, repo27, &User{ID: 20}))
+	CheckConsistencyFor(t, &Repository{})
+}
@@ -18,6 +18,8 @@ const (
 	RepoIndexerTypeCode RepoIndexerType = iota // 0
 	// RepoIndexerTypeStats repository stats indexer
 	RepoIndexerTypeStats // 1
+	// RepoIndexerTypeCodeFrequency repository code frequency indexer
+	RepoIndexerTypeCodeFrequency // 2
 )
 
 // RepoIndexerStatus status of a repo's entry in the repo indexer
@@ -4,7 +4,10 @@
 
 package models
 
-import "code.gitea.io/gitea/modules/setting"
+import (
+	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/setting"
+)
 
 // ___________.__             ___________                     __
 // \__    ___/|__| _____   ___\__    ___/___________    ____ |  | __ ___________
@@ -42,3 +45,35 @@ func (repo *Repository) AllowOnlyContributorsToTrackTime() bool {
 	}
 	return u.IssuesConfig().AllowOnlyContributorsToTrackTime
 }
+
+// IssuesConfig returns the config of the issue tracker or the default config if the issue tracker is disabled
+func (repo *Repository) IssuesConfig() *IssuesConfig {
+	u, err := repo.GetUnit(UnitTypeIssues)
+	if err != nil {
+		return &IssuesConfig{
+			EnableTimetracker:                setting.Service.DefaultEnableTimetracking,
+			AllowOnlyContributorsToTrackTime: setting.Service.DefaultAllowOnlyContributorsToTrackTime,
+			EnableDependencies:               setting.Service.DefaultEnableDependencies,
+		}
+	}
+	return u.IssuesConfig()
+}
+
+// IssueKeywords returns the keywords closing and reopening the issues referenced in the repository,
+// nil for the keywords of the settings
+func (repo *Repository) IssueKeywords() *references.IssueKeywords {
+	return repo.issueKeywords(x)
+}
+
+func (repo *Repository) issueKeywords(e Engine) *references.IssueKeywords {
+	u, err := repo.getUnit(e, UnitTypeIssues)
+	if err != nil {
+		return nil
+	}
+	return u.IssuesConfig().IssueKeywords()
+}
+
+// IssueCloseTrigger returns which events close and reopen the issues referenced with keywords in the repository
+func (repo *Repository) IssueCloseTrigger() IssueCloseTrigger {
+	return repo.IssuesConfig().CloseTrigger
+}
@@ -177,6 +177,8 @@ type SearchRepoOptions struct {
 	HasMilestones util.OptionalBool
 	// LowerNames represents valid lower names to restrict to
 	LowerNames []string
+	// License restricts to repositories with this detected SPDX license identifier
+	License string
 }
 
 //SearchOrderBy is used to sort the result
@@ -257,7 +259,11 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 					builder.In("`repository`.id",
 						builder.Select("`access`.repo_id").
 							From("access").
-							Where(builder.Eq{"`access`.user_id": opts.OwnerID})),
+							Where(builder.Eq{"`access`.user_id": opts.OwnerID})).
+						And(builder.NotIn("`repository`.id",
+							builder.Select("`collaboration`.repo_id").
+								From("collaboration").
+								Where(builder.Eq{"`collaboration`.user_id": opts.OwnerID}.And(expiredCollaborationCond())))),
 					// B. We are in a team for
 					builder.In("`repository`.id", builder.Select("`team_repo`.repo_id").
 						From("team_repo").
@@ -339,6 +345,10 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 		cond = cond.And(builder.Eq{"is_archived": opts.Archived == util.OptionalBoolTrue})
 	}
 
+	if opts.License != "" {
+		cond = cond.And(builder.Eq{"license": opts.License})
+	}
+
 	switch opts.HasMilestones {
 	case util.OptionalBoolTrue:
 		cond = cond.And(builder.Gt{"num_milestones": 0})
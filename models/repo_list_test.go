@@ -293,3 +293,33 @@ func TestSearchRepositoryByTopicName(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchRepositoryByLicense(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	repo.License = "MIT"
+	assert.NoError(t, UpdateRepositoryCols(repo, "license"))
+
+	repos, count, err := SearchRepository(&SearchRepoOptions{
+		ListOptions: ListOptions{Page: 1, PageSize: 10},
+		AllPublic:   true,
+		License:     "MIT",
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 1, repos[0].ID)
+	}
+
+	user := AssertExistsAndLoadBean(t, &User{ID: repo.OwnerID}).(*User)
+	repos, count, err = GetUserRepositories(&SearchRepoOptions{
+		ListOptions: ListOptions{Page: 1, PageSize: 10},
+		Actor:       user,
+		Private:     true,
+		License:     "Apache-2.0",
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	assert.Len(t, repos, 0)
+}
@@ -21,6 +21,8 @@ type Mirror struct {
 	Repo        *Repository `xorm:"-"`
 	Interval    time.Duration
 	EnablePrune bool `xorm:"NOT NULL DEFAULT true"`
+	// EnableReleaseAssets mirrors the releases and release assets of the upstream repository
+	EnableReleaseAssets bool `xorm:"NOT NULL DEFAULT false"`
 
 	UpdatedUnix    timeutil.TimeStamp `xorm:"INDEX"`
 	NextUpdateUnix timeutil.TimeStamp `xorm:"INDEX"`
@@ -201,6 +201,31 @@ func getUserRepoPermission(e Engine, repo *Repository, user *User) (perm Permiss
 		return
 	}
 
+	// Members who did not enroll in two-factor authentication required by the organization
+	// lose the access granted by their teams and only keep the access of non-members,
+	// including the access they have as collaborator of the repository
+	if repo.Owner.IsOrganization() {
+		var blocked bool
+		if blocked, err = repo.Owner.isBlockedByTwoFactor(e, user); err != nil {
+			return
+		} else if blocked {
+			if repo.IsPrivate || user.IsRestricted {
+				perm.AccessMode = AccessModeNone
+			} else {
+				perm.AccessMode = AccessModeRead
+			}
+			if isCollaborator {
+				var collaboration *Collaboration
+				if collaboration, err = repo.getCollaboration(e, user.ID); err != nil {
+					return
+				} else if collaboration != nil && collaboration.Mode > perm.AccessMode {
+					perm.AccessMode = collaboration.Mode
+				}
+			}
+			return
+		}
+	}
+
 	// plain user
 	perm.AccessMode, err = accessLevel(e, user, repo)
 	if err != nil {
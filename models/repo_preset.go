@@ -0,0 +1,365 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RepoPresetWebhook is a webhook created in the repositories created from a preset
+type RepoPresetWebhook struct {
+	URL            string
+	ContentType    HookContentType
+	Secret         string
+	SendEverything bool
+}
+
+// RepoPreset is a set of settings of an organization applied to new repositories of the organization.
+// A preset of a team can only be used by the members of the team.
+type RepoPreset struct {
+	ID                   int64                `xorm:"pk autoincr"`
+	OrgID                int64                `xorm:"UNIQUE(s) NOT NULL"`
+	TeamID               int64                `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Team                 *Team                `xorm:"-"`
+	Name                 string               `xorm:"UNIQUE(s) NOT NULL"`
+	Description          string               `xorm:"TEXT"`
+	Units                []UnitType           `xorm:"JSON TEXT"`
+	IssueLabels          string               `xorm:"NOT NULL DEFAULT ''"`
+	Topics               []string             `xorm:"JSON TEXT"`
+	ProtectDefaultBranch bool                 `xorm:"NOT NULL DEFAULT false"`
+	RequiredApprovals    int64                `xorm:"NOT NULL DEFAULT 0"`
+	StatusCheckContexts  []string             `xorm:"JSON TEXT"`
+	Webhooks             []*RepoPresetWebhook `xorm:"JSON TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// ErrRepoPresetNotExist represents a "RepoPresetNotExist" kind of error.
+type ErrRepoPresetNotExist struct {
+	ID    int64
+	OrgID int64
+}
+
+// IsErrRepoPresetNotExist checks if an error is a ErrRepoPresetNotExist.
+func IsErrRepoPresetNotExist(err error) bool {
+	_, ok := err.(ErrRepoPresetNotExist)
+	return ok
+}
+
+func (err ErrRepoPresetNotExist) Error() string {
+	return fmt.Sprintf("repository preset does not exist [id: %d, org_id: %d]", err.ID, err.OrgID)
+}
+
+// ErrRepoPresetAlreadyExist represents a "RepoPresetAlreadyExist" kind of error.
+type ErrRepoPresetAlreadyExist struct {
+	OrgID int64
+	Name  string
+}
+
+// IsErrRepoPresetAlreadyExist checks if an error is a ErrRepoPresetAlreadyExist.
+func IsErrRepoPresetAlreadyExist(err error) bool {
+	_, ok := err.(ErrRepoPresetAlreadyExist)
+	return ok
+}
+
+func (err ErrRepoPresetAlreadyExist) Error() string {
+	return fmt.Sprintf("repository preset already exists [org_id: %d, name: %s]", err.OrgID, err.Name)
+}
+
+// ErrInvalidRepoPreset represents an error for a preset with invalid settings
+type ErrInvalidRepoPreset struct {
+	Reason string
+}
+
+// IsErrInvalidRepoPreset checks if an error is a ErrInvalidRepoPreset.
+func IsErrInvalidRepoPreset(err error) bool {
+	_, ok := err.(ErrInvalidRepoPreset)
+	return ok
+}
+
+func (err ErrInvalidRepoPreset) Error() string {
+	return fmt.Sprintf("invalid repository preset: %s", err.Reason)
+}
+
+// LoadTeam loads the team of the preset, if it is restricted to a team
+func (p *RepoPreset) LoadTeam() (err error) {
+	if p.TeamID == 0 || p.Team != nil {
+		return nil
+	}
+	p.Team, err = GetTeamByID(p.TeamID)
+	return err
+}
+
+// HasUnit returns true if the repositories created from the preset get the unit
+func (p *RepoPreset) HasUnit(tp UnitType) bool {
+	if len(p.Units) == 0 || !tp.CanDisable() {
+		return true
+	}
+	for _, u := range p.Units {
+		if u == tp {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the settings of the preset and normalizes its topics
+func (p *RepoPreset) Validate() error {
+	if len(strings.TrimSpace(p.Name)) == 0 {
+		return ErrInvalidRepoPreset{"name is empty"}
+	}
+	for _, u := range p.Units {
+		found := false
+		for _, tp := range DefaultRepoUnits {
+			if u == tp {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrInvalidRepoPreset{fmt.Sprintf("unit %d can not be enabled by a preset", u)}
+		}
+	}
+
+	if len(p.IssueLabels) > 0 {
+		if _, err := GetLabelTemplateFile(p.IssueLabels); err != nil {
+			return ErrInvalidRepoPreset{fmt.Sprintf("unknown label template: %s", p.IssueLabels)}
+		}
+	}
+
+	validTopics, invalidTopics := SanitizeAndValidateTopics(p.Topics)
+	if len(invalidTopics) > 0 {
+		return ErrInvalidRepoPreset{fmt.Sprintf("invalid topics: %s", strings.Join(invalidTopics, ", "))}
+	}
+	if len(validTopics) > 25 {
+		return ErrInvalidRepoPreset{"more than 25 topics"}
+	}
+	p.Topics = validTopics
+
+	if p.RequiredApprovals < 0 {
+		return ErrInvalidRepoPreset{"required approvals is negative"}
+	}
+	for _, w := range p.Webhooks {
+		if !strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://") {
+			return ErrInvalidRepoPreset{fmt.Sprintf("invalid webhook URL: %s", w.URL)}
+		}
+		if w.ContentType != ContentTypeJSON && w.ContentType != ContentTypeForm {
+			w.ContentType = ContentTypeJSON
+		}
+	}
+
+	if p.TeamID > 0 {
+		if err := p.LoadTeam(); err != nil {
+			if IsErrTeamNotExist(err) {
+				return ErrInvalidRepoPreset{fmt.Sprintf("team %d does not exist", p.TeamID)}
+			}
+			return err
+		}
+		if p.Team.OrgID != p.OrgID {
+			return ErrInvalidRepoPreset{fmt.Sprintf("team %d does not belong to the organization", p.TeamID)}
+		}
+	}
+	return nil
+}
+
+// IsUsableBy returns true if the user may create repositories of the organization from the preset
+func (p *RepoPreset) IsUsableBy(user *User) (bool, error) {
+	if user.IsAdmin {
+		return true, nil
+	}
+	if isOwner, err := IsOrganizationOwner(p.OrgID, user.ID); err != nil || isOwner {
+		return isOwner, err
+	}
+	if canCreate, err := CanCreateOrgRepo(p.OrgID, user.ID); err != nil || !canCreate {
+		return false, err
+	}
+	if p.TeamID == 0 {
+		return true, nil
+	}
+	return IsTeamMember(p.OrgID, p.TeamID, user.ID)
+}
+
+func isRepoPresetNameExist(e Engine, orgID, presetID int64, name string) (bool, error) {
+	return e.
+		Where("org_id = ? AND id != ?", orgID, presetID).
+		And("lower(name) = ?", strings.ToLower(name)).
+		Exist(new(RepoPreset))
+}
+
+// CreateRepoPreset creates a new repository preset of an organization
+func CreateRepoPreset(p *RepoPreset) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if exist, err := isRepoPresetNameExist(sess, p.OrgID, 0, p.Name); err != nil {
+		return err
+	} else if exist {
+		return ErrRepoPresetAlreadyExist{p.OrgID, p.Name}
+	}
+	if _, err := sess.Insert(p); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// UpdateRepoPreset updates the settings of a repository preset
+func UpdateRepoPreset(p *RepoPreset) error {
+	p.Team = nil
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if exist, err := isRepoPresetNameExist(sess, p.OrgID, p.ID, p.Name); err != nil {
+		return err
+	} else if exist {
+		return ErrRepoPresetAlreadyExist{p.OrgID, p.Name}
+	}
+	if _, err := sess.ID(p.ID).AllCols().Update(p); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// DeleteRepoPreset deletes a repository preset of an organization
+func DeleteRepoPreset(orgID, id int64) error {
+	n, err := x.Delete(&RepoPreset{ID: id, OrgID: orgID})
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrRepoPresetNotExist{id, orgID}
+	}
+	return nil
+}
+
+// GetRepoPresetByID returns the repository preset of an organization by its ID
+func GetRepoPresetByID(orgID, id int64) (*RepoPreset, error) {
+	p := &RepoPreset{ID: id, OrgID: orgID}
+	has, err := x.Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoPresetNotExist{id, orgID}
+	}
+	return p, nil
+}
+
+// GetRepoPresets returns all repository presets of an organization
+func (org *User) GetRepoPresets() ([]*RepoPreset, error) {
+	presets := make([]*RepoPreset, 0, 5)
+	return presets, x.Where("org_id = ?", org.ID).Asc("name").Find(&presets)
+}
+
+// GetUsableRepoPresets returns the repository presets of an organization the user may create repositories from
+func (org *User) GetUsableRepoPresets(user *User) ([]*RepoPreset, error) {
+	if user.IsAdmin {
+		return org.GetRepoPresets()
+	}
+	if isOwner, err := org.IsOwnedBy(user.ID); err != nil {
+		return nil, err
+	} else if isOwner {
+		return org.GetRepoPresets()
+	}
+	if canCreate, err := org.CanCreateOrgRepo(user.ID); err != nil {
+		return nil, err
+	} else if !canCreate {
+		return []*RepoPreset{}, nil
+	}
+
+	teamIDs, err := org.GetUserTeamIDs(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	presets := make([]*RepoPreset, 0, 5)
+	return presets, x.Where("org_id = ?", org.ID).
+		And(builder.Eq{"team_id": 0}.Or(builder.In("team_id", teamIDs))).
+		Asc("name").
+		Find(&presets)
+}
+
+// ApplyRepoPreset applies the units, topics, branch protection and webhooks of a preset
+// to a newly created repository. The issue labels of the preset are initialized by the caller.
+func ApplyRepoPreset(ctx DBContext, repo *Repository, p *RepoPreset) error {
+	e := ctx.e
+
+	deleteUnitTypes := make([]UnitType, 0, len(DefaultRepoUnits))
+	for _, tp := range DefaultRepoUnits {
+		if !p.HasUnit(tp) {
+			deleteUnitTypes = append(deleteUnitTypes, tp)
+		}
+	}
+	if len(deleteUnitTypes) > 0 {
+		if _, err := e.Where("repo_id = ?", repo.ID).In("type", deleteUnitTypes).Delete(new(RepoUnit)); err != nil {
+			return fmt.Errorf("delete units: %v", err)
+		}
+		repo.Units = nil
+	}
+
+	if len(p.Topics) > 0 {
+		for _, name := range p.Topics {
+			if _, err := addTopicByNameToRepo(e, repo.ID, name); err != nil {
+				return fmt.Errorf("addTopicByNameToRepo: %v", err)
+			}
+		}
+		repo.Topics = p.Topics
+		if _, err := e.ID(repo.ID).Cols("topics").Update(repo); err != nil {
+			return fmt.Errorf("update topics: %v", err)
+		}
+	}
+
+	if p.ProtectDefaultBranch {
+		protectBranch := &ProtectedBranch{
+			RepoID:              repo.ID,
+			BranchName:          repo.DefaultBranch,
+			RequiredApprovals:   p.RequiredApprovals,
+			EnableStatusCheck:   len(p.StatusCheckContexts) > 0,
+			StatusCheckContexts: p.StatusCheckContexts,
+		}
+		if _, err := e.Insert(protectBranch); err != nil {
+			return fmt.Errorf("insert protected branch: %v", err)
+		}
+	}
+
+	for _, pw := range p.Webhooks {
+		w := &Webhook{
+			RepoID:      repo.ID,
+			URL:         pw.URL,
+			HTTPMethod:  "POST",
+			ContentType: pw.ContentType,
+			Secret:      pw.Secret,
+			HookEvent: &HookEvent{
+				PushOnly:       !pw.SendEverything,
+				SendEverything: pw.SendEverything,
+			},
+			IsActive:     true,
+			HookTaskType: GITEA,
+		}
+		if err := w.UpdateEvent(); err != nil {
+			return fmt.Errorf("UpdateEvent: %v", err)
+		}
+		if err := createWebhook(e, w); err != nil {
+			return fmt.Errorf("createWebhook: %v", err)
+		}
+	}
+	return nil
+}
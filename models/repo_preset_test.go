@@ -0,0 +1,119 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRepoPreset(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	preset := &RepoPreset{OrgID: 3, Name: "Service", Topics: []string{"Go", "go", "backend"}}
+	assert.NoError(t, CreateRepoPreset(preset))
+	assert.EqualValues(t, []string{"go", "backend"}, preset.Topics)
+	AssertExistsAndLoadBean(t, &RepoPreset{ID: preset.ID, OrgID: 3})
+
+	err := CreateRepoPreset(&RepoPreset{OrgID: 3, Name: "service"})
+	assert.True(t, IsErrRepoPresetAlreadyExist(err))
+
+	err = CreateRepoPreset(&RepoPreset{OrgID: 3, Name: "Wiki", Units: []UnitType{UnitTypeExternalWiki}})
+	assert.True(t, IsErrInvalidRepoPreset(err))
+
+	err = CreateRepoPreset(&RepoPreset{OrgID: 3, Name: "Topics", Topics: []string{"not a topic"}})
+	assert.True(t, IsErrInvalidRepoPreset(err))
+
+	err = CreateRepoPreset(&RepoPreset{OrgID: 3, Name: "Labels", IssueLabels: "NotALabelTemplate"})
+	assert.True(t, IsErrInvalidRepoPreset(err))
+
+	// team 3 belongs to organization 6
+	err = CreateRepoPreset(&RepoPreset{OrgID: 3, TeamID: 3, Name: "Team"})
+	assert.True(t, IsErrInvalidRepoPreset(err))
+
+	preset.Name = "Services"
+	preset.TeamID = 12
+	assert.NoError(t, UpdateRepoPreset(preset))
+	AssertExistsAndLoadBean(t, &RepoPreset{ID: preset.ID, Name: "Services", TeamID: 12})
+
+	assert.NoError(t, DeleteRepoPreset(3, preset.ID))
+	AssertNotExistsBean(t, &RepoPreset{ID: preset.ID})
+	assert.True(t, IsErrRepoPresetNotExist(DeleteRepoPreset(3, preset.ID)))
+}
+
+func TestGetUsableRepoPresets(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	assert.NoError(t, CreateRepoPreset(&RepoPreset{OrgID: 3, Name: "Everyone"}))
+	assert.NoError(t, CreateRepoPreset(&RepoPreset{OrgID: 3, TeamID: 12, Name: "Creators"}))
+	assert.NoError(t, CreateRepoPreset(&RepoPreset{OrgID: 3, TeamID: 2, Name: "Team1"}))
+
+	test := func(userID int64, expected ...string) {
+		user := AssertExistsAndLoadBean(t, &User{ID: userID}).(*User)
+		presets, err := org.GetUsableRepoPresets(user)
+		assert.NoError(t, err)
+		var names []string
+		for _, preset := range presets {
+			names = append(names, preset.Name)
+
+			usable, err := preset.IsUsableBy(user)
+			assert.NoError(t, err)
+			assert.True(t, usable)
+		}
+		assert.EqualValues(t, expected, names)
+	}
+
+	// owner
+	test(2, "Creators", "Everyone", "Team1")
+	// member of team12Creators which can create repositories
+	test(28, "Creators", "Everyone")
+	// member of team1 which can not create repositories
+	test(4)
+}
+
+func TestApplyRepoPreset(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	preset := &RepoPreset{
+		OrgID:                3,
+		Name:                 "Service",
+		Units:                []UnitType{UnitTypeIssues, UnitTypePullRequests},
+		Topics:               []string{"backend"},
+		ProtectDefaultBranch: true,
+		RequiredApprovals:    2,
+		StatusCheckContexts:  []string{"ci/build"},
+		Webhooks: []*RepoPresetWebhook{{
+			URL:         "https://example.com/hook",
+			ContentType: ContentTypeJSON,
+		}},
+	}
+	assert.NoError(t, CreateRepoPreset(preset))
+
+	assert.NoError(t, WithTx(func(ctx DBContext) error {
+		return ApplyRepoPreset(ctx, repo, preset)
+	}))
+
+	AssertExistsAndLoadBean(t, &RepoUnit{RepoID: repo.ID, Type: UnitTypeCode})
+	AssertExistsAndLoadBean(t, &RepoUnit{RepoID: repo.ID, Type: UnitTypeIssues})
+	AssertNotExistsBean(t, &RepoUnit{RepoID: repo.ID, Type: UnitTypeWiki})
+	AssertNotExistsBean(t, &RepoUnit{RepoID: repo.ID, Type: UnitTypeProjects})
+
+	topics, err := FindTopics(&FindTopicOptions{RepoID: repo.ID})
+	assert.NoError(t, err)
+	assert.Len(t, topics, 1)
+	assert.EqualValues(t, "backend", topics[0].Name)
+
+	protectBranch := AssertExistsAndLoadBean(t, &ProtectedBranch{RepoID: repo.ID, BranchName: repo.DefaultBranch}).(*ProtectedBranch)
+	assert.EqualValues(t, 2, protectBranch.RequiredApprovals)
+	assert.True(t, protectBranch.EnableStatusCheck)
+	assert.EqualValues(t, []string{"ci/build"}, protectBranch.StatusCheckContexts)
+
+	hook := AssertExistsAndLoadBean(t, &Webhook{RepoID: repo.ID, URL: "https://example.com/hook"}).(*Webhook)
+	assert.True(t, hook.IsActive)
+	assert.True(t, hook.PushOnly)
+}
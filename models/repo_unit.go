@@ -7,6 +7,8 @@ package models
 import (
 	"encoding/json"
 
+	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/unknwon/com"
@@ -69,11 +71,70 @@ func (cfg *ExternalTrackerConfig) ToDB() ([]byte, error) {
 	return json.Marshal(cfg)
 }
 
+// IssueCloseTrigger decides which events close and reopen the issues referenced with keywords
+type IssueCloseTrigger string
+
+// Events closing and reopening issues
+const (
+	// IssueCloseTriggerAll closes issues on push to the default branch and on merge of pull requests
+	IssueCloseTriggerAll IssueCloseTrigger = ""
+	// IssueCloseTriggerPush only closes issues referenced by pushed commits
+	IssueCloseTriggerPush IssueCloseTrigger = "push"
+	// IssueCloseTriggerMerge only closes issues referenced by pull requests when they are merged into any branch
+	IssueCloseTriggerMerge IssueCloseTrigger = "merge"
+)
+
+// IsValid returns whether the trigger is known
+func (t IssueCloseTrigger) IsValid() bool {
+	return t == IssueCloseTriggerAll || t == IssueCloseTriggerPush || t == IssueCloseTriggerMerge
+}
+
+// OnPush returns whether pushed commits close and reopen issues
+func (t IssueCloseTrigger) OnPush() bool {
+	return t != IssueCloseTriggerMerge
+}
+
+// OnMerge returns whether merged pull requests close and reopen issues
+func (t IssueCloseTrigger) OnMerge() bool {
+	return t != IssueCloseTriggerPush
+}
+
 // IssuesConfig describes issues config
 type IssuesConfig struct {
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableDependencies               bool
+	// DisableCloseKeywords disables closing and reopening issues with keywords
+	DisableCloseKeywords bool
+	// CloseKeywords and ReopenKeywords replace the keywords of the settings if not empty
+	CloseKeywords  []string
+	ReopenKeywords []string
+	CloseTrigger   IssueCloseTrigger
+}
+
+// Keywords returns the keywords closing and reopening issues and whether they differ from the keywords of the settings
+func (cfg *IssuesConfig) Keywords() (closeKeywords, reopenKeywords []string, custom bool) {
+	if cfg.DisableCloseKeywords {
+		return nil, nil, true
+	}
+	closeKeywords, reopenKeywords = cfg.CloseKeywords, cfg.ReopenKeywords
+	custom = len(closeKeywords) > 0 || len(reopenKeywords) > 0
+	if len(closeKeywords) == 0 {
+		closeKeywords = setting.Repository.PullRequest.CloseKeywords
+	}
+	if len(reopenKeywords) == 0 {
+		reopenKeywords = setting.Repository.PullRequest.ReopenKeywords
+	}
+	return closeKeywords, reopenKeywords, custom
+}
+
+// IssueKeywords returns the keywords closing and reopening issues, nil for the keywords of the settings
+func (cfg *IssuesConfig) IssueKeywords() *references.IssueKeywords {
+	closeKeywords, reopenKeywords, custom := cfg.Keywords()
+	if !custom {
+		return nil
+	}
+	return references.NewIssueKeywords(closeKeywords, reopenKeywords)
 }
 
 // FromDB fills up a IssuesConfig from serialized format.
@@ -0,0 +1,180 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ScheduledPost is an issue or a comment which is posted at a future time,
+// e.g. a release announcement. It is published by the publish_scheduled_posts cron task.
+type ScheduledPost struct {
+	ID       int64       `xorm:"pk autoincr"`
+	RepoID   int64       `xorm:"INDEX NOT NULL"`
+	Repo     *Repository `xorm:"-"`
+	PosterID int64       `xorm:"INDEX NOT NULL"`
+	Poster   *User       `xorm:"-"`
+	// IssueID is the issue the comment is posted to, 0 if a new issue is posted
+	IssueID int64  `xorm:"INDEX"`
+	Issue   *Issue `xorm:"-"`
+
+	// Title and the metadata are only used by new issues
+	Title       string `xorm:"name"`
+	Content     string `xorm:"LONGTEXT"`
+	Ref         string
+	MilestoneID int64
+	ProjectID   int64
+	LabelIDs    []int64 `xorm:"TEXT JSON"`
+	AssigneeIDs []int64 `xorm:"TEXT JSON"`
+
+	PostUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+
+	// IsPublishing is set once the post has been picked up by the cron task, it is never published twice
+	IsPublishing bool `xorm:"NOT NULL DEFAULT false"`
+	// PublishError is the reason the post could not be published, the post is kept for the poster to see
+	PublishError string `xorm:"TEXT"`
+}
+
+// IsComment returns whether a comment is posted
+func (p *ScheduledPost) IsComment() bool {
+	return p.IssueID > 0
+}
+
+// ErrScheduledPostNotExist represents a "ScheduledPostNotExist" kind of error.
+type ErrScheduledPostNotExist struct {
+	ID int64
+}
+
+// IsErrScheduledPostNotExist checks if an error is a ErrScheduledPostNotExist.
+func IsErrScheduledPostNotExist(err error) bool {
+	_, ok := err.(ErrScheduledPostNotExist)
+	return ok
+}
+
+func (err ErrScheduledPostNotExist) Error() string {
+	return fmt.Sprintf("scheduled post does not exist [id: %d]", err.ID)
+}
+
+// ErrScheduledPostInPast represents a "ScheduledPostInPast" kind of error.
+type ErrScheduledPostInPast struct {
+	PostUnix timeutil.TimeStamp
+}
+
+// IsErrScheduledPostInPast checks if an error is a ErrScheduledPostInPast.
+func IsErrScheduledPostInPast(err error) bool {
+	_, ok := err.(ErrScheduledPostInPast)
+	return ok
+}
+
+func (err ErrScheduledPostInPast) Error() string {
+	return fmt.Sprintf("scheduled post is not in the future [post_unix: %d]", err.PostUnix)
+}
+
+// LoadAttributes loads the repository, the poster and the issue of the scheduled post
+func (p *ScheduledPost) LoadAttributes() error {
+	var err error
+	if p.Repo == nil {
+		if p.Repo, err = GetRepositoryByID(p.RepoID); err != nil {
+			return err
+		}
+	}
+	if p.Poster == nil {
+		if p.Poster, err = GetUserByID(p.PosterID); err != nil {
+			return err
+		}
+	}
+	if p.IsComment() && p.Issue == nil {
+		if p.Issue, err = GetIssueByID(p.IssueID); err != nil {
+			return err
+		}
+		p.Issue.Repo = p.Repo
+	}
+	return nil
+}
+
+// CreateScheduledPost schedules the issue or comment, it must be posted in the future
+func CreateScheduledPost(p *ScheduledPost) error {
+	if p.PostUnix <= timeutil.TimeStampNow() {
+		return ErrScheduledPostInPast{p.PostUnix}
+	}
+	_, err := x.Insert(p)
+	return err
+}
+
+// GetScheduledPostByID returns the scheduled post by its id
+func GetScheduledPostByID(id int64) (*ScheduledPost, error) {
+	p := new(ScheduledPost)
+	has, err := x.ID(id).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrScheduledPostNotExist{id}
+	}
+	return p, nil
+}
+
+// FindScheduledPostsOptions represents the options to find scheduled posts
+type FindScheduledPostsOptions struct {
+	RepoID   int64
+	PosterID int64
+	IssueID  int64
+}
+
+func (opts FindScheduledPostsOptions) toCond() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.PosterID > 0 {
+		cond = cond.And(builder.Eq{"poster_id": opts.PosterID})
+	}
+	if opts.IssueID > 0 {
+		cond = cond.And(builder.Eq{"issue_id": opts.IssueID})
+	}
+	return cond
+}
+
+// FindScheduledPosts returns the scheduled posts, the next one to be posted first
+func FindScheduledPosts(opts FindScheduledPostsOptions) ([]*ScheduledPost, error) {
+	posts := make([]*ScheduledPost, 0, 5)
+	return posts, x.Where(opts.toCond()).Asc("post_unix", "id").Find(&posts)
+}
+
+// CountScheduledPosts returns the number of scheduled posts
+func CountScheduledPosts(opts FindScheduledPostsOptions) (int64, error) {
+	return x.Where(opts.toCond()).Count(new(ScheduledPost))
+}
+
+// GetDueScheduledPosts returns the scheduled posts which have to be posted now and are not being published yet, the oldest first
+func GetDueScheduledPosts() ([]*ScheduledPost, error) {
+	posts := make([]*ScheduledPost, 0, 5)
+	return posts, x.Where("post_unix <= ? AND is_publishing = ?", timeutil.TimeStampNow(), false).Asc("post_unix", "id").Find(&posts)
+}
+
+// ClaimScheduledPost marks the scheduled post as being published, it returns false
+// if the post was claimed already or deleted in the meantime.
+func ClaimScheduledPost(p *ScheduledPost) (bool, error) {
+	p.IsPublishing = true
+	affected, err := x.ID(p.ID).Where("is_publishing = ?", false).Cols("is_publishing").Update(p)
+	return affected == 1, err
+}
+
+// SetScheduledPostPublishError records why the claimed scheduled post could not be published
+func SetScheduledPostPublishError(p *ScheduledPost, publishErr error) error {
+	p.PublishError = publishErr.Error()
+	_, err := x.ID(p.ID).Cols("publish_error").Update(p)
+	return err
+}
+
+// DeleteScheduledPost deletes the scheduled post
+func DeleteScheduledPost(p *ScheduledPost) error {
+	_, err := x.ID(p.ID).Delete(new(ScheduledPost))
+	return err
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateScheduledPost(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	p := &ScheduledPost{
+		RepoID:   1,
+		PosterID: 2,
+		IssueID:  1,
+		Content:  "announcement",
+		PostUnix: timeutil.TimeStampNow().Add(3600),
+	}
+	assert.NoError(t, CreateScheduledPost(p))
+	AssertExistsAndLoadBean(t, &ScheduledPost{ID: p.ID, IssueID: 1})
+
+	err := CreateScheduledPost(&ScheduledPost{
+		RepoID:   1,
+		PosterID: 2,
+		Content:  "too late",
+		PostUnix: timeutil.TimeStampNow().Add(-60),
+	})
+	assert.True(t, IsErrScheduledPostInPast(err))
+}
+
+func TestFindScheduledPosts(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	posts, err := FindScheduledPosts(FindScheduledPostsOptions{RepoID: 1, PosterID: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, posts, 3) {
+		// the next one to be posted first
+		assert.EqualValues(t, 1, posts[0].ID)
+		assert.EqualValues(t, 3, posts[2].ID)
+	}
+
+	count, err := CountScheduledPosts(FindScheduledPostsOptions{IssueID: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	p, err := GetScheduledPostByID(1)
+	assert.NoError(t, err)
+	assert.False(t, p.IsComment())
+	assert.EqualValues(t, []int64{1}, p.LabelIDs)
+	assert.NoError(t, p.LoadAttributes())
+	assert.EqualValues(t, 2, p.Poster.ID)
+
+	_, err = GetScheduledPostByID(NonexistentID)
+	assert.True(t, IsErrScheduledPostNotExist(err))
+}
+
+func TestGetDueScheduledPosts(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	posts, err := GetDueScheduledPosts()
+	assert.NoError(t, err)
+	if assert.Len(t, posts, 3) {
+		assert.EqualValues(t, 1, posts[0].ID)
+		assert.EqualValues(t, 2, posts[1].ID)
+		assert.EqualValues(t, 4, posts[2].ID)
+	}
+
+	assert.NoError(t, DeleteScheduledPost(posts[0]))
+	AssertNotExistsBean(t, &ScheduledPost{ID: 1})
+
+	// a claimed post is neither due nor claimed again
+	ok, err := ClaimScheduledPost(posts[1])
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = ClaimScheduledPost(&ScheduledPost{ID: posts[1].ID})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	posts, err = GetDueScheduledPosts()
+	assert.NoError(t, err)
+	if assert.Len(t, posts, 1) {
+		assert.EqualValues(t, 4, posts[0].ID)
+	}
+
+	assert.NoError(t, SetScheduledPostPublishError(&ScheduledPost{ID: 2}, fmt.Errorf("failure")))
+	AssertExistsAndLoadBean(t, &ScheduledPost{ID: 2, IsPublishing: true, PublishError: "failure"})
+}
@@ -118,7 +118,13 @@ func UpdateAccessToken(t *AccessToken) error {
 
 // DeleteAccessTokenByID deletes access token by given ID.
 func DeleteAccessTokenByID(id, userID int64) error {
-	cnt, err := x.ID(id).Delete(&AccessToken{
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	cnt, err := sess.ID(id).Delete(&AccessToken{
 		UID: userID,
 	})
 	if err != nil {
@@ -126,5 +132,8 @@ func DeleteAccessTokenByID(id, userID int64) error {
 	} else if cnt != 1 {
 		return ErrAccessTokenNotExist{}
 	}
-	return nil
+	if _, err = sess.Delete(&TokenUsage{TokenID: id}); err != nil {
+		return err
+	}
+	return sess.Commit()
 }
@@ -0,0 +1,159 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// TokenUsage represents the API calls made with an access token to the resources of an organization
+// from an IP address, aggregated per endpoint class
+type TokenUsage struct {
+	ID      int64        `xorm:"pk autoincr"`
+	TokenID int64        `xorm:"UNIQUE(s) NOT NULL"`
+	Token   *AccessToken `xorm:"-"`
+	// UID is the owner of the token
+	UID   int64 `xorm:"INDEX NOT NULL"`
+	User  *User `xorm:"-"`
+	OrgID int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	// Endpoint is the class of the called endpoints, e.g. repos/issues or orgs/teams
+	Endpoint string `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+	IsWrite  bool   `xorm:"UNIQUE(s) NOT NULL DEFAULT false"`
+	IP       string `xorm:"UNIQUE(s) VARCHAR(64) NOT NULL"`
+	Count    int64  `xorm:"NOT NULL DEFAULT 0"`
+
+	FirstUsedUnix timeutil.TimeStamp `xorm:"created"`
+	LastUsedUnix  timeutil.TimeStamp `xorm:"INDEX"`
+}
+
+func (u *TokenUsage) cond() builder.Cond {
+	return builder.Eq{
+		"token_id": u.TokenID,
+		"org_id":   u.OrgID,
+		"endpoint": u.Endpoint,
+		"is_write": u.IsWrite,
+		"ip":       u.IP,
+	}
+}
+
+// RecordTokenUsage adds the count of API calls made with the access token to the resources of the organization
+func RecordTokenUsage(usage *TokenUsage) error {
+	// X-Forwarded-For may contain a list of addresses
+	if len(usage.IP) > 64 {
+		usage.IP = usage.IP[:64]
+	}
+	if usage.LastUsedUnix == 0 {
+		usage.LastUsedUnix = timeutil.TimeStampNow()
+	}
+
+	// the usage is inserted by the first call, a concurrent call may have inserted it in the meantime
+	var err error
+	for i := 0; i < 2; i++ {
+		var n int64
+		n, err = x.Where(usage.cond()).Incr("count", usage.Count).Cols("last_used_unix").Update(&TokenUsage{LastUsedUnix: usage.LastUsedUnix})
+		if err != nil {
+			return err
+		} else if n > 0 {
+			return nil
+		}
+		if _, err = x.Insert(usage); err == nil {
+			return nil
+		}
+		log.Trace("Insert token usage failed, retrying the update: %v", err)
+		usage.ID = 0
+	}
+	return err
+}
+
+// FindTokenUsagesOptions represents the options to find the token usages of an organization
+type FindTokenUsagesOptions struct {
+	ListOptions
+	OrgID int64
+	UID   int64
+}
+
+func (opts *FindTokenUsagesOptions) toCond() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OrgID > 0 {
+		cond = cond.And(builder.Eq{"org_id": opts.OrgID})
+	}
+	if opts.UID > 0 {
+		cond = cond.And(builder.Eq{"uid": opts.UID})
+	}
+	return cond
+}
+
+// FindTokenUsages returns the token usages, the last used first, and their total count
+func FindTokenUsages(opts FindTokenUsagesOptions) ([]*TokenUsage, int64, error) {
+	count, err := x.Where(opts.toCond()).Count(new(TokenUsage))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sess := x.Where(opts.toCond()).Desc("last_used_unix", "id")
+	if opts.Page > 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	usages := make([]*TokenUsage, 0, opts.PageSize)
+	return usages, count, sess.Find(&usages)
+}
+
+// TokenUsageList is a list of token usages
+type TokenUsageList []*TokenUsage
+
+// LoadAttributes loads the tokens and their owners
+func (usages TokenUsageList) LoadAttributes() error {
+	if len(usages) == 0 {
+		return nil
+	}
+
+	tokens := make(map[int64]*AccessToken)
+	users := make(map[int64]*User)
+	for _, u := range usages {
+		tokens[u.TokenID] = nil
+		users[u.UID] = nil
+	}
+
+	tokenIDs := make([]int64, 0, len(tokens))
+	for id := range tokens {
+		tokenIDs = append(tokenIDs, id)
+	}
+	if err := x.In("id", tokenIDs).Find(&tokens); err != nil {
+		return err
+	}
+	userIDs := make([]int64, 0, len(users))
+	for id := range users {
+		userIDs = append(userIDs, id)
+	}
+	if err := x.In("id", userIDs).Find(&users); err != nil {
+		return err
+	}
+
+	for _, u := range usages {
+		u.Token = tokens[u.TokenID]
+		u.User = users[u.UID]
+		if u.User == nil {
+			u.User = NewGhostUser()
+		}
+	}
+	return nil
+}
+
+// DeleteOldTokenUsages deletes the token usages which have not been used for the given duration
+func DeleteOldTokenUsages(ctx context.Context, olderThan time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ErrCancelledf("before deleting the token usages older than %s", olderThan)
+	default:
+	}
+	_, err := x.Where("last_used_unix < ?", timeutil.TimeStampNow().AddDuration(-olderThan)).Delete(new(TokenUsage))
+	return err
+}
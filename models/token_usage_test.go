@@ -0,0 +1,64 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordTokenUsage(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, RecordTokenUsage(&TokenUsage{TokenID: 1, UID: 1, OrgID: 3, Endpoint: "repos/issues", IP: "127.0.0.1", Count: 1}))
+	assert.NoError(t, RecordTokenUsage(&TokenUsage{TokenID: 1, UID: 1, OrgID: 3, Endpoint: "repos/issues", IP: "127.0.0.1", Count: 1}))
+	assert.NoError(t, RecordTokenUsage(&TokenUsage{TokenID: 1, UID: 1, OrgID: 3, Endpoint: "repos/issues", IsWrite: true, IP: "127.0.0.1", Count: 1}))
+	assert.NoError(t, RecordTokenUsage(&TokenUsage{TokenID: 1, UID: 1, OrgID: 3, Endpoint: "repos/issues", IP: "10.0.0.1", Count: 1}))
+	assert.NoError(t, RecordTokenUsage(&TokenUsage{TokenID: 2, UID: 1, OrgID: 6, Endpoint: "orgs", IP: "127.0.0.1", Count: 1}))
+
+	AssertExistsAndLoadBean(t, &TokenUsage{TokenID: 1, OrgID: 3, Endpoint: "repos/issues", IP: "127.0.0.1", Count: 2})
+
+	// counts collected over a while are added at once
+	assert.NoError(t, RecordTokenUsage(&TokenUsage{TokenID: 1, UID: 1, OrgID: 3, Endpoint: "repos/issues", IP: "127.0.0.1", Count: 5}))
+	AssertExistsAndLoadBean(t, &TokenUsage{TokenID: 1, OrgID: 3, Endpoint: "repos/issues", IP: "127.0.0.1", Count: 7})
+
+	usages, count, err := FindTokenUsages(FindTokenUsagesOptions{OrgID: 3})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	assert.Len(t, usages, 3)
+
+	assert.NoError(t, TokenUsageList(usages).LoadAttributes())
+	for _, u := range usages {
+		assert.EqualValues(t, 1, u.Token.ID)
+		assert.EqualValues(t, 1, u.User.ID)
+	}
+
+	_, count, err = FindTokenUsages(FindTokenUsagesOptions{OrgID: 3, UID: 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+
+	// deleting the token deletes its usage
+	assert.NoError(t, DeleteAccessTokenByID(1, 1))
+	AssertNotExistsBean(t, &TokenUsage{TokenID: 1})
+	AssertExistsAndLoadBean(t, &TokenUsage{TokenID: 2})
+}
+
+func TestDeleteOldTokenUsages(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	assert.NoError(t, RecordTokenUsage(&TokenUsage{TokenID: 1, UID: 1, OrgID: 3, Endpoint: "repos", IP: "127.0.0.1", Count: 1}))
+	assert.NoError(t, RecordTokenUsage(&TokenUsage{TokenID: 1, UID: 1, OrgID: 3, Endpoint: "orgs", IP: "127.0.0.1", Count: 1}))
+	_, err := x.Where("endpoint = ?", "orgs").Cols("last_used_unix").Update(&TokenUsage{LastUsedUnix: timeutil.TimeStampNow().Add(-100 * 24 * 3600)})
+	assert.NoError(t, err)
+
+	assert.NoError(t, DeleteOldTokenUsages(context.Background(), 90*24*time.Hour))
+	AssertExistsAndLoadBean(t, &TokenUsage{Endpoint: "repos"})
+	AssertNotExistsBean(t, &TokenUsage{Endpoint: "orgs"})
+}
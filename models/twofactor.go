@@ -111,6 +111,15 @@ func GetTwoFactorByUID(uid int64) (*TwoFactor, error) {
 	return twofa, nil
 }
 
+// HasTwoFactorByUID returns true if the user is enrolled in two-factor authentication.
+func HasTwoFactorByUID(uid int64) (bool, error) {
+	return hasTwoFactorByUID(x, uid)
+}
+
+func hasTwoFactorByUID(e Engine, uid int64) (bool, error) {
+	return e.Where("uid=?", uid).Exist(&TwoFactor{})
+}
+
 // DeleteTwoFactorByID deletes two-factor authentication token by given ID.
 func DeleteTwoFactorByID(id, userID int64) error {
 	cnt, err := x.ID(id).Delete(&TwoFactor{
@@ -139,6 +139,10 @@ type User struct {
 	AllowCreateOrganization bool `xorm:"DEFAULT true"`
 	ProhibitLogin           bool `xorm:"NOT NULL DEFAULT false"`
 
+	// Dormant accounts are flagged after being inactive for a while and deactivated after a grace period
+	DormantFlaggedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	IsDormant          bool               `xorm:"NOT NULL DEFAULT false"`
+
 	// Avatar
 	Avatar          string `xorm:"VARCHAR(2048) NOT NULL"`
 	AvatarEmail     string `xorm:"NOT NULL"`
@@ -158,6 +162,9 @@ type User struct {
 	MembersIsPublic           map[int64]bool      `xorm:"-"`
 	Visibility                structs.VisibleType `xorm:"NOT NULL DEFAULT 0"`
 	RepoAdminChangeTeamAccess bool                `xorm:"NOT NULL DEFAULT false"`
+	RequireTwoFactor          bool                `xorm:"NOT NULL DEFAULT false"`
+	TwoFactorGraceDays        int                 `xorm:"NOT NULL DEFAULT 0"`
+	TwoFactorRequiredUnix     timeutil.TimeStamp  `xorm:"NOT NULL DEFAULT 0"`
 
 	// Preferences
 	DiffViewStyle       string `xorm:"NOT NULL DEFAULT ''"`
@@ -1082,10 +1089,17 @@ func deleteUser(e *xorm.Session, u *User) error {
 		&TeamUser{UID: u.ID},
 		&Collaboration{UserID: u.ID},
 		&Stopwatch{UserID: u.ID},
+		&NotificationRule{OwnerID: u.ID},
+		&ScheduledPost{PosterID: u.ID},
+		&TokenUsage{UID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err = removeFeatureFlagTarget(e, u.ID); err != nil {
+		return fmt.Errorf("removeFeatureFlagTarget: %v", err)
+	}
+
 	// ***** START: PublicKey *****
 	if _, err = e.Delete(&PublicKey{OwnerID: u.ID}); err != nil {
 		return fmt.Errorf("deletePublicKeys: %v", err)
@@ -1325,10 +1339,43 @@ func GetUserIDsByNames(names []string, ignoreNonExistent bool) ([]int64, error)
 
 // UserCommit represents a commit with validation of user.
 type UserCommit struct {
-	User *User
+	User      *User
+	CoAuthors []*CommitCoAuthor
 	*git.Commit
 }
 
+// CommitCoAuthor represents a co-author named in a Co-authored-by trailer of a commit,
+// User is nil if the e-mail does not correspond to a user.
+type CommitCoAuthor struct {
+	*git.Signature
+	User *User
+}
+
+// GetCommitCoAuthors returns the co-authors of the commit with validation of user.
+func GetCommitCoAuthors(c *git.Commit) []*CommitCoAuthor {
+	return getCommitCoAuthors(c, map[string]*User{})
+}
+
+func getCommitCoAuthors(c *git.Commit, emails map[string]*User) []*CommitCoAuthor {
+	sigs := c.CoAuthors()
+	if len(sigs) == 0 {
+		return nil
+	}
+	coAuthors := make([]*CommitCoAuthor, 0, len(sigs))
+	for _, sig := range sigs {
+		u, ok := emails[sig.Email]
+		if !ok {
+			u, _ = GetUserByEmail(sig.Email)
+			emails[sig.Email] = u
+		}
+		coAuthors = append(coAuthors, &CommitCoAuthor{
+			Signature: sig,
+			User:      u,
+		})
+	}
+	return coAuthors
+}
+
 // ValidateCommitWithEmail check if author's e-mail of commit is corresponding to a user.
 func ValidateCommitWithEmail(c *git.Commit) *User {
 	if c.Author == nil {
@@ -1364,8 +1411,9 @@ func ValidateCommitsWithEmails(oldCommits *list.List) *list.List {
 		}
 
 		newCommits.PushBack(UserCommit{
-			User:   u,
-			Commit: c,
+			User:      u,
+			CoAuthors: getCommitCoAuthors(c, emails),
+			Commit:    c,
 		})
 		e = e.Next()
 	}
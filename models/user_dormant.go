@@ -0,0 +1,107 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// dormantUserCond returns the condition for users who neither signed in nor used
+// an SSH key or access token since inactiveSince. Administrators and users who are
+// not allowed to sign in anyway are never dormant.
+func dormantUserCond(inactiveSince timeutil.TimeStamp) builder.Cond {
+	return builder.Eq{
+		"`user`.type":           UserTypeIndividual,
+		"`user`.is_admin":       false,
+		"`user`.prohibit_login": false,
+	}.
+		And(builder.Lt{"`user`.last_login_unix": inactiveSince}.Or(builder.IsNull{"`user`.last_login_unix"})).
+		And(builder.Lt{"`user`.created_unix": inactiveSince}.Or(builder.IsNull{"`user`.created_unix"})).
+		And(builder.NotIn("`user`.id", builder.Select("owner_id").From("public_key").
+			Where(builder.Gte{"updated_unix": inactiveSince}))).
+		And(builder.NotIn("`user`.id", builder.Select("uid").From("access_token").
+			Where(builder.Gte{"updated_unix": inactiveSince})))
+}
+
+// FindDormantUsers returns the users who have been inactive since inactiveSince
+func FindDormantUsers(inactiveSince timeutil.TimeStamp) ([]*User, error) {
+	users := make([]*User, 0, 10)
+	return users, x.Where(dormantUserCond(inactiveSince)).Asc("id").Find(&users)
+}
+
+// GetFlaggedDormantUsers returns the users who were notified that their account is dormant
+// and who have not been deactivated yet
+func GetFlaggedDormantUsers() ([]*User, error) {
+	users := make([]*User, 0, 10)
+	return users, x.Where("dormant_flagged_unix > 0 AND is_dormant = ?", false).Asc("id").Find(&users)
+}
+
+// SearchDormantUsers returns the users who are flagged as dormant or were deactivated because of it
+func SearchDormantUsers(opts ListOptions) ([]*User, int64, error) {
+	cond := builder.Gt{"dormant_flagged_unix": 0}.Or(builder.Eq{"is_dormant": true})
+	count, err := x.Where(cond).Count(new(User))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sess := x.Where(cond).Asc("dormant_flagged_unix", "id")
+	if opts.Page > 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	users := make([]*User, 0, opts.PageSize)
+	return users, count, sess.Find(&users)
+}
+
+// DormantDeadline returns the time at which the account of the flagged user will be deactivated
+func (u *User) DormantDeadline() timeutil.TimeStamp {
+	if u.DormantFlaggedUnix == 0 {
+		return 0
+	}
+	return u.DormantFlaggedUnix.AddDuration(time.Duration(setting.Service.DormantUserGracePeriodDays) * 24 * time.Hour)
+}
+
+// FlagDormantUser records that the user has been notified that their account is dormant
+func FlagDormantUser(u *User) error {
+	u.DormantFlaggedUnix = timeutil.TimeStampNow()
+	return UpdateUserCols(u, "dormant_flagged_unix")
+}
+
+// UnflagDormantUser removes the dormant flag of a user who has been active again
+func UnflagDormantUser(u *User) error {
+	u.DormantFlaggedUnix = 0
+	return UpdateUserCols(u, "dormant_flagged_unix")
+}
+
+// DeactivateDormantUser prohibits the dormant user to sign in
+func DeactivateDormantUser(u *User) error {
+	u.IsDormant = true
+	u.ProhibitLogin = true
+	return UpdateUserCols(u, "is_dormant", "prohibit_login")
+}
+
+// ReactivateDormantUser allows a deactivated dormant user to sign in again
+func ReactivateDormantUser(u *User) error {
+	u.IsDormant = false
+	u.ProhibitLogin = false
+	u.DormantFlaggedUnix = 0
+	return UpdateUserCols(u, "is_dormant", "prohibit_login", "dormant_flagged_unix")
+}
+
+// checkUserProhibitLogin returns ErrUserProhibitLogin if the user is not allowed to sign in.
+// Deactivated dormant users are reactivated if it is allowed.
+func checkUserProhibitLogin(u *User) error {
+	if !u.ProhibitLogin {
+		return nil
+	}
+	if u.IsDormant && setting.Service.DormantUserAllowReactivation {
+		return ReactivateDormantUser(u)
+	}
+	return ErrUserProhibitLogin{u.ID, u.Name}
+}
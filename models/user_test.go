@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 
@@ -404,3 +405,22 @@ func TestGetMaileableUsersByIDs(t *testing.T) {
 		assert.Equal(t, results[1].ID, 4)
 	}
 }
+
+func TestGetCommitCoAuthors(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	commit := &git.Commit{
+		CommitMessage: "Add feature\n\nCo-authored-by: User Two <user2@example.com>\nCo-authored-by: Someone <someone@example.com>\nReviewed-by: User Five <user5@example.com>\n",
+	}
+	coAuthors := GetCommitCoAuthors(commit)
+	if assert.Len(t, coAuthors, 2) {
+		assert.Equal(t, "User Two", coAuthors[0].Name)
+		if assert.NotNil(t, coAuthors[0].User) {
+			assert.EqualValues(t, 2, coAuthors[0].User.ID)
+		}
+		assert.Equal(t, "someone@example.com", coAuthors[1].Email)
+		assert.Nil(t, coAuthors[1].User)
+	}
+
+	assert.Nil(t, GetCommitCoAuthors(&git.Commit{CommitMessage: "Add feature\n"}))
+}
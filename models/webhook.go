@@ -79,10 +79,11 @@ type HookEvents struct {
 
 // HookEvent represents events that will delivery hook.
 type HookEvent struct {
-	PushOnly       bool   `json:"push_only"`
-	SendEverything bool   `json:"send_everything"`
-	ChooseEvents   bool   `json:"choose_events"`
-	BranchFilter   string `json:"branch_filter"`
+	PushOnly       bool     `json:"push_only"`
+	SendEverything bool     `json:"send_everything"`
+	ChooseEvents   bool     `json:"choose_events"`
+	BranchFilter   string   `json:"branch_filter"`
+	TopicFilter    []string `json:"topic_filter"`
 
 	HookEvents `json:"events"`
 }
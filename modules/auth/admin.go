@@ -59,3 +59,17 @@ type AdminDashboardForm struct {
 func (f *AdminDashboardForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+// AdminFeatureFlagForm form for creating or editing a feature flag
+type AdminFeatureFlagForm struct {
+	Name        string `binding:"Required;MaxSize(50)"`
+	Description string `binding:"MaxSize(255)"`
+	Enabled     bool
+	Percentage  int `binding:"Range(0,100)"`
+	Targets     string
+}
+
+// Validate validates form fields
+func (f *AdminFeatureFlagForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
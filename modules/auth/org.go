@@ -49,6 +49,36 @@ func (f *UpdateOrgSettingForm) Validate(ctx *macaron.Context, errs binding.Error
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// OrgTwoFactorForm form for updating the two-factor authentication requirement of an organization
+type OrgTwoFactorForm struct {
+	RequireTwoFactor   bool
+	TwoFactorGraceDays int `binding:"Range(0,365)"`
+}
+
+// Validate validates the fields
+func (f *OrgTwoFactorForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// RepoPresetForm form for creating or editing a repository preset of an organization
+type RepoPresetForm struct {
+	Name                 string `binding:"Required;MaxSize(50)"`
+	Description          string `binding:"MaxSize(255)"`
+	TeamID               int64
+	Units                []models.UnitType
+	IssueLabels          string
+	Topics               string
+	ProtectDefaultBranch bool
+	RequiredApprovals    int64 `binding:"Range(0,100)"`
+	StatusCheckContexts  string
+	Webhooks             string
+}
+
+// Validate validates the fields
+func (f *RepoPresetForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // ___________
 // \__    ___/___ _____    _____
 //   |    |_/ __ \\__  \  /     \
@@ -40,6 +40,7 @@ type CreateRepoForm struct {
 	License       string
 	Readme        string
 	Template      bool
+	RepoPreset    int64
 
 	RepoTemplate int64
 	GitContent   bool
@@ -135,6 +136,8 @@ type RepoSettingForm struct {
 	Template       bool
 	EnablePrune    bool
 
+	EnableMirrorReleaseAssets bool
+
 	// Advanced settings
 	EnableWiki                       bool
 	EnableExternalWiki               bool
@@ -154,6 +157,10 @@ type RepoSettingForm struct {
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableIssueDependencies          bool
+	DisableCloseKeywords             bool
+	CloseKeywords                    string
+	ReopenKeywords                   string
+	IssueCloseTrigger                string
 	IsArchived                       bool
 
 	// Signing Settings
@@ -169,6 +176,40 @@ func (f *RepoSettingForm) Validate(ctx *macaron.Context, errs binding.Errors) bi
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// AttachmentRetentionForm form for changing the retention of orphaned attachments of a repository
+type AttachmentRetentionForm struct {
+	RetentionDays int `binding:"Range(0,36500)"`
+}
+
+// Validate validates the fields
+func (f *AttachmentRetentionForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ReleaseChannelForm form for adding a release channel of a repository
+type ReleaseChannelForm struct {
+	Name       string `binding:"Required;MaxSize(50)"`
+	TagPattern string `binding:"Required;MaxSize(255)"`
+	KeepLast   int    `binding:"Range(0,10000)"`
+}
+
+// Validate validates the fields
+func (f *ReleaseChannelForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// IssueSyncForm form for setting up the issue sync of a repository migrated from GitHub
+type IssueSyncForm struct {
+	PullEnabled bool
+	PushEnabled bool
+	Token       string `binding:"MaxSize(255)"`
+}
+
+// Validate validates the fields
+func (f *IssueSyncForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // __________                             .__
 // \______   \____________    ____   ____ |  |__
 //  |    |  _/\_  __ \__  \  /    \_/ ___\|  |  \
@@ -198,6 +239,7 @@ type ProtectBranchForm struct {
 	DismissStaleApprovals         bool
 	RequireSignedCommits          bool
 	ProtectedFilePatterns         string
+	PushToPullRequest             string
 }
 
 // Validate validates the fields
@@ -235,6 +277,7 @@ type WebhookForm struct {
 	Repository           bool
 	Active               bool
 	BranchFilter         string `binding:"GlobPattern"`
+	TopicFilter          string
 }
 
 // PushOnly if the hook will be triggered when push
@@ -380,15 +423,17 @@ func (f *NewFeishuHookForm) Validate(ctx *macaron.Context, errs binding.Errors)
 
 // CreateIssueForm form for creating issue
 type CreateIssueForm struct {
-	Title       string `binding:"Required;MaxSize(255)"`
-	LabelIDs    string `form:"label_ids"`
-	AssigneeIDs string `form:"assignee_ids"`
-	Ref         string `form:"ref"`
-	MilestoneID int64
-	ProjectID   int64
-	AssigneeID  int64
-	Content     string
-	Files       []string
+	Title        string `binding:"Required;MaxSize(255)"`
+	LabelIDs     string `form:"label_ids"`
+	AssigneeIDs  string `form:"assignee_ids"`
+	Ref          string `form:"ref"`
+	MilestoneID  int64
+	ProjectID    int64
+	AssigneeID   int64
+	Content      string
+	Files        []string
+	PostAt       string `form:"post_at"`
+	PostAtOffset string `form:"post_at_offset"`
 }
 
 // Validate validates the fields
@@ -398,9 +443,11 @@ func (f *CreateIssueForm) Validate(ctx *macaron.Context, errs binding.Errors) bi
 
 // CreateCommentForm form for creating comment
 type CreateCommentForm struct {
-	Content string
-	Status  string `binding:"OmitEmpty;In(reopen,close)"`
-	Files   []string
+	Content      string
+	Status       string `binding:"OmitEmpty;In(reopen,close)"`
+	Files        []string
+	PostAt       string `form:"post_at"`
+	PostAtOffset string `form:"post_at_offset"`
 }
 
 // Validate validates the fields
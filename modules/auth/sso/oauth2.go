@@ -103,6 +103,7 @@ func (o *OAuth2) userIDFromToken(ctx *macaron.Context) int64 {
 		log.Error("UpdateAccessToken: %v", err)
 	}
 	ctx.Data["IsApiToken"] = true
+	ctx.Data["ApiToken"] = t
 	return t.UID
 }
 
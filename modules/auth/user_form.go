@@ -300,6 +300,32 @@ func (f *AddKeyForm) Validate(ctx *macaron.Context, errs binding.Errors) binding
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// NotificationRuleForm form for adding a notification rule of a user or an organization
+type NotificationRuleForm struct {
+	RepoPattern string `binding:"MaxSize(255)"`
+	Event       string
+	Action      string `binding:"Required"`
+	Priority    int
+}
+
+// Validate validates the fields
+func (f *NotificationRuleForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// ReportAbuseForm form for reporting an issue, a comment, a repository or a user to the site administrators
+type ReportAbuseForm struct {
+	Type   string `binding:"Required"`
+	ID     int64  `binding:"Required"`
+	Reason string `binding:"Required"`
+	Remark string `binding:"MaxSize(2000)"`
+}
+
+// Validate validates the fields
+func (f *ReportAbuseForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // NewAccessTokenForm form for creating access token
 type NewAccessTokenForm struct {
 	Name string `binding:"Required;MaxSize(255)"`
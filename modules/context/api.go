@@ -9,12 +9,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
 
 	"gitea.com/macaron/csrf"
 	"gitea.com/macaron/macaron"
@@ -97,6 +99,21 @@ func (ctx *APIContext) Error(status int, title string, obj interface{}) {
 	})
 }
 
+// FieldValidationError responds with the errors of the invalid fields of the input, keyed by their JSON names
+func (ctx *APIContext) FieldValidationError(errs map[string]string) {
+	fields := make([]string, 0, len(errs))
+	for field := range errs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	ctx.JSON(http.StatusUnprocessableEntity, api.APIFieldValidationError{
+		Message: "invalid fields: " + strings.Join(fields, ", "),
+		Errors:  errs,
+		URL:     setting.API.SwaggerURL,
+	})
+}
+
 // InternalServerError responds with an error message to the client with the error as a message
 // and the file and line of the caller.
 func (ctx *APIContext) InternalServerError(err error) {
@@ -364,6 +364,7 @@ func repoAssignment(ctx *Context, repo *models.Repository) {
 			return
 		}
 		ctx.Data["MirrorEnablePrune"] = ctx.Repo.Mirror.EnablePrune
+		ctx.Data["MirrorEnableReleaseAssets"] = ctx.Repo.Mirror.EnableReleaseAssets
 		ctx.Data["MirrorInterval"] = ctx.Repo.Mirror.Interval
 		ctx.Data["Mirror"] = ctx.Repo.Mirror
 	}
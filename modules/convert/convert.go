@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/structs"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -77,6 +78,20 @@ func ToBranch(repo *models.Repository, b *git.Branch, c *git.Commit, bp *models.
 	return branch, nil
 }
 
+// ToStatusCheckContextChange convert a StatusCheckContextChange to api.StatusCheckContextChange
+func ToStatusCheckContextChange(change *models.StatusCheckContextChange) *api.StatusCheckContextChange {
+	action := "unregistered"
+	if change.IsRegistered {
+		action = "registered"
+	}
+	return &api.StatusCheckContextChange{
+		Context: change.Context,
+		Action:  action,
+		Doer:    ToUser(change.Doer, false, false),
+		Created: change.CreatedUnix.AsTime(),
+	}
+}
+
 // ToBranchProtection convert a ProtectedBranch to api.BranchProtection
 func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 	pushWhitelistUsernames, err := models.GetUserNamesByIDs(bp.WhitelistUserIDs)
@@ -126,6 +141,7 @@ func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 		DismissStaleApprovals:         bp.DismissStaleApprovals,
 		RequireSignedCommits:          bp.RequireSignedCommits,
 		ProtectedFilePatterns:         bp.ProtectedFilePatterns,
+		PushToPullRequest:             bp.PushToPullRequest.String(),
 		Created:                       bp.CreatedUnix.AsTime(),
 		Updated:                       bp.UpdatedUnix.AsTime(),
 	}
@@ -234,14 +250,15 @@ func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 	}
 
 	return &api.Hook{
-		ID:      w.ID,
-		Type:    w.HookTaskType.Name(),
-		URL:     fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
-		Active:  w.IsActive,
-		Config:  config,
-		Events:  w.EventsArray(),
-		Updated: w.UpdatedUnix.AsTime(),
-		Created: w.CreatedUnix.AsTime(),
+		ID:          w.ID,
+		Type:        w.HookTaskType.Name(),
+		URL:         fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
+		Active:      w.IsActive,
+		Config:      config,
+		Events:      w.EventsArray(),
+		TopicFilter: w.TopicFilter,
+		Updated:     w.UpdatedUnix.AsTime(),
+		Created:     w.CreatedUnix.AsTime(),
 	}
 }
 
@@ -254,6 +271,40 @@ func ToGitHook(h *git.Hook) *api.GitHook {
 	}
 }
 
+// ToGitHookScript convert git.HookScript to api.GitHookScript
+func ToGitHookScript(s *git.HookScript) *api.GitHookScript {
+	return &api.GitHookScript{
+		Name:    s.Name,
+		Content: s.Content,
+		Managed: s.IsManaged(),
+	}
+}
+
+// ToMarkupHeadings convert a flat list of markdown.Header to a tree of api.MarkupHeading
+func ToMarkupHeadings(headers []markdown.Header) []*api.MarkupHeading {
+	result := make([]*api.MarkupHeading, 0, len(headers))
+	var parents []*api.MarkupHeading
+	for _, header := range headers {
+		heading := &api.MarkupHeading{
+			Level:    header.Level,
+			Text:     header.Text,
+			ID:       header.ID,
+			Children: []*api.MarkupHeading{},
+		}
+		for len(parents) > 0 && parents[len(parents)-1].Level >= heading.Level {
+			parents = parents[:len(parents)-1]
+		}
+		if len(parents) == 0 {
+			result = append(result, heading)
+		} else {
+			parent := parents[len(parents)-1]
+			parent.Children = append(parent.Children, heading)
+		}
+		parents = append(parents, heading)
+	}
+	return result
+}
+
 // ToDeployKey convert models.DeployKey to api.DeployKey
 func ToDeployKey(apiLink string, key *models.DeployKey) *api.DeployKey {
 	return &api.DeployKey{
@@ -280,7 +331,54 @@ func ToOrganization(org *models.User) *api.Organization {
 		Location:                  org.Location,
 		Visibility:                org.Visibility.String(),
 		RepoAdminChangeTeamAccess: org.RepoAdminChangeTeamAccess,
+		RequireTwoFactor:          org.RequireTwoFactor,
+		TwoFactorGraceDays:        org.TwoFactorGraceDays,
+	}
+}
+
+// ToOrgMemberTwoFactorStatus convert models.OrgMemberTwoFactorStatus to api.OrgMemberTwoFactorStatus
+func ToOrgMemberTwoFactorStatus(status *models.OrgMemberTwoFactorStatus) *api.OrgMemberTwoFactorStatus {
+	return &api.OrgMemberTwoFactorStatus{
+		User:             ToUser(status.User, true, true),
+		IsOwner:          status.IsOwner,
+		TwoFactorEnabled: status.HasTwoFactor,
+	}
+}
+
+// ToOutsideCollaborator convert models.OutsideCollaboration to api.OutsideCollaborator
+func ToOutsideCollaborator(c *models.OutsideCollaboration) *api.OutsideCollaborator {
+	result := &api.OutsideCollaborator{
+		User:       ToUser(c.User, true, true),
+		Repository: c.Repo.Name,
+		Permission: c.Collaboration.Mode.String(),
+		Created:    c.Collaboration.CreatedUnix.AsTime(),
 	}
+	if c.Collaboration.HasExpiry() {
+		result.ExpiresAt = c.Collaboration.ExpiresUnix.AsTimePtr()
+	}
+	return result
+}
+
+// ToTokenUsage converts models.TokenUsage to api.TokenUsage
+func ToTokenUsage(u *models.TokenUsage) *api.TokenUsage {
+	result := &api.TokenUsage{
+		User:      ToUser(u.User, true, true),
+		Endpoint:  u.Endpoint,
+		Access:    "read",
+		IP:        u.IP,
+		Count:     u.Count,
+		FirstUsed: u.FirstUsedUnix.AsTime(),
+		LastUsed:  u.LastUsedUnix.AsTime(),
+	}
+	if u.IsWrite {
+		result.Access = "write"
+	}
+	if u.Token != nil {
+		result.TokenID = u.Token.ID
+		result.TokenName = u.Token.Name
+		result.TokenLastEight = u.Token.TokenLastEight
+	}
+	return result
 }
 
 // ToTeam convert models.Team to api.Team
@@ -300,6 +398,69 @@ func ToTeam(team *models.Team) *api.Team {
 	}
 }
 
+// ToRepoPreset convert models.RepoPreset to api.RepoPreset
+func ToRepoPreset(p *models.RepoPreset) *api.RepoPreset {
+	units := make([]string, 0, len(p.Units))
+	for _, tp := range p.Units {
+		units = append(units, models.Units[tp].NameKey)
+	}
+	webhooks := make([]*api.RepoPresetWebhook, 0, len(p.Webhooks))
+	for _, w := range p.Webhooks {
+		webhooks = append(webhooks, &api.RepoPresetWebhook{
+			URL:            w.URL,
+			ContentType:    w.ContentType.Name(),
+			SendEverything: w.SendEverything,
+		})
+	}
+
+	return &api.RepoPreset{
+		ID:                   p.ID,
+		Name:                 p.Name,
+		Description:          p.Description,
+		TeamID:               p.TeamID,
+		Units:                units,
+		IssueLabels:          p.IssueLabels,
+		Topics:               p.Topics,
+		ProtectDefaultBranch: p.ProtectDefaultBranch,
+		RequiredApprovals:    p.RequiredApprovals,
+		StatusCheckContexts:  p.StatusCheckContexts,
+		Webhooks:             webhooks,
+		Created:              p.CreatedUnix.AsTime(),
+		Updated:              p.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToFeatureFlag convert models.FeatureFlag to api.FeatureFlag, the targets of the flag must be loaded
+func ToFeatureFlag(f *models.FeatureFlag) *api.FeatureFlag {
+	targets := make([]string, 0, len(f.Targets))
+	for _, u := range f.Targets {
+		targets = append(targets, u.Name)
+	}
+	return &api.FeatureFlag{
+		ID:          f.ID,
+		Name:        f.Name,
+		Description: f.Description,
+		Enabled:     f.Enabled,
+		Percentage:  f.Percentage,
+		Targets:     targets,
+		Created:     f.CreatedUnix.AsTime(),
+		Updated:     f.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToNotificationRule convert models.NotificationRule to api.NotificationRule
+func ToNotificationRule(r *models.NotificationRule) *api.NotificationRule {
+	return &api.NotificationRule{
+		ID:          r.ID,
+		RepoPattern: r.RepoPattern,
+		Event:       string(r.Event),
+		Action:      string(r.Action),
+		Priority:    r.Priority,
+		Created:     r.CreatedUnix.AsTime(),
+		Updated:     r.UpdatedUnix.AsTime(),
+	}
+}
+
 // ToAnnotatedTag convert git.Tag to api.AnnotatedTag
 func ToAnnotatedTag(repo *models.Repository, t *git.Tag, c *git.Commit) *api.AnnotatedTag {
 	return &api.AnnotatedTag{
@@ -365,3 +526,47 @@ func ToCommitStatus(status *models.CommitStatus) *api.Status {
 
 	return apiStatus
 }
+
+// ToDirectoryCodeFrequency converts models.DirectoryCodeFrequency to api.DirectoryCodeFrequency
+func ToDirectoryCodeFrequency(f *models.DirectoryCodeFrequency) *api.DirectoryCodeFrequency {
+	weeks := make([]*api.CodeFrequencyWeek, len(f.Weeks))
+	for i, w := range f.Weeks {
+		weeks[i] = &api.CodeFrequencyWeek{
+			Week:      w.WeekUnix.AsTime().UTC(),
+			Additions: w.Additions,
+			Deletions: w.Deletions,
+			Commits:   w.Commits,
+		}
+	}
+	return &api.DirectoryCodeFrequency{
+		Directory: f.Directory,
+		Additions: f.Additions,
+		Deletions: f.Deletions,
+		Commits:   f.Commits,
+		Weeks:     weeks,
+	}
+}
+
+// ToProjectAnalytics convert models.ProjectAnalytics to api.ProjectAnalytics
+func ToProjectAnalytics(analytics *models.ProjectAnalytics) *api.ProjectAnalytics {
+	result := &api.ProjectAnalytics{
+		Boards:           make([]*api.ProjectBoardAnalytics, 0, len(analytics.Boards)),
+		LeadTimeCount:    int64(analytics.NumLeadTimes),
+		LeadTimeAverage:  analytics.AverageLeadTime,
+		LeadTimeMedian:   analytics.MedianLeadTime,
+		CycleTimeCount:   int64(analytics.NumCycleTimes),
+		CycleTimeAverage: analytics.AverageCycleTime,
+		CycleTimeMedian:  analytics.MedianCycleTime,
+	}
+	for _, board := range analytics.Boards {
+		result.Boards = append(result.Boards, &api.ProjectBoardAnalytics{
+			ID:              board.Board.ID,
+			Title:           board.Board.Title,
+			Issues:          int64(board.NumIssues),
+			Stays:           int64(board.NumStays),
+			DurationAverage: board.AverageDuration,
+			DurationMedian:  board.MedianDuration,
+		})
+	}
+	return result
+}
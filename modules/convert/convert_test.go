@@ -0,0 +1,34 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/markup/markdown"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToMarkupHeadings(t *testing.T) {
+	headings := ToMarkupHeadings([]markdown.Header{
+		{Level: 2, Text: "Intro", ID: "intro"},
+		{Level: 3, Text: "Goals", ID: "goals"},
+		{Level: 4, Text: "Non-goals", ID: "non-goals"},
+		{Level: 3, Text: "Audience", ID: "audience"},
+		{Level: 1, Text: "Usage", ID: "usage"},
+	})
+	if assert.Len(t, headings, 2) {
+		assert.Equal(t, "intro", headings[0].ID)
+		if assert.Len(t, headings[0].Children, 2) {
+			assert.Equal(t, "goals", headings[0].Children[0].ID)
+			assert.Len(t, headings[0].Children[0].Children, 1)
+			assert.Equal(t, "audience", headings[0].Children[1].ID)
+		}
+		assert.Equal(t, "usage", headings[1].ID)
+		assert.Len(t, headings[1].Children, 0)
+	}
+	assert.Len(t, ToMarkupHeadings(nil), 0)
+}
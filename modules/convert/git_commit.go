@@ -158,9 +158,22 @@ func ToCommit(repo *models.Repository, commit *git.Commit, userCache map[string]
 				URL: repo.APIURL() + "/git/trees/" + commit.ID.String(),
 				SHA: commit.ID.String(),
 			},
+			Trailers: ToCommitTrailers(commit.Trailers()),
 		},
 		Author:    apiAuthor,
 		Committer: apiCommitter,
 		Parents:   apiParents,
 	}, nil
 }
+
+// ToCommitTrailers convert a list of git.CommitTrailer to a list of api.CommitTrailer
+func ToCommitTrailers(trailers []*git.CommitTrailer) []*api.CommitTrailer {
+	apiTrailers := make([]*api.CommitTrailer, 0, len(trailers))
+	for _, t := range trailers {
+		apiTrailers = append(apiTrailers, &api.CommitTrailer{
+			Key:   t.Key,
+			Value: t.Value,
+		})
+	}
+	return apiTrailers
+}
@@ -26,6 +26,12 @@ func ToAPIIssue(issue *models.Issue) *api.Issue {
 		return &api.Issue{}
 	}
 
+	body := issue.Content
+	if issue.IsHidden {
+		// hidden by the site administrators
+		body = ""
+	}
+
 	apiIssue := &api.Issue{
 		ID:       issue.ID,
 		URL:      issue.APIURL(),
@@ -33,7 +39,7 @@ func ToAPIIssue(issue *models.Issue) *api.Issue {
 		Index:    issue.Index,
 		Poster:   ToUser(issue.Poster, false, false),
 		Title:    issue.Title,
-		Body:     issue.Content,
+		Body:     body,
 		Labels:   ToLabelList(issue.Labels),
 		State:    issue.State(),
 		IsLocked: issue.IsLocked,
@@ -87,6 +93,25 @@ func ToAPIIssue(issue *models.Issue) *api.Issue {
 	return apiIssue
 }
 
+// ToIssueReferenceSuggestion converts an issue which is referenced by ref to an api.IssueReferenceSuggestion,
+// the repository of the issue has to be loaded
+func ToIssueReferenceSuggestion(issue *models.Issue, ref string) *api.IssueReferenceSuggestion {
+	return &api.IssueReferenceSuggestion{
+		Index:   issue.Index,
+		Title:   issue.Title,
+		State:   issue.State(),
+		IsPull:  issue.IsPull,
+		HTMLURL: issue.HTMLURL(),
+		Ref:     ref,
+		Repo: &api.RepositoryMeta{
+			ID:       issue.Repo.ID,
+			Name:     issue.Repo.Name,
+			Owner:    issue.Repo.OwnerName,
+			FullName: issue.Repo.FullName(),
+		},
+	}
+}
+
 // ToAPIIssueList converts an IssueList to API format
 func ToAPIIssueList(il models.IssueList) []*api.Issue {
 	result := make([]*api.Issue, len(il))
@@ -11,14 +11,29 @@ import (
 
 // ToComment converts a models.Comment to the api.Comment format
 func ToComment(c *models.Comment) *api.Comment {
+	body := c.Content
+	if c.IsHidden {
+		// hidden by the site administrators
+		body = ""
+	}
 	return &api.Comment{
 		ID:       c.ID,
 		Poster:   ToUser(c.Poster, false, false),
 		HTMLURL:  c.HTMLURL(),
 		IssueURL: c.IssueURL(),
 		PRURL:    c.PRURL(),
-		Body:     c.Content,
+		Body:     body,
 		Created:  c.CreatedUnix.AsTime(),
 		Updated:  c.UpdatedUnix.AsTime(),
 	}
 }
+
+// ToContentTranslation converts a models.IssueContentTranslation to api.ContentTranslation
+func ToContentTranslation(t *models.IssueContentTranslation) *api.ContentTranslation {
+	return &api.ContentTranslation{
+		Language:       t.Language,
+		SourceLanguage: t.SourceLanguage,
+		Body:           t.Content,
+		Created:        t.CreatedUnix.AsTime(),
+	}
+}
@@ -46,3 +46,36 @@ func ToReleaseAttachment(a *models.Attachment) *api.Attachment {
 		DownloadURL:   a.DownloadURL(),
 	}
 }
+
+// ToRepoAttachment converts models.Attachment to api.RepoAttachment, attributes must be loaded
+func ToRepoAttachment(a *models.Attachment) *api.RepoAttachment {
+	apiAttachment := &api.RepoAttachment{
+		ID:            a.ID,
+		Name:          a.Name,
+		Size:          a.Size,
+		DownloadCount: a.DownloadCount,
+		Created:       a.CreatedUnix.AsTime(),
+		UUID:          a.UUID,
+		DownloadURL:   a.DownloadURL(),
+		Uploader:      ToUser(a.Uploader, false, false),
+		CommentID:     a.CommentID,
+		ReleaseID:     a.ReleaseID,
+		Orphaned:      a.IsOrphaned(),
+	}
+	if a.Issue != nil {
+		apiAttachment.IssueNumber = a.Issue.Index
+	}
+	return apiAttachment
+}
+
+// ToReleaseChannel convert a models.ReleaseChannel to api.ReleaseChannel
+func ToReleaseChannel(c *models.ReleaseChannel) *api.ReleaseChannel {
+	return &api.ReleaseChannel{
+		ID:         c.ID,
+		Name:       c.Name,
+		TagPattern: c.TagPattern,
+		KeepLast:   c.KeepLast,
+		Created:    c.CreatedUnix.AsTime(),
+		Updated:    c.UpdatedUnix.AsTime(),
+	}
+}
@@ -36,3 +36,18 @@ func ToUser(user *models.User, signed, authed bool) *api.User {
 	}
 	return result
 }
+
+// ToDormantUser convert a dormant models.User to api.DormantUser
+func ToDormantUser(user *models.User) *api.DormantUser {
+	result := &api.DormantUser{
+		User:        ToUser(user, true, true),
+		Deactivated: user.IsDormant,
+	}
+	if user.DormantFlaggedUnix > 0 {
+		flagged := user.DormantFlaggedUnix.AsTime()
+		deadline := user.DormantDeadline().AsTime()
+		result.Flagged = &flagged
+		result.Deadline = &deadline
+	}
+	return result
+}
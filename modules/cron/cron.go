@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/sync"
 
 	"github.com/gogs/cron"
@@ -24,8 +25,12 @@ var taskStatusTable = sync.NewStatusTable()
 // Each cron task is run within the shutdown context as a running server
 // AtShutdown the cron server is stopped
 func NewContext() {
-	initBasicTasks()
-	initExtendedTasks()
+	if setting.Replication.IsReplica {
+		initReplicaTasks()
+	} else {
+		initBasicTasks()
+		initExtendedTasks()
+	}
 
 	lock.Lock()
 	for _, task := range tasks {
@@ -11,7 +11,12 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/migrations"
 	repository_service "code.gitea.io/gitea/modules/repository"
+	issue_service "code.gitea.io/gitea/services/issue"
+	issuesync_service "code.gitea.io/gitea/services/issuesync"
+	"code.gitea.io/gitea/services/mailer"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	release_service "code.gitea.io/gitea/services/release"
+	user_service "code.gitea.io/gitea/services/user"
 )
 
 func registerUpdateMirrorTask() {
@@ -108,6 +113,91 @@ func registerUpdateMigrationPosterID() {
 	})
 }
 
+func registerOrphanedAttachmentsCleanup() {
+	RegisterTaskFatal("orphaned_attachments_cleanup", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return models.DeleteExpiredOrphanedAttachments(ctx)
+	})
+}
+
+func registerOrgTwoFactorReminders() {
+	RegisterTaskFatal("org_two_factor_reminders", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return mailer.SendOrgTwoFactorReminderMails(ctx)
+	})
+}
+
+func registerDeleteExpiredCollaborations() {
+	RegisterTaskFatal("delete_expired_collaborations", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return models.DeleteExpiredCollaborations(ctx)
+	})
+}
+
+func registerDeactivateDormantUsers() {
+	RegisterTaskFatal("deactivate_dormant_users", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return user_service.DeactivateDormantUsers(ctx)
+	})
+}
+
+func registerPublishScheduledPosts() {
+	RegisterTaskFatal("publish_scheduled_posts", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      false,
+		Schedule:        "@every 1m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return issue_service.PublishScheduledPosts(ctx)
+	})
+}
+
+func registerDeleteOldTokenUsage() {
+	RegisterTaskFatal("delete_old_token_usage", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		OlderThan: 90 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		return models.DeleteOldTokenUsages(ctx, config.(*OlderThanConfig).OlderThan)
+	})
+}
+
+func registerDeleteExpiredChannelReleases() {
+	RegisterTaskFatal("delete_expired_channel_releases", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return release_service.DeleteExpiredChannelReleases(ctx)
+	})
+}
+
+func registerSyncUpstreamIssues() {
+	RegisterTaskFatal("sync_upstream_issues", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      false,
+		Schedule:        "@every 10m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return issuesync_service.PullAll(ctx)
+	})
+}
+
 func initBasicTasks() {
 	registerUpdateMirrorTask()
 	registerRepoHealthCheck()
@@ -116,4 +206,12 @@ func initBasicTasks() {
 	registerSyncExternalUsers()
 	registerDeletedBranchesCleanup()
 	registerUpdateMigrationPosterID()
+	registerOrphanedAttachmentsCleanup()
+	registerOrgTwoFactorReminders()
+	registerDeleteExpiredCollaborations()
+	registerDeactivateDormantUsers()
+	registerPublishScheduledPosts()
+	registerDeleteOldTokenUsage()
+	registerDeleteExpiredChannelReleases()
+	registerSyncUpstreamIssues()
 }
@@ -87,6 +87,16 @@ func registerRepositoryUpdateHook() {
 	})
 }
 
+func registerSyncManagedHookScripts() {
+	RegisterTaskFatal("sync_managed_hooks", &BaseConfig{
+		Enabled:    false,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return repo_module.SyncManagedHookScripts(ctx)
+	})
+}
+
 func registerReinitMissingRepositories() {
 	RegisterTaskFatal("reinit_missing_repos", &BaseConfig{
 		Enabled:    false,
@@ -124,6 +134,7 @@ func initExtendedTasks() {
 	registerRewriteAllPublicKeys()
 	registerRewriteAllPrincipalKeys()
 	registerRepositoryUpdateHook()
+	registerSyncManagedHookScripts()
 	registerReinitMissingRepositories()
 	registerDeleteMissingRepositories()
 	registerRemoveRandomAvatars()
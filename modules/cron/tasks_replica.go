@@ -0,0 +1,31 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cron
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/services/replication"
+)
+
+func registerSyncReplicaRepositories() {
+	RegisterTaskFatal("sync_replica_repositories", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return replication.SyncAllRepositories(ctx)
+	})
+}
+
+// initReplicaTasks registers the tasks of a read replica, everything touching
+// the shared database is left to the primary
+func initReplicaTasks() {
+	registerSyncReplicaRepositories()
+	registerRepoHealthCheck()
+	registerArchiveCleanup()
+	registerGarbageCollectRepositories()
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package eventarchive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	gouuid "github.com/google/uuid"
+)
+
+// SchemaVersion is the version of the schema of the archived events,
+// it is increased for every incompatible change of the records
+const SchemaVersion = 1
+
+// saveAttempts is the number of attempts to save a batch of events to the storage
+const saveAttempts = 3
+
+// Event is the record of a repository event in the archive, the payload is the payload
+// of the webhooks of the event
+type Event struct {
+	SchemaVersion int             `json:"schema_version"`
+	ID            string          `json:"id"`
+	Event         string          `json:"event"`
+	Created       time.Time       `json:"created_at"`
+	RepoID        int64           `json:"repo_id"`
+	Repo          string          `json:"repository"`
+	OwnerID       int64           `json:"owner_id"`
+	Owner         string          `json:"owner"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// dir returns the directory of the archived events of the repository, it is keyed by
+// ids as the names of owners and repositories change and may be reused by others
+func (e *Event) dir() string {
+	return path.Join(strconv.FormatInt(e.OwnerID, 10), strconv.FormatInt(e.RepoID, 10), fmt.Sprintf("v%d", e.SchemaVersion))
+}
+
+var archiveQueue queue.Queue
+
+// Init starts the queue archiving the repository events
+func Init() error {
+	if !setting.EventArchive.Enabled {
+		return nil
+	}
+
+	archiveQueue = queue.CreateQueue("event_archive", handle, &Event{})
+	if archiveQueue == nil {
+		return fmt.Errorf("Unable to create event_archive Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(archiveQueue.Run)
+	return nil
+}
+
+// Archive adds an event of the repository to the archive
+func Archive(repo *models.Repository, event models.HookEventType, p api.Payloader) error {
+	if archiveQueue == nil {
+		return nil
+	}
+
+	owner := repo.MustOwner()
+	if len(setting.EventArchive.Owners) > 0 && !util.IsStringInSlice(owner.LowerName, setting.EventArchive.Owners) {
+		return nil
+	}
+
+	payload, err := p.JSONPayload()
+	if err != nil {
+		return fmt.Errorf("JSONPayload: %v", err)
+	}
+	return archiveQueue.Push(&Event{
+		SchemaVersion: SchemaVersion,
+		ID:            gouuid.New().String(),
+		Event:         string(event),
+		Created:       time.Now().UTC(),
+		RepoID:        repo.ID,
+		Repo:          repo.Name,
+		OwnerID:       owner.ID,
+		Owner:         owner.Name,
+		Payload:       payload,
+	})
+}
+
+func handle(data ...queue.Data) {
+	// the events of a batch are written to one file per repository
+	batches := make(map[string][]*Event)
+	dirs := make([]string, 0, len(data))
+	for _, datum := range data {
+		e := datum.(*Event)
+		dir := e.dir()
+		if _, ok := batches[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		batches[dir] = append(batches[dir], e)
+	}
+
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, dir := range dirs {
+		if err := saveBatch(ctx, storage.EventArchive, dir, batches[dir]); err != nil {
+			// the events are archived with a later batch instead of being lost
+			log.Error("Failed to archive %d events to %s, requeuing them: %v", len(batches[dir]), dir, err)
+			for _, e := range batches[dir] {
+				if err := archiveQueue.Push(e); err != nil {
+					log.Error("Unable to requeue event %s of repository %d: %v", e.ID, e.RepoID, err)
+				}
+			}
+		}
+	}
+}
+
+// saveBatch writes the events as JSON lines to a new file in the directory of the storage, the file is
+// <owner id>/<repo id>/v<schema version>/<year>/<month>/<day>/<time>-<id of the first event>.jsonl.
+// The retries are given up at shutdown.
+func saveBatch(ctx context.Context, s storage.ObjectStorage, dir string, events []*Event) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+
+	first := events[0].Created
+	p := path.Join(dir, first.Format("2006/01/02"), fmt.Sprintf("%s-%s.jsonl", first.Format("20060102T150405.000000000Z"), events[0].ID))
+
+	var err error
+	for attempt := 1; attempt <= saveAttempts; attempt++ {
+		if _, err = s.Save(p, bytes.NewReader(buf.Bytes())); err == nil {
+			log.Trace("Archived %d events to %s", len(events), p)
+			return nil
+		}
+		if attempt < saveAttempts {
+			log.Warn("Attempt %d to archive events to %s failed: %v", attempt, p, err)
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package eventarchive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveBatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event-archive")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s, err := storage.NewLocalStorage(context.Background(), storage.LocalStorageConfig{Path: dir})
+	assert.NoError(t, err)
+
+	created := time.Date(2020, 10, 15, 10, 30, 0, 0, time.UTC)
+	events := []*Event{
+		{SchemaVersion: SchemaVersion, ID: "a", Event: "push", Created: created, RepoID: 1, Repo: "Repo1", OwnerID: 2, Owner: "User2", Payload: json.RawMessage(`{"ref":"refs/heads/master"}`)},
+		{SchemaVersion: SchemaVersion, ID: "b", Event: "issues", Created: created.Add(time.Second), RepoID: 1, Repo: "Repo1", OwnerID: 2, Owner: "User2", Payload: json.RawMessage(`{"action":"opened"}`)},
+	}
+	assert.EqualValues(t, "2/1/v1", events[0].dir())
+	assert.NoError(t, saveBatch(context.Background(), s, events[0].dir(), events))
+
+	f, err := os.Open(filepath.Join(dir, "2/1/v1/2020/10/15/20201015T103000.000000000Z-a.jsonl"))
+	assert.NoError(t, err)
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	var archived []*Event
+	for scanner.Scan() {
+		e := new(Event)
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), e))
+		archived = append(archived, e)
+	}
+	if assert.Len(t, archived, 2) {
+		assert.EqualValues(t, "push", archived[0].Event)
+		assert.EqualValues(t, 1, archived[0].SchemaVersion)
+		assert.JSONEq(t, `{"action":"opened"}`, string(archived[1].Payload))
+	}
+}
+
+type failingStorage struct {
+	storage.ObjectStorage
+	saves int
+}
+
+func (s *failingStorage) Save(path string, r io.Reader) (int64, error) {
+	s.saves++
+	return 0, errors.New("storage unavailable")
+}
+
+func TestSaveBatchShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the retries are given up at shutdown
+	s := &failingStorage{}
+	events := []*Event{{SchemaVersion: SchemaVersion, ID: "a", Created: time.Now(), RepoID: 1, OwnerID: 2}}
+	start := time.Now()
+	assert.Error(t, saveBatch(ctx, s, events[0].dir(), events))
+	assert.Equal(t, 1, s.saves)
+	assert.True(t, time.Since(start) < time.Second)
+}
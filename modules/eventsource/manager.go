@@ -13,13 +13,16 @@ type Manager struct {
 	mutex sync.Mutex
 
 	messengers map[int64]*Messenger
+	// repoMessengers are the Messengers of the clients listening for the events of a repository
+	repoMessengers map[int64]*Messenger
 }
 
 var manager *Manager
 
 func init() {
 	manager = &Manager{
-		messengers: make(map[int64]*Messenger),
+		messengers:     make(map[int64]*Messenger),
+		repoMessengers: make(map[int64]*Messenger),
 	}
 }
 
@@ -61,6 +64,10 @@ func (m *Manager) UnregisterAll() {
 		messenger.UnregisterAll()
 	}
 	m.messengers = map[int64]*Messenger{}
+	for _, messenger := range m.repoMessengers {
+		messenger.UnregisterAll()
+	}
+	m.repoMessengers = map[int64]*Messenger{}
 }
 
 // SendMessage sends a message to a particular user
@@ -82,3 +89,38 @@ func (m *Manager) SendMessageBlocking(uid int64, message *Event) {
 		messenger.SendMessageBlocking(message)
 	}
 }
+
+// RegisterRepo registers a message channel for the events of a repository
+func (m *Manager) RegisterRepo(repoID int64) <-chan *Event {
+	m.mutex.Lock()
+	messenger, ok := m.repoMessengers[repoID]
+	if !ok {
+		messenger = NewMessenger(repoID)
+		m.repoMessengers[repoID] = messenger
+	}
+	m.mutex.Unlock()
+	return messenger.Register()
+}
+
+// UnregisterRepo unregisters a message channel for the events of a repository
+func (m *Manager) UnregisterRepo(repoID int64, channel <-chan *Event) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	messenger, ok := m.repoMessengers[repoID]
+	if !ok {
+		return
+	}
+	if messenger.Unregister(channel) {
+		delete(m.repoMessengers, repoID)
+	}
+}
+
+// SendRepoMessage sends a message to the clients listening for the events of a repository
+func (m *Manager) SendRepoMessage(repoID int64, message *Event) {
+	m.mutex.Lock()
+	messenger, ok := m.repoMessengers[repoID]
+	m.mutex.Unlock()
+	if ok {
+		messenger.SendMessage(message)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package eventsource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManager_RepoMessages(t *testing.T) {
+	m := &Manager{
+		messengers:     make(map[int64]*Messenger),
+		repoMessengers: make(map[int64]*Messenger),
+	}
+
+	channel := m.RegisterRepo(1)
+	// messages of other repositories and users are not received
+	m.SendRepoMessage(2, &Event{Name: "push"})
+	m.SendMessage(1, &Event{Name: "notification-count"})
+	m.SendRepoMessage(1, &Event{Name: "issue.opened"})
+	event := <-channel
+	assert.Equal(t, "issue.opened", event.Name)
+
+	m.UnregisterRepo(1, channel)
+	_, ok := <-channel
+	assert.False(t, ok)
+	assert.Empty(t, m.repoMessengers)
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package featureflag checks the feature flags gating features which are rolled out gradually.
+//
+// Code of a gated feature checks its flag before offering the feature:
+//
+//	if featureflag.IsEnabled("merge-queue", ctx.User, ctx.Repo.Owner) { ... }
+//
+// and templates use the FeatureEnabled function:
+//
+//	{{if FeatureEnabled "merge-queue" .SignedUser .Repository.Owner}} ... {{end}}
+package featureflag
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var (
+	lock     sync.RWMutex
+	flags    map[string]*models.FeatureFlag
+	loadedAt time.Time
+)
+
+// getFlags returns the cached feature flags, they are reloaded from the database once the cache expired
+func getFlags() map[string]*models.FeatureFlag {
+	lock.RLock()
+	if flags != nil && time.Since(loadedAt) < setting.FeatureFlags.CacheTTL {
+		defer lock.RUnlock()
+		return flags
+	}
+	lock.RUnlock()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if flags != nil && time.Since(loadedAt) < setting.FeatureFlags.CacheTTL {
+		return flags
+	}
+
+	list, err := models.GetFeatureFlags()
+	if err != nil {
+		log.Error("GetFeatureFlags: %v", err)
+		if flags == nil {
+			return map[string]*models.FeatureFlag{}
+		}
+		// keep the stale flags rather than switching all features off
+		loadedAt = time.Now()
+		return flags
+	}
+	flags = make(map[string]*models.FeatureFlag, len(list))
+	for _, f := range list {
+		flags[f.Name] = f
+	}
+	loadedAt = time.Now()
+	return flags
+}
+
+// Invalidate drops the cached feature flags, it is called after a flag changed
+func Invalidate() {
+	lock.Lock()
+	flags = nil
+	lock.Unlock()
+}
+
+// IsEnabled returns true if the feature flag is on for the user, nil for anonymous users,
+// or for one of the owners of what the user is working on, e.g. the owner of the repository.
+// Unknown flags are off.
+func IsEnabled(name string, user *models.User, owners ...*models.User) bool {
+	f, ok := getFlags()[name]
+	if !ok {
+		return false
+	}
+	return f.IsOnFor(user, owners...)
+}
+
+// EnabledFlags returns the names of the feature flags which are on for the user
+func EnabledFlags(user *models.User) []string {
+	names := make([]string, 0, 5)
+	for name, f := range getFlags() {
+		if f.IsOnFor(user) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// Well known commit trailer keys
+const (
+	TrailerCoAuthoredBy = "Co-authored-by"
+	TrailerReviewedBy   = "Reviewed-by"
+	TrailerSignedOffBy  = "Signed-off-by"
+	TrailerFixes        = "Fixes"
+	TrailerCloses       = "Closes"
+)
+
+var commitTrailerPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*):\s*(.*)$`)
+
+// CommitTrailer represents a trailer of a commit message, e.g. "Reviewed-by: A U Thor <author@example.com>"
+type CommitTrailer struct {
+	Key   string
+	Value string
+}
+
+// Is returns true if the trailer has the key, keys are compared case insensitively
+func (t *CommitTrailer) Is(key string) bool {
+	return strings.EqualFold(t.Key, key)
+}
+
+// Signature parses the value of the trailer as "Name <email>", as used by Co-authored-by, Reviewed-by etc.
+func (t *CommitTrailer) Signature() (*Signature, bool) {
+	addr, err := mail.ParseAddress(t.Value)
+	if err != nil {
+		return nil, false
+	}
+	return &Signature{
+		Name:  addr.Name,
+		Email: addr.Address,
+	}, true
+}
+
+// ParseCommitTrailers parses the trailers of a commit message. Like git interpret-trailers they are
+// taken from the last paragraph of the message, which must not be the subject, and every line of it
+// has to be a trailer or the continuation of one.
+func ParseCommitTrailers(message string) []*CommitTrailer {
+	paragraphs := strings.Split(strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n")), "\n\n")
+	if len(paragraphs) < 2 {
+		return nil
+	}
+
+	lines := strings.Split(strings.TrimSpace(paragraphs[len(paragraphs)-1]), "\n")
+	trailers := make([]*CommitTrailer, 0, len(lines))
+	for _, line := range lines {
+		if len(trailers) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			last := trailers[len(trailers)-1]
+			last.Value += " " + strings.TrimSpace(line)
+			continue
+		}
+		m := commitTrailerPattern.FindStringSubmatch(line)
+		if m == nil {
+			return nil
+		}
+		trailers = append(trailers, &CommitTrailer{
+			Key:   m[1],
+			Value: strings.TrimSpace(m[2]),
+		})
+	}
+	return trailers
+}
+
+// StripCommitTrailers removes the trailer paragraph parsed by ParseCommitTrailers from the message
+func StripCommitTrailers(message string) string {
+	if len(ParseCommitTrailers(message)) == 0 {
+		return message
+	}
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	return message[:strings.LastIndex(message, "\n\n")] + "\n"
+}
+
+// Trailers returns the trailers of the commit message
+func (c *Commit) Trailers() []*CommitTrailer {
+	return ParseCommitTrailers(c.CommitMessage)
+}
+
+// CoAuthors returns the co-authors named in the Co-authored-by trailers of the commit message
+func (c *Commit) CoAuthors() []*Signature {
+	var coAuthors []*Signature
+	for _, trailer := range c.Trailers() {
+		if !trailer.Is(TrailerCoAuthoredBy) {
+			continue
+		}
+		if sig, ok := trailer.Signature(); ok {
+			coAuthors = append(coAuthors, sig)
+		}
+	}
+	return coAuthors
+}
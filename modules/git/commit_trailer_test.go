@@ -0,0 +1,49 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommitTrailers(t *testing.T) {
+	trailers := ParseCommitTrailers(`Fix crash on empty repository
+
+Some explanation: of the change.
+
+Fixes: #123
+Reviewed-by: A U Thor <author@example.com>
+Co-authored-by: Jane Doe
+  <jane@example.com>
+`)
+	if assert.Len(t, trailers, 3) {
+		assert.Equal(t, &CommitTrailer{Key: "Fixes", Value: "#123"}, trailers[0])
+		assert.True(t, trailers[1].Is(TrailerReviewedBy))
+		assert.Equal(t, "Jane Doe <jane@example.com>", trailers[2].Value)
+
+		sig, ok := trailers[2].Signature()
+		assert.True(t, ok)
+		assert.Equal(t, "Jane Doe", sig.Name)
+		assert.Equal(t, "jane@example.com", sig.Email)
+	}
+
+	// the subject is never parsed as trailers
+	assert.Empty(t, ParseCommitTrailers("Fixes: #123"))
+	// the last paragraph must only consist of trailers
+	assert.Empty(t, ParseCommitTrailers("Subject\n\nFixes: #123\nand some text"))
+	assert.Empty(t, ParseCommitTrailers("Subject\n\nSigned-off-by: A\n\nSome text"))
+
+	assert.Equal(t, "Subject\n\nSome text\n", StripCommitTrailers("Subject\r\n\r\nSome text\r\n\r\nFixes: #123\r\n"))
+	assert.Equal(t, "Subject\n\nSome text", StripCommitTrailers("Subject\n\nSome text"))
+
+	c := &Commit{CommitMessage: "Subject\r\n\r\nCo-authored-by: Jane Doe <jane@example.com>\r\nCo-authored-by: invalid\r\nCo-Authored-By: John <john@example.com>\r\n"}
+	coAuthors := c.CoAuthors()
+	if assert.Len(t, coAuthors, 2) {
+		assert.Equal(t, "jane@example.com", coAuthors[0].Email)
+		assert.Equal(t, "John", coAuthors[1].Name)
+	}
+}
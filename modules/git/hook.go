@@ -10,6 +10,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"code.gitea.io/gitea/modules/util"
@@ -25,8 +26,27 @@ var hookNames = []string{
 var (
 	// ErrNotValidHook error when a git hook is not valid
 	ErrNotValidHook = errors.New("not a valid Git hook")
+	// ErrNotValidHookScript error when a git hook script name is not valid
+	ErrNotValidHookScript = errors.New("not a valid Git hook script")
 )
 
+// hookScriptNamePattern restricts the names of scripts in a hook chain,
+// so they can never escape the hook's ".d" directory.
+var hookScriptNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+const (
+	// HookScriptGitea is the name of the script that calls back into Gitea,
+	// it must never be modified through the hook script API.
+	HookScriptGitea = "gitea"
+	// HookScriptManagedPrefix is the prefix of scripts synced from the hooks source repository
+	HookScriptManagedPrefix = "managed-"
+)
+
+// IsValidHookScriptName returns true if given name can be used as the name of a hook script.
+func IsValidHookScriptName(name string) bool {
+	return name != HookScriptGitea && hookScriptNamePattern.MatchString(name)
+}
+
 // IsValidHookName returns true if given name is a valid Git hook.
 func IsValidHookName(name string) bool {
 	for _, hn := range hookNames {
@@ -119,6 +139,92 @@ func ListHooks(repoPath string) (_ []*Hook, err error) {
 	return hooks, nil
 }
 
+// HookScript represents one script of a Git hook chain.
+// All executable files in the "<hook>.d" directory are run in order by the delegate hook.
+type HookScript struct {
+	HookName string
+	Name     string
+	Content  string
+	path     string
+}
+
+// IsManaged returns true if the script is synced from the hooks source repository.
+func (s *HookScript) IsManaged() bool {
+	return strings.HasPrefix(s.Name, HookScriptManagedPrefix)
+}
+
+// GetHookScript returns a script of a Git hook chain by given hook and script name.
+func GetHookScript(repoPath, hookName, name string) (*HookScript, error) {
+	if !IsValidHookName(hookName) {
+		return nil, ErrNotValidHook
+	}
+	if !IsValidHookScriptName(name) {
+		return nil, ErrNotValidHookScript
+	}
+	s := &HookScript{
+		HookName: hookName,
+		Name:     name,
+		path:     filepath.Join(repoPath, "hooks", hookName+".d", name),
+	}
+	if isFile(s.path) {
+		data, err := ioutil.ReadFile(s.path)
+		if err != nil {
+			return nil, err
+		}
+		s.Content = string(data)
+	}
+	return s, nil
+}
+
+// ListHookScripts returns the scripts of a Git hook chain, except the one calling back into Gitea.
+func ListHookScripts(repoPath, hookName string) ([]*HookScript, error) {
+	if !IsValidHookName(hookName) {
+		return nil, ErrNotValidHook
+	}
+	dir := filepath.Join(repoPath, "hooks", hookName+".d")
+	if !isDir(dir) {
+		return []*HookScript{}, nil
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	scripts := make([]*HookScript, 0, len(infos))
+	for _, info := range infos {
+		if info.IsDir() || !IsValidHookScriptName(info.Name()) {
+			continue
+		}
+		script, err := GetHookScript(repoPath, hookName, info.Name())
+		if err != nil {
+			return nil, err
+		}
+		scripts = append(scripts, script)
+	}
+	return scripts, nil
+}
+
+// Update writes the content of the script, an empty content removes the script.
+func (s *HookScript) Update() error {
+	if len(strings.TrimSpace(s.Content)) == 0 {
+		return s.Delete()
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path, []byte(strings.ReplaceAll(s.Content, "\r", "")), os.ModePerm)
+}
+
+// Delete removes the script from the hook chain.
+func (s *HookScript) Delete() error {
+	if isExist(s.path) {
+		if err := util.Remove(s.path); err != nil {
+			return err
+		}
+	}
+	s.Content = ""
+	return nil
+}
+
 const (
 	// HookPathUpdate hook update path
 	HookPathUpdate = "hooks/update"
@@ -0,0 +1,75 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidHookScriptName(t *testing.T) {
+	assert.True(t, IsValidHookScriptName("check-commits"))
+	assert.True(t, IsValidHookScriptName("managed-10_lint.sh"))
+	assert.False(t, IsValidHookScriptName(HookScriptGitea))
+	assert.False(t, IsValidHookScriptName(""))
+	assert.False(t, IsValidHookScriptName(".hidden"))
+	assert.False(t, IsValidHookScriptName("../escape"))
+	assert.False(t, IsValidHookScriptName("sub/dir"))
+}
+
+func TestHookScripts(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "hook-scripts")
+	assert.NoError(t, err)
+	defer util.RemoveAll(repoPath)
+
+	hookDir := filepath.Join(repoPath, "hooks", "pre-receive.d")
+	assert.NoError(t, os.MkdirAll(hookDir, os.ModePerm))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(hookDir, HookScriptGitea), []byte("#!/bin/sh\n"), os.ModePerm))
+
+	_, err = GetHookScript(repoPath, "pre-commit", "lint")
+	assert.Equal(t, ErrNotValidHook, err)
+	_, err = GetHookScript(repoPath, "pre-receive", HookScriptGitea)
+	assert.Equal(t, ErrNotValidHookScript, err)
+
+	scripts, err := ListHookScripts(repoPath, "pre-receive")
+	assert.NoError(t, err)
+	assert.Len(t, scripts, 0)
+
+	for _, name := range []string{"b-lint", "a-check", HookScriptManagedPrefix + "policy"} {
+		script, err := GetHookScript(repoPath, "pre-receive", name)
+		assert.NoError(t, err)
+		script.Content = "#!/bin/sh\r\nexit 0\r\n"
+		assert.NoError(t, script.Update())
+	}
+
+	scripts, err = ListHookScripts(repoPath, "pre-receive")
+	assert.NoError(t, err)
+	if assert.Len(t, scripts, 3) {
+		assert.Equal(t, "a-check", scripts[0].Name)
+		assert.Equal(t, "#!/bin/sh\nexit 0\n", scripts[0].Content)
+		assert.False(t, scripts[0].IsManaged())
+		assert.Equal(t, "b-lint", scripts[1].Name)
+		assert.Equal(t, HookScriptManagedPrefix+"policy", scripts[2].Name)
+		assert.True(t, scripts[2].IsManaged())
+	}
+
+	scripts[0].Content = " "
+	assert.NoError(t, scripts[0].Update())
+	assert.NoError(t, scripts[1].Delete())
+
+	scripts, err = ListHookScripts(repoPath, "pre-receive")
+	assert.NoError(t, err)
+	assert.Len(t, scripts, 1)
+
+	scripts, err = ListHookScripts(repoPath, "update")
+	assert.NoError(t, err)
+	assert.Len(t, scripts, 0)
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CodeFrequency represents the lines added and deleted in a top level directory during a week
+type CodeFrequency struct {
+	// Directory is the top level directory, empty for the files in the root of the repository
+	Directory string
+	// Week is the start of the week, sunday 00:00 UTC
+	Week      time.Time
+	Additions int64
+	Deletions int64
+	Commits   int64
+}
+
+// WeekStart returns the start of the week of the time, sunday 00:00 UTC
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day()-int(t.Weekday()), 0, 0, 0, 0, time.UTC)
+}
+
+// topLevelDirectory returns the top level directory of a path in the numstat output
+func topLevelDirectory(path string) string {
+	// paths with special characters are quoted
+	path = strings.TrimPrefix(path, "\"")
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// GetCodeFrequencyByDirectory returns the weekly lines added and deleted per top level directory
+// by the non merge commits reachable from the revision since the given time, the oldest week first
+func (repo *Repository) GetCodeFrequencyByDirectory(revision string, since time.Time) ([]*CodeFrequency, error) {
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = stdoutReader.Close()
+		_ = stdoutWriter.Close()
+	}()
+
+	type key struct {
+		directory string
+		week      int64
+	}
+	frequencies := make(map[key]*CodeFrequency)
+
+	stderr := new(strings.Builder)
+	err = NewCommand("-c", "core.quotepath=false", "log", "--numstat", "--no-merges", "--no-renames", "--pretty=format:---%n%ct",
+		fmt.Sprintf("--since=%d", since.Unix()), revision, "--").RunInDirTimeoutEnvFullPipelineFunc(
+		nil, -1, repo.Path,
+		stdoutWriter, stderr, nil,
+		func(ctx context.Context, cancel context.CancelFunc) error {
+			_ = stdoutWriter.Close()
+
+			scanner := bufio.NewScanner(stdoutReader)
+			scanner.Split(bufio.ScanLines)
+			var week time.Time
+			// the directories changed by the current commit
+			var directories map[string]bool
+			p := 0
+			for scanner.Scan() {
+				l := strings.TrimSpace(scanner.Text())
+				if l == "---" {
+					p = 1
+					directories = make(map[string]bool)
+					continue
+				} else if p == 0 {
+					continue
+				}
+				p++
+				if p == 2 { // Commit time
+					unix, err := strconv.ParseInt(l, 10, 64)
+					if err != nil {
+						return fmt.Errorf("invalid commit time %q: %w", l, err)
+					}
+					week = WeekStart(time.Unix(unix, 0))
+					continue
+				}
+
+				// Changed file
+				parts := strings.SplitN(l, "\t", 3)
+				if len(parts) < 3 {
+					continue
+				}
+				k := key{topLevelDirectory(parts[2]), week.Unix()}
+				f, ok := frequencies[k]
+				if !ok {
+					f = &CodeFrequency{Directory: k.directory, Week: week}
+					frequencies[k] = f
+				}
+				// binary files are listed with "-"
+				if c, err := strconv.ParseInt(parts[0], 10, 64); err == nil {
+					f.Additions += c
+				}
+				if c, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+					f.Deletions += c
+				}
+				if !directories[k.directory] {
+					directories[k.directory] = true
+					f.Commits++
+				}
+			}
+			_ = stdoutReader.Close()
+			return scanner.Err()
+		})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get GetCodeFrequencyByDirectory for repository.\nError: %w\nStderr: %s", err, stderr)
+	}
+
+	result := make([]*CodeFrequency, 0, len(frequencies))
+	for _, f := range frequencies {
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Week.Equal(result[j].Week) {
+			return result[i].Week.Before(result[j].Week)
+		}
+		return result[i].Directory < result[j].Directory
+	})
+	return result, nil
+}
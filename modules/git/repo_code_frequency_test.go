@@ -0,0 +1,50 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeekStart(t *testing.T) {
+	// wednesday
+	tm := time.Date(2017, 12, 20, 6, 15, 9, 0, time.UTC)
+	assert.Equal(t, time.Date(2017, 12, 17, 0, 0, 0, 0, time.UTC), WeekStart(tm))
+	// sunday
+	tm = time.Date(2018, 4, 15, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2018, 4, 15, 0, 0, 0, 0, time.UTC), WeekStart(tm))
+}
+
+func TestRepository_GetCodeFrequencyByDirectory(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	bareRepo1, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	frequencies, err := bareRepo1.GetCodeFrequencyByDirectory("master", time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	if assert.Len(t, frequencies, 2) {
+		assert.Equal(t, &CodeFrequency{
+			Directory: "",
+			Week:      time.Date(2017, 12, 17, 0, 0, 0, 0, time.UTC),
+			Additions: 2,
+			Commits:   2,
+		}, frequencies[0])
+		assert.Equal(t, &CodeFrequency{
+			Directory: "foo",
+			Week:      time.Date(2018, 4, 15, 0, 0, 0, 0, time.UTC),
+			Additions: 5,
+			Commits:   3,
+		}, frequencies[1])
+	}
+
+	frequencies, err = bareRepo1.GetCodeFrequencyByDirectory("master", time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, frequencies, 1)
+}
@@ -20,9 +20,21 @@ import (
 
 // CompareInfo represents needed information for comparing references.
 type CompareInfo struct {
-	MergeBase string
-	Commits   *list.List
-	NumFiles  int
+	MergeBase    string
+	BaseCommitID string
+	Commits      *list.List
+	NumFiles     int
+	// DirectComparison is set if the head is compared with the base itself (base..head)
+	// instead of with the merge base of both (base...head)
+	DirectComparison bool
+}
+
+// CompareStartCommitID returns the commit the changes of the comparison start from
+func (ci *CompareInfo) CompareStartCommitID() string {
+	if ci.DirectComparison {
+		return ci.BaseCommitID
+	}
+	return ci.MergeBase
 }
 
 // GetMergeBase checks and returns merge base of two branches and the reference used as base.
@@ -45,7 +57,9 @@ func (repo *Repository) GetMergeBase(tmpRemote string, base, head string) (strin
 }
 
 // GetCompareInfo generates and returns compare information between base and head branches of repositories.
-func (repo *Repository) GetCompareInfo(basePath, baseBranch, headBranch string) (_ *CompareInfo, err error) {
+// If directComparison is set the changes are computed between base and head (base..head), otherwise
+// between the merge base of both and head (base...head).
+func (repo *Repository) GetCompareInfo(basePath, baseBranch, headBranch string, directComparison bool) (_ *CompareInfo, err error) {
 	var (
 		remoteBranch string
 		tmpRemote    string
@@ -65,11 +79,15 @@ func (repo *Repository) GetCompareInfo(basePath, baseBranch, headBranch string)
 		}()
 	}
 
-	compareInfo := new(CompareInfo)
+	compareInfo := &CompareInfo{DirectComparison: directComparison}
 	compareInfo.MergeBase, remoteBranch, err = repo.GetMergeBase(tmpRemote, baseBranch, headBranch)
 	if err == nil {
 		// We have a common base - therefore we know that ... should work
-		logs, err := NewCommand("log", compareInfo.MergeBase+"..."+headBranch, prettyLogFormat).RunInDirBytes(repo.Path)
+		logRange := compareInfo.MergeBase + "..." + headBranch
+		if directComparison {
+			logRange = remoteBranch + ".." + headBranch
+		}
+		logs, err := NewCommand("log", logRange, prettyLogFormat).RunInDirBytes(repo.Path)
 		if err != nil {
 			return nil, err
 		}
@@ -85,10 +103,15 @@ func (repo *Repository) GetCompareInfo(basePath, baseBranch, headBranch string)
 		}
 	}
 
+	compareInfo.BaseCommitID, err = GetFullCommitID(repo.Path, remoteBranch)
+	if err != nil {
+		compareInfo.BaseCommitID = remoteBranch
+	}
+
 	// Count number of changed files.
 	// This probably should be removed as we need to use shortstat elsewhere
 	// Now there is git diff --shortstat but this appears to be slower than simply iterating with --nameonly
-	compareInfo.NumFiles, err = repo.GetDiffNumChangedFiles(remoteBranch, headBranch)
+	compareInfo.NumFiles, err = repo.GetDiffNumChangedFiles(remoteBranch, headBranch, directComparison)
 	if err != nil {
 		return nil, err
 	}
@@ -108,12 +131,17 @@ func (l *lineCountWriter) Write(p []byte) (n int, err error) {
 
 // GetDiffNumChangedFiles counts the number of changed files
 // This is substantially quicker than shortstat but...
-func (repo *Repository) GetDiffNumChangedFiles(base, head string) (int, error) {
+func (repo *Repository) GetDiffNumChangedFiles(base, head string, directComparison bool) (int, error) {
 	// Now there is git diff --shortstat but this appears to be slower than simply iterating with --nameonly
 	w := &lineCountWriter{}
 	stderr := new(bytes.Buffer)
 
-	if err := NewCommand("diff", "-z", "--name-only", base+"..."+head).
+	separator := "..."
+	if directComparison {
+		separator = ".."
+	}
+
+	if err := NewCommand("diff", "-z", "--name-only", base+separator+head).
 		RunInDirPipeline(repo.Path, w, stderr); err != nil {
 		if strings.Contains(stderr.String(), "no merge base") {
 			// git >= 2.28 now returns an error if base and head have become unrelated.
@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/modules/util"
 	"github.com/stretchr/testify/assert"
@@ -31,3 +32,33 @@ func TestGetFormatPatch(t *testing.T) {
 	assert.Regexp(t, "^From 8d92fc95", patch)
 	assert.Contains(t, patch, "Subject: [PATCH] Add file2.txt")
 }
+
+func TestGetCompareInfo(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	// work on a clone, the comparison must not change the shared test repository
+	clonedPath, err := ioutil.TempDir("", "repo1_TestGetCompareInfo")
+	assert.NoError(t, err)
+	defer util.RemoveAll(clonedPath)
+	assert.NoError(t, Clone(bareRepo1Path, clonedPath, CloneRepoOptions{Bare: true, Quiet: true, Timeout: 5 * time.Minute}))
+	repo, err := OpenRepository(clonedPath)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	// branch1 and branch2 both branched off from "Add file1.txt"
+	compareInfo, err := repo.GetCompareInfo(repo.Path, "branch1", "branch2", false)
+	assert.NoError(t, err)
+	assert.False(t, compareInfo.DirectComparison)
+	assert.Equal(t, "95bb4d39648ee7e325106df01a621c530863a653", compareInfo.MergeBase)
+	assert.Equal(t, "2839944139e0de9737a044f78b0e4b40d989a9e3", compareInfo.BaseCommitID)
+	assert.Equal(t, compareInfo.MergeBase, compareInfo.CompareStartCommitID())
+	assert.Equal(t, 2, compareInfo.Commits.Len())
+	assert.Equal(t, 2, compareInfo.NumFiles)
+
+	compareInfo, err = repo.GetCompareInfo(repo.Path, "branch1", "branch2", true)
+	assert.NoError(t, err)
+	assert.True(t, compareInfo.DirectComparison)
+	assert.Equal(t, compareInfo.BaseCommitID, compareInfo.CompareStartCommitID())
+	assert.Equal(t, 2, compareInfo.Commits.Len())
+	// the changes of branch1 are reverted by the direct comparison as well
+	assert.Equal(t, 4, compareInfo.NumFiles)
+}
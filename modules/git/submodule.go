@@ -113,6 +113,11 @@ func getRefURL(refURL, urlPrefix, repoFullName, sshDomain string) string {
 	return ""
 }
 
+// RefURL guesses and returns reference URL.
+func (sm *SubModule) RefURL(urlPrefix, repoFullName, sshDomain string) string {
+	return getRefURL(sm.URL, urlPrefix, repoFullName, sshDomain)
+}
+
 // RefURL guesses and returns reference URL.
 func (sf *SubModuleFile) RefURL(urlPrefix, repoFullName, sshDomain string) string {
 	return getRefURL(sf.refURL, urlPrefix, repoFullName, sshDomain)
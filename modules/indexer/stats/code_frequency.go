@@ -0,0 +1,125 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// codeFrequencyQueue represents a queue to handle repository code frequency updates
+var codeFrequencyQueue queue.UniqueQueue
+
+func handleCodeFrequency(data ...queue.Data) {
+	for _, datum := range data {
+		id := datum.(int64)
+		if err := updateCodeFrequency(id); err != nil {
+			log.Error("code frequency queue updateCodeFrequency(%d) failed: %v", id, err)
+		}
+	}
+}
+
+func initCodeFrequencyQueue() error {
+	codeFrequencyQueue = queue.CreateUniqueQueue("repo_code_frequency_update", handleCodeFrequency, int64(0)).(queue.UniqueQueue)
+	if codeFrequencyQueue == nil {
+		return fmt.Errorf("Unable to create repo_code_frequency_update Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(codeFrequencyQueue.Run)
+
+	return nil
+}
+
+// codeFrequencySince returns the start of the oldest week the code frequency is computed for
+func codeFrequencySince() time.Time {
+	return git.WeekStart(time.Now()).AddDate(0, 0, -7*(models.CodeFrequencyWeeks-1))
+}
+
+// updateCodeFrequency computes and saves the code frequency of the default branch of the repository
+func updateCodeFrequency(id int64) error {
+	repo, err := models.GetRepositoryByID(id)
+	if err != nil {
+		return err
+	}
+	if repo.IsEmpty {
+		return nil
+	}
+
+	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeCodeFrequency)
+	if err != nil {
+		return err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	commitID, err := gitRepo.GetBranchCommitID(repo.DefaultBranch)
+	if err != nil {
+		return err
+	}
+
+	// Do not recalculate the code frequency if already calculated for this commit
+	if status.CommitSha == commitID {
+		return nil
+	}
+
+	weeks, err := gitRepo.GetCodeFrequencyByDirectory(commitID, codeFrequencySince())
+	if err != nil {
+		return err
+	}
+	frequencies := make([]*models.CodeFrequency, 0, len(weeks))
+	for _, w := range weeks {
+		frequencies = append(frequencies, &models.CodeFrequency{
+			Directory: w.Directory,
+			WeekUnix:  timeutil.TimeStamp(w.Week.Unix()),
+			Additions: w.Additions,
+			Deletions: w.Deletions,
+			Commits:   w.Commits,
+		})
+	}
+	return repo.UpdateCodeFrequencies(commitID, frequencies)
+}
+
+// GetCodeFrequency returns the code frequency per top level directory of the default branch of the repository
+// for the last weeks. The code frequency is computed in the background if it is outdated for the given head commit,
+// false is returned if it has never been computed.
+func GetCodeFrequency(repo *models.Repository, commitID string) ([]*models.DirectoryCodeFrequency, bool, error) {
+	if repo.IsEmpty {
+		return []*models.DirectoryCodeFrequency{}, true, nil
+	}
+
+	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeCodeFrequency)
+	if err != nil {
+		return nil, false, err
+	}
+	if status.CommitSha != commitID {
+		if err := codeFrequencyQueue.Push(repo.ID); err != nil {
+			if err != queue.ErrAlreadyInQueue {
+				return nil, false, err
+			}
+			log.Debug("Repo ID: %d already queued", repo.ID)
+		}
+		// show the outdated code frequency while it is computed
+		if len(status.CommitSha) == 0 {
+			return nil, false, nil
+		}
+	}
+
+	frequencies, err := repo.GetCodeFrequencies(timeutil.TimeStamp(codeFrequencySince().Unix()))
+	if err != nil {
+		return nil, false, err
+	}
+	return models.GroupCodeFrequenciesByDirectory(frequencies), true, nil
+}
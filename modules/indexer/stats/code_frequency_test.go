@@ -0,0 +1,31 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateCodeFrequency(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	assert.NoError(t, updateCodeFrequency(1))
+
+	repo, err := models.GetRepositoryByID(1)
+	assert.NoError(t, err)
+	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeCodeFrequency)
+	assert.NoError(t, err)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", status.CommitSha)
+
+	// the commits of the repository are older than the computed weeks
+	frequencies, ready, err := GetCodeFrequency(repo, status.CommitSha)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+	assert.Empty(t, frequencies)
+}
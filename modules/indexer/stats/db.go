@@ -7,6 +7,8 @@ package stats
 import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/license"
+	"code.gitea.io/gitea/modules/setting"
 )
 
 // DBIndexer implements Indexer interface to use database's like search
@@ -45,6 +47,10 @@ func (db *DBIndexer) Index(id int64) error {
 		return nil
 	}
 
+	if err := updateLicenses(repo, gitRepo, commitID); err != nil {
+		return err
+	}
+
 	// Calculate and save language statistics to database
 	stats, err := gitRepo.GetLanguageStats(commitID)
 	if err != nil {
@@ -53,6 +59,26 @@ func (db *DBIndexer) Index(id int64) error {
 	return repo.UpdateLanguageStats(commitID, stats)
 }
 
+// updateLicenses detects and saves the licenses of the repository at the given commit
+func updateLicenses(repo *models.Repository, gitRepo *git.Repository, commitID string) error {
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		return err
+	}
+
+	repo.License, err = license.DetectRepoLicense(commit)
+	if err != nil {
+		return err
+	}
+	repo.FileLicenses = nil
+	if setting.Repository.DetectFileLicenses {
+		if repo.FileLicenses, err = license.DetectFileLicenses(gitRepo, commit); err != nil {
+			return err
+		}
+	}
+	return models.UpdateRepositoryCols(repo, "license", "file_licenses")
+}
+
 // Close dummy function
 func (db *DBIndexer) Close() {
 }
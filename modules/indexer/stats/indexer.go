@@ -26,6 +26,9 @@ func Init() error {
 	if err := initStatsQueue(); err != nil {
 		return err
 	}
+	if err := initCodeFrequencyQueue(); err != nil {
+		return err
+	}
 
 	go populateRepoIndexer()
 
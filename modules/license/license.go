@@ -0,0 +1,137 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package license
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/options"
+)
+
+// NoAssertion is the SPDX identifier used for license files which could not be recognized
+const NoAssertion = "NOASSERTION"
+
+// MinSimilarity is the minimum similarity of a license file to a known license text to be detected as such
+const MinSimilarity = 0.9
+
+var (
+	spdxIdentifierPattern = regexp.MustCompile(`SPDX-License-Identifier:\s*([^\r\n]+)`)
+	// spdxTrailingPattern matches comment terminators following an identifier in a source file header
+	spdxTrailingPattern = regexp.MustCompile(`\s*(\*/|-->|--%>|\*\)|#\}|\}\}).*$`)
+	placeholderPattern  = regexp.MustCompile(`[<\[][^>\]]*[>\]]`)
+	copyrightPattern    = regexp.MustCompile(`(?im)^.*copyright (\(c\)|©|\d{4}).*$`)
+
+	knownLicensesOnce sync.Once
+	knownLicenses     []*knownLicense
+)
+
+type knownLicense struct {
+	name  string
+	words map[string]struct{}
+}
+
+// IsLicenseFile reports whether name looks like a license file, e.g. LICENSE, LICENSE.md or COPYING
+func IsLicenseFile(name string) bool {
+	name = strings.ToLower(name)
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		switch name[i:] {
+		case ".md", ".txt", ".markdown", ".rst":
+			name = name[:i]
+		default:
+			return false
+		}
+	}
+	switch name {
+	case "license", "licence", "copying", "unlicense", "license-mit", "license-apache":
+		return true
+	}
+	return false
+}
+
+// wordSet returns the set of normalized words of a license text,
+// ignoring placeholders and copyright notices
+func wordSet(content []byte) map[string]struct{} {
+	content = copyrightPattern.ReplaceAll(content, nil)
+	content = placeholderPattern.ReplaceAll(content, nil)
+	words := make(map[string]struct{})
+	for _, word := range bytes.FieldsFunc(bytes.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		words[string(word)] = struct{}{}
+	}
+	return words
+}
+
+func loadKnownLicenses() {
+	names, err := options.Dir("license")
+	if err != nil {
+		log.Error("Unable to list licenses: %v", err)
+		return
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		content, err := options.License(name)
+		if err != nil {
+			log.Error("Unable to load license %s: %v", name, err)
+			continue
+		}
+		knownLicenses = append(knownLicenses, &knownLicense{
+			name:  name,
+			words: wordSet(content),
+		})
+	}
+}
+
+// similarity returns the Sørensen–Dice coefficient of two word sets
+func similarity(a, b map[string]struct{}) float64 {
+	if len(a)+len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for word := range a {
+		if _, ok := b[word]; ok {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// Detect returns the SPDX identifier of the license text of a license file.
+// An explicit SPDX-License-Identifier tag takes precedence over the text,
+// NoAssertion is returned if the text matches no known license.
+func Detect(content []byte) string {
+	if ids := ParseSPDXIdentifiers(content); len(ids) > 0 {
+		return ids[0]
+	}
+
+	knownLicensesOnce.Do(loadKnownLicenses)
+
+	words := wordSet(content)
+	best, bestSimilarity := NoAssertion, MinSimilarity
+	for _, known := range knownLicenses {
+		if s := similarity(words, known.words); s >= bestSimilarity && (s > bestSimilarity || best == NoAssertion) {
+			best, bestSimilarity = known.name, s
+		}
+	}
+	return best
+}
+
+// ParseSPDXIdentifiers returns the license expressions of all SPDX-License-Identifier tags in content
+func ParseSPDXIdentifiers(content []byte) []string {
+	var ids []string
+	for _, match := range spdxIdentifierPattern.FindAllSubmatch(content, -1) {
+		id := strings.TrimSpace(spdxTrailingPattern.ReplaceAllString(string(match[1]), ""))
+		if len(id) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package license
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsLicenseFile(t *testing.T) {
+	for _, name := range []string{"LICENSE", "license.md", "LICENCE.txt", "COPYING", "UNLICENSE"} {
+		assert.True(t, IsLicenseFile(name), name)
+	}
+	for _, name := range []string{"README.md", "LICENSE.go", "licenses", ".license"} {
+		assert.False(t, IsLicenseFile(name), name)
+	}
+}
+
+func TestParseSPDXIdentifiers(t *testing.T) {
+	assert.Equal(t, []string{"MIT", "GPL-2.0-or-later OR Apache-2.0", "BSD-3-Clause"}, ParseSPDXIdentifiers([]byte(
+		"// SPDX-License-Identifier: MIT\n"+
+			"/* SPDX-License-Identifier: GPL-2.0-or-later OR Apache-2.0 */\n"+
+			"<!-- SPDX-License-Identifier: BSD-3-Clause -->\r\n")))
+	assert.Empty(t, ParseSPDXIdentifiers([]byte("package main\n")))
+}
+
+func TestDetect(t *testing.T) {
+	setting.StaticRootPath = filepath.Join("..", "..")
+
+	mit, err := ioutil.ReadFile(filepath.Join("..", "..", "options", "license", "MIT"))
+	assert.NoError(t, err)
+	filled := strings.Replace(string(mit), "<year> <copyright holders>", "2020 The Gitea Authors", 1)
+	assert.Equal(t, "MIT", Detect([]byte(filled)))
+
+	own, err := ioutil.ReadFile(filepath.Join("..", "..", "LICENSE"))
+	assert.NoError(t, err)
+	assert.Equal(t, "MIT", Detect(own))
+
+	apache, err := ioutil.ReadFile(filepath.Join("..", "..", "options", "license", "Apache-2.0"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Apache-2.0", Detect(apache))
+
+	assert.Equal(t, "ISC", Detect([]byte("SPDX-License-Identifier: ISC\n\nSee the website for details.")))
+	assert.Equal(t, NoAssertion, Detect([]byte("All rights reserved. Do not copy.")))
+}
+
+func TestDetectFileLicenses(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "licenses")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	assert.NoError(t, git.InitRepository(tmpDir, false))
+	assert.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "cmd"), os.ModePerm))
+	for name, content := range map[string]string{
+		"main.go":     "// SPDX-License-Identifier: MIT\npackage main\n",
+		"cmd/run.sh":  "#!/bin/sh\n# SPDX-License-Identifier: Apache-2.0\n",
+		"empty.txt":   "",
+		"late.txt":    strings.Repeat("x", fileHeaderSize) + "\n# SPDX-License-Identifier: GPL-2.0-only\n",
+		"another.go":  "// SPDX-License-Identifier: MIT\n",
+		"README.md":   "# README\n",
+		"generated.c": strings.Repeat("/* generated */\n", 1000),
+	} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+	assert.NoError(t, git.AddChanges(tmpDir, true))
+	sig := &git.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	assert.NoError(t, git.CommitChanges(tmpDir, git.CommitChangesOptions{Committer: sig, Message: "init"}))
+
+	gitRepo, err := git.OpenRepository(tmpDir)
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+	commit, err := gitRepo.GetCommit("HEAD")
+	assert.NoError(t, err)
+
+	// there is no license file in the root of the repository
+	repoLicense, err := DetectRepoLicense(commit)
+	assert.NoError(t, err)
+	assert.Empty(t, repoLicense)
+
+	licenses, err := DetectFileLicenses(gitRepo, commit)
+	assert.NoError(t, err)
+	// only the headers of the files are searched
+	assert.Equal(t, []string{"Apache-2.0", "MIT"}, licenses)
+}
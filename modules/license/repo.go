@@ -0,0 +1,141 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package license
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/git/pipeline"
+)
+
+const (
+	// maxLicenseFileSize is the maximum number of bytes of a license file that are read
+	maxLicenseFileSize = 256 * 1024
+	// fileHeaderSize is the number of bytes of a file searched for SPDX identifiers
+	fileHeaderSize = 1024
+)
+
+func readBlob(blob *git.Blob, limit int64) ([]byte, error) {
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer dataRc.Close()
+	return ioutil.ReadAll(io.LimitReader(dataRc, limit))
+}
+
+// DetectRepoLicense detects the license of the repository tree at commit from the license file in its root,
+// it returns an empty string if there is no license file
+func DetectRepoLicense(commit *git.Commit) (string, error) {
+	entries, err := commit.Tree.ListEntries()
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		if !(entry.IsRegular() || entry.IsExecutable()) || !IsLicenseFile(entry.Name()) {
+			continue
+		}
+		content, err := readBlob(entry.Blob(), maxLicenseFileSize)
+		if err != nil {
+			return "", err
+		}
+		return Detect(content), nil
+	}
+	return "", nil
+}
+
+// DetectFileLicenses returns the sorted unique SPDX license expressions
+// tagged in the headers of all files of the repository tree at commit
+func DetectFileLicenses(gitRepo *git.Repository, commit *git.Commit) ([]string, error) {
+	entries, err := commit.Tree.ListEntriesRecursive()
+	if err != nil {
+		return nil, err
+	}
+
+	// the blobs are read by a single cat-file --batch rather than a git process per file
+	shas := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsRegular() || entry.IsExecutable() {
+			shas = append(shas, entry.ID.String())
+		}
+	}
+
+	shasToBatchReader, shasToBatchWriter := io.Pipe()
+	catFileBatchReader, catFileBatchWriter := io.Pipe()
+	defer catFileBatchReader.Close()
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go pipeline.CatFileBatch(shasToBatchReader, catFileBatchWriter, &wg, gitRepo.Path)
+	go func() {
+		for _, sha := range shas {
+			if _, err := shasToBatchWriter.Write([]byte(sha + "\n")); err != nil {
+				_ = shasToBatchWriter.CloseWithError(err)
+				return
+			}
+		}
+		_ = shasToBatchWriter.Close()
+	}()
+
+	seen := make(map[string]bool)
+	licenses := make([]string, 0, 2)
+	bufferedReader := bufio.NewReader(catFileBatchReader)
+	header := make([]byte, fileHeaderSize)
+	for range shas {
+		header, err = readBatchBlobHeader(bufferedReader, header[:fileHeaderSize])
+		if err != nil {
+			_ = catFileBatchReader.CloseWithError(err)
+			wg.Wait()
+			return nil, err
+		}
+		for _, id := range ParseSPDXIdentifiers(header) {
+			if !seen[id] {
+				seen[id] = true
+				licenses = append(licenses, id)
+			}
+		}
+	}
+	wg.Wait()
+
+	sort.Strings(licenses)
+	return licenses, nil
+}
+
+// readBatchBlobHeader reads the next blob of the output of cat-file --batch, it returns at most
+// the first len(buf) bytes of it and discards the rest
+func readBatchBlobHeader(rd *bufio.Reader, buf []byte) ([]byte, error) {
+	// <sha> <type> <size>
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return nil, fmt.Errorf("unexpected cat-file --batch output: %q", line)
+	}
+	size, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	if size < int64(len(buf)) {
+		buf = buf[:size]
+	}
+	if _, err := io.ReadFull(rd, buf); err != nil {
+		return nil, err
+	}
+	// discard the rest of the blob and the trailing newline
+	if _, err := rd.Discard(int(size) - len(buf) + 1); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
@@ -170,6 +170,30 @@ type postProcessCtx struct {
 
 	// processors used by this context.
 	procs []processor
+
+	issueKeywords       *references.IssueKeywords
+	issueKeywordsParsed bool
+}
+
+// getIssueKeywords returns the keywords closing and reopening issues of the metas,
+// nil for the keywords of the settings
+func (ctx *postProcessCtx) getIssueKeywords() *references.IssueKeywords {
+	if !ctx.issueKeywordsParsed {
+		ctx.issueKeywordsParsed = true
+		closeKeywords, hasClose := ctx.metas["closeKeywords"]
+		reopenKeywords, hasReopen := ctx.metas["reopenKeywords"]
+		if hasClose || hasReopen {
+			ctx.issueKeywords = references.NewIssueKeywords(splitKeywords(closeKeywords), splitKeywords(reopenKeywords))
+		}
+	}
+	return ctx.issueKeywords
+}
+
+func splitKeywords(keywords string) []string {
+	if keywords == "" {
+		return nil
+	}
+	return strings.Split(keywords, ",")
 }
 
 // PostProcess does the final required transformations to the passed raw HTML
@@ -787,9 +811,10 @@ func issueIndexPatternProcessor(ctx *postProcessCtx, node *html.Node) {
 
 	// Repos with external issue trackers might still need to reference local PRs
 	// We need to concern with the first one that shows up in the text, whichever it is
-	found, ref = references.FindRenderizableReferenceNumeric(node.Data, exttrack && alphanum)
+	keywords := ctx.getIssueKeywords()
+	found, ref = keywords.FindRenderizableReferenceNumeric(node.Data, exttrack && alphanum)
 	if exttrack && alphanum {
-		if found2, ref2 := references.FindRenderizableReferenceAlphanumeric(node.Data); found2 {
+		if found2, ref2 := keywords.FindRenderizableReferenceAlphanumeric(node.Data); found2 {
 			if !found || ref2.RefLocation.Start < ref.RefLocation.Start {
 				found = true
 				ref = ref2
@@ -43,81 +43,83 @@ func NewGiteaParseContext(urlPrefix string, metas map[string]string, isWiki bool
 	return pc
 }
 
-// render renders Markdown to HTML without handling special links.
-func render(body []byte, urlPrefix string, metas map[string]string, wikiMarkdown bool) []byte {
-	once.Do(func() {
-		converter = goldmark.New(
-			goldmark.WithExtensions(extension.Table,
-				extension.Strikethrough,
-				extension.TaskList,
-				extension.DefinitionList,
-				common.FootnoteExtension,
-				highlighting.NewHighlighting(
-					highlighting.WithFormatOptions(
-						chromahtml.WithClasses(true),
-						chromahtml.PreventSurroundingPre(true),
-					),
-					highlighting.WithWrapperRenderer(func(w util.BufWriter, c highlighting.CodeBlockContext, entering bool) {
-						if entering {
-							language, _ := c.Language()
-							if language == nil {
-								language = []byte("text")
-							}
-
-							languageStr := string(language)
+// initConverter creates the goldmark converter shared by all renders
+func initConverter() {
+	converter = goldmark.New(
+		goldmark.WithExtensions(extension.Table,
+			extension.Strikethrough,
+			extension.TaskList,
+			extension.DefinitionList,
+			common.FootnoteExtension,
+			highlighting.NewHighlighting(
+				highlighting.WithFormatOptions(
+					chromahtml.WithClasses(true),
+					chromahtml.PreventSurroundingPre(true),
+				),
+				highlighting.WithWrapperRenderer(func(w util.BufWriter, c highlighting.CodeBlockContext, entering bool) {
+					if entering {
+						language, _ := c.Language()
+						if language == nil {
+							language = []byte("text")
+						}
 
-							preClasses := []string{}
-							if languageStr == "mermaid" {
-								preClasses = append(preClasses, "is-loading")
-							}
+						languageStr := string(language)
 
-							if len(preClasses) > 0 {
-								_, err := w.WriteString(`<pre class="` + strings.Join(preClasses, " ") + `">`)
-								if err != nil {
-									return
-								}
-							} else {
-								_, err := w.WriteString(`<pre>`)
-								if err != nil {
-									return
-								}
-							}
+						preClasses := []string{}
+						if languageStr == "mermaid" {
+							preClasses = append(preClasses, "is-loading")
+						}
 
-							// include language-x class as part of commonmark spec
-							_, err := w.WriteString(`<code class="chroma language-` + string(language) + `">`)
+						if len(preClasses) > 0 {
+							_, err := w.WriteString(`<pre class="` + strings.Join(preClasses, " ") + `">`)
 							if err != nil {
 								return
 							}
 						} else {
-							_, err := w.WriteString("</code></pre>")
+							_, err := w.WriteString(`<pre>`)
 							if err != nil {
 								return
 							}
 						}
-					}),
-				),
-				meta.Meta,
-			),
-			goldmark.WithParserOptions(
-				parser.WithAttribute(),
-				parser.WithAutoHeadingID(),
-				parser.WithASTTransformers(
-					util.Prioritized(&ASTTransformer{}, 10000),
-				),
-			),
-			goldmark.WithRendererOptions(
-				html.WithUnsafe(),
-			),
-		)
 
-		// Override the original Tasklist renderer!
-		converter.Renderer().AddOptions(
-			renderer.WithNodeRenderers(
-				util.Prioritized(NewHTMLRenderer(), 10),
+						// include language-x class as part of commonmark spec
+						_, err := w.WriteString(`<code class="chroma language-` + string(language) + `">`)
+						if err != nil {
+							return
+						}
+					} else {
+						_, err := w.WriteString("</code></pre>")
+						if err != nil {
+							return
+						}
+					}
+				}),
+			),
+			meta.Meta,
+		),
+		goldmark.WithParserOptions(
+			parser.WithAttribute(),
+			parser.WithAutoHeadingID(),
+			parser.WithASTTransformers(
+				util.Prioritized(&ASTTransformer{}, 10000),
 			),
-		)
+		),
+		goldmark.WithRendererOptions(
+			html.WithUnsafe(),
+		),
+	)
+
+	// Override the original Tasklist renderer!
+	converter.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.Prioritized(NewHTMLRenderer(), 10),
+		),
+	)
+}
 
-	})
+// render renders Markdown to HTML without handling special links.
+func render(body []byte, urlPrefix string, metas map[string]string, wikiMarkdown bool) []byte {
+	once.Do(initConverter)
 
 	pc := NewGiteaParseContext(urlPrefix, metas, wikiMarkdown)
 	var buf bytes.Buffer
@@ -308,3 +308,19 @@ func TestRender_RenderParagraphs(t *testing.T) {
 	test(t, "A\n\nB\nC\n", 2)
 	test(t, "A\n\n\nB\nC\n", 2)
 }
+
+func TestExtractTOC(t *testing.T) {
+	doc := "---\ntitle: Guide\n---\n# Guide\n\nIntro\n\n## Install *now*\n\n### From source\n\n## Install *now*\n\n    # not a heading\n"
+	toc := ExtractTOC([]byte(doc))
+	assert.Equal(t, []Header{
+		{Level: 1, Text: "Guide", ID: "user-content-guide"},
+		{Level: 2, Text: "Install now", ID: "user-content-install-now"},
+		{Level: 3, Text: "From source", ID: "user-content-from-source"},
+		{Level: 2, Text: "Install now", ID: "user-content-install-now-1"},
+	}, toc)
+
+	res := RenderRaw([]byte(doc), "", false)
+	for _, header := range toc {
+		assert.Contains(t, string(res), `id="`+header.ID+`"`)
+	}
+}
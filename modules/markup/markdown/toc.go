@@ -8,10 +8,43 @@ import (
 	"fmt"
 	"net/url"
 
+	giteautil "code.gitea.io/gitea/modules/util"
+
 	"github.com/unknwon/i18n"
 	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
+// ExtractTOC returns the headers of a Markdown document in document order.
+// The header IDs match the anchors of the headers in the rendered document.
+func ExtractTOC(body []byte) []Header {
+	once.Do(initConverter)
+
+	source := giteautil.NormalizeEOL(body)
+	pc := NewGiteaParseContext("", map[string]string{}, false)
+	doc := converter.Parser().Parse(text.NewReader(source), parser.WithContext(pc))
+
+	toc := make([]Header, 0, 10)
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		header := Header{
+			Text:  string(heading.Text(source)),
+			Level: heading.Level,
+		}
+		if id, found := heading.AttributeString("id"); found {
+			header.ID = util.BytesToReadOnlyString(id.([]byte))
+		}
+		toc = append(toc, header)
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
 func createTOCNode(toc []Header, lang string) ast.Node {
 	details := NewDetails()
 	summary := NewSummary()
@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markup
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Previewer defines an interface for rendering a preview of a binary file
+// which cannot be shown as text, e.g. in the file view and in diffs
+type Previewer interface {
+	Name() string // previewer name
+	Extensions() []string
+	// Preview renders the file content read from rd to HTML, rawLink is the link to the raw file.
+	// The returned HTML is not sanitized, so implementations must escape everything they emit.
+	Preview(rd io.Reader, rawLink string) ([]byte, error)
+}
+
+var (
+	extPreviewers = make(map[string]Previewer)
+	previewers    = make(map[string]Previewer)
+)
+
+// RegisterPreviewer registers a new binary file previewer
+func RegisterPreviewer(previewer Previewer) {
+	previewers[previewer.Name()] = previewer
+	for _, ext := range previewer.Extensions() {
+		extPreviewers[strings.ToLower(ext)] = previewer
+	}
+}
+
+// GetPreviewerByFileName get previewer by filename
+func GetPreviewerByFileName(filename string) Previewer {
+	extension := strings.ToLower(filepath.Ext(filename))
+	return extPreviewers[extension]
+}
+
+// GetPreviewerByType returns a previewer according type
+func GetPreviewerByType(tp string) Previewer {
+	return previewers[tp]
+}
+
+// PreviewType returns the name of the previewer for the file, or an empty string if there is none
+func PreviewType(filename string) string {
+	if previewer := GetPreviewerByFileName(filename); previewer != nil {
+		return previewer.Name()
+	}
+	return ""
+}
+
+// Preview renders a preview of the file with the previewer registered for its extension
+func Preview(filename string, rd io.Reader, rawLink string) ([]byte, error) {
+	previewer := GetPreviewerByFileName(filename)
+	if previewer == nil {
+		return nil, nil
+	}
+	return previewer.Preview(rd, rawLink)
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markup_test
+
+import (
+	"strings"
+	"testing"
+
+	. "code.gitea.io/gitea/modules/markup"
+	_ "code.gitea.io/gitea/modules/markup/stl"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreview(t *testing.T) {
+	assert.Equal(t, "stl", PreviewType("models/part.STL"))
+	assert.Equal(t, "", PreviewType("README.md"))
+	assert.NotNil(t, GetPreviewerByType("stl"))
+
+	out, err := Preview("README.md", strings.NewReader("# README"), "/raw/README.md")
+	assert.NoError(t, err)
+	assert.Nil(t, out)
+
+	_, err = Preview("part.stl", strings.NewReader("garbage"), "/raw/part.stl")
+	assert.Error(t, err)
+}
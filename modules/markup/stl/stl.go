@@ -0,0 +1,234 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+
+	"code.gitea.io/gitea/modules/markup"
+)
+
+// MaxPreviewTriangles is the maximum number of triangles drawn in a preview
+const MaxPreviewTriangles = 20000
+
+// previewSize is the size of the longer side of the preview viewBox
+const previewSize = 1000
+
+// ErrInvalidSTL is returned when the content is neither an ASCII nor a binary STL file
+var ErrInvalidSTL = errors.New("invalid STL file")
+
+func init() {
+	markup.RegisterPreviewer(Previewer{})
+}
+
+// Vertex represents a point in the model space
+type Vertex [3]float64
+
+// Triangle represents a facet of the model
+type Triangle [3]Vertex
+
+// Model represents a parsed STL model
+type Model struct {
+	Triangles []Triangle
+}
+
+// Parse parses an ASCII or binary STL file
+func Parse(data []byte) (*Model, error) {
+	if len(data) >= 84 {
+		count := binary.LittleEndian.Uint32(data[80:84])
+		if uint64(len(data)) == 84+50*uint64(count) {
+			return parseBinary(data[84:], int(count)), nil
+		}
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("solid")) {
+		return parseASCII(data)
+	}
+	return nil, ErrInvalidSTL
+}
+
+func parseBinary(data []byte, count int) *Model {
+	model := &Model{Triangles: make([]Triangle, count)}
+	for i := 0; i < count; i++ {
+		// every facet is a normal and three vertices of three float32 each followed by two attribute bytes
+		facet := data[i*50+12 : i*50+48]
+		for v := 0; v < 3; v++ {
+			for c := 0; c < 3; c++ {
+				bits := binary.LittleEndian.Uint32(facet[(v*3+c)*4:])
+				model.Triangles[i][v][c] = float64(math.Float32frombits(bits))
+			}
+		}
+	}
+	return model
+}
+
+func parseASCII(data []byte) (*Model, error) {
+	model := &Model{}
+	fields := bytes.Fields(data)
+	var triangle Triangle
+	n := 0
+	for i := 0; i < len(fields); i++ {
+		if !bytes.Equal(fields[i], []byte("vertex")) {
+			continue
+		}
+		if i+3 >= len(fields) {
+			return nil, ErrInvalidSTL
+		}
+		for c := 0; c < 3; c++ {
+			f, err := strconv.ParseFloat(string(fields[i+1+c]), 64)
+			if err != nil {
+				return nil, ErrInvalidSTL
+			}
+			triangle[n][c] = f
+		}
+		i += 3
+		n++
+		if n == 3 {
+			model.Triangles = append(model.Triangles, triangle)
+			n = 0
+		}
+	}
+	if n != 0 {
+		return nil, ErrInvalidSTL
+	}
+	return model, nil
+}
+
+// Bounds returns the minimum and maximum corners of the bounding box of the model
+func (m *Model) Bounds() (min, max Vertex) {
+	if len(m.Triangles) == 0 {
+		return
+	}
+	min = m.Triangles[0][0]
+	max = m.Triangles[0][0]
+	for _, t := range m.Triangles {
+		for _, v := range t {
+			for c := 0; c < 3; c++ {
+				min[c] = math.Min(min[c], v[c])
+				max[c] = math.Max(max[c], v[c])
+			}
+		}
+	}
+	return
+}
+
+// project returns the isometric projection of v, as seen from (1, 1, 1) with z pointing up
+func project(v Vertex) (x, y float64) {
+	return (v[1] - v[0]) / math.Sqrt2, (v[0] + v[1] - 2*v[2]) / math.Sqrt(6)
+}
+
+// shade returns the grey level of a triangle lit from the viewing direction
+func shade(t Triangle) int {
+	a := Vertex{t[1][0] - t[0][0], t[1][1] - t[0][1], t[1][2] - t[0][2]}
+	b := Vertex{t[2][0] - t[0][0], t[2][1] - t[0][1], t[2][2] - t[0][2]}
+	n := Vertex{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+	length := math.Sqrt(n[0]*n[0] + n[1]*n[1] + n[2]*n[2])
+	if length == 0 {
+		return 96
+	}
+	intensity := math.Abs(n[0]+n[1]+n[2]) / (length * math.Sqrt(3))
+	return 96 + int(intensity*128)
+}
+
+// RenderSVG draws the model as a flat shaded isometric SVG image
+func (m *Model) RenderSVG(w io.Writer) error {
+	triangles := make([]Triangle, len(m.Triangles))
+	copy(triangles, m.Triangles)
+	// painter's algorithm, draw the triangles farthest from the viewer first
+	sort.SliceStable(triangles, func(i, j int) bool {
+		var di, dj float64
+		for v := 0; v < 3; v++ {
+			di += triangles[i][v][0] + triangles[i][v][1] + triangles[i][v][2]
+			dj += triangles[j][v][0] + triangles[j][v][1] + triangles[j][v][2]
+		}
+		return di < dj
+	})
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, t := range triangles {
+		for _, v := range t {
+			x, y := project(v)
+			minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+			minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+		}
+	}
+	scale := 1.0
+	if extent := math.Max(maxX-minX, maxY-minY); extent > 0 {
+		scale = previewSize / extent
+	}
+	if len(triangles) == 0 {
+		minX, maxX, minY, maxY = 0, 0, 0, 0
+	}
+
+	if _, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" class="stl-preview-image" viewBox="0 0 %.1f %.1f">`,
+		(maxX-minX)*scale, (maxY-minY)*scale); err != nil {
+		return err
+	}
+	for _, t := range triangles {
+		var points [3]string
+		for i, v := range t {
+			x, y := project(v)
+			points[i] = fmt.Sprintf("%.1f,%.1f", (x-minX)*scale, (y-minY)*scale)
+		}
+		grey := shade(t)
+		if _, err := fmt.Fprintf(w, `<polygon points="%s %s %s" fill="#%02x%02x%02x" stroke="#%02x%02x%02x"/>`,
+			points[0], points[1], points[2], grey, grey, grey, grey, grey, grey); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "</svg>")
+	return err
+}
+
+// Previewer implements markup.Previewer for STL 3D models
+type Previewer struct {
+}
+
+// Name implements markup.Previewer
+func (Previewer) Name() string {
+	return "stl"
+}
+
+// Extensions implements markup.Previewer
+func (Previewer) Extensions() []string {
+	return []string{".stl"}
+}
+
+// Preview implements markup.Previewer
+func (Previewer) Preview(rd io.Reader, rawLink string) ([]byte, error) {
+	data, err := ioutil.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+	model, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	min, max := model.Bounds()
+	var tmpBlock bytes.Buffer
+	fmt.Fprintf(&tmpBlock, `<div class="stl-preview" data-triangles="%d">`, len(model.Triangles))
+	if len(model.Triangles) <= MaxPreviewTriangles {
+		if err := model.RenderSVG(&tmpBlock); err != nil {
+			return nil, err
+		}
+	}
+	fmt.Fprintf(&tmpBlock, `<div class="stl-preview-size">%g &times; %g &times; %g</div>`,
+		round(max[0]-min[0]), round(max[1]-min[1]), round(max[2]-min[2]))
+	tmpBlock.WriteString("</div>")
+	return tmpBlock.Bytes(), nil
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package stl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const asciiTetrahedron = `solid tetrahedron
+facet normal 0 0 -1
+ outer loop
+  vertex 0 0 0
+  vertex 0 10 0
+  vertex 10 0 0
+ endloop
+endfacet
+facet normal 0 -1 0
+ outer loop
+  vertex 0 0 0
+  vertex 10 0 0
+  vertex 0 0 5
+ endloop
+endfacet
+facet normal -1 0 0
+ outer loop
+  vertex 0 0 0
+  vertex 0 0 5
+  vertex 0 10 0
+ endloop
+endfacet
+facet normal 1 1 1
+ outer loop
+  vertex 10 0 0
+  vertex 0 10 0
+  vertex 0 0 5
+ endloop
+endfacet
+endsolid tetrahedron
+`
+
+func toBinary(model *Model) []byte {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 80))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(model.Triangles)))
+	for _, t := range model.Triangles {
+		buf.Write(make([]byte, 12))
+		for _, v := range t {
+			for _, c := range v {
+				_ = binary.Write(&buf, binary.LittleEndian, math.Float32bits(float32(c)))
+			}
+		}
+		buf.Write(make([]byte, 2))
+	}
+	return buf.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	model, err := Parse([]byte(asciiTetrahedron))
+	assert.NoError(t, err)
+	assert.Len(t, model.Triangles, 4)
+	min, max := model.Bounds()
+	assert.Equal(t, Vertex{0, 0, 0}, min)
+	assert.Equal(t, Vertex{10, 10, 5}, max)
+
+	binModel, err := Parse(toBinary(model))
+	assert.NoError(t, err)
+	assert.Equal(t, model, binModel)
+
+	_, err = Parse([]byte("not a model"))
+	assert.Equal(t, ErrInvalidSTL, err)
+	_, err = Parse([]byte("solid broken\nfacet normal 0 0 0\nouter loop\nvertex 0 0 0\nvertex 1 1\n"))
+	assert.Equal(t, ErrInvalidSTL, err)
+}
+
+func TestPreview(t *testing.T) {
+	out, err := Previewer{}.Preview(strings.NewReader(asciiTetrahedron), "/user2/repo1/raw/branch/master/model.stl")
+	assert.NoError(t, err)
+	html := string(out)
+	assert.Contains(t, html, `data-triangles="4"`)
+	assert.Contains(t, html, `<svg xmlns="http://www.w3.org/2000/svg" class="stl-preview-image"`)
+	assert.Equal(t, 4, strings.Count(html, "<polygon "))
+	assert.Contains(t, html, "10 &times; 10 &times; 5")
+}
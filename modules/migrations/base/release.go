@@ -20,6 +20,7 @@ type ReleaseAsset struct {
 
 // Release represents a release
 type Release struct {
+	ID              int64 // the id of the release on the source, if its assets are downloaded by it
 	TagName         string
 	TargetCommitish string
 	Name            string
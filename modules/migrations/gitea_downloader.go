@@ -252,6 +252,7 @@ func (g *GiteaDownloader) GetLabels() ([]*base.Label, error) {
 
 func (g *GiteaDownloader) convertGiteaRelease(rel *gitea_sdk.Release) *base.Release {
 	r := &base.Release{
+		ID:              rel.ID,
 		TagName:         rel.TagName,
 		TargetCommitish: rel.Target,
 		Name:            rel.Title,
@@ -127,6 +127,11 @@ func TestGiteaDownloadRepo(t *testing.T) {
 
 	releases, err := downloader.GetReleases()
 	assert.NoError(t, err)
+	for _, release := range releases {
+		// the ids are needed to download the assets, their values are not fixed by the test repository
+		assert.NotZero(t, release.ID)
+		release.ID = 0
+	}
 	assert.EqualValues(t, []*base.Release{
 		{
 			Name:            "Second Release",
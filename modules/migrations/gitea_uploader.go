@@ -266,6 +266,7 @@ func (g *GiteaLocalUploader) CreateReleases(downloader base.Downloader, releases
 		for _, asset := range release.Assets {
 			var attach = models.Attachment{
 				UUID:          gouuid.New().String(),
+				RepoID:        g.repo.ID,
 				Name:          asset.Name,
 				DownloadCount: int64(*asset.DownloadCount),
 				Size:          int64(*asset.Size),
@@ -276,7 +277,7 @@ func (g *GiteaLocalUploader) CreateReleases(downloader base.Downloader, releases
 			err = func() error {
 				var rc io.ReadCloser
 				if asset.DownloadURL == nil {
-					rc, err = downloader.GetAsset(rel.TagName, rel.ID, asset.ID)
+					rc, err = downloader.GetAsset(rel.TagName, release.ID, asset.ID)
 					if err != nil {
 						return err
 					}
@@ -67,6 +67,17 @@ func isMigrateURLAllowed(remoteURL string) error {
 	return nil
 }
 
+// NewDownloader returns a downloader for the git service of the options,
+// it returns nil if there is no downloader for the git service
+func NewDownloader(ctx context.Context, opts base.MigrateOptions) (base.Downloader, error) {
+	for _, factory := range factories {
+		if factory.GitServiceType() == opts.GitServiceType {
+			return factory.New(ctx, opts)
+		}
+	}
+	return nil, nil
+}
+
 // MigrateRepository migrate repository according MigrateOptions
 func MigrateRepository(ctx context.Context, doer *models.User, ownerName string, opts base.MigrateOptions) (*models.Repository, error) {
 	err := isMigrateURLAllowed(opts.CloneAddr)
@@ -79,14 +90,9 @@ func MigrateRepository(ctx context.Context, doer *models.User, ownerName string,
 		uploader   = NewGiteaLocalUploader(ctx, doer, ownerName, opts.RepoName)
 	)
 
-	for _, factory := range factories {
-		if factory.GitServiceType() == opts.GitServiceType {
-			downloader, err = factory.New(ctx, opts)
-			if err != nil {
-				return nil, err
-			}
-			break
-		}
+	downloader, err = NewDownloader(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	if downloader == nil {
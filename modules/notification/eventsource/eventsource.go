@@ -0,0 +1,199 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package eventsource
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/eventsource"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+type eventSourceNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &eventSourceNotifier{}
+)
+
+// NewNotifier create a new eventSourceNotifier notifier which streams the events
+// of a repository to the clients listening for them
+func NewNotifier() base.Notifier {
+	return &eventSourceNotifier{}
+}
+
+func send(event *api.RepoEvent) {
+	eventsource.GetManager().SendRepoMessage(event.RepoID, &eventsource.Event{
+		Name: event.Type,
+		Data: event,
+	})
+}
+
+func sendIssueEvent(eventType string, doer *models.User, issue *models.Issue, commentID int64) {
+	send(&api.RepoEvent{
+		Type:       eventType,
+		RepoID:     issue.RepoID,
+		ActorID:    doer.ID,
+		IssueIndex: issue.Index,
+		IsPull:     issue.IsPull,
+		CommentID:  commentID,
+	})
+}
+
+func sendCommentEvent(eventType string, doer *models.User, c *models.Comment) {
+	if err := c.LoadIssue(); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	sendIssueEvent(eventType, doer, c.Issue, c.ID)
+}
+
+func (*eventSourceNotifier) NotifyNewIssue(issue *models.Issue) {
+	sendIssueEvent(api.RepoEventIssueOpened, &models.User{ID: issue.PosterID}, issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyNewPullRequest(pr *models.PullRequest) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	sendIssueEvent(api.RepoEventIssueOpened, &models.User{ID: pr.Issue.PosterID}, pr.Issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyIssueChangeStatus(doer *models.User, issue *models.Issue, actionComment *models.Comment, isClosed bool) {
+	eventType := api.RepoEventIssueReopened
+	if isClosed {
+		eventType = api.RepoEventIssueClosed
+	}
+	var commentID int64
+	if actionComment != nil {
+		commentID = actionComment.ID
+	}
+	sendIssueEvent(eventType, doer, issue, commentID)
+}
+
+func (*eventSourceNotifier) NotifyIssueChangeMilestone(doer *models.User, issue *models.Issue, oldMilestoneID int64) {
+	sendIssueEvent(api.RepoEventIssueUpdated, doer, issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyIssueChangeAssignee(doer *models.User, issue *models.Issue, assignee *models.User, removed bool, comment *models.Comment) {
+	sendIssueEvent(api.RepoEventIssueUpdated, doer, issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyPullReviewRequest(doer *models.User, issue *models.Issue, reviewer *models.User, isRequest bool, comment *models.Comment) {
+	sendIssueEvent(api.RepoEventIssueUpdated, doer, issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyIssueChangeContent(doer *models.User, issue *models.Issue, oldContent string) {
+	sendIssueEvent(api.RepoEventIssueUpdated, doer, issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyIssueClearLabels(doer *models.User, issue *models.Issue) {
+	sendIssueEvent(api.RepoEventIssueUpdated, doer, issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyIssueChangeTitle(doer *models.User, issue *models.Issue, oldTitle string) {
+	sendIssueEvent(api.RepoEventIssueUpdated, doer, issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyIssueChangeRef(doer *models.User, issue *models.Issue, oldRef string) {
+	sendIssueEvent(api.RepoEventIssueUpdated, doer, issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyIssueChangeLabels(doer *models.User, issue *models.Issue,
+	addedLabels []*models.Label, removedLabels []*models.Label) {
+	sendIssueEvent(api.RepoEventIssueUpdated, doer, issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	sendIssueEvent(api.RepoEventPullRequestMerged, doer, pr.Issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyPullRequestSynchronized(doer *models.User, pr *models.PullRequest) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	sendIssueEvent(api.RepoEventPullRequestSynced, doer, pr.Issue, 0)
+}
+
+func (*eventSourceNotifier) NotifyPullRequestReview(pr *models.PullRequest, review *models.Review, comment *models.Comment) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	var commentID int64
+	if comment != nil {
+		commentID = comment.ID
+	}
+	sendIssueEvent(api.RepoEventPullRequestReviewed, &models.User{ID: review.ReviewerID}, pr.Issue, commentID)
+}
+
+func (*eventSourceNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment) {
+	sendIssueEvent(api.RepoEventIssueCommentCreated, doer, issue, comment.ID)
+}
+
+func (*eventSourceNotifier) NotifyUpdateComment(doer *models.User, c *models.Comment, oldContent string) {
+	sendCommentEvent(api.RepoEventIssueCommentUpdated, doer, c)
+}
+
+func (*eventSourceNotifier) NotifyDeleteComment(doer *models.User, c *models.Comment) {
+	sendCommentEvent(api.RepoEventIssueCommentDeleted, doer, c)
+}
+
+func sendReleaseEvent(eventType string, doer *models.User, rel *models.Release) {
+	// drafts are not visible to everyone who can read the repository
+	if rel.IsDraft {
+		return
+	}
+	send(&api.RepoEvent{
+		Type:      eventType,
+		RepoID:    rel.RepoID,
+		ActorID:   doer.ID,
+		ReleaseID: rel.ID,
+		Ref:       rel.TagName,
+	})
+}
+
+func (*eventSourceNotifier) NotifyNewRelease(rel *models.Release) {
+	sendReleaseEvent(api.RepoEventReleasePublished, &models.User{ID: rel.PublisherID}, rel)
+}
+
+func (*eventSourceNotifier) NotifyUpdateRelease(doer *models.User, rel *models.Release) {
+	sendReleaseEvent(api.RepoEventReleaseUpdated, doer, rel)
+}
+
+func (*eventSourceNotifier) NotifyDeleteRelease(doer *models.User, rel *models.Release) {
+	sendReleaseEvent(api.RepoEventReleaseDeleted, doer, rel)
+}
+
+func (*eventSourceNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	send(&api.RepoEvent{
+		Type:    api.RepoEventPush,
+		RepoID:  repo.ID,
+		ActorID: pusher.ID,
+		Ref:     opts.RefFullName,
+		SHA:     opts.NewCommitID,
+	})
+}
+
+func (*eventSourceNotifier) NotifyCreateCommitStatus(doer *models.User, repo *models.Repository, sha string, status *models.CommitStatus) {
+	send(&api.RepoEvent{
+		Type:    api.RepoEventCommitStatusCreated,
+		RepoID:  repo.ID,
+		ActorID: doer.ID,
+		SHA:     sha,
+		State:   string(status.State),
+		Context: status.Context,
+	})
+}
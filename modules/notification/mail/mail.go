@@ -94,16 +94,30 @@ func (m *mailNotifier) NotifyPullRequestReview(pr *models.PullRequest, r *models
 	}
 }
 
+// isMailedAbout returns whether the notification rules of the user deliver notifications about the issue by email
+func isMailedAbout(u *models.User, issue *models.Issue) bool {
+	if err := issue.LoadRepo(); err != nil {
+		log.Error("issue.LoadRepo: %v", err)
+		return false
+	}
+	action, err := u.GetNotificationAction(issue.Repo, models.NotificationRuleEventOfIssue(issue), false)
+	if err != nil {
+		log.Error("GetNotificationAction: %v", err)
+		return false
+	}
+	return action == models.NotificationRuleActionEmail
+}
+
 func (m *mailNotifier) NotifyIssueChangeAssignee(doer *models.User, issue *models.Issue, assignee *models.User, removed bool, comment *models.Comment) {
 	// mail only sent to added assignees and not self-assignee
-	if !removed && doer.ID != assignee.ID && assignee.EmailNotifications() == models.EmailNotificationsEnabled {
+	if !removed && doer.ID != assignee.ID && isMailedAbout(assignee, issue) {
 		ct := fmt.Sprintf("Assigned #%d.", issue.Index)
 		mailer.SendIssueAssignedMail(issue, doer, ct, comment, []string{assignee.Email})
 	}
 }
 
 func (m *mailNotifier) NotifyPullReviewRequest(doer *models.User, issue *models.Issue, reviewer *models.User, isRequest bool, comment *models.Comment) {
-	if isRequest && doer.ID != reviewer.ID && reviewer.EmailNotifications() == models.EmailNotificationsEnabled {
+	if isRequest && doer.ID != reviewer.ID && isMailedAbout(reviewer, issue) {
 		ct := fmt.Sprintf("Requested to review %s.", issue.HTMLURL())
 		mailer.SendIssueAssignedMail(issue, doer, ct, comment, []string{reviewer.Email})
 	}
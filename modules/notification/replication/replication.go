@@ -0,0 +1,127 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package replication
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+type replicationNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &replicationNotifier{}
+)
+
+// NewNotifier create a new replicationNotifier notifier which asks
+// the read replicas to sync changed repositories
+func NewNotifier() base.Notifier {
+	return &replicationNotifier{}
+}
+
+// syncReplicas notifies all read replicas of the repository change in the background
+func syncReplicas(opts *private.ReplicaSyncOptions) {
+	for _, replicaURL := range setting.Replication.ReplicaURLs {
+		go func(replicaURL string) {
+			if err := private.SyncReplica(replicaURL, opts); err != nil {
+				log.Error("SyncReplica[%s/%s]: %v", opts.OwnerName, opts.RepoName, err)
+			}
+		}(replicaURL)
+	}
+}
+
+func syncRepository(repo *models.Repository) {
+	syncReplicas(&private.ReplicaSyncOptions{
+		RepoID:    repo.ID,
+		OwnerName: repo.OwnerName,
+		RepoName:  repo.Name,
+	})
+}
+
+func (r *replicationNotifier) NotifyCreateRepository(doer *models.User, u *models.User, repo *models.Repository) {
+	syncRepository(repo)
+}
+
+func (r *replicationNotifier) NotifyMigrateRepository(doer *models.User, u *models.User, repo *models.Repository) {
+	syncRepository(repo)
+}
+
+func (r *replicationNotifier) NotifyForkRepository(doer *models.User, oldRepo, repo *models.Repository) {
+	syncRepository(repo)
+}
+
+func (r *replicationNotifier) NotifyDeleteRepository(doer *models.User, repo *models.Repository) {
+	syncReplicas(&private.ReplicaSyncOptions{
+		RepoID:    repo.ID,
+		OwnerName: repo.OwnerName,
+		RepoName:  repo.Name,
+		Deleted:   true,
+	})
+}
+
+func (r *replicationNotifier) NotifyRenameRepository(doer *models.User, repo *models.Repository, oldRepoName string) {
+	syncReplicas(&private.ReplicaSyncOptions{
+		RepoID:      repo.ID,
+		OwnerName:   repo.OwnerName,
+		RepoName:    repo.Name,
+		OldRepoName: oldRepoName,
+	})
+}
+
+func (r *replicationNotifier) NotifyTransferRepository(doer *models.User, repo *models.Repository, oldOwnerName string) {
+	syncReplicas(&private.ReplicaSyncOptions{
+		RepoID:       repo.ID,
+		OwnerName:    repo.OwnerName,
+		RepoName:     repo.Name,
+		OldOwnerName: oldOwnerName,
+	})
+}
+
+func (r *replicationNotifier) NotifyNewPullRequest(pr *models.PullRequest) {
+	// The head of the pull request is copied into the base repository
+	if err := pr.LoadBaseRepo(); err != nil {
+		log.Error("LoadBaseRepo: %v", err)
+		return
+	}
+	syncRepository(pr.BaseRepo)
+}
+
+func (r *replicationNotifier) NotifyPullRequestSynchronized(doer *models.User, pr *models.PullRequest) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		log.Error("LoadBaseRepo: %v", err)
+		return
+	}
+	syncRepository(pr.BaseRepo)
+}
+
+func (r *replicationNotifier) NotifyPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	syncRepository(repo)
+}
+
+func (r *replicationNotifier) NotifyCreateRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	syncRepository(repo)
+}
+
+func (r *replicationNotifier) NotifyDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	syncRepository(repo)
+}
+
+func (r *replicationNotifier) NotifySyncPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	syncRepository(repo)
+}
+
+func (r *replicationNotifier) NotifySyncCreateRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	syncRepository(repo)
+}
+
+func (r *replicationNotifier) NotifySyncDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
+	syncRepository(repo)
+}
@@ -28,8 +28,11 @@ type GitPushOptions map[string]string
 
 // GitPushOptions keys
 const (
-	GitPushOptionRepoPrivate  = "repo.private"
-	GitPushOptionRepoTemplate = "repo.template"
+	GitPushOptionRepoPrivate            = "repo.private"
+	GitPushOptionRepoTemplate           = "repo.template"
+	GitPushOptionPullRequest            = "pull-request"
+	GitPushOptionPullRequestTitle       = "pull-request.title"
+	GitPushOptionPullRequestDescription = "pull-request.description"
 )
 
 // Bool checks for a key in the map and parses as a boolean
@@ -66,10 +69,11 @@ type HookPostReceiveResult struct {
 
 // HookPostReceiveBranchResult represents an individual branch result from PostReceive
 type HookPostReceiveBranchResult struct {
-	Message bool
-	Create  bool
-	Branch  string
-	URL     string
+	Message           bool
+	Create            bool
+	PushToPullRequest bool
+	Branch            string
+	URL               string
 }
 
 // HookPreReceive check whether the provided commits are allowed
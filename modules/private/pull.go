@@ -0,0 +1,86 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// PullCheckTask represents a pull request handed out to an external merge checker
+type PullCheckTask struct {
+	PullID                    int64
+	BaseRepoOwner             string
+	BaseRepoName              string
+	BaseBranch                string
+	BaseCommitID              string
+	HeadRepoOwner             string
+	HeadRepoName              string
+	HeadBranch                string
+	HeadCommitID              string
+	IgnoreWhitespaceConflicts bool
+	ProtectedFilePatterns     string
+}
+
+// PullCheckResult represents the result of checking a pull request for conflicts
+type PullCheckResult struct {
+	// BaseCommitID and HeadCommitID are the commits of the task the result belongs to
+	BaseCommitID          string
+	HeadCommitID          string
+	MergeBase             string
+	Conflicted            bool
+	ConflictedFiles       []string
+	ChangedProtectedFiles []string
+	Err                   string
+}
+
+// ClaimPullCheck asks the running Gitea instance for the next pull request of the shard to check.
+// It returns nil if there is none.
+func ClaimPullCheck(shard, shards int64) (*PullCheckTask, error) {
+	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/pull/check/claim?shard=%d&shards=%d", shard, shards)
+
+	resp, err := newInternalRequest(reqURL, "POST").Response()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to contact gitea: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil, nil
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("Error returned from gitea: %v", decodeJSONError(resp).Err)
+	}
+
+	task := &PullCheckTask{}
+	if err := json.NewDecoder(resp.Body).Decode(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// SubmitPullCheck reports the result of checking a pull request to the running Gitea instance
+func SubmitPullCheck(pullID int64, result *PullCheckResult) error {
+	reqURL := setting.LocalURL + fmt.Sprintf("api/internal/pull/check/%d", pullID)
+
+	req := newInternalRequest(reqURL, "POST")
+	req = req.Header("Content-Type", "application/json")
+	jsonBytes, _ := json.Marshal(result)
+	req.Body(jsonBytes)
+	resp, err := req.Response()
+	if err != nil {
+		return fmt.Errorf("Unable to contact gitea: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error returned from gitea: %v", decodeJSONError(resp).Err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ReplicaSyncOptions represents a repository change the primary notifies a read replica of
+type ReplicaSyncOptions struct {
+	RepoID    int64
+	OwnerName string
+	RepoName  string
+	// OldOwnerName and OldRepoName are set if the repository has been transferred or renamed
+	OldOwnerName string
+	OldRepoName  string
+	Deleted      bool
+}
+
+// SyncReplica asks the read replica at replicaURL to sync the repository from the primary
+func SyncReplica(replicaURL string, opts *ReplicaSyncOptions) error {
+	reqURL := replicaURL + "api/internal/replica/sync"
+
+	req := newRequest(reqURL, "POST")
+	req = req.Header("Content-Type", "application/json")
+	jsonBytes, _ := json.Marshal(opts)
+	req.Body(jsonBytes)
+	resp, err := req.Response()
+	if err != nil {
+		return fmt.Errorf("Unable to contact replica %s: %v", replicaURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error returned from replica %s: %v", replicaURL, decodeJSONError(resp).Err)
+	}
+	return nil
+}
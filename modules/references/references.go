@@ -39,6 +39,8 @@ var (
 	spaceTrimmedPattern = regexp.MustCompile(`(?:.*[0-9a-zA-Z-_])\s`)
 	// timeLogPattern matches string for time tracking
 	timeLogPattern = regexp.MustCompile(`(?:\s|^|\(|\[)(@([0-9]+([\.,][0-9]+)?(w|d|m|h))+)(?:\s|$|\)|\]|[:;,.?!]\s|[:;,.?!]$)`)
+	// keywordPattern matches valid keywords closing or reopening issues
+	keywordPattern = regexp.MustCompile(`^[\pL]+$`)
 
 	issueCloseKeywordsPat, issueReopenKeywordsPat *regexp.Regexp
 	issueKeywordsOnce                             sync.Once
@@ -126,13 +128,17 @@ func makeKeywordsPat(words []string) *regexp.Regexp {
 	return regexp.MustCompile(`(?i)(?:\s|^|\(|\[)(` + strings.Join(acceptedWords, `|`) + `):? $`)
 }
 
+// IsValidKeyword returns whether the word can be used as a keyword closing or reopening issues
+func IsValidKeyword(word string) bool {
+	// Accept Unicode letter class runes (a-z, á, à, ä, )
+	return keywordPattern.MatchString(strings.TrimSpace(word))
+}
+
 func parseKeywords(words []string) []string {
 	acceptedWords := make([]string, 0, 5)
-	wordPat := regexp.MustCompile(`^[\pL]+$`)
 	for _, word := range words {
 		word = strings.ToLower(strings.TrimSpace(word))
-		// Accept Unicode letter class runes (a-z, á, à, ä, )
-		if wordPat.MatchString(word) {
+		if IsValidKeyword(word) {
 			acceptedWords = append(acceptedWords, word)
 		} else {
 			log.Info("Invalid keyword: %s", word)
@@ -153,6 +159,29 @@ func doNewKeywords(close []string, reopen []string) {
 	issueReopenKeywordsPat = makeKeywordsPat(reopen)
 }
 
+// IssueKeywords are the keywords which close or reopen the issues referenced after them.
+// A nil *IssueKeywords uses the keywords of the settings.
+type IssueKeywords struct {
+	closePat, reopenPat *regexp.Regexp
+}
+
+// NewIssueKeywords returns the keywords closing and reopening issues, invalid words are ignored.
+// Empty lists never match, so NewIssueKeywords(nil, nil) disables closing and reopening issues.
+func NewIssueKeywords(close []string, reopen []string) *IssueKeywords {
+	return &IssueKeywords{
+		closePat:  makeKeywordsPat(close),
+		reopenPat: makeKeywordsPat(reopen),
+	}
+}
+
+func (k *IssueKeywords) patterns() (closePat, reopenPat *regexp.Regexp) {
+	if k == nil {
+		newKeywords()
+		return issueCloseKeywordsPat, issueReopenKeywordsPat
+	}
+	return k.closePat, k.reopenPat
+}
+
 // getGiteaHostName returns a normalized string with the local host name, with no scheme or port information
 func getGiteaHostName() string {
 	giteaHostInit.Do(func() {
@@ -227,12 +256,18 @@ func FindFirstMentionBytes(content []byte) (bool, RefSpan) {
 // FindAllIssueReferencesMarkdown strips content from markdown markup
 // and returns a list of unvalidated references found in it.
 func FindAllIssueReferencesMarkdown(content string) []IssueReference {
-	return rawToIssueReferenceList(findAllIssueReferencesMarkdown(content))
+	return (*IssueKeywords)(nil).FindAllIssueReferencesMarkdown(content)
+}
+
+// FindAllIssueReferencesMarkdown strips content from markdown markup and returns a list
+// of unvalidated references found in it, their actions are found using the keywords.
+func (k *IssueKeywords) FindAllIssueReferencesMarkdown(content string) []IssueReference {
+	return rawToIssueReferenceList(findAllIssueReferencesMarkdown(content, k))
 }
 
-func findAllIssueReferencesMarkdown(content string) []*rawReference {
+func findAllIssueReferencesMarkdown(content string, keywords *IssueKeywords) []*rawReference {
 	bcontent, links := mdstripper.StripMarkdownBytes([]byte(content))
-	return findAllIssueReferencesBytes(bcontent, links)
+	return findAllIssueReferencesBytes(bcontent, links, keywords)
 }
 
 func convertFullHTMLReferencesToShortRefs(re *regexp.Regexp, contentBytes *[]byte) {
@@ -303,6 +338,12 @@ func convertFullHTMLReferencesToShortRefs(re *regexp.Regexp, contentBytes *[]byt
 
 // FindAllIssueReferences returns a list of unvalidated references found in a string.
 func FindAllIssueReferences(content string) []IssueReference {
+	return (*IssueKeywords)(nil).FindAllIssueReferences(content)
+}
+
+// FindAllIssueReferences returns a list of unvalidated references found in a string,
+// their actions are found using the keywords.
+func (k *IssueKeywords) FindAllIssueReferences(content string) []IssueReference {
 	// Need to convert fully qualified html references to local system to #/! short codes
 	contentBytes := []byte(content)
 	if re := getGiteaIssuePullPattern(); re != nil {
@@ -310,18 +351,24 @@ func FindAllIssueReferences(content string) []IssueReference {
 	} else {
 		log.Debug("No GiteaIssuePullPattern pattern")
 	}
-	return rawToIssueReferenceList(findAllIssueReferencesBytes(contentBytes, []string{}))
+	return rawToIssueReferenceList(findAllIssueReferencesBytes(contentBytes, []string{}, k))
 }
 
 // FindRenderizableReferenceNumeric returns the first unvalidated reference found in a string.
 func FindRenderizableReferenceNumeric(content string, prOnly bool) (bool, *RenderizableReference) {
+	return (*IssueKeywords)(nil).FindRenderizableReferenceNumeric(content, prOnly)
+}
+
+// FindRenderizableReferenceNumeric returns the first unvalidated reference found in a string,
+// its action is found using the keywords.
+func (k *IssueKeywords) FindRenderizableReferenceNumeric(content string, prOnly bool) (bool, *RenderizableReference) {
 	match := issueNumericPattern.FindStringSubmatchIndex(content)
 	if match == nil {
 		if match = crossReferenceIssueNumericPattern.FindStringSubmatchIndex(content); match == nil {
 			return false, nil
 		}
 	}
-	r := getCrossReference([]byte(content), match[2], match[3], false, prOnly)
+	r := getCrossReference([]byte(content), match[2], match[3], false, prOnly, k)
 	if r == nil {
 		return false, nil
 	}
@@ -339,12 +386,18 @@ func FindRenderizableReferenceNumeric(content string, prOnly bool) (bool, *Rende
 
 // FindRenderizableReferenceAlphanumeric returns the first alphanumeric unvalidated references found in a string.
 func FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableReference) {
+	return (*IssueKeywords)(nil).FindRenderizableReferenceAlphanumeric(content)
+}
+
+// FindRenderizableReferenceAlphanumeric returns the first alphanumeric unvalidated references found in a string,
+// its action is found using the keywords.
+func (k *IssueKeywords) FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableReference) {
 	match := issueAlphanumericPattern.FindStringSubmatchIndex(content)
 	if match == nil {
 		return false, nil
 	}
 
-	action, location := findActionKeywords([]byte(content), match[2])
+	action, location := findActionKeywords([]byte(content), match[2], k)
 
 	return true, &RenderizableReference{
 		Issue:          string(content[match[2]:match[3]]),
@@ -356,7 +409,7 @@ func FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableR
 }
 
 // FindAllIssueReferencesBytes returns a list of unvalidated references found in a byte slice.
-func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference {
+func findAllIssueReferencesBytes(content []byte, links []string, keywords *IssueKeywords) []*rawReference {
 
 	ret := make([]*rawReference, 0, 10)
 	pos := 0
@@ -370,7 +423,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 		if match == nil {
 			break
 		}
-		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false); ref != nil {
+		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false, keywords); ref != nil {
 			ret = append(ret, ref)
 		}
 		notrail := spaceTrimmedPattern.FindSubmatchIndex(content[match[2]+pos : match[3]+pos])
@@ -388,7 +441,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 		if match == nil {
 			break
 		}
-		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false); ref != nil {
+		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false, keywords); ref != nil {
 			ret = append(ret, ref)
 		}
 		notrail := spaceTrimmedPattern.FindSubmatchIndex(content[match[2]+pos : match[3]+pos])
@@ -422,7 +475,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 			}
 			// Note: closing/reopening keywords not supported with URLs
 			bytes := []byte(parts[1] + "/" + parts[2] + sep + parts[4])
-			if ref := getCrossReference(bytes, 0, len(bytes), true, false); ref != nil {
+			if ref := getCrossReference(bytes, 0, len(bytes), true, false, keywords); ref != nil {
 				ref.refLocation = nil
 				ret = append(ret, ref)
 			}
@@ -464,7 +517,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 	return ret
 }
 
-func getCrossReference(content []byte, start, end int, fromLink bool, prOnly bool) *rawReference {
+func getCrossReference(content []byte, start, end int, fromLink bool, prOnly bool, keywords *IssueKeywords) *rawReference {
 	refid := string(content[start:end])
 	sep := strings.IndexAny(refid, "#!")
 	if sep < 0 {
@@ -485,7 +538,7 @@ func getCrossReference(content []byte, start, end int, fromLink bool, prOnly boo
 			// Markdown links must specify owner/repo
 			return nil
 		}
-		action, location := findActionKeywords(content, start)
+		action, location := findActionKeywords(content, start, keywords)
 		return &rawReference{
 			index:          index,
 			action:         action,
@@ -503,7 +556,7 @@ func getCrossReference(content []byte, start, end int, fromLink bool, prOnly boo
 	if !validNamePattern.MatchString(owner) || !validNamePattern.MatchString(name) {
 		return nil
 	}
-	action, location := findActionKeywords(content, start)
+	action, location := findActionKeywords(content, start, keywords)
 	return &rawReference{
 		index:          index,
 		owner:          owner,
@@ -516,17 +569,17 @@ func getCrossReference(content []byte, start, end int, fromLink bool, prOnly boo
 	}
 }
 
-func findActionKeywords(content []byte, start int) (XRefAction, *RefSpan) {
-	newKeywords()
+func findActionKeywords(content []byte, start int, keywords *IssueKeywords) (XRefAction, *RefSpan) {
+	closePat, reopenPat := keywords.patterns()
 	var m []int
-	if issueCloseKeywordsPat != nil {
-		m = issueCloseKeywordsPat.FindSubmatchIndex(content[:start])
+	if closePat != nil {
+		m = closePat.FindSubmatchIndex(content[:start])
 		if m != nil {
 			return XRefActionCloses, &RefSpan{Start: m[2], End: m[3]}
 		}
 	}
-	if issueReopenKeywordsPat != nil {
-		m = issueReopenKeywordsPat.FindSubmatchIndex(content[:start])
+	if reopenPat != nil {
+		m = reopenPat.FindSubmatchIndex(content[:start])
 		if m != nil {
 			return XRefActionReopens, &RefSpan{Start: m[2], End: m[3]}
 		}
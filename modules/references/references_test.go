@@ -280,7 +280,7 @@ func testFixtures(t *testing.T, fixtures []testFixture, context string) {
 		expref := rawToIssueReferenceList(expraw)
 		refs := FindAllIssueReferencesMarkdown(fixture.input)
 		assert.EqualValues(t, expref, refs, "[%s] Failed to parse: {%s}", context, fixture.input)
-		rawrefs := findAllIssueReferencesMarkdown(fixture.input)
+		rawrefs := findAllIssueReferencesMarkdown(fixture.input, nil)
 		assert.EqualValues(t, expraw, rawrefs, "[%s] Failed to parse: {%s}", context, fixture.input)
 	}
 
@@ -452,6 +452,39 @@ func TestCustomizeCloseKeywords(t *testing.T) {
 	doNewKeywords(setting.Repository.PullRequest.CloseKeywords, setting.Repository.PullRequest.ReopenKeywords)
 }
 
+func TestIssueKeywords(t *testing.T) {
+	keywords := NewIssueKeywords([]string{"cierra", "cerró"}, []string{"reabre"})
+	refs := keywords.FindAllIssueReferences("Simplemente cierra: #29, closes #30 y reabre user3/repo4#200")
+	if assert.Len(t, refs, 3) {
+		assert.Equal(t, XRefActionCloses, refs[0].Action)
+		assert.Equal(t, XRefActionNone, refs[1].Action)
+		assert.Equal(t, XRefActionReopens, refs[2].Action)
+	}
+
+	found, ref := keywords.FindRenderizableReferenceNumeric("cierra #29", false)
+	assert.True(t, found)
+	assert.Equal(t, XRefActionCloses, ref.Action)
+	assert.Equal(t, &RefSpan{Start: 0, End: 6}, ref.ActionLocation)
+
+	// nil keywords are the keywords of the settings
+	refs = (*IssueKeywords)(nil).FindAllIssueReferencesMarkdown("closes #30")
+	if assert.Len(t, refs, 1) {
+		assert.Equal(t, XRefActionCloses, refs[0].Action)
+	}
+
+	disabled := NewIssueKeywords(nil, nil)
+	refs = disabled.FindAllIssueReferences("closes #30, reopens #31")
+	if assert.Len(t, refs, 2) {
+		assert.Equal(t, XRefActionNone, refs[0].Action)
+		assert.Equal(t, XRefActionNone, refs[1].Action)
+	}
+
+	assert.True(t, IsValidKeyword("cierra"))
+	assert.True(t, IsValidKeyword(" cerró "))
+	assert.False(t, IsValidKeyword("fix-es"))
+	assert.False(t, IsValidKeyword("99"))
+}
+
 func TestParseCloseKeywords(t *testing.T) {
 	// Test parsing of CloseKeywords and ReopenKeywords
 	assert.Len(t, parseKeywords([]string{""}), 0)
@@ -124,6 +124,9 @@ func changeIssueStatus(repo *models.Repository, issue *models.Issue, doer *model
 
 // UpdateIssuesCommit checks if issues are manipulated by commit message.
 func UpdateIssuesCommit(doer *models.User, repo *models.Repository, commits []*repository.PushCommit, branchName string) error {
+	keywords := repo.IssueKeywords()
+	closeOnPush := repo.IssueCloseTrigger().OnPush()
+
 	// Commits are appended in the reverse order.
 	for i := len(commits) - 1; i >= 0; i-- {
 		c := commits[i]
@@ -137,7 +140,7 @@ func UpdateIssuesCommit(doer *models.User, repo *models.Repository, commits []*r
 		var refRepo *models.Repository
 		var refIssue *models.Issue
 		var err error
-		for _, ref := range references.FindAllIssueReferences(c.Message) {
+		for _, ref := range keywords.FindAllIssueReferences(c.Message) {
 
 			// issue is from another repo
 			if len(ref.Owner) > 0 && len(ref.Name) > 0 {
@@ -190,6 +193,11 @@ func UpdateIssuesCommit(doer *models.User, repo *models.Repository, commits []*r
 				continue
 			}
 
+			// The repository may only close issues when pull requests are merged
+			if !closeOnPush {
+				continue
+			}
+
 			if !repo.CloseIssuesViaCommitInAnyBranch {
 				// If the issue was specified to be in a particular branch, don't allow commits in other branches to close it
 				if refIssue.Ref != "" {
@@ -295,3 +295,48 @@ func TestUpdateIssuesCommit_AnotherRepoNoPermission(t *testing.T) {
 	models.AssertNotExistsBean(t, issueBean, "is_closed=1")
 	models.CheckConsistencyFor(t, &models.Action{})
 }
+
+func TestUpdateIssuesCommit_CustomKeywords(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+	setIssuesConfig := func(config *models.IssuesConfig) *models.Repository {
+		assert.NoError(t, models.UpdateRepositoryUnits(repo, []models.RepoUnit{{
+			RepoID: repo.ID,
+			Type:   models.UnitTypeIssues,
+			Config: config,
+		}}, nil))
+		return models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	}
+	pushCommits := func(message string) []*repository.PushCommit {
+		return []*repository.PushCommit{{
+			Sha1:           "abcdef1",
+			CommitterEmail: "user2@example.com",
+			CommitterName:  "User Two",
+			AuthorEmail:    "user2@example.com",
+			AuthorName:     "User Two",
+			Message:        message,
+		}}
+	}
+	issueBean := &models.Issue{RepoID: 1, Index: 1}
+
+	// the keywords of the settings are replaced by the keywords of the repository
+	customRepo := setIssuesConfig(&models.IssuesConfig{CloseKeywords: []string{"cierra"}})
+	assert.NoError(t, UpdateIssuesCommit(user, customRepo, pushCommits("close #1"), customRepo.DefaultBranch))
+	models.AssertNotExistsBean(t, issueBean, "is_closed=1")
+
+	// pushes do not close issues if only merged pull requests close them
+	mergeRepo := setIssuesConfig(&models.IssuesConfig{CloseKeywords: []string{"cierra"}, CloseTrigger: models.IssueCloseTriggerMerge})
+	assert.NoError(t, UpdateIssuesCommit(user, mergeRepo, pushCommits("cierra #1"), mergeRepo.DefaultBranch))
+	models.AssertNotExistsBean(t, issueBean, "is_closed=1")
+
+	disabledRepo := setIssuesConfig(&models.IssuesConfig{DisableCloseKeywords: true})
+	assert.NoError(t, UpdateIssuesCommit(user, disabledRepo, pushCommits("close #1"), disabledRepo.DefaultBranch))
+	models.AssertNotExistsBean(t, issueBean, "is_closed=1")
+
+	customRepo = setIssuesConfig(&models.IssuesConfig{CloseKeywords: []string{"cierra"}, CloseTrigger: models.IssueCloseTriggerPush})
+	assert.NoError(t, UpdateIssuesCommit(user, customRepo, pushCommits("cierra #1"), customRepo.DefaultBranch))
+	models.AssertExistsAndLoadBean(t, issueBean, "is_closed=1")
+	models.CheckConsistencyFor(t, &models.Action{})
+}
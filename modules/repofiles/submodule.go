@@ -0,0 +1,94 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// SubmoduleTarget represents the repository hosted on this instance and the commit a submodule points to
+type SubmoduleTarget struct {
+	Repo     *models.Repository
+	CommitID string
+}
+
+// HTMLURL returns the URL of the tree of the submodule at the commit it points to
+func (t *SubmoduleTarget) HTMLURL() string {
+	return t.Repo.HTMLURL() + "/src/commit/" + t.CommitID
+}
+
+// GetSubmoduleTarget returns the repository on this instance the submodule with the given git URL of repo
+// points to. It returns nil if the submodule is hosted elsewhere, the repository does not exist
+// or the doer is not allowed to read its code.
+func GetSubmoduleTarget(repo *models.Repository, doer *models.User, submoduleURL, commitID string) (*SubmoduleTarget, error) {
+	sm := &git.SubModule{URL: submoduleURL}
+	refURL := sm.RefURL(setting.AppURL, repo.FullName(), setting.SSH.Domain)
+
+	urlPrefix := strings.TrimSuffix(setting.AppURL, "/") + "/"
+	if !strings.HasPrefix(refURL, urlPrefix) {
+		return nil, nil
+	}
+	fields := strings.Split(strings.Trim(strings.TrimPrefix(refURL, urlPrefix), "/"), "/")
+	if len(fields) != 2 {
+		return nil, nil
+	}
+
+	target, err := models.GetRepositoryByOwnerAndName(fields[0], fields[1])
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	perm, err := models.GetUserRepoPermission(target, doer)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.CanRead(models.UnitTypeCode) {
+		return nil, nil
+	}
+
+	return &SubmoduleTarget{
+		Repo:     target,
+		CommitID: commitID,
+	}, nil
+}
+
+// ResolveSubmoduleTargets fills in the targets of the submodules in a response of GetContentsOrList
+// which point to repositories on this instance the doer can read.
+func ResolveSubmoduleTargets(repo *models.Repository, doer *models.User, contents interface{}) error {
+	var list []*api.ContentsResponse
+	switch c := contents.(type) {
+	case *api.ContentsResponse:
+		list = []*api.ContentsResponse{c}
+	case []*api.ContentsResponse:
+		list = c
+	}
+
+	for _, content := range list {
+		if content.Type != string(ContentTypeSubmodule) || content.SubmoduleGitURL == nil {
+			continue
+		}
+		target, err := GetSubmoduleTarget(repo, doer, *content.SubmoduleGitURL, content.SHA)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			continue
+		}
+		content.SubmoduleTarget = &api.SubmoduleTarget{
+			FullName: target.Repo.FullName(),
+			SHA:      target.CommitID,
+			URL:      target.Repo.APIURL() + "/contents?ref=" + target.CommitID,
+			HTMLURL:  target.HTMLURL(),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSubmoduleTarget(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	const commitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	for _, submoduleURL := range []string{
+		"https://try.gitea.io/user2/repo1.git",
+		"git@try.gitea.io:user2/repo1.git",
+		"../repo1",
+	} {
+		target, err := GetSubmoduleTarget(repo, nil, submoduleURL, commitID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, target, submoduleURL) {
+			assert.EqualValues(t, 1, target.Repo.ID)
+			assert.Equal(t, commitID, target.CommitID)
+			assert.Equal(t, "https://try.gitea.io/user2/repo1/src/commit/"+commitID, target.HTMLURL())
+		}
+	}
+
+	for _, submoduleURL := range []string{
+		"https://github.com/go-gitea/gitea.git",
+		"https://try.gitea.io/user2/does-not-exist.git",
+		"https://try.gitea.io/user2/repo1/wiki",
+	} {
+		target, err := GetSubmoduleTarget(repo, user2, submoduleURL, commitID)
+		assert.NoError(t, err)
+		assert.Nil(t, target, submoduleURL)
+	}
+
+	// private repositories are only resolved for users who can read them
+	target, err := GetSubmoduleTarget(repo, nil, "../repo2", commitID)
+	assert.NoError(t, err)
+	assert.Nil(t, target)
+	target, err = GetSubmoduleTarget(repo, user2, "../repo2", commitID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, target) {
+		assert.EqualValues(t, 2, target.Repo.ID)
+	}
+}
+
+func TestResolveSubmoduleTargets(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	const commitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	local, remote := "../repo1", "https://github.com/go-gitea/gitea.git"
+	contents := []*api.ContentsResponse{
+		{Name: "local", Type: string(ContentTypeSubmodule), SHA: commitID, SubmoduleGitURL: &local},
+		{Name: "remote", Type: string(ContentTypeSubmodule), SHA: commitID, SubmoduleGitURL: &remote},
+		{Name: "README.md", Type: string(ContentTypeRegular)},
+	}
+	assert.NoError(t, ResolveSubmoduleTargets(repo, nil, contents))
+	assert.Equal(t, &api.SubmoduleTarget{
+		FullName: "user2/repo1",
+		SHA:      commitID,
+		URL:      "https://try.gitea.io/api/v1/repos/user2/repo1/contents?ref=" + commitID,
+		HTMLURL:  "https://try.gitea.io/user2/repo1/src/commit/" + commitID,
+	}, contents[0].SubmoduleTarget)
+	assert.Nil(t, contents[1].SubmoduleTarget)
+	assert.Nil(t, contents[2].SubmoduleTarget)
+}
@@ -85,6 +85,11 @@ func CreateRepository(doer, u *models.User, opts models.CreateRepoOptions) (*mod
 			return fmt.Errorf("initRepository: %v", err)
 		}
 
+		// The labels of the preset are used unless others were selected
+		if opts.Preset != nil && len(opts.IssueLabels) == 0 {
+			opts.IssueLabels = opts.Preset.IssueLabels
+		}
+
 		// Initialize Issue Labels if selected
 		if len(opts.IssueLabels) > 0 {
 			if err := models.InitializeLabels(ctx, repo.ID, opts.IssueLabels, false); err != nil {
@@ -95,6 +100,17 @@ func CreateRepository(doer, u *models.User, opts models.CreateRepoOptions) (*mod
 			}
 		}
 
+		if opts.Preset != nil {
+			if err := models.ApplyRepoPreset(ctx, repo, opts.Preset); err != nil {
+				if err2 := util.RemoveAll(repoPath); err2 != nil {
+					log.Error("ApplyRepoPreset: %v", err)
+					return fmt.Errorf(
+						"delete repo directory %s/%s failed(2): %v", u.Name, repo.Name, err2)
+				}
+				return fmt.Errorf("ApplyRepoPreset: %v", err)
+			}
+		}
+
 		if stdout, err := git.NewCommand("update-server-info").
 			SetDescription(fmt.Sprintf("CreateRepository(git update-server-info): %s", repoPath)).
 			RunInDir(repoPath); err != nil {
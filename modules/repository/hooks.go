@@ -10,8 +10,11 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
@@ -196,3 +199,200 @@ func SyncRepositoryHooks(ctx context.Context) error {
 	log.Trace("Finished: SyncRepositoryHooks")
 	return nil
 }
+
+// IsHooksSourceRepo returns true if the repository is the configured source of managed hook scripts
+func IsHooksSourceRepo(repo *models.Repository) bool {
+	return !setting.DisableGitHooks && len(setting.Git.HooksSourceRepo) > 0 && strings.EqualFold(setting.Git.HooksSourceRepo, repo.FullName())
+}
+
+// isAdminOwned returns true if the owner is a site admin or an organization with a site admin among its owners
+func isAdminOwned(owner *models.User) (bool, error) {
+	if !owner.IsOrganization() {
+		return owner.IsAdmin, nil
+	}
+	team, err := owner.GetOwnerTeam()
+	if err != nil {
+		return false, err
+	}
+	members, err := models.GetTeamMembers(team.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, member := range members {
+		if member.IsAdmin {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// loadManagedHookScripts reads the managed hook scripts from the default branch of the hooks source repository.
+// The scripts of a hook are the files in the top level directory named after the hook.
+func loadManagedHookScripts() (map[string]map[string]string, error) {
+	parts := strings.SplitN(setting.Git.HooksSourceRepo, "/", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid hooks source repository: %s", setting.Git.HooksSourceRepo)
+	}
+	repo, err := models.GetRepositoryByOwnerAndName(parts[0], parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("GetRepositoryByOwnerAndName: %v", err)
+	}
+	if err := repo.GetOwner(); err != nil {
+		return nil, fmt.Errorf("GetOwner: %v", err)
+	}
+	// everyone who can push to the hooks source repository can run code on the server
+	if ok, err := isAdminOwned(repo.Owner); err != nil {
+		return nil, fmt.Errorf("isAdminOwned: %v", err)
+	} else if !ok {
+		return nil, fmt.Errorf("hooks source repository %s is not owned by a site admin", repo.FullName())
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %v", err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return nil, fmt.Errorf("GetBranchCommit: %v", err)
+	}
+
+	hookNames, _, _ := getHookTemplates()
+	scripts := make(map[string]map[string]string, len(hookNames))
+	for _, hookName := range hookNames {
+		scripts[hookName] = make(map[string]string)
+
+		tree, err := commit.SubTree(hookName)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("SubTree: %v", err)
+		}
+		entries, err := tree.ListEntries()
+		if err != nil {
+			return nil, fmt.Errorf("ListEntries: %v", err)
+		}
+		for _, entry := range entries {
+			name := git.HookScriptManagedPrefix + entry.Name()
+			if !(entry.IsRegular() || entry.IsExecutable()) || !git.IsValidHookScriptName(name) {
+				continue
+			}
+			dataRc, err := entry.Blob().DataAsync()
+			if err != nil {
+				return nil, fmt.Errorf("DataAsync: %v", err)
+			}
+			content, err := ioutil.ReadAll(dataRc)
+			dataRc.Close()
+			if err != nil {
+				return nil, fmt.Errorf("ReadAll: %v", err)
+			}
+			scripts[hookName][name] = string(content)
+		}
+	}
+	return scripts, nil
+}
+
+// syncManagedHookScripts writes the managed hook scripts of the repository and
+// removes the ones which no longer exist in the hooks source repository
+func syncManagedHookScripts(repoPath string, scripts map[string]map[string]string) error {
+	for hookName, contents := range scripts {
+		existing, err := git.ListHookScripts(repoPath, hookName)
+		if err != nil {
+			return err
+		}
+		for _, script := range existing {
+			if _, ok := contents[script.Name]; script.IsManaged() && !ok {
+				if err := script.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+		for name, content := range contents {
+			script, err := git.GetHookScript(repoPath, hookName, name)
+			if err != nil {
+				return err
+			}
+			if script.Content == content {
+				continue
+			}
+			script.Content = content
+			if err := script.Update(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	managedHookSyncLock    sync.Mutex
+	managedHookSyncPending = make(chan struct{}, 1)
+	// syncManagedHooks is replaced by tests
+	syncManagedHooks = syncAllManagedHookScripts
+)
+
+// TriggerSyncManagedHookScripts requests a sync of the managed hook scripts in the background.
+// Only one sync runs at a time and requests made while it runs are coalesced into a single further sync.
+func TriggerSyncManagedHookScripts(ctx context.Context) {
+	select {
+	case managedHookSyncPending <- struct{}{}:
+	default:
+		return
+	}
+	go func() {
+		managedHookSyncLock.Lock()
+		defer managedHookSyncLock.Unlock()
+		<-managedHookSyncPending
+		if err := syncManagedHooks(ctx); err != nil {
+			log.Error("SyncManagedHookScripts: %v", err)
+		}
+	}()
+}
+
+// SyncManagedHookScripts copies the hook scripts of the hooks source repository into the hooks of all repositories
+func SyncManagedHookScripts(ctx context.Context) error {
+	managedHookSyncLock.Lock()
+	defer managedHookSyncLock.Unlock()
+	return syncManagedHooks(ctx)
+}
+
+func syncAllManagedHookScripts(ctx context.Context) error {
+	if len(setting.Git.HooksSourceRepo) == 0 {
+		return nil
+	}
+	if setting.DisableGitHooks {
+		return fmt.Errorf("SyncManagedHookScripts: git hooks are disabled, set DISABLE_GIT_HOOKS = false to sync %s", setting.Git.HooksSourceRepo)
+	}
+	log.Trace("Doing: SyncManagedHookScripts")
+
+	scripts, err := loadManagedHookScripts()
+	if err != nil {
+		return fmt.Errorf("SyncManagedHookScripts: %v", err)
+	}
+
+	if err := models.Iterate(
+		models.DefaultDBContext(),
+		new(models.Repository),
+		builder.Gt{"id": 0},
+		func(idx int, bean interface{}) error {
+			repo := bean.(*models.Repository)
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before sync managed hook scripts for %s", repo.FullName())
+			default:
+			}
+
+			if err := syncManagedHookScripts(repo.RepoPath(), scripts); err != nil {
+				return fmt.Errorf("SyncManagedHookScripts: %v", err)
+			}
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	log.Trace("Finished: SyncManagedHookScripts")
+	return nil
+}
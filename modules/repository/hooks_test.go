@@ -0,0 +1,93 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAdminOwned(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	for _, tc := range []struct {
+		userID int64
+		admin  bool
+	}{
+		{1, true},
+		{2, false},
+		{3, false}, // organization without site admin owners
+	} {
+		owner := models.AssertExistsAndLoadBean(t, &models.User{ID: tc.userID}).(*models.User)
+		ok, err := isAdminOwned(owner)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.admin, ok, "user %d", tc.userID)
+	}
+
+	// an admin joining the owners team makes the organization admin owned
+	org := models.AssertExistsAndLoadBean(t, &models.User{ID: 3}).(*models.User)
+	team, err := org.GetOwnerTeam()
+	assert.NoError(t, err)
+	assert.NoError(t, team.AddMember(1))
+	ok, err := isAdminOwned(org)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestSyncManagedHookScriptsRequiresGitHooks(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	oldSource, oldDisable := setting.Git.HooksSourceRepo, setting.DisableGitHooks
+	defer func() {
+		setting.Git.HooksSourceRepo, setting.DisableGitHooks = oldSource, oldDisable
+	}()
+	setting.Git.HooksSourceRepo = "user2/repo1"
+	setting.DisableGitHooks = true
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.False(t, IsHooksSourceRepo(repo))
+	assert.Error(t, SyncManagedHookScripts(context.Background()))
+
+	setting.DisableGitHooks = false
+	assert.True(t, IsHooksSourceRepo(repo))
+	// user2 is no site admin, so its repository is refused as hooks source
+	assert.Error(t, SyncManagedHookScripts(context.Background()))
+}
+
+func TestTriggerSyncManagedHookScripts(t *testing.T) {
+	var syncs int32
+	done := make(chan struct{}, 2)
+	defer func(old func(context.Context) error) { syncManagedHooks = old }(syncManagedHooks)
+	syncManagedHooks = func(context.Context) error {
+		atomic.AddInt32(&syncs, 1)
+		done <- struct{}{}
+		return nil
+	}
+
+	// while a sync runs, two quick triggers cause only one further sync
+	managedHookSyncLock.Lock()
+	TriggerSyncManagedHookScripts(context.Background())
+	TriggerSyncManagedHookScripts(context.Background())
+	managedHookSyncLock.Unlock()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the managed hook scripts were not synced")
+	}
+	select {
+	case <-done:
+		t.Fatal("the triggers were not coalesced")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(&syncs))
+}
@@ -313,6 +313,9 @@ func initRepository(ctx models.DBContext, repoPath string, u *models.User, repo
 
 	if !opts.AutoInit {
 		repo.IsEmpty = true
+	} else {
+		// The license files are named by their SPDX identifiers
+		repo.License = opts.License
 	}
 
 	repo.DefaultBranch = setting.Repository.DefaultBranch
@@ -12,6 +12,9 @@ var (
 		MaxSize      int64
 		MaxFiles     int
 		Enabled      bool
+
+		SignedURLDefaultExpiry int
+		SignedURLMaxExpiry     int
 	}{
 		Storage: Storage{
 			ServeDirect: false,
@@ -20,6 +23,9 @@ var (
 		MaxSize:      4,
 		MaxFiles:     5,
 		Enabled:      true,
+
+		SignedURLDefaultExpiry: 10,
+		SignedURLMaxExpiry:     60,
 	}
 )
 
@@ -33,4 +39,9 @@ func newAttachmentService() {
 	Attachment.MaxSize = sec.Key("MAX_SIZE").MustInt64(4)
 	Attachment.MaxFiles = sec.Key("MAX_FILES").MustInt(5)
 	Attachment.Enabled = sec.Key("ENABLED").MustBool(true)
+	Attachment.SignedURLMaxExpiry = sec.Key("SIGNED_URL_MAX_EXPIRY").MustInt(60)
+	Attachment.SignedURLDefaultExpiry = sec.Key("SIGNED_URL_DEFAULT_EXPIRY").MustInt(10)
+	if Attachment.SignedURLDefaultExpiry > Attachment.SignedURLMaxExpiry {
+		Attachment.SignedURLDefaultExpiry = Attachment.SignedURLMaxExpiry
+	}
 }
@@ -0,0 +1,41 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// EventArchive settings
+var (
+	EventArchive = struct {
+		Storage
+		// Enabled archives the webhook payloads of all repository events to the storage
+		Enabled bool
+		// Owners limits the archive to the repositories of these users and organizations, all repositories if empty
+		Owners []string
+	}{}
+)
+
+func newEventArchiveService() {
+	sec := Cfg.Section("event_archive")
+	EventArchive.Enabled = sec.Key("ENABLED").MustBool(false)
+	if !EventArchive.Enabled {
+		return
+	}
+
+	EventArchive.Storage = getStorage("event-archive", sec.Key("STORAGE_TYPE").MustString(""), sec)
+
+	owners := sec.Key("OWNERS").Strings(",")
+	EventArchive.Owners = make([]string, 0, len(owners))
+	for _, owner := range owners {
+		if owner = strings.TrimSpace(owner); len(owner) > 0 {
+			EventArchive.Owners = append(EventArchive.Owners, strings.ToLower(owner))
+		}
+	}
+	log.Info("Archiving repository events with %s storage", EventArchive.Storage.Type)
+}
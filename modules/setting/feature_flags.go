@@ -0,0 +1,28 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// FeatureFlags settings
+var (
+	FeatureFlags = struct {
+		// CacheTTL is how long the feature flags are cached, changes made on other instances
+		// sharing the database take effect after this duration
+		CacheTTL time.Duration `ini:"CACHE_TTL"`
+	}{
+		CacheTTL: time.Minute,
+	}
+)
+
+func newFeatureFlagsService() {
+	if err := Cfg.Section("feature_flags").MapTo(&FeatureFlags); err != nil {
+		log.Fatal("Failed to map FeatureFlags settings: %v", err)
+	}
+}
@@ -24,6 +24,7 @@ var (
 		GCArgs                    []string `ini:"GC_ARGS" delim:" "`
 		EnableAutoGitWireProtocol bool
 		PullRequestPushMessage    bool
+		HooksSourceRepo           string
 		Timeout                   struct {
 			Default int
 			Migrate int
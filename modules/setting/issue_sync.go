@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"code.gitea.io/gitea/modules/log"
+)
+
+// IssueSync settings
+var (
+	IssueSync = struct {
+		// Enabled allows repository admins to mirror the issues and comments of repositories
+		// migrated from GitHub with their upstream repository in both directions
+		Enabled bool
+	}{
+		Enabled: false,
+	}
+)
+
+func newIssueSyncService() {
+	if err := Cfg.Section("issue_sync").MapTo(&IssueSync); err != nil {
+		log.Fatal("Failed to map IssueSync settings: %v", err)
+	}
+	if IssueSync.Enabled {
+		log.Info("Two-way issue sync with GitHub enabled")
+	}
+}
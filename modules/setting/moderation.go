@@ -0,0 +1,26 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import "code.gitea.io/gitea/modules/log"
+
+// Moderation settings
+var (
+	Moderation = struct {
+		// Enabled allows users to report abuse to the site administrators
+		Enabled bool
+		// NotifyAdmins sends an email to the site administrators for each new abuse report
+		NotifyAdmins bool
+	}{
+		Enabled:      false,
+		NotifyAdmins: true,
+	}
+)
+
+func newModerationService() {
+	if err := Cfg.Section("moderation").MapTo(&Moderation); err != nil {
+		log.Fatal("Failed to map Moderation settings: %v", err)
+	}
+}
@@ -163,6 +163,12 @@ func NewQueueService() {
 	if _, ok := sectionMap["LENGTH"]; !ok {
 		_, _ = section.NewKey("LENGTH", fmt.Sprintf("%d", Repository.PullRequestQueueLength))
 	}
+
+	// The events of a batch are written to the same files of the event archive
+	section = Cfg.Section("queue.event_archive")
+	if !section.HasKey("BATCH_LENGTH") {
+		_, _ = section.NewKey("BATCH_LENGTH", "100")
+	}
 }
 
 // ParseQueueConnStr parses a queue connection string
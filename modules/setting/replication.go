@@ -0,0 +1,74 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Replication settings
+var (
+	Replication = struct {
+		// PrimaryURL is the root URL of the primary instance, setting it turns this instance into a read replica
+		PrimaryURL string `ini:"PRIMARY_URL"`
+		// ReplicaURLs are the root URLs of the read replicas the primary notifies of repository changes
+		ReplicaURLs []string `ini:"REPLICA_URLS"`
+		// Secret authenticates the git fetches of the read replicas on the primary
+		Secret string `ini:"SECRET"`
+		// AllowInsecurePrimaryURL allows sending the secret to a primary which is not served over https
+		AllowInsecurePrimaryURL bool `ini:"ALLOW_INSECURE_PRIMARY_URL"`
+		IsReplica               bool `ini:"-"`
+	}{}
+)
+
+func newReplication() {
+	sec := Cfg.Section("replication")
+	if err := sec.MapTo(&Replication); err != nil {
+		log.Fatal("Failed to map Replication settings: %v", err)
+	}
+
+	if len(Replication.PrimaryURL) > 0 {
+		primaryURL, err := url.Parse(Replication.PrimaryURL)
+		if err != nil {
+			log.Fatal("Invalid PRIMARY_URL in [replication] '%s': %v", Replication.PrimaryURL, err)
+		}
+		if primaryURL.Scheme != "https" && !Replication.AllowInsecurePrimaryURL {
+			log.Fatal("PRIMARY_URL in [replication] '%s' is not an https URL, set ALLOW_INSECURE_PRIMARY_URL = true to send the replication secret over it anyway", Replication.PrimaryURL)
+		}
+		if !strings.HasSuffix(Replication.PrimaryURL, "/") {
+			Replication.PrimaryURL += "/"
+		}
+		Replication.IsReplica = true
+	}
+
+	replicaURLs := make([]string, 0, len(Replication.ReplicaURLs))
+	for _, replicaURL := range Replication.ReplicaURLs {
+		replicaURL = strings.TrimSpace(replicaURL)
+		if len(replicaURL) == 0 {
+			continue
+		}
+		if !strings.HasSuffix(replicaURL, "/") {
+			replicaURL += "/"
+		}
+		replicaURLs = append(replicaURLs, replicaURL)
+	}
+	Replication.ReplicaURLs = replicaURLs
+
+	if Replication.IsReplica && len(Replication.ReplicaURLs) > 0 {
+		log.Fatal("A read replica cannot have replicas itself, PRIMARY_URL and REPLICA_URLS in [replication] are mutually exclusive")
+	}
+
+	if Replication.IsReplica || len(Replication.ReplicaURLs) > 0 {
+		if len(Replication.Secret) == 0 {
+			log.Fatal("SECRET in [replication] must be set on the primary and its read replicas")
+		}
+		if Replication.Secret == InternalToken {
+			log.Fatal("SECRET in [replication] must differ from INTERNAL_TOKEN")
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 
@@ -34,6 +35,7 @@ var (
 		MirrorQueueLength                       int
 		PullRequestQueueLength                  int
 		PreferredLicenses                       []string
+		DetectFileLicenses                      bool
 		DisableHTTPGit                          bool
 		AccessControlAllowOrigin                string
 		UseCompatSSHURI                         bool
@@ -43,6 +45,7 @@ var (
 		DisabledRepoUnits                       []string
 		DefaultRepoUnits                        []string
 		PrefixArchiveFiles                      bool
+		MaxConcurrentArchivesPerUser            int
 		DisableMirrors                          bool
 		DefaultBranch                           string
 		AllowAdoptionOfUnadoptedRepositories    bool
@@ -78,6 +81,8 @@ var (
 			DefaultMergeMessageAllAuthors            bool
 			DefaultMergeMessageMaxApprovers          int
 			DefaultMergeMessageOfficialApproversOnly bool
+			ExternalMergeChecker                     bool
+			ExternalMergeCheckerTimeout              time.Duration
 		} `ini:"repository.pull-request"`
 
 		// Issue Setting
@@ -144,6 +149,7 @@ var (
 		MirrorQueueLength:                       1000,
 		PullRequestQueueLength:                  1000,
 		PreferredLicenses:                       []string{"Apache License 2.0", "MIT License"},
+		DetectFileLicenses:                      false,
 		DisableHTTPGit:                          false,
 		AccessControlAllowOrigin:                "",
 		UseCompatSSHURI:                         false,
@@ -153,6 +159,7 @@ var (
 		DisabledRepoUnits:                       []string{},
 		DefaultRepoUnits:                        []string{},
 		PrefixArchiveFiles:                      true,
+		MaxConcurrentArchivesPerUser:            3,
 		DisableMirrors:                          false,
 		DefaultBranch:                           "master",
 
@@ -197,6 +204,8 @@ var (
 			DefaultMergeMessageAllAuthors            bool
 			DefaultMergeMessageMaxApprovers          int
 			DefaultMergeMessageOfficialApproversOnly bool
+			ExternalMergeChecker                     bool
+			ExternalMergeCheckerTimeout              time.Duration
 		}{
 			WorkInProgressPrefixes: []string{"WIP:", "[WIP]"},
 			// Same as GitHub. See
@@ -208,6 +217,8 @@ var (
 			DefaultMergeMessageAllAuthors:            false,
 			DefaultMergeMessageMaxApprovers:          10,
 			DefaultMergeMessageOfficialApproversOnly: true,
+			ExternalMergeChecker:                     false,
+			ExternalMergeCheckerTimeout:              10 * time.Minute,
 		},
 
 		// Issue settings
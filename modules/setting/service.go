@@ -49,6 +49,9 @@ var Service struct {
 	AutoWatchNewRepos                       bool
 	AutoWatchOnChanges                      bool
 	DefaultOrgMemberVisible                 bool
+	DormantUserInactiveMonths               int
+	DormantUserGracePeriodDays              int
+	DormantUserAllowReactivation            bool
 
 	// OpenID settings
 	EnableOpenIDSignIn bool
@@ -96,6 +99,9 @@ func newService() {
 	Service.DefaultOrgVisibility = sec.Key("DEFAULT_ORG_VISIBILITY").In("public", structs.ExtractKeysFromMapString(structs.VisibilityModes))
 	Service.DefaultOrgVisibilityMode = structs.VisibilityModes[Service.DefaultOrgVisibility]
 	Service.DefaultOrgMemberVisible = sec.Key("DEFAULT_ORG_MEMBER_VISIBLE").MustBool()
+	Service.DormantUserInactiveMonths = sec.Key("DORMANT_USER_INACTIVE_MONTHS").MustInt(0)
+	Service.DormantUserGracePeriodDays = sec.Key("DORMANT_USER_GRACE_PERIOD_DAYS").MustInt(14)
+	Service.DormantUserAllowReactivation = sec.Key("DORMANT_USER_ALLOW_REACTIVATION").MustBool(true)
 
 	sec = Cfg.Section("openid")
 	Service.EnableOpenIDSignIn = sec.Key("ENABLE_OPENID_SIGNIN").MustBool(!InstallLock)
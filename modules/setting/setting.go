@@ -158,6 +158,8 @@ var (
 	PasswordComplexity                 []string
 	PasswordHashAlgo                   string
 	PasswordCheckPwn                   bool
+	GitCredentialDefaultExpiry         int
+	GitCredentialMaxExpiry             int
 
 	// UI settings
 	UI = struct {
@@ -295,8 +297,11 @@ var (
 
 	// Mirror settings
 	Mirror struct {
-		DefaultInterval time.Duration
-		MinInterval     time.Duration
+		DefaultInterval           time.Duration
+		MinInterval               time.Duration
+		EnableReleaseAssets       bool
+		ReleaseAssetMaxSize       int64
+		ReleaseAssetsMaxTotalSize int64
 	}
 
 	// API settings
@@ -785,6 +790,11 @@ func NewContext() {
 	PasswordHashAlgo = sec.Key("PASSWORD_HASH_ALGO").MustString("argon2")
 	CSRFCookieHTTPOnly = sec.Key("CSRF_COOKIE_HTTP_ONLY").MustBool(true)
 	PasswordCheckPwn = sec.Key("PASSWORD_CHECK_PWN").MustBool(false)
+	GitCredentialMaxExpiry = sec.Key("GIT_CREDENTIAL_MAX_EXPIRY").MustInt(60)
+	GitCredentialDefaultExpiry = sec.Key("GIT_CREDENTIAL_DEFAULT_EXPIRY").MustInt(15)
+	if GitCredentialDefaultExpiry > GitCredentialMaxExpiry {
+		GitCredentialDefaultExpiry = GitCredentialMaxExpiry
+	}
 
 	InternalToken = loadInternalToken(sec)
 
@@ -892,6 +902,9 @@ func NewContext() {
 		log.Warn("Mirror.DefaultInterval is less than Mirror.MinInterval")
 		Mirror.DefaultInterval = time.Hour * 8
 	}
+	Mirror.EnableReleaseAssets = sec.Key("ENABLE_RELEASE_ASSETS").MustBool(false)
+	Mirror.ReleaseAssetMaxSize = sec.Key("RELEASE_ASSET_MAX_SIZE").MustInt64(100) * 1024 * 1024
+	Mirror.ReleaseAssetsMaxTotalSize = sec.Key("RELEASE_ASSETS_MAX_TOTAL_SIZE").MustInt64(1024) * 1024 * 1024
 
 	Langs = Cfg.Section("i18n").Key("LANGS").Strings(",")
 	if len(Langs) == 0 {
@@ -923,6 +936,7 @@ func NewContext() {
 	}
 
 	newMarkup()
+	newReplication()
 
 	sec = Cfg.Section("U2F")
 	U2F.TrustedFacets, _ = shellquote.Split(sec.Key("TRUSTED_FACETS").MustString(strings.TrimSuffix(AppURL, AppSubURL+"/")))
@@ -1057,4 +1071,9 @@ func NewServices() {
 	newTaskService()
 	NewQueueService()
 	newProject()
+	newModerationService()
+	newTranslationService()
+	newEventArchiveService()
+	newFeatureFlagsService()
+	newIssueSyncService()
 }
@@ -0,0 +1,55 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"net/url"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Translation settings
+var (
+	Translation = struct {
+		// Enabled allows users to translate issues and comments with a machine translation service
+		Enabled bool
+		// Type is the type of the machine translation service, "generic" or "libretranslate"
+		Type   string
+		URL    string `ini:"URL"`
+		APIKey string `ini:"API_KEY"`
+		// Timeout of the requests to the machine translation service
+		Timeout time.Duration
+		// MaxLength is the maximum length of the content which can be translated
+		MaxLength int
+	}{
+		Enabled:   false,
+		Type:      "generic",
+		Timeout:   30 * time.Second,
+		MaxLength: 20000,
+	}
+)
+
+func newTranslationService() {
+	sec := Cfg.Section("translation")
+	if err := sec.MapTo(&Translation); err != nil {
+		log.Fatal("Failed to map Translation settings: %v", err)
+	}
+	if !Translation.Enabled {
+		return
+	}
+
+	switch Translation.Type {
+	case "generic", "libretranslate":
+	default:
+		log.Fatal("Unknown TYPE in [translation]: %s", Translation.Type)
+	}
+	if len(Translation.URL) == 0 {
+		log.Fatal("URL in [translation] is required when the translation service is enabled")
+	} else if _, err := url.Parse(Translation.URL); err != nil {
+		log.Fatal("Invalid URL in [translation] '%s': %v", Translation.URL, err)
+	}
+	log.Info("Machine translation of issues and comments enabled with %s service", Translation.Type)
+}
@@ -109,6 +109,9 @@ var (
 	Avatars ObjectStorage
 	// RepoAvatars represents repository avatars storage
 	RepoAvatars ObjectStorage
+
+	// EventArchive represents the storage of the archived repository events
+	EventArchive ObjectStorage
 )
 
 // Init init the stoarge
@@ -125,6 +128,10 @@ func Init() error {
 		return err
 	}
 
+	if err := initEventArchive(); err != nil {
+		return err
+	}
+
 	return initLFS()
 }
 
@@ -164,3 +171,12 @@ func initRepoAvatars() (err error) {
 	RepoAvatars, err = NewStorage(setting.RepoAvatar.Storage.Type, &setting.RepoAvatar.Storage)
 	return
 }
+
+func initEventArchive() (err error) {
+	if !setting.EventArchive.Enabled {
+		return nil
+	}
+	log.Info("Initialising Event Archive storage with type: %s", setting.EventArchive.Storage.Type)
+	EventArchive, err = NewStorage(setting.EventArchive.Storage.Type, &setting.EventArchive.Storage)
+	return
+}
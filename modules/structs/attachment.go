@@ -21,6 +21,51 @@ type Attachment struct {
 	DownloadURL string    `json:"browser_download_url"`
 }
 
+// RepoAttachment an attachment of an issue, a comment or a release of a repository
+// swagger:model
+type RepoAttachment struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Size          int64  `json:"size"`
+	DownloadCount int64  `json:"download_count"`
+	// swagger:strfmt date-time
+	Created     time.Time `json:"created_at"`
+	UUID        string    `json:"uuid"`
+	DownloadURL string    `json:"browser_download_url"`
+	Uploader    *User     `json:"uploader"`
+	// number of the issue or pull request the attachment belongs to, 0 if none
+	IssueNumber int64 `json:"issue_number"`
+	// id of the comment the attachment belongs to, 0 if it is attached to the issue itself
+	CommentID int64 `json:"comment_id"`
+	// id of the release the attachment belongs to, 0 if none
+	ReleaseID int64 `json:"release_id"`
+	// whether the attachment belongs to no issue, comment or release
+	Orphaned bool `json:"orphaned"`
+}
+
+// CreateSignedURLOption options for creating a signed download URL of an attachment
+// swagger:model
+type CreateSignedURLOption struct {
+	// minutes the URL is valid, defaults to and is limited by the server settings
+	ExpiresIn int `json:"expires_in"`
+}
+
+// SignedURL a download URL of an attachment which can be used without signing in until it expires
+// swagger:model
+type SignedURL struct {
+	URL string `json:"url"`
+	// swagger:strfmt date-time
+	Expires time.Time `json:"expires_at"`
+}
+
+// DeleteAttachmentsOption options for deleting attachments of a repository
+// swagger:model
+type DeleteAttachmentsOption struct {
+	// ids of the attachments to delete
+	// required: true
+	IDs []int64 `json:"ids" binding:"Required"`
+}
+
 // EditAttachmentOptions options for editing attachments
 // swagger:model
 type EditAttachmentOptions struct {
@@ -0,0 +1,47 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// FeatureFlag represents a feature flag of the instance
+type FeatureFlag struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// whether the flag is switched on, a disabled flag is off for everyone
+	Enabled bool `json:"enabled"`
+	// the percentage of all users the flag is on for
+	Percentage int `json:"percentage"`
+	// the names of the users and organizations the flag is on for
+	Targets []string `json:"targets"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateFeatureFlagOption options for creating a feature flag
+type CreateFeatureFlagOption struct {
+	// required: true
+	Name        string `json:"name" binding:"Required;MaxSize(50)"`
+	Description string `json:"description" binding:"MaxSize(255)"`
+	Enabled     bool   `json:"enabled"`
+	Percentage  int    `json:"percentage" binding:"Range(0,100)"`
+	// the names of the users and organizations the flag is on for
+	Targets []string `json:"targets"`
+}
+
+// EditFeatureFlagOption options for editing a feature flag
+type EditFeatureFlagOption struct {
+	Name        *string `json:"name" binding:"OmitEmpty;MaxSize(50)"`
+	Description *string `json:"description" binding:"MaxSize(255)"`
+	Enabled     *bool   `json:"enabled"`
+	Percentage  *int    `json:"percentage"`
+	// the names of the users and organizations the flag is on for
+	Targets *[]string `json:"targets"`
+}
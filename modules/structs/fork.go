@@ -9,3 +9,19 @@ type CreateForkOption struct {
 	// organization name, if forking into an organization
 	Organization *string `json:"organization"`
 }
+
+// ForkNetworkRepository represents a repository of a fork network
+type ForkNetworkRepository struct {
+	Repository *Repository `json:"repository"`
+	// id of the repository the repository was forked from, 0 for the root of the network
+	ParentID int64 `json:"parent_id"`
+	// number of forks between the root of the network and the repository
+	Depth int `json:"depth"`
+}
+
+// ReRootForkNetworkOption options for replacing a repository in its fork network by one of its forks
+type ReRootForkNetworkOption struct {
+	// id of the fork replacing the repository
+	// required: true
+	NewRootID int64 `json:"new_root_id" binding:"Required"`
+}
@@ -18,3 +18,20 @@ type GitHookList []*GitHook
 type EditGitHookOption struct {
 	Content string `json:"content"`
 }
+
+// GitHookScript represents one script of a Git repository hook chain
+type GitHookScript struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	// managed scripts are synced from the hooks source repository and can't be edited
+	Managed bool `json:"managed"`
+}
+
+// GitHookScriptList represents a list of Git hook scripts
+type GitHookScriptList []*GitHookScript
+
+// EditGitHookScriptOption options when creating or modifying one Git hook script
+type EditGitHookScriptOption struct {
+	// required: true
+	Content string `json:"content" binding:"Required"`
+}
@@ -25,6 +25,8 @@ type Hook struct {
 	Config map[string]string `json:"config"`
 	Events []string          `json:"events"`
 	Active bool              `json:"active"`
+	// topics a repository must have one of for its events to be delivered
+	TopicFilter []string `json:"topic_filter"`
 	// swagger:strfmt date-time
 	Updated time.Time `json:"updated_at"`
 	// swagger:strfmt date-time
@@ -47,6 +49,9 @@ type CreateHookOption struct {
 	Config       CreateHookOptionConfig `json:"config" binding:"Required"`
 	Events       []string               `json:"events"`
 	BranchFilter string                 `json:"branch_filter" binding:"GlobPattern"`
+	// only deliver events of repositories having at least one of these topics,
+	// only supported for organization webhooks
+	TopicFilter []string `json:"topic_filter"`
 	// default: false
 	Active bool `json:"active"`
 }
@@ -56,7 +61,10 @@ type EditHookOption struct {
 	Config       map[string]string `json:"config"`
 	Events       []string          `json:"events"`
 	BranchFilter string            `json:"branch_filter" binding:"GlobPattern"`
-	Active       *bool             `json:"active"`
+	// only deliver events of repositories having at least one of these topics,
+	// only supported for organization webhooks. Left unchanged if omitted.
+	TopicFilter []string `json:"topic_filter"`
+	Active      *bool    `json:"active"`
 }
 
 // Payloader payload is some part of one hook
@@ -35,6 +35,18 @@ type RepositoryMeta struct {
 	FullName string `json:"full_name"`
 }
 
+// IssueReferenceSuggestion represents an issue or pull request which can be referenced from a repository
+type IssueReferenceSuggestion struct {
+	Index   int64     `json:"number"`
+	Title   string    `json:"title"`
+	State   StateType `json:"state"`
+	IsPull  bool      `json:"is_pull"`
+	HTMLURL string    `json:"html_url"`
+	// reference to the issue from the repository, e.g. "#12" or "owner/repo#12"
+	Ref  string          `json:"ref"`
+	Repo *RepositoryMeta `json:"repository"`
+}
+
 // Issue represents an issue in a repository
 // swagger:model
 type Issue struct {
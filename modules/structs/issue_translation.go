@@ -0,0 +1,20 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// ContentTranslation is the machine translation of the body of an issue or a comment
+type ContentTranslation struct {
+	// Language the body was translated to
+	Language string `json:"language"`
+	// SourceLanguage is the language of the body detected by the translation service
+	SourceLanguage string `json:"source_language"`
+	Body           string `json:"body"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
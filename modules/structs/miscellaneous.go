@@ -50,3 +50,11 @@ type APIError struct {
 	Message string `json:"message"`
 	URL     string `json:"url"`
 }
+
+// APIFieldValidationError is an api error with the errors of the invalid fields of the input
+type APIFieldValidationError struct {
+	Message string `json:"message"`
+	// errors of the invalid fields keyed by their JSON names
+	Errors map[string]string `json:"errors"`
+	URL    string            `json:"url"`
+}
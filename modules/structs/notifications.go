@@ -32,3 +32,33 @@ type NotificationSubject struct {
 type NotificationCount struct {
 	New int64 `json:"new"`
 }
+
+// NotificationRule decides how notifications about the repositories matching its pattern are delivered
+type NotificationRule struct {
+	ID int64 `json:"id"`
+	// glob matched against the full name of the repository, empty matches all repositories
+	RepoPattern string `json:"repo_pattern"`
+	// one of issue, pull_request, release or mention, empty matches all events
+	Event string `json:"event"`
+	// how matched notifications are delivered, one of email, web or none
+	Action string `json:"action"`
+	// rules with a higher priority are evaluated first
+	Priority int `json:"priority"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateNotificationRuleOption options for creating a notification rule
+type CreateNotificationRuleOption struct {
+	// glob matched against the full name of the repository, empty matches all repositories
+	RepoPattern string `json:"repo_pattern" binding:"MaxSize(255)"`
+	// one of issue, pull_request, release or mention, empty matches all events
+	Event string `json:"event"`
+	// required: true
+	// enum: email,web,none
+	Action string `json:"action" binding:"Required"`
+	// rules with a higher priority are evaluated first
+	Priority int `json:"priority"`
+}
@@ -15,6 +15,10 @@ type Organization struct {
 	Location                  string `json:"location"`
 	Visibility                string `json:"visibility"`
 	RepoAdminChangeTeamAccess bool   `json:"repo_admin_change_team_access"`
+	// whether members are required to enable two-factor authentication
+	RequireTwoFactor bool `json:"require_two_factor"`
+	// number of days members have to enable two-factor authentication after it was required
+	TwoFactorGraceDays int `json:"two_factor_grace_days"`
 }
 
 // CreateOrgOption options for creating an organization
@@ -41,4 +45,8 @@ type EditOrgOption struct {
 	// enum: public,limited,private
 	Visibility                string `json:"visibility" binding:"In(,public,limited,private)"`
 	RepoAdminChangeTeamAccess bool   `json:"repo_admin_change_team_access"`
+	// require members to enable two-factor authentication, the user enabling it must have enabled it
+	RequireTwoFactor *bool `json:"require_two_factor"`
+	// number of days members have to enable two-factor authentication before they lose access
+	TwoFactorGraceDays *int `json:"two_factor_grace_days"`
 }
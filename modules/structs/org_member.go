@@ -4,6 +4,29 @@
 
 package structs
 
+import (
+	"time"
+)
+
+// OrgMemberTwoFactorStatus represents the two-factor authentication status of an organization member
+type OrgMemberTwoFactorStatus struct {
+	User             *User `json:"user"`
+	IsOwner          bool  `json:"is_owner"`
+	TwoFactorEnabled bool  `json:"two_factor_enabled"`
+}
+
+// OutsideCollaborator represents the access of a user who is not a member of an organization
+// to one of its repositories
+type OutsideCollaborator struct {
+	User       *User  `json:"user"`
+	Repository string `json:"repository"`
+	Permission string `json:"permission"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
 // AddOrgMembershipOption add user to organization options
 type AddOrgMembershipOption struct {
 	Role string `json:"role" binding:"Required"`
@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// TokenUsage represents the API calls made with an access token to the resources of an organization
+type TokenUsage struct {
+	// owner of the access token
+	User *User `json:"user"`
+	// id of the access token, 0 if it has been deleted
+	TokenID        int64  `json:"token_id"`
+	TokenName      string `json:"token_name"`
+	TokenLastEight string `json:"token_last_eight"`
+	// class of the called endpoints, e.g. repos/issues or orgs/teams
+	Endpoint string `json:"endpoint"`
+	// read or write
+	Access string `json:"access"`
+	IP     string `json:"ip"`
+	Count  int64  `json:"count"`
+	// swagger:strfmt date-time
+	FirstUsed time.Time `json:"first_used_at"`
+	// swagger:strfmt date-time
+	LastUsed time.Time `json:"last_used_at"`
+}
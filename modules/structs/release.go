@@ -49,3 +49,33 @@ type EditReleaseOption struct {
 	IsDraft      *bool  `json:"draft"`
 	IsPrerelease *bool  `json:"prerelease"`
 }
+
+// ReleaseChannel represents a group of releases whose tags match a pattern
+type ReleaseChannel struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	TagPattern string `json:"tag_pattern"`
+	// number of releases the channel keeps, older releases are deleted with their tags; 0 keeps all releases
+	KeepLast int `json:"keep_last"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateReleaseChannelOption options when creating a release channel
+type CreateReleaseChannelOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required"`
+	// glob pattern of the tags of the releases in the channel
+	// required: true
+	TagPattern string `json:"tag_pattern" binding:"Required"`
+	KeepLast   int    `json:"keep_last"`
+}
+
+// EditReleaseChannelOption options when editing a release channel
+type EditReleaseChannelOption struct {
+	Name       *string `json:"name"`
+	TagPattern *string `json:"tag_pattern"`
+	KeepLast   *int    `json:"keep_last"`
+}
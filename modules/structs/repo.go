@@ -25,6 +25,16 @@ type InternalTracker struct {
 	AllowOnlyContributorsToTrackTime bool `json:"allow_only_contributors_to_track_time"`
 	// Enable dependencies for issues and pull requests (Built-in issue tracker)
 	EnableIssueDependencies bool `json:"enable_issue_dependencies"`
+	// Disable closing and reopening issues with keywords (Built-in issue tracker)
+	DisableCloseKeywords bool `json:"disable_close_keywords"`
+	// Keywords closing issues, empty for the keywords of the instance (Built-in issue tracker)
+	CloseKeywords []string `json:"close_keywords"`
+	// Keywords reopening issues, empty for the keywords of the instance (Built-in issue tracker)
+	ReopenKeywords []string `json:"reopen_keywords"`
+	// Events closing and reopening issues: empty for pushes and merged pull requests,
+	// `push` for pushes only or `merge` for merged pull requests only (Built-in issue tracker)
+	// enum: ,push,merge
+	CloseTrigger string `json:"close_trigger"`
 }
 
 // ExternalTracker represents settings for external tracker
@@ -72,6 +82,13 @@ type Repository struct {
 	Releases      int         `json:"release_counter"`
 	DefaultBranch string      `json:"default_branch"`
 	Archived      bool        `json:"archived"`
+	// SPDX identifier of the license detected from the license file of the default branch,
+	// `NOASSERTION` if the license file was not recognized
+	License string `json:"license"`
+	// SPDX license expressions tagged in the file headers of the default branch
+	FileLicenses []string `json:"file_licenses"`
+	// number of days after which orphaned attachments are deleted, 0 if they are kept
+	AttachmentRetentionDays int `json:"attachment_retention_days"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -91,6 +108,9 @@ type Repository struct {
 	AllowSquash               bool             `json:"allow_squash_merge"`
 	AvatarURL                 string           `json:"avatar_url"`
 	Internal                  bool             `json:"internal"`
+	// trust model used to verify the signatures of the commits
+	// enum: default,collaborator,committer,collaboratorcommitter
+	TrustModel string `json:"trust_model"`
 }
 
 // CreateRepoOption options when creating repository
@@ -122,9 +142,13 @@ type CreateRepoOption struct {
 	// TrustModel of the repository
 	// enum: default,collaborator,committer,collaboratorcommitter
 	TrustModel string `json:"trust_model"`
+	// ID of a preset of the organization to apply to the repository
+	PresetID int64 `json:"preset_id"`
 }
 
-// EditRepoOption options when editing a repository's properties
+// EditRepoOption options when editing a repository's properties.
+// It covers the options of the settings page of the repository, branch protections and collaborators
+// have their own endpoints. Nothing is changed unless all fields are valid.
 // swagger:model
 type EditRepoOption struct {
 	// name of the repository
@@ -168,6 +192,21 @@ type EditRepoOption struct {
 	AllowSquash *bool `json:"allow_squash_merge,omitempty"`
 	// set to `true` to archive this repository.
 	Archived *bool `json:"archived,omitempty"`
+	// set to the number of days after which orphaned attachments are deleted, or 0 to keep them.
+	AttachmentRetentionDays *int `json:"attachment_retention_days,omitempty"`
+	// trust model used to verify the signatures of the commits.
+	// enum: default,collaborator,committer,collaboratorcommitter
+	TrustModel *string `json:"trust_model,omitempty"`
+	// set the interval between the updates of a mirror, e.g. `8h0m0s`, or `0` to disable periodic updates. Only for mirrors.
+	MirrorInterval *string `json:"mirror_interval,omitempty"`
+	// either `true` to delete the branches and tags deleted upstream when updating a mirror, or `false` to keep them. Only for mirrors.
+	MirrorEnablePrune *bool `json:"mirror_enable_prune,omitempty"`
+	// either `true` to mirror the releases and release assets of the upstream repository, or `false` to not mirror them. Only for mirrors.
+	MirrorReleaseAssets *bool `json:"mirror_release_assets,omitempty"`
+	// either `true` to enable the periodic health check of the repository, or `false` to disable it. Only site administrators can change it.
+	EnableHealthCheck *bool `json:"enable_health_check,omitempty"`
+	// either `true` to close issues by commits pushed to any branch, or `false` to only close them by commits pushed to the default branch. Only site administrators can change it.
+	CloseIssuesViaCommitInAnyBranch *bool `json:"close_issues_via_commit_in_any_branch,omitempty"`
 }
 
 // CreateBranchRepoOption options when creating a branch in a repository
@@ -278,3 +317,14 @@ var (
 		GiteaService,
 	}
 )
+
+// RepoArchiveStatus represents the status of the generation of a repository archive
+type RepoArchiveStatus struct {
+	// the state of the generation, one of queued, running, complete, failed or cancelled
+	Status   string `json:"status"`
+	Complete bool   `json:"complete"`
+	// number of bytes of the archive written so far
+	Size int64 `json:"size"`
+	// number of archives queued ahead of this one, -1 if it is not queued
+	QueuePosition int `json:"queue_position"`
+}
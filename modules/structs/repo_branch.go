@@ -44,6 +44,7 @@ type BranchProtection struct {
 	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
 	RequireSignedCommits          bool     `json:"require_signed_commits"`
 	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
+	PushToPullRequest             string   `json:"push_to_pull_request"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -73,6 +74,8 @@ type CreateBranchProtectionOption struct {
 	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
 	RequireSignedCommits          bool     `json:"require_signed_commits"`
 	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
+	// enum: disabled,option,always
+	PushToPullRequest string `json:"push_to_pull_request"`
 }
 
 // EditBranchProtectionOption options for editing a branch protection
@@ -97,4 +100,21 @@ type EditBranchProtectionOption struct {
 	DismissStaleApprovals         *bool    `json:"dismiss_stale_approvals"`
 	RequireSignedCommits          *bool    `json:"require_signed_commits"`
 	ProtectedFilePatterns         *string  `json:"protected_file_patterns"`
+	// enum: disabled,option,always
+	PushToPullRequest *string `json:"push_to_pull_request"`
+}
+
+// StatusCheckContextsOption options for registering or unregistering required status check contexts of a branch protection
+type StatusCheckContextsOption struct {
+	Contexts []string `json:"contexts" binding:"Required"`
+}
+
+// StatusCheckContextChange represents the registration or removal of a required status check context of a branch protection
+type StatusCheckContextChange struct {
+	Context string `json:"context"`
+	// enum: registered,unregistered
+	Action string `json:"action"`
+	Doer   *User  `json:"doer"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
 }
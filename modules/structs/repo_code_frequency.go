@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// DirectoryCodeFrequency represents the lines added and deleted in a top level directory of a repository
+type DirectoryCodeFrequency struct {
+	// top level directory, empty for the files in the root of the repository
+	Directory string `json:"directory"`
+	Additions int64  `json:"additions"`
+	Deletions int64  `json:"deletions"`
+	Commits   int64  `json:"commits"`
+	// weeks with changes in the directory, the oldest first
+	Weeks []*CodeFrequencyWeek `json:"weeks"`
+}
+
+// CodeFrequencyWeek represents the lines added and deleted in a top level directory during a week
+type CodeFrequencyWeek struct {
+	// swagger:strfmt date-time
+	Week      time.Time `json:"week"`
+	Additions int64     `json:"additions"`
+	Deletions int64     `json:"deletions"`
+	Commits   int64     `json:"commits"`
+}
@@ -4,7 +4,14 @@
 
 package structs
 
+import (
+	"time"
+)
+
 // AddCollaboratorOption options when adding a user as a collaborator of a repository
 type AddCollaboratorOption struct {
 	Permission *string `json:"permission"`
+	// date at which the collaborator is removed from the repository, keeps the current expiry if not set
+	// swagger:strfmt date-time
+	ExpiresAt *time.Time `json:"expires_at"`
 }
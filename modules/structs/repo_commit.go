@@ -37,6 +37,14 @@ type RepoCommit struct {
 	Committer *CommitUser `json:"committer"`
 	Message   string      `json:"message"`
 	Tree      *CommitMeta `json:"tree"`
+	// trailers of the commit message, e.g. Co-authored-by, Reviewed-by or Fixes
+	Trailers []*CommitTrailer `json:"trailers"`
+}
+
+// CommitTrailer contains a trailer of a commit message like "Reviewed-by: A U Thor <author@example.com>"
+type CommitTrailer struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 // Commit contains information generated from a Git commit.
@@ -56,3 +64,17 @@ type CommitDateOptions struct {
 	// swagger:strfmt date-time
 	Committer time.Time `json:"committer"`
 }
+
+// Compare represents the comparison of two branches, tags or commits
+type Compare struct {
+	// the commits of the head which are not part of the base
+	TotalCommits int       `json:"total_commits"`
+	Commits      []*Commit `json:"commits"`
+	// the number of files changed
+	NumFiles  int    `json:"num_files"`
+	MergeBase string `json:"merge_base"`
+	// the commit the base resolves to
+	BaseCommit string `json:"base_commit"`
+	// whether the head is compared with the base itself (base..head) instead of the merge base (base...head)
+	DirectComparison bool `json:"direct_comparison"`
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Types of repository events
+const (
+	RepoEventIssueOpened         = "issue.opened"
+	RepoEventIssueClosed         = "issue.closed"
+	RepoEventIssueReopened       = "issue.reopened"
+	RepoEventIssueUpdated        = "issue.updated"
+	RepoEventIssueCommentCreated = "issue_comment.created"
+	RepoEventIssueCommentUpdated = "issue_comment.updated"
+	RepoEventIssueCommentDeleted = "issue_comment.deleted"
+	RepoEventPullRequestMerged   = "pull_request.merged"
+	RepoEventPullRequestSynced   = "pull_request.synchronized"
+	RepoEventPullRequestReviewed = "pull_request.reviewed"
+	RepoEventPush                = "push"
+	RepoEventCommitStatusCreated = "commit_status.created"
+	RepoEventReleasePublished    = "release.published"
+	RepoEventReleaseUpdated      = "release.updated"
+	RepoEventReleaseDeleted      = "release.deleted"
+)
+
+// RepoEvent is an event of a repository streamed to the clients listening for the events of the repository.
+// It only identifies what changed, clients fetch the changed objects from the API.
+// swagger:model
+type RepoEvent struct {
+	// type of the event, also used as the name of the server-sent event
+	Type   string `json:"type"`
+	RepoID int64  `json:"repo_id"`
+	// id of the user who caused the event
+	ActorID int64 `json:"actor_id"`
+	// index of the issue or pull request the event is about
+	IssueIndex int64 `json:"issue_index,omitempty"`
+	// whether the event is about a pull request
+	IsPull    bool   `json:"is_pull,omitempty"`
+	CommentID int64  `json:"comment_id,omitempty"`
+	ReleaseID int64  `json:"release_id,omitempty"`
+	Ref       string `json:"ref,omitempty"`
+	SHA       string `json:"sha,omitempty"`
+	// state of the commit status
+	State   string `json:"state,omitempty"`
+	Context string `json:"context,omitempty"`
+}
@@ -74,10 +74,22 @@ type ContentsResponse struct {
 	GitURL      *string `json:"git_url"`
 	DownloadURL *string `json:"download_url"`
 	// `submodule_git_url` is populated when `type` is `submodule`, otherwise null
-	SubmoduleGitURL *string            `json:"submodule_git_url"`
+	SubmoduleGitURL *string `json:"submodule_git_url"`
+	// `submodule_target` is populated when `type` is `submodule` and it points to a repository
+	// on this instance which can be read, otherwise null
+	SubmoduleTarget *SubmoduleTarget   `json:"submodule_target"`
 	Links           *FileLinksResponse `json:"_links"`
 }
 
+// SubmoduleTarget contains the repository and commit a submodule points to
+type SubmoduleTarget struct {
+	FullName string `json:"full_name"`
+	SHA      string `json:"sha"`
+	// url of the contents of the repository at the commit
+	URL     string `json:"url"`
+	HTMLURL string `json:"html_url"`
+}
+
 // FileCommitResponse contains information generated from a Git commit for a repo's file.
 type FileCommitResponse struct {
 	CommitMeta
@@ -102,3 +114,24 @@ type FileDeleteResponse struct {
 	Commit       *FileCommitResponse        `json:"commit"`
 	Verification *PayloadCommitVerification `json:"verification"`
 }
+
+// RenderedFileResponse contains a markup file of a repo rendered to HTML
+type RenderedFileResponse struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	SHA  string `json:"sha"`
+	// markup language of the file, e.g. `markdown` or `orgmode`
+	Type string `json:"type"`
+	HTML string `json:"html"`
+	// table of contents, only populated for markdown files
+	TOC []*MarkupHeading `json:"toc"`
+}
+
+// MarkupHeading represents a heading in the table of contents of a rendered markup file
+type MarkupHeading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	// id of the heading anchor in the rendered HTML
+	ID       string           `json:"id"`
+	Children []*MarkupHeading `json:"children"`
+}
@@ -0,0 +1,29 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// CreateGitCredentialOption options for creating a short-lived git credential
+// swagger:model
+type CreateGitCredentialOption struct {
+	// git operation the credential allows, `write` also allows to read
+	// enum: read,write
+	Operation string `json:"operation" binding:"In(,read,write)"`
+	// minutes the credential is valid, defaults to and is limited by the server settings
+	ExpiresIn int `json:"expires_in"`
+}
+
+// GitCredential a username and password which allow a git operation over HTTP on a repository until they expire
+// swagger:model
+type GitCredential struct {
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	Operation string `json:"operation"`
+	// swagger:strfmt date-time
+	Expires time.Time `json:"expires_at"`
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// RepoPresetWebhook represents a webhook created in the repositories created from a preset
+type RepoPresetWebhook struct {
+	URL string `json:"url"`
+	// enum: json,form
+	ContentType string `json:"content_type"`
+	// whether the webhook is triggered by all events instead of only by pushes
+	SendEverything bool `json:"send_everything"`
+}
+
+// RepoPreset represents a set of settings of an organization applied to new repositories
+type RepoPreset struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// the team whose members may use the preset, 0 if every member who can create repositories may use it
+	TeamID int64 `json:"team_id"`
+	// the units enabled in the repositories, all default units if empty
+	// example: ["repo.code","repo.issues","repo.pulls"]
+	Units []string `json:"units"`
+	// the issue label set initialized in the repositories
+	IssueLabels string   `json:"issue_labels"`
+	Topics      []string `json:"topics"`
+	// whether the default branch of the repositories is protected
+	ProtectDefaultBranch bool                 `json:"protect_default_branch"`
+	RequiredApprovals    int64                `json:"required_approvals"`
+	StatusCheckContexts  []string             `json:"status_check_contexts"`
+	Webhooks             []*RepoPresetWebhook `json:"webhooks"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateRepoPresetWebhookOption options for a webhook of a repository preset
+type CreateRepoPresetWebhookOption struct {
+	// required: true
+	URL string `json:"url" binding:"Required"`
+	// enum: json,form
+	ContentType    string `json:"content_type"`
+	Secret         string `json:"secret"`
+	SendEverything bool   `json:"send_everything"`
+}
+
+// CreateRepoPresetOption options for creating a repository preset
+type CreateRepoPresetOption struct {
+	// required: true
+	Name        string `json:"name" binding:"Required;MaxSize(50)"`
+	Description string `json:"description" binding:"MaxSize(255)"`
+	TeamID      int64  `json:"team_id"`
+	// example: ["repo.code","repo.issues","repo.pulls"]
+	Units                []string                         `json:"units"`
+	IssueLabels          string                           `json:"issue_labels"`
+	Topics               []string                         `json:"topics"`
+	ProtectDefaultBranch bool                             `json:"protect_default_branch"`
+	RequiredApprovals    int64                            `json:"required_approvals"`
+	StatusCheckContexts  []string                         `json:"status_check_contexts"`
+	Webhooks             []*CreateRepoPresetWebhookOption `json:"webhooks"`
+}
+
+// EditRepoPresetOption options for editing a repository preset
+type EditRepoPresetOption struct {
+	Name        *string `json:"name" binding:"OmitEmpty;MaxSize(50)"`
+	Description *string `json:"description" binding:"MaxSize(255)"`
+	TeamID      *int64  `json:"team_id"`
+	// example: ["repo.code","repo.issues","repo.pulls"]
+	Units                *[]string                         `json:"units"`
+	IssueLabels          *string                           `json:"issue_labels"`
+	Topics               *[]string                         `json:"topics"`
+	ProtectDefaultBranch *bool                             `json:"protect_default_branch"`
+	RequiredApprovals    *int64                            `json:"required_approvals"`
+	StatusCheckContexts  *[]string                         `json:"status_check_contexts"`
+	Webhooks             *[]*CreateRepoPresetWebhookOption `json:"webhooks"`
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// ProjectAnalytics represents the time issues spend on the boards of a project, durations are in seconds
+type ProjectAnalytics struct {
+	Boards []*ProjectBoardAnalytics `json:"boards"`
+	// number of closed issues on the project with a lead time
+	LeadTimeCount int64 `json:"lead_time_count"`
+	// time from adding an issue to the project until it is closed
+	LeadTimeAverage int64 `json:"lead_time_average"`
+	LeadTimeMedian  int64 `json:"lead_time_median"`
+	// number of closed issues on the project with a cycle time
+	CycleTimeCount int64 `json:"cycle_time_count"`
+	// time from the first move of an issue between boards until it is closed
+	CycleTimeAverage int64 `json:"cycle_time_average"`
+	CycleTimeMedian  int64 `json:"cycle_time_median"`
+}
+
+// ProjectBoardAnalytics represents how long issues stay on a board of a project, durations are in seconds
+type ProjectBoardAnalytics struct {
+	// board id, 0 for the uncategorized board
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// number of issues currently on the board
+	Issues int64 `json:"issues"`
+	// number of times an issue entered the board
+	Stays           int64 `json:"stays"`
+	DurationAverage int64 `json:"duration_average"`
+	DurationMedian  int64 `json:"duration_median"`
+}
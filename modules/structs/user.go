@@ -32,6 +32,19 @@ type User struct {
 	Created time.Time `json:"created,omitempty"`
 }
 
+// DormantUser represents a user flagged as dormant or deactivated because of it
+type DormantUser struct {
+	User *User `json:"user"`
+	// when the user was notified that the account is dormant
+	// swagger:strfmt date-time
+	Flagged *time.Time `json:"flagged_at"`
+	// when the account will be or was deactivated
+	// swagger:strfmt date-time
+	Deadline *time.Time `json:"deadline"`
+	// whether the account has been deactivated
+	Deactivated bool `json:"deactivated"`
+}
+
 // MarshalJSON implements the json.Marshaler interface for User, adding field(s) for backward compatibility
 func (u User) MarshalJSON() ([]byte, error) {
 	// Re-declaring User to avoid recursion
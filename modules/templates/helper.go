@@ -27,6 +27,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/emoji"
+	"code.gitea.io/gitea/modules/featureflag"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/repository"
@@ -79,6 +80,16 @@ func NewFuncMap() []template.FuncMap {
 		"DefaultShowFullName": func() bool {
 			return setting.UI.DefaultShowFullName
 		},
+		"EnableAbuseReports": func() bool {
+			return setting.Moderation.Enabled
+		},
+		"EnableTranslation": func() bool {
+			return setting.Translation.Enabled
+		},
+		"EnableIssueSync": func() bool {
+			return setting.IssueSync.Enabled
+		},
+		"FeatureEnabled": featureflag.IsEnabled,
 		"ShowFooterTemplateLoadTime": func() bool {
 			return setting.ShowFooterTemplateLoadTime
 		},
@@ -253,6 +264,7 @@ func NewFuncMap() []template.FuncMap {
 		"ParseDeadline": func(deadline string) []string {
 			return strings.Split(deadline, "|")
 		},
+		"Join": strings.Join,
 		"DefaultTheme": func() string {
 			return setting.UI.DefaultTheme
 		},
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/eventarchive"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -52,6 +53,17 @@ func PrepareWebhook(w *models.Webhook, repo *models.Repository, event models.Hoo
 	return nil
 }
 
+// PrepareTestWebhook adds a test delivery of the payload to the task queue of the webhook,
+// regardless of the events the webhook is subscribed to and its branch filter.
+func PrepareTestWebhook(w *models.Webhook, repo *models.Repository, event models.HookEventType, p api.Payloader) error {
+	if err := createHookTask(w, repo, event, p); err != nil {
+		return err
+	}
+
+	go hookQueue.Add(repo.ID)
+	return nil
+}
+
 func checkBranch(w *models.Webhook, branch string) bool {
 	if w.BranchFilter == "" || w.BranchFilter == "*" {
 		return true
@@ -67,6 +79,24 @@ func checkBranch(w *models.Webhook, branch string) bool {
 	return g.Match(branch)
 }
 
+// checkTopics reports whether the repository carries at least one of the topics
+// the webhook is restricted to. Topics are read from the repository at dispatch
+// time, so topic changes take effect for the next event.
+func checkTopics(w *models.Webhook, repo *models.Repository) bool {
+	if len(w.TopicFilter) == 0 {
+		return true
+	}
+
+	for _, topic := range w.TopicFilter {
+		for _, repoTopic := range repo.Topics {
+			if strings.EqualFold(topic, repoTopic) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func prepareWebhook(w *models.Webhook, repo *models.Repository, event models.HookEventType, p api.Payloader) error {
 	for _, e := range w.EventCheckers() {
 		if event == e.Type {
@@ -76,6 +106,11 @@ func prepareWebhook(w *models.Webhook, repo *models.Repository, event models.Hoo
 		}
 	}
 
+	if !checkTopics(w, repo) {
+		log.Trace("Repository %s has none of the topics %v of webhook %d, skipping", repo.FullName(), w.TopicFilter, w.ID)
+		return nil
+	}
+
 	// Avoid sending "0 new commits" to non-integration relevant webhooks (e.g. slack, discord, etc.).
 	// Integration webhooks (e.g. drone) still receive the required data.
 	if pushEvent, ok := p.(*api.PushPayload); ok &&
@@ -93,6 +128,10 @@ func prepareWebhook(w *models.Webhook, repo *models.Repository, event models.Hoo
 		}
 	}
 
+	return createHookTask(w, repo, event, p)
+}
+
+func createHookTask(w *models.Webhook, repo *models.Repository, event models.HookEventType, p api.Payloader) error {
 	var payloader api.Payloader
 	var err error
 	// Use separate objects so modifications won't be made on payload on non-Gogs/Gitea type hooks.
@@ -179,6 +218,10 @@ func PrepareWebhooks(repo *models.Repository, event models.HookEventType, p api.
 }
 
 func prepareWebhooks(repo *models.Repository, event models.HookEventType, p api.Payloader) error {
+	if err := eventarchive.Archive(repo, event, p); err != nil {
+		log.Error("Archive: %v", err)
+	}
+
 	ws, err := models.GetActiveWebhooksByRepoID(repo.ID)
 	if err != nil {
 		return fmt.Errorf("GetActiveWebhooksByRepoID: %v", err)
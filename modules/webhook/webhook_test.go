@@ -74,6 +74,28 @@ func TestPrepareWebhooksBranchFilterNoMatch(t *testing.T) {
 	}
 }
 
+func TestPrepareWebhooksTopicFilter(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	w := models.AssertExistsAndLoadBean(t, &models.Webhook{ID: 3}).(*models.Webhook)
+	w.SendEverything = true
+	w.TopicFilter = []string{"backend", "service"}
+	assert.NoError(t, w.UpdateEvent())
+	assert.NoError(t, models.UpdateWebhook(w))
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+	hookTask := &models.HookTask{RepoID: repo.ID, HookID: w.ID, EventType: models.HookEventPush}
+	payload := &api.PushPayload{Ref: "refs/heads/master", Commits: []*api.PayloadCommit{{}}}
+
+	repo.Topics = []string{"frontend"}
+	assert.NoError(t, PrepareWebhooks(repo, models.HookEventPush, payload))
+	models.AssertNotExistsBean(t, hookTask)
+
+	repo.Topics = []string{"frontend", "Service"}
+	assert.NoError(t, PrepareWebhooks(repo, models.HookEventPush, payload))
+	models.AssertExistsAndLoadBean(t, hookTask)
+}
+
 // TODO TestHookTask_deliver
 
 // TODO TestDeliverHooks
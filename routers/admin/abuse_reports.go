@@ -0,0 +1,131 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/moderation"
+)
+
+const (
+	tplAbuseReports    base.TplName = "admin/abuse_report/list"
+	tplAbuseReportView base.TplName = "admin/abuse_report/view"
+)
+
+// AbuseReports show the moderation queue of abuse reports
+func AbuseReports(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.abuse_reports")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminAbuseReports"] = true
+
+	status := models.AbuseReportStatusOpen
+	if s := ctx.Query("status"); s != "" {
+		if status = models.ParseAbuseReportStatus(s); status < 0 {
+			status = models.AbuseReportStatusOpen
+		}
+	}
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	reports, total, err := models.FindAbuseReports(models.FindAbuseReportsOptions{
+		ListOptions: models.ListOptions{
+			Page:     page,
+			PageSize: setting.UI.Admin.UserPagingNum,
+		},
+		Status: status,
+	})
+	if err != nil {
+		ctx.ServerError("FindAbuseReports", err)
+		return
+	}
+	for _, r := range reports {
+		if err := r.LoadAttributes(); err != nil {
+			ctx.ServerError("LoadAttributes", err)
+			return
+		}
+	}
+
+	ctx.Data["Reports"] = reports
+	ctx.Data["Total"] = total
+	ctx.Data["Status"] = status.Name()
+
+	pager := context.NewPagination(int(total), setting.UI.Admin.UserPagingNum, page, 5)
+	pager.AddParamString("status", status.Name())
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplAbuseReports)
+}
+
+func getAbuseReport(ctx *context.Context) *models.AbuseReport {
+	r, err := models.GetAbuseReportByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrAbuseReportNotExist(err) {
+			ctx.NotFound("GetAbuseReportByID", err)
+		} else {
+			ctx.ServerError("GetAbuseReportByID", err)
+		}
+		return nil
+	}
+	if err := r.LoadAttributes(); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return nil
+	}
+	return r
+}
+
+// ViewAbuseReport show an abuse report with its audit trail
+func ViewAbuseReport(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.abuse_reports")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminAbuseReports"] = true
+
+	r := getAbuseReport(ctx)
+	if ctx.Written() {
+		return
+	}
+	logs, err := r.GetLogs()
+	if err != nil {
+		ctx.ServerError("GetLogs", err)
+		return
+	}
+	others, err := r.GetOtherReportsCount()
+	if err != nil {
+		ctx.ServerError("GetOtherReportsCount", err)
+		return
+	}
+
+	ctx.Data["Report"] = r
+	ctx.Data["Logs"] = logs
+	ctx.Data["OtherReportsCount"] = others
+	ctx.HTML(http.StatusOK, tplAbuseReportView)
+}
+
+// AbuseReportPost response for an action of a site administrator on an abuse report
+func AbuseReportPost(ctx *context.Context) {
+	r := getAbuseReport(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	action := models.AbuseReportAction(ctx.Query("action"))
+	if err := moderation.HandleAbuseReport(ctx.User, r, action, ctx.Query("comment")); err != nil {
+		if !models.IsErrAbuseReportActionNotApplicable(err) {
+			ctx.ServerError("HandleAbuseReport", err)
+			return
+		}
+		ctx.Flash.Error(ctx.Tr("admin.abuse_reports.action_not_applicable"))
+	} else {
+		ctx.Flash.Success(ctx.Tr("admin.abuse_reports.action_success"))
+	}
+	ctx.Redirect(fmt.Sprintf("%s/admin/abuse_reports/%d", setting.AppSubURL, r.ID))
+}
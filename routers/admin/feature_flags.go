@@ -0,0 +1,185 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/featureflag"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplFeatureFlags   base.TplName = "admin/feature_flag/list"
+	tplFeatureFlagNew base.TplName = "admin/feature_flag/new"
+)
+
+// FeatureFlags show the feature flags of the instance
+func FeatureFlags(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.feature_flags")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminFeatureFlags"] = true
+
+	flags, err := models.GetFeatureFlags()
+	if err != nil {
+		ctx.ServerError("GetFeatureFlags", err)
+		return
+	}
+	for _, f := range flags {
+		if err := f.LoadTargets(); err != nil {
+			ctx.ServerError("LoadTargets", err)
+			return
+		}
+	}
+	ctx.Data["Flags"] = flags
+
+	ctx.HTML(http.StatusOK, tplFeatureFlags)
+}
+
+func prepareFeatureFlagForm(ctx *context.Context, f *models.FeatureFlag) {
+	ctx.Data["Title"] = ctx.Tr("admin.feature_flags")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminFeatureFlags"] = true
+	ctx.Data["Flag"] = f
+
+	if err := f.LoadTargets(); err != nil {
+		ctx.ServerError("LoadTargets", err)
+		return
+	}
+	names := make([]string, 0, len(f.Targets))
+	for _, u := range f.Targets {
+		names = append(names, u.Name)
+	}
+	ctx.Data["targets"] = strings.Join(names, ",")
+}
+
+func saveFeatureFlagForm(ctx *context.Context, f *models.FeatureFlag, form auth.AdminFeatureFlagForm) {
+	f.Name = form.Name
+	f.Description = form.Description
+	f.Enabled = form.Enabled
+	f.Percentage = form.Percentage
+
+	prepareFeatureFlagForm(ctx, f)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["targets"] = form.Targets
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplFeatureFlagNew)
+		return
+	}
+
+	names := strings.Split(form.Targets, ",")
+	f.TargetIDs = make([]int64, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); len(name) == 0 {
+			continue
+		}
+		u, err := models.GetUserByName(name)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Data["Err_Targets"] = true
+				ctx.RenderWithErr(ctx.Tr("admin.feature_flags.target_not_exist", name), tplFeatureFlagNew, &form)
+			} else {
+				ctx.ServerError("GetUserByName", err)
+			}
+			return
+		}
+		f.TargetIDs = append(f.TargetIDs, u.ID)
+	}
+
+	var err error
+	if f.ID == 0 {
+		err = models.CreateFeatureFlag(f)
+	} else {
+		err = models.UpdateFeatureFlag(f)
+	}
+	if err != nil {
+		switch {
+		case models.IsErrFeatureFlagAlreadyExist(err):
+			ctx.Data["Err_Name"] = true
+			ctx.RenderWithErr(ctx.Tr("admin.feature_flags.name_been_taken"), tplFeatureFlagNew, &form)
+		case models.IsErrInvalidFeatureFlag(err):
+			ctx.RenderWithErr(ctx.Tr("admin.feature_flags.invalid", err.(models.ErrInvalidFeatureFlag).Reason), tplFeatureFlagNew, &form)
+		default:
+			ctx.ServerError("SaveFeatureFlag", err)
+		}
+		return
+	}
+	featureflag.Invalidate()
+
+	log.Trace("Feature flag %s saved by admin %s", f.Name, ctx.User.Name)
+	ctx.Flash.Success(ctx.Tr("admin.feature_flags.save_success", f.Name))
+	ctx.Redirect(setting.AppSubURL + "/admin/feature_flags")
+}
+
+// NewFeatureFlag render creating a feature flag
+func NewFeatureFlag(ctx *context.Context) {
+	prepareFeatureFlagForm(ctx, &models.FeatureFlag{})
+	if ctx.Written() {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplFeatureFlagNew)
+}
+
+// NewFeatureFlagPost response for creating a feature flag
+func NewFeatureFlagPost(ctx *context.Context, form auth.AdminFeatureFlagForm) {
+	saveFeatureFlagForm(ctx, &models.FeatureFlag{}, form)
+}
+
+func getFeatureFlag(ctx *context.Context) *models.FeatureFlag {
+	f, err := models.GetFeatureFlagByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrFeatureFlagNotExist(err) {
+			ctx.NotFound("GetFeatureFlagByID", err)
+		} else {
+			ctx.ServerError("GetFeatureFlagByID", err)
+		}
+		return nil
+	}
+	return f
+}
+
+// EditFeatureFlag render editing a feature flag
+func EditFeatureFlag(ctx *context.Context) {
+	f := getFeatureFlag(ctx)
+	if ctx.Written() {
+		return
+	}
+	prepareFeatureFlagForm(ctx, f)
+	if ctx.Written() {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplFeatureFlagNew)
+}
+
+// EditFeatureFlagPost response for editing a feature flag
+func EditFeatureFlagPost(ctx *context.Context, form auth.AdminFeatureFlagForm) {
+	f := getFeatureFlag(ctx)
+	if ctx.Written() {
+		return
+	}
+	saveFeatureFlagForm(ctx, f, form)
+}
+
+// DeleteFeatureFlag response for deleting a feature flag
+func DeleteFeatureFlag(ctx *context.Context) {
+	if err := models.DeleteFeatureFlag(ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteFeatureFlag: " + err.Error())
+	} else {
+		featureflag.Invalidate()
+		ctx.Flash.Success(ctx.Tr("admin.feature_flags.deletion_success"))
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/admin/feature_flags",
+	})
+}
@@ -25,6 +25,7 @@ const (
 	tplUsers    base.TplName = "admin/user/list"
 	tplUserNew  base.TplName = "admin/user/new"
 	tplUserEdit base.TplName = "admin/user/edit"
+	tplDormant  base.TplName = "admin/user/dormant"
 )
 
 // Users show all the users
@@ -42,6 +43,36 @@ func Users(ctx *context.Context) {
 	}, tplUsers)
 }
 
+// DormantUsers show the users flagged as dormant and the users deactivated because of it
+func DormantUsers(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.users.dormant")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminUsers"] = true
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	opts := models.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.Admin.UserPagingNum,
+	}
+
+	users, count, err := models.SearchDormantUsers(opts)
+	if err != nil {
+		ctx.ServerError("SearchDormantUsers", err)
+		return
+	}
+	ctx.Data["Users"] = users
+	ctx.Data["Total"] = count
+	ctx.Data["InactiveMonths"] = setting.Service.DormantUserInactiveMonths
+
+	pager := context.NewPagination(int(count), opts.PageSize, opts.Page, 5)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(200, tplDormant)
+}
+
 // NewUser render adding a new user page
 func NewUser(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("admin.users.new_account")
@@ -275,6 +306,11 @@ func EditUserPost(ctx *context.Context, form auth.AdminEditUserForm) {
 	} else {
 		u.ProhibitLogin = form.ProhibitLogin
 	}
+	// allowing a deactivated dormant user to sign in reactivates them
+	if !u.ProhibitLogin && u.IsDormant {
+		u.IsDormant = false
+		u.DormantFlaggedUnix = 0
+	}
 
 	if err := models.UpdateUser(u); err != nil {
 		if models.IsErrEmailAlreadyUsed(err) {
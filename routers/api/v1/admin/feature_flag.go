@@ -0,0 +1,265 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/featureflag"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+func getFeatureFlagByParams(ctx *context.APIContext) *models.FeatureFlag {
+	f, err := models.GetFeatureFlagByName(ctx.Params(":name"))
+	if err != nil {
+		if models.IsErrFeatureFlagNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetFeatureFlagByName", err)
+		}
+		return nil
+	}
+	return f
+}
+
+// setFeatureFlagTargets sets the targets of the flag to the users and organizations with the names
+func setFeatureFlagTargets(ctx *context.APIContext, f *models.FeatureFlag, names []string) {
+	f.TargetIDs = make([]int64, 0, len(names))
+	f.Targets = make([]*models.User, 0, len(names))
+	for _, name := range names {
+		u, err := models.GetUserByName(name)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("target '%s' does not exist", name))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		f.TargetIDs = append(f.TargetIDs, u.ID)
+		f.Targets = append(f.Targets, u)
+	}
+}
+
+func saveFeatureFlag(ctx *context.APIContext, f *models.FeatureFlag, status int) {
+	var err error
+	if f.ID == 0 {
+		err = models.CreateFeatureFlag(f)
+	} else {
+		err = models.UpdateFeatureFlag(f)
+	}
+	if err != nil {
+		if models.IsErrFeatureFlagAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else if models.IsErrInvalidFeatureFlag(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SaveFeatureFlag", err)
+		}
+		return
+	}
+	featureflag.Invalidate()
+	log.Trace("Feature flag %s saved by admin %s", f.Name, ctx.User.Name)
+
+	if err := f.LoadTargets(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadTargets", err)
+		return
+	}
+	ctx.JSON(status, convert.ToFeatureFlag(f))
+}
+
+// ListFeatureFlags api for listing the feature flags
+func ListFeatureFlags(ctx *context.APIContext) {
+	// swagger:operation GET /admin/feature_flags admin adminListFeatureFlags
+	// ---
+	// summary: List the feature flags
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FeatureFlagList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	flags, err := models.GetFeatureFlags()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFeatureFlags", err)
+		return
+	}
+
+	apiFlags := make([]*api.FeatureFlag, len(flags))
+	for i := range flags {
+		if err := flags[i].LoadTargets(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadTargets", err)
+			return
+		}
+		apiFlags[i] = convert.ToFeatureFlag(flags[i])
+	}
+	ctx.JSON(http.StatusOK, apiFlags)
+}
+
+// GetFeatureFlag api for getting a feature flag
+func GetFeatureFlag(ctx *context.APIContext) {
+	// swagger:operation GET /admin/feature_flags/{name} admin adminGetFeatureFlag
+	// ---
+	// summary: Get a feature flag
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: name
+	//   in: path
+	//   description: name of the feature flag
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FeatureFlag"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	f := getFeatureFlagByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := f.LoadTargets(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadTargets", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToFeatureFlag(f))
+}
+
+// CreateFeatureFlag api for creating a feature flag
+func CreateFeatureFlag(ctx *context.APIContext, form api.CreateFeatureFlagOption) {
+	// swagger:operation POST /admin/feature_flags admin adminCreateFeatureFlag
+	// ---
+	// summary: Create a feature flag
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateFeatureFlagOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FeatureFlag"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	f := &models.FeatureFlag{
+		Name:        form.Name,
+		Description: form.Description,
+		Enabled:     form.Enabled,
+		Percentage:  form.Percentage,
+	}
+	setFeatureFlagTargets(ctx, f, form.Targets)
+	if ctx.Written() {
+		return
+	}
+	saveFeatureFlag(ctx, f, http.StatusCreated)
+}
+
+// EditFeatureFlag api for editing a feature flag
+func EditFeatureFlag(ctx *context.APIContext, form api.EditFeatureFlagOption) {
+	// swagger:operation PATCH /admin/feature_flags/{name} admin adminEditFeatureFlag
+	// ---
+	// summary: Edit a feature flag
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: name
+	//   in: path
+	//   description: name of the feature flag to edit
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditFeatureFlagOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FeatureFlag"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	f := getFeatureFlagByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil {
+		f.Name = *form.Name
+	}
+	if form.Description != nil {
+		f.Description = *form.Description
+	}
+	if form.Enabled != nil {
+		f.Enabled = *form.Enabled
+	}
+	if form.Percentage != nil {
+		f.Percentage = *form.Percentage
+	}
+	if form.Targets != nil {
+		setFeatureFlagTargets(ctx, f, *form.Targets)
+		if ctx.Written() {
+			return
+		}
+	}
+	saveFeatureFlag(ctx, f, http.StatusOK)
+}
+
+// DeleteFeatureFlag api for deleting a feature flag
+func DeleteFeatureFlag(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/feature_flags/{name} admin adminDeleteFeatureFlag
+	// ---
+	// summary: Delete a feature flag
+	// parameters:
+	// - name: name
+	//   in: path
+	//   description: name of the feature flag to delete
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	f := getFeatureFlagByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.DeleteFeatureFlag(f.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteFeatureFlag", err)
+		return
+	}
+	featureflag.Invalidate()
+	log.Trace("Feature flag %s deleted by admin %s", f.Name, ctx.User.Name)
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -219,6 +219,11 @@ func EditUser(ctx *context.APIContext, form api.EditUserOption) {
 	}
 	if form.ProhibitLogin != nil {
 		u.ProhibitLogin = *form.ProhibitLogin
+		// allowing a deactivated dormant user to sign in reactivates them
+		if !u.ProhibitLogin && u.IsDormant {
+			u.IsDormant = false
+			u.DormantFlaggedUnix = 0
+		}
 	}
 
 	if err := models.UpdateUser(u); err != nil {
@@ -404,3 +409,44 @@ func GetAllUsers(ctx *context.APIContext) {
 	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
 	ctx.JSON(http.StatusOK, &results)
 }
+
+// GetDormantUsers api for listing the users flagged as dormant or deactivated because of it
+func GetDormantUsers(ctx *context.APIContext) {
+	// swagger:operation GET /admin/users/dormant admin adminGetDormantUsers
+	// ---
+	// summary: List the users flagged as dormant or deactivated because of it
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DormantUserList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listOptions := utils.GetListOptions(ctx)
+
+	users, maxResults, err := models.SearchDormantUsers(listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchDormantUsers", err)
+		return
+	}
+
+	results := make([]*api.DormantUser, len(users))
+	for i := range users {
+		results[i] = convert.ToDormantUser(users[i])
+	}
+
+	ctx.SetLinkHeader(int(maxResults), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", maxResults))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &results)
+}
@@ -82,6 +82,7 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/settings"
 	_ "code.gitea.io/gitea/routers/api/v1/swagger" // for swagger generation
 	"code.gitea.io/gitea/routers/api/v1/user"
+	"code.gitea.io/gitea/services/tokenusage"
 
 	"gitea.com/macaron/binding"
 	"gitea.com/macaron/macaron"
@@ -174,9 +175,47 @@ func repoAssignment() macaron.Handler {
 			ctx.NotFound()
 			return
 		}
+
+		if owner.IsOrganization() {
+			recordTokenUsage(ctx, owner.ID)
+		}
 	}
 }
 
+// recordTokenUsage records the call of an endpoint with an access token to the resources of an organization,
+// the endpoint class is the path after the identifiers of the resource, e.g. repos/issues for
+// /repos/{owner}/{repo}/issues/{index}. The remaining handlers are run first, so only calls which
+// passed the permission checks of the endpoint are recorded.
+func recordTokenUsage(ctx *context.APIContext, orgID int64) {
+	token, ok := ctx.Data["ApiToken"].(*models.AccessToken)
+	if !ok {
+		return
+	}
+
+	ctx.Next()
+	if ctx.Resp.Status() >= http.StatusBadRequest {
+		return
+	}
+
+	path := ctx.Req.URL.Path
+	if i := strings.Index(path, "/api/v1/"); i >= 0 {
+		path = path[i+len("/api/v1/"):]
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	endpoint := parts[0]
+	// skip the owner and the name of repositories, the name of organizations and the id of teams
+	idParts := 1
+	if endpoint == "repos" {
+		idParts = 2
+	}
+	if len(parts) > idParts+1 {
+		endpoint += "/" + parts[idParts+1]
+	}
+
+	isWrite := ctx.Req.Method != http.MethodGet && ctx.Req.Method != http.MethodHead
+	tokenusage.Record(token, orgID, endpoint, isWrite, ctx.RemoteAddr())
+}
+
 // Contexter middleware already checks token for user sign in process.
 func reqToken() macaron.Handler {
 	return func(ctx *context.APIContext) {
@@ -412,6 +451,12 @@ func orgAssignment(args ...bool) macaron.Handler {
 				return
 			}
 		}
+
+		if ctx.Org.Organization != nil {
+			recordTokenUsage(ctx, ctx.Org.Organization.ID)
+		} else if ctx.Org.Team != nil {
+			recordTokenUsage(ctx, ctx.Org.Team.OrgID)
+		}
 	}
 }
 
@@ -494,6 +539,13 @@ func mustEnableUserHeatmap(ctx *context.APIContext) {
 	}
 }
 
+func mustEnableTranslation(ctx *context.APIContext) {
+	if !setting.Translation.Enabled {
+		ctx.NotFound()
+		return
+	}
+}
+
 func mustNotBeArchived(ctx *context.APIContext) {
 	if ctx.Repo.Repository.IsArchived {
 		ctx.NotFound()
@@ -608,6 +660,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Combo("/repos").Get(user.ListMyRepos).
 				Post(bind(api.CreateRepoOption{}), repo.Create)
 
+			m.Group("/notification_rules", func() {
+				m.Combo("").Get(user.ListNotificationRules).
+					Post(bind(api.CreateNotificationRuleOption{}), user.CreateNotificationRule)
+				m.Delete("/:id", user.DeleteNotificationRule)
+			})
+
 			m.Group("/starred", func() {
 				m.Get("", user.GetMyStarredRepos)
 				m.Group("/:username/:reponame", func() {
@@ -623,6 +681,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/subscriptions", user.GetMyWatchedRepos)
 
 			m.Get("/teams", org.ListUserTeams)
+
+			m.Get("/feature_flags", user.GetMyFeatureFlags)
 		}, reqToken())
 
 		// Repositories
@@ -660,9 +720,15 @@ func RegisterRoutes(m *macaron.Macaron) {
 							m.Combo("").Get(repo.GetGitHook).
 								Patch(bind(api.EditGitHookOption{}), repo.EditGitHook).
 								Delete(repo.DeleteGitHook)
+							m.Get("/scripts", repo.ListGitHookScripts)
+							m.Combo("/scripts/:name").Get(repo.GetGitHookScript).
+								Put(bind(api.EditGitHookScriptOption{}), repo.EditGitHookScript).
+								Delete(repo.DeleteGitHookScript)
 						})
 					}, reqGitHook(), context.ReferencesGitRepo(true))
 				}, reqToken(), reqAdmin())
+				m.Combo("/attachments", reqToken(), reqAdmin()).Get(repo.ListRepoAttachments).
+					Delete(bind(api.DeleteAttachmentsOption{}), repo.DeleteRepoAttachments)
 				m.Group("/collaborators", func() {
 					m.Get("", reqAnyRepoReader(), repo.ListCollaborators)
 					m.Combo("/:collaborator").Get(reqAnyRepoReader(), repo.IsCollaborator).
@@ -670,9 +736,16 @@ func RegisterRoutes(m *macaron.Macaron) {
 						Delete(reqAdmin(), repo.DeleteCollaborator)
 				}, reqToken())
 				m.Get("/raw/*", context.RepoRefForAPI(), reqRepoReader(models.UnitTypeCode), repo.GetRawFile)
-				m.Get("/archive/*", reqRepoReader(models.UnitTypeCode), repo.GetArchive)
+				m.Combo("/archive/*", reqRepoReader(models.UnitTypeCode)).Get(repo.GetArchive).
+					Post(context.ReferencesGitRepo(false), repo.CreateArchive).
+					Delete(reqToken(), context.ReferencesGitRepo(false), repo.CancelArchive)
 				m.Combo("/forks").Get(repo.ListForks).
 					Post(reqToken(), reqRepoReader(models.UnitTypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
+				m.Get("/forks/network", repo.ListForkNetwork)
+				m.Get("/events", reqToken(), repo.StreamEvents)
+				m.Post("/git_credentials", reqToken(), reqRepoReader(models.UnitTypeCode), bind(api.CreateGitCredentialOption{}), repo.CreateGitCredential)
+				m.Post("/forks/detach", reqToken(), reqSiteAdmin(), repo.DetachFork)
+				m.Post("/forks/reroot", reqToken(), reqSiteAdmin(), bind(api.ReRootForkNetworkOption{}), repo.ReRootForkNetwork)
 				m.Group("/branches", func() {
 					m.Get("", repo.ListBranches)
 					m.Get("/*", repo.GetBranch)
@@ -686,6 +759,11 @@ func RegisterRoutes(m *macaron.Macaron) {
 						m.Get("", repo.GetBranchProtection)
 						m.Patch("", bind(api.EditBranchProtectionOption{}), repo.EditBranchProtection)
 						m.Delete("", repo.DeleteBranchProtection)
+						m.Group("/status_check_contexts", func() {
+							m.Combo("").Post(bind(api.StatusCheckContextsOption{}), repo.RegisterStatusCheckContexts).
+								Delete(bind(api.StatusCheckContextsOption{}), repo.UnregisterStatusCheckContexts)
+							m.Get("/changes", repo.ListStatusCheckContextChanges)
+						})
 					})
 				}, reqToken(), reqAdmin())
 				m.Group("/tags", func() {
@@ -704,6 +782,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Group("/issues", func() {
 					m.Combo("").Get(repo.ListIssues).
 						Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueOption{}), repo.CreateIssue)
+					m.Get("/suggestions", repo.SuggestIssueReferences)
 					m.Group("/comments", func() {
 						m.Get("", repo.ListRepoIssueComments)
 						m.Group("/:id", func() {
@@ -715,6 +794,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 								Get(repo.GetIssueCommentReactions).
 								Post(bind(api.EditReactionOption{}), reqToken(), repo.PostIssueCommentReaction).
 								Delete(bind(api.EditReactionOption{}), reqToken(), repo.DeleteIssueCommentReaction)
+							m.Get("/translation", reqToken(), mustEnableTranslation, repo.GetIssueCommentTranslation)
 						})
 					})
 					m.Group("/:index", func() {
@@ -756,6 +836,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 							Get(repo.GetIssueReactions).
 							Post(bind(api.EditReactionOption{}), reqToken(), repo.PostIssueReaction).
 							Delete(bind(api.EditReactionOption{}), reqToken(), repo.DeleteIssueReaction)
+						m.Get("/translation", reqToken(), mustEnableTranslation, repo.GetIssueTranslation)
 					})
 				}, mustEnableIssuesOrPulls)
 				m.Group("/labels", func() {
@@ -784,6 +865,14 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Group("/releases", func() {
 					m.Combo("").Get(repo.ListReleases).
 						Post(reqToken(), reqRepoWriter(models.UnitTypeReleases), context.ReferencesGitRepo(false), bind(api.CreateReleaseOption{}), repo.CreateRelease)
+					m.Group("/channels", func() {
+						m.Combo("").Get(repo.ListReleaseChannels).
+							Post(reqToken(), reqRepoWriter(models.UnitTypeReleases), bind(api.CreateReleaseChannelOption{}), repo.CreateReleaseChannel)
+						m.Combo("/:channel").Get(repo.GetReleaseChannel).
+							Patch(reqToken(), reqRepoWriter(models.UnitTypeReleases), bind(api.EditReleaseChannelOption{}), repo.EditReleaseChannel).
+							Delete(reqToken(), reqRepoWriter(models.UnitTypeReleases), repo.DeleteReleaseChannel)
+						m.Get("/:channel/latest", repo.GetReleaseChannelLatest)
+					})
 					m.Group("/:id", func() {
 						m.Combo("").Get(repo.GetRelease).
 							Patch(reqToken(), reqRepoWriter(models.UnitTypeReleases), context.ReferencesGitRepo(false), bind(api.EditReleaseOption{}), repo.EditRelease).
@@ -794,6 +883,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 							m.Combo("/:asset").Get(repo.GetReleaseAttachment).
 								Patch(reqToken(), reqRepoWriter(models.UnitTypeReleases), bind(api.EditAttachmentOptions{}), repo.EditReleaseAttachment).
 								Delete(reqToken(), reqRepoWriter(models.UnitTypeReleases), repo.DeleteReleaseAttachment)
+							m.Post("/:asset/signed_url", reqToken(), bind(api.CreateSignedURLOption{}), repo.CreateReleaseAttachmentSignedURL)
 						})
 					})
 					m.Group("/tags", func() {
@@ -844,6 +934,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 						m.Get("/statuses", repo.GetCommitStatusesByRef)
 					})
 				}, reqRepoReader(models.UnitTypeCode))
+				m.Get("/compare/*", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false), repo.CompareDiff)
 				m.Group("/git", func() {
 					m.Group("/commits", func() {
 						m.Get("/:sha", repo.GetSingleCommit)
@@ -863,6 +954,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 						m.Delete("", bind(api.DeleteFileOptions{}), repo.DeleteFile)
 					}, reqRepoWriter(models.UnitTypeCode), reqToken())
 				}, reqRepoReader(models.UnitTypeCode))
+				m.Get("/rendered/*", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false), repo.GetRenderedFile)
 				m.Get("/signing-key.gpg", misc.SigningKey)
 				m.Group("/topics", func() {
 					m.Combo("").Get(repo.ListTopics).
@@ -874,6 +966,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 				}, reqAnyRepoReader())
 				m.Get("/issue_templates", context.ReferencesGitRepo(false), repo.GetIssueTemplates)
 				m.Get("/languages", reqRepoReader(models.UnitTypeCode), repo.GetLanguages)
+				m.Get("/stats/code_frequency", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false), repo.GetCodeFrequency)
+				m.Get("/projects/:id/analytics", reqRepoReader(models.UnitTypeProjects), repo.GetProjectAnalytics)
 			}, repoAssignment())
 		})
 
@@ -893,6 +987,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Combo("/:username").Get(org.IsMember).
 					Delete(reqToken(), reqOrgOwnership(), org.DeleteMember)
 			})
+			m.Get("/two_factor_compliance", reqToken(), reqOrgOwnership(), org.ListMembersTwoFactorStatus)
+			m.Get("/outside_collaborators", reqToken(), reqOrgOwnership(), org.ListOutsideCollaborators)
+			m.Get("/token_usage", reqToken(), reqOrgOwnership(), org.ListTokenUsage)
 			m.Group("/public_members", func() {
 				m.Get("", org.ListPublicMembers)
 				m.Combo("/:username").Get(org.IsPublicMember).
@@ -911,6 +1008,18 @@ func RegisterRoutes(m *macaron.Macaron) {
 					Patch(reqToken(), reqOrgOwnership(), bind(api.EditLabelOption{}), org.EditLabel).
 					Delete(reqToken(), reqOrgOwnership(), org.DeleteLabel)
 			})
+			m.Group("/repo_presets", func() {
+				m.Combo("").Get(org.ListRepoPresets).
+					Post(reqOrgOwnership(), bind(api.CreateRepoPresetOption{}), org.CreateRepoPreset)
+				m.Combo("/:id").Get(org.GetRepoPreset).
+					Patch(reqOrgOwnership(), bind(api.EditRepoPresetOption{}), org.EditRepoPreset).
+					Delete(reqOrgOwnership(), org.DeleteRepoPreset)
+			}, reqToken(), reqOrgMembership())
+			m.Group("/notification_rules", func() {
+				m.Combo("").Get(org.ListNotificationRules).
+					Post(bind(api.CreateNotificationRuleOption{}), org.CreateNotificationRule)
+				m.Delete("/:id", org.DeleteNotificationRule)
+			}, reqToken(), reqOrgOwnership())
 			m.Group("/hooks", func() {
 				m.Combo("").Get(org.ListHooks).
 					Post(bind(api.CreateHookOption{}), org.CreateHook)
@@ -947,10 +1056,18 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Get("", admin.ListCronTasks)
 				m.Post("/:task", admin.PostCronTask)
 			})
+			m.Group("/feature_flags", func() {
+				m.Combo("").Get(admin.ListFeatureFlags).
+					Post(bind(api.CreateFeatureFlagOption{}), admin.CreateFeatureFlag)
+				m.Combo("/:name").Get(admin.GetFeatureFlag).
+					Patch(bind(api.EditFeatureFlagOption{}), admin.EditFeatureFlag).
+					Delete(admin.DeleteFeatureFlag)
+			})
 			m.Get("/orgs", admin.GetAllOrgs)
 			m.Group("/users", func() {
 				m.Get("", admin.GetAllUsers)
 				m.Post("", bind(api.CreateUserOption{}), admin.CreateUser)
+				m.Get("/dormant", admin.GetDormantUsers)
 				m.Group("/:username", func() {
 					m.Combo("").Patch(bind(api.EditUserOption{}), admin.EditUser).
 						Delete(admin.DeleteUser)
@@ -302,3 +302,82 @@ func DeleteMember(ctx *context.APIContext) {
 	}
 	ctx.Status(http.StatusNoContent)
 }
+
+// ListMembersTwoFactorStatus list the two-factor authentication status of an organization's members
+func ListMembersTwoFactorStatus(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/two_factor_compliance organization orgListMembersTwoFactorStatus
+	// ---
+	// summary: List the two-factor authentication status of an organization's members
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgMemberTwoFactorStatusList"
+
+	statuses, err := ctx.Org.Organization.GetMembersTwoFactorStatus()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMembersTwoFactorStatus", err)
+		return
+	}
+
+	apiStatuses := make([]*api.OrgMemberTwoFactorStatus, len(statuses))
+	for i := range statuses {
+		apiStatuses[i] = convert.ToOrgMemberTwoFactorStatus(statuses[i])
+	}
+	ctx.JSON(http.StatusOK, apiStatuses)
+}
+
+// ListOutsideCollaborators list the access of users who are collaborators on repositories
+// of an organization without being a member of it
+func ListOutsideCollaborators(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/outside_collaborators organization orgListOutsideCollaborators
+	// ---
+	// summary: List the access of users to an organization's repositories who are not members of the organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OutsideCollaboratorList"
+
+	listOptions := utils.GetListOptions(ctx)
+	count, err := ctx.Org.Organization.CountOutsideCollaborations()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountOutsideCollaborations", err)
+		return
+	}
+	collaborations, err := ctx.Org.Organization.GetOutsideCollaborations(listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetOutsideCollaborations", err)
+		return
+	}
+
+	apiCollaborators := make([]*api.OutsideCollaborator, len(collaborations))
+	for i := range collaborations {
+		apiCollaborators[i] = convert.ToOutsideCollaborator(collaborations[i])
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, apiCollaborators)
+}
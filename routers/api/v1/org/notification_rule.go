@@ -0,0 +1,122 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListNotificationRules list the notification rules of an organization
+func ListNotificationRules(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/notification_rules organization orgListNotificationRules
+	// ---
+	// summary: List the notification rules of an organization in the order they are evaluated
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationRuleList"
+
+	rules, err := ctx.Org.Organization.GetNotificationRules()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetNotificationRules", err)
+		return
+	}
+
+	apiRules := make([]*api.NotificationRule, len(rules))
+	for i := range rules {
+		apiRules[i] = convert.ToNotificationRule(rules[i])
+	}
+	ctx.JSON(http.StatusOK, apiRules)
+}
+
+// CreateNotificationRule create a notification rule for an organization
+func CreateNotificationRule(ctx *context.APIContext, form api.CreateNotificationRuleOption) {
+	// swagger:operation POST /orgs/{org}/notification_rules organization orgCreateNotificationRule
+	// ---
+	// summary: Create a notification rule for an organization
+	// description: The rules of an organization apply to notifications of its members about the repositories of the organization which are not matched by a rule of the member.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateNotificationRuleOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/NotificationRule"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	rule := &models.NotificationRule{
+		OwnerID:     ctx.Org.Organization.ID,
+		RepoPattern: form.RepoPattern,
+		Event:       models.NotificationRuleEvent(form.Event),
+		Action:      models.NotificationRuleAction(form.Action),
+		Priority:    form.Priority,
+	}
+	if err := models.CreateNotificationRule(rule); err != nil {
+		if models.IsErrInvalidNotificationRule(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateNotificationRule", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToNotificationRule(rule))
+}
+
+// DeleteNotificationRule delete a notification rule of an organization
+func DeleteNotificationRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/notification_rules/{id} organization orgDeleteNotificationRule
+	// ---
+	// summary: Delete a notification rule of an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the rule to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteNotificationRule(ctx.Org.Organization.ID, ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrNotificationRuleNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteNotificationRule", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
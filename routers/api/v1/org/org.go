@@ -244,8 +244,32 @@ func Edit(ctx *context.APIContext, form api.EditOrgOption) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Organization"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	org := ctx.Org.Organization
+	if form.RequireTwoFactor != nil || form.TwoFactorGraceDays != nil {
+		require, graceDays := org.RequireTwoFactor, org.TwoFactorGraceDays
+		if form.RequireTwoFactor != nil {
+			require = *form.RequireTwoFactor
+		}
+		if form.TwoFactorGraceDays != nil {
+			graceDays = *form.TwoFactorGraceDays
+		}
+		if graceDays < 0 || graceDays > 365 {
+			ctx.Error(http.StatusUnprocessableEntity, "TwoFactorGraceDays", fmt.Errorf("two_factor_grace_days must be between 0 and 365"))
+			return
+		}
+		if err := org.SetTwoFactorRequirement(ctx.User, require, graceDays); err != nil {
+			if models.IsErrTwoFactorNotEnrolled(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "SetTwoFactorRequirement", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "SetTwoFactorRequirement", err)
+			}
+			return
+		}
+	}
+
 	org.FullName = form.FullName
 	org.Description = form.Description
 	org.Website = form.Website
@@ -0,0 +1,274 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+func toRepoPresetWebhooks(opts []*api.CreateRepoPresetWebhookOption) []*models.RepoPresetWebhook {
+	webhooks := make([]*models.RepoPresetWebhook, 0, len(opts))
+	for _, opt := range opts {
+		webhooks = append(webhooks, &models.RepoPresetWebhook{
+			URL:            opt.URL,
+			ContentType:    models.ToHookContentType(opt.ContentType),
+			Secret:         opt.Secret,
+			SendEverything: opt.SendEverything,
+		})
+	}
+	return webhooks
+}
+
+func getRepoPresetByParams(ctx *context.APIContext) *models.RepoPreset {
+	preset, err := models.GetRepoPresetByID(ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrRepoPresetNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoPresetByID", err)
+		}
+		return nil
+	}
+	return preset
+}
+
+func saveRepoPreset(ctx *context.APIContext, preset *models.RepoPreset, status int) {
+	var err error
+	if preset.ID == 0 {
+		err = models.CreateRepoPreset(preset)
+	} else {
+		err = models.UpdateRepoPreset(preset)
+	}
+	if err != nil {
+		if models.IsErrRepoPresetAlreadyExist(err) || models.IsErrInvalidRepoPreset(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SaveRepoPreset", err)
+		}
+		return
+	}
+	ctx.JSON(status, convert.ToRepoPreset(preset))
+}
+
+// ListRepoPresets list the repository presets of an organization
+func ListRepoPresets(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/repo_presets organization orgListRepoPresets
+	// ---
+	// summary: List the repository presets of an organization
+	// description: Owners get all presets, other members get the presets they may create repositories from.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoPresetList"
+
+	presets, err := ctx.Org.Organization.GetUsableRepoPresets(ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUsableRepoPresets", err)
+		return
+	}
+
+	apiPresets := make([]*api.RepoPreset, len(presets))
+	for i := range presets {
+		apiPresets[i] = convert.ToRepoPreset(presets[i])
+	}
+	ctx.JSON(http.StatusOK, apiPresets)
+}
+
+// GetRepoPreset get a repository preset of an organization
+func GetRepoPreset(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/repo_presets/{id} organization orgGetRepoPreset
+	// ---
+	// summary: Get a repository preset of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the preset to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoPreset"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	preset := getRepoPresetByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if usable, err := preset.IsUsableBy(ctx.User); err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsUsableBy", err)
+		return
+	} else if !usable {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoPreset(preset))
+}
+
+// CreateRepoPreset create a repository preset for an organization
+func CreateRepoPreset(ctx *context.APIContext, form api.CreateRepoPresetOption) {
+	// swagger:operation POST /orgs/{org}/repo_presets organization orgCreateRepoPreset
+	// ---
+	// summary: Create a repository preset for an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateRepoPresetOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RepoPreset"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	saveRepoPreset(ctx, &models.RepoPreset{
+		OrgID:                ctx.Org.Organization.ID,
+		TeamID:               form.TeamID,
+		Name:                 form.Name,
+		Description:          form.Description,
+		Units:                models.FindUnitTypes(form.Units...),
+		IssueLabels:          form.IssueLabels,
+		Topics:               form.Topics,
+		ProtectDefaultBranch: form.ProtectDefaultBranch,
+		RequiredApprovals:    form.RequiredApprovals,
+		StatusCheckContexts:  form.StatusCheckContexts,
+		Webhooks:             toRepoPresetWebhooks(form.Webhooks),
+	}, http.StatusCreated)
+}
+
+// EditRepoPreset update a repository preset of an organization
+func EditRepoPreset(ctx *context.APIContext, form api.EditRepoPresetOption) {
+	// swagger:operation PATCH /orgs/{org}/repo_presets/{id} organization orgEditRepoPreset
+	// ---
+	// summary: Update a repository preset of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the preset to edit
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRepoPresetOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoPreset"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	preset := getRepoPresetByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil {
+		preset.Name = *form.Name
+	}
+	if form.Description != nil {
+		preset.Description = *form.Description
+	}
+	if form.TeamID != nil {
+		preset.TeamID = *form.TeamID
+	}
+	if form.Units != nil {
+		preset.Units = models.FindUnitTypes(*form.Units...)
+	}
+	if form.IssueLabels != nil {
+		preset.IssueLabels = *form.IssueLabels
+	}
+	if form.Topics != nil {
+		preset.Topics = *form.Topics
+	}
+	if form.ProtectDefaultBranch != nil {
+		preset.ProtectDefaultBranch = *form.ProtectDefaultBranch
+	}
+	if form.RequiredApprovals != nil {
+		preset.RequiredApprovals = *form.RequiredApprovals
+	}
+	if form.StatusCheckContexts != nil {
+		preset.StatusCheckContexts = *form.StatusCheckContexts
+	}
+	if form.Webhooks != nil {
+		preset.Webhooks = toRepoPresetWebhooks(*form.Webhooks)
+	}
+	saveRepoPreset(ctx, preset, http.StatusOK)
+}
+
+// DeleteRepoPreset delete a repository preset of an organization
+func DeleteRepoPreset(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/repo_presets/{id} organization orgDeleteRepoPreset
+	// ---
+	// summary: Delete a repository preset of an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the preset to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteRepoPreset(ctx.Org.Organization.ID, ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrRepoPresetNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteRepoPreset", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
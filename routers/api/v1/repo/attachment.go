@@ -0,0 +1,143 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListRepoAttachments lists the attachments of the issues, comments and releases of a repository
+func ListRepoAttachments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/attachments repository repoListAttachments
+	// ---
+	// summary: List all attachments of a repository's issues, comments and releases
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: orphaned
+	//   in: query
+	//   description: if true only list attachments which belong to no issue, comment or release, if false only list the others
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoAttachmentList"
+
+	listOptions := utils.GetListOptions(ctx)
+	opts := &models.FindRepoAttachmentsOptions{
+		ListOptions: listOptions,
+		RepoID:      ctx.Repo.Repository.ID,
+		Orphaned:    util.OptionalBoolNone,
+	}
+	if len(ctx.Query("orphaned")) > 0 {
+		opts.Orphaned = util.OptionalBoolOf(ctx.QueryBool("orphaned"))
+	}
+
+	count, _, err := models.CountRepoAttachments(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountRepoAttachments", err)
+		return
+	}
+	attachments, err := models.FindRepoAttachments(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoAttachments", err)
+		return
+	}
+	if err := attachments.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+
+	apiAttachments := make([]*api.RepoAttachment, len(attachments))
+	for i := range attachments {
+		apiAttachments[i] = convert.ToRepoAttachment(attachments[i])
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &apiAttachments)
+}
+
+// DeleteRepoAttachments deletes attachments of a repository
+func DeleteRepoAttachments(ctx *context.APIContext, form api.DeleteAttachmentsOption) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/attachments repository repoDeleteAttachments
+	// ---
+	// summary: Delete attachments of a repository's issues, comments and releases
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/DeleteAttachmentsOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	ids := make(map[int64]bool, len(form.IDs))
+	for _, id := range form.IDs {
+		ids[id] = true
+	}
+
+	attachments, err := models.GetRepoAttachmentsByIDs(ctx.Repo.Repository.ID, form.IDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoAttachmentsByIDs", err)
+		return
+	}
+	// refuse to delete anything if some of the attachments do not belong to this repository
+	if len(attachments) != len(ids) {
+		ctx.NotFound()
+		return
+	}
+
+	if _, err := models.DeleteAttachments(attachments, true); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteAttachments", err)
+		return
+	}
+	log.Trace("%d attachments of repository %s deleted by %s", len(attachments), ctx.Repo.Repository.FullName(), ctx.User.Name)
+	ctx.Status(http.StatusNoContent)
+}
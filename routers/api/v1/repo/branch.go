@@ -525,6 +525,7 @@ func CreateBranchProtection(ctx *context.APIContext, form api.CreateBranchProtec
 		RequireSignedCommits:          form.RequireSignedCommits,
 		ProtectedFilePatterns:         form.ProtectedFilePatterns,
 		BlockOnOutdatedBranch:         form.BlockOnOutdatedBranch,
+		PushToPullRequest:             models.ToPushToPullRequestMode(form.PushToPullRequest),
 	}
 
 	err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
@@ -669,6 +670,10 @@ func EditBranchProtection(ctx *context.APIContext, form api.EditBranchProtection
 		protectBranch.ProtectedFilePatterns = *form.ProtectedFilePatterns
 	}
 
+	if form.PushToPullRequest != nil {
+		protectBranch.PushToPullRequest = models.ToPushToPullRequestMode(*form.PushToPullRequest)
+	}
+
 	if form.BlockOnOutdatedBranch != nil {
 		protectBranch.BlockOnOutdatedBranch = *form.BlockOnOutdatedBranch
 	}
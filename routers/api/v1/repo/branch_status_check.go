@@ -0,0 +1,229 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// getBranchProtectionByParams returns the branch protection named by the :name parameter, writing a response if it does not exist
+func getBranchProtectionByParams(ctx *context.APIContext) *models.ProtectedBranch {
+	bp, err := models.GetProtectedBranchBy(ctx.Repo.Repository.ID, ctx.Params(":name"))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProtectedBranchBy", err)
+		return nil
+	}
+	if bp == nil || bp.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return bp
+}
+
+// validateStatusCheckContexts trims the contexts and checks they are valid status contexts
+func validateStatusCheckContexts(contexts []string) ([]string, error) {
+	result := make([]string, 0, len(contexts))
+	for _, context := range contexts {
+		context = strings.TrimSpace(context)
+		if len(context) == 0 {
+			return nil, fmt.Errorf("status check contexts must not be empty")
+		} else if len(context) > 255 {
+			return nil, fmt.Errorf("status check context %q is longer than 255 characters", context)
+		}
+		result = append(result, context)
+	}
+	return result, nil
+}
+
+// RegisterStatusCheckContexts adds required status check contexts to a branch protection
+func RegisterStatusCheckContexts(ctx *context.APIContext, form api.StatusCheckContextsOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/branch_protections/{name}/status_check_contexts repository repoRegisterStatusCheckContexts
+	// ---
+	// summary: Add required status check contexts to a branch protection and enable status checks
+	// description: Contexts which are already required are left untouched. Every added context is recorded in the
+	//   changes of the branch protection.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of protected branch
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/StatusCheckContextsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtection"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	changeStatusCheckContexts(ctx, form, true)
+}
+
+// UnregisterStatusCheckContexts removes required status check contexts from a branch protection
+func UnregisterStatusCheckContexts(ctx *context.APIContext, form api.StatusCheckContextsOption) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/branch_protections/{name}/status_check_contexts repository repoUnregisterStatusCheckContexts
+	// ---
+	// summary: Remove required status check contexts from a branch protection
+	// description: Contexts which are not required are ignored. Every removed context is recorded in the
+	//   changes of the branch protection.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of protected branch
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/StatusCheckContextsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BranchProtection"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	changeStatusCheckContexts(ctx, form, false)
+}
+
+func changeStatusCheckContexts(ctx *context.APIContext, form api.StatusCheckContextsOption, register bool) {
+	contexts, err := validateStatusCheckContexts(form.Contexts)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	bp := getBranchProtectionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if register {
+		added, err := bp.RegisterStatusCheckContexts(ctx.User, contexts)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "RegisterStatusCheckContexts", err)
+			return
+		}
+		log.Trace("Required status check contexts %v registered on %s of %s by %s", added, bp.BranchName, ctx.Repo.Repository.FullName(), ctx.User.Name)
+	} else {
+		removed, err := bp.UnregisterStatusCheckContexts(ctx.User, contexts)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "UnregisterStatusCheckContexts", err)
+			return
+		}
+		log.Trace("Required status check contexts %v unregistered on %s of %s by %s", removed, bp.BranchName, ctx.Repo.Repository.FullName(), ctx.User.Name)
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToBranchProtection(bp))
+}
+
+// ListStatusCheckContextChanges lists the recorded changes of the required status check contexts of a branch protection
+func ListStatusCheckContextChanges(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/branch_protections/{name}/status_check_contexts/changes repository repoListStatusCheckContextChanges
+	// ---
+	// summary: List the changes of the required status check contexts of a branch protection made through the API, the latest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of protected branch
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StatusCheckContextChangeList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	bp := getBranchProtectionByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	count, err := bp.CountStatusCheckContextChanges()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountStatusCheckContextChanges", err)
+		return
+	}
+	changes, err := bp.GetStatusCheckContextChanges(listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStatusCheckContextChanges", err)
+		return
+	}
+
+	apiChanges := make([]*api.StatusCheckContextChange, len(changes))
+	for i := range changes {
+		apiChanges[i] = convert.ToStatusCheckContextChange(changes[i])
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, apiChanges)
+}
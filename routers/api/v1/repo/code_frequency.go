@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/indexer/stats"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetCodeFrequency returns the weekly lines added and deleted per top level directory
+func GetCodeFrequency(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stats/code_frequency repository repoGetCodeFrequency
+	// ---
+	// summary: Get the lines added and deleted per top level directory and week of the last year
+	// description: The code frequency of the default branch is computed in the background,
+	//   202 is returned if it has not been computed yet.
+	// produces:
+	//   - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DirectoryCodeFrequencyList"
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	var commitID string
+	if !ctx.Repo.Repository.IsEmpty {
+		var err error
+		commitID, err = ctx.Repo.GitRepo.GetBranchCommitID(ctx.Repo.Repository.DefaultBranch)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetBranchCommitID", err)
+			return
+		}
+	}
+
+	directories, ready, err := stats.GetCodeFrequency(ctx.Repo.Repository, commitID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCodeFrequency", err)
+		return
+	}
+	if !ready {
+		ctx.Status(http.StatusAccepted)
+		return
+	}
+
+	apiDirectories := make([]*api.DirectoryCodeFrequency, len(directories))
+	for i, d := range directories {
+		apiDirectories[i] = convert.ToDirectoryCodeFrequency(d)
+	}
+	ctx.JSON(http.StatusOK, apiDirectories)
+}
@@ -8,11 +8,13 @@ package repo
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
@@ -158,6 +160,11 @@ func AddCollaborator(ctx *context.APIContext, form api.AddCollaboratorOption) {
 		return
 	}
 
+	if form.ExpiresAt != nil && !form.ExpiresAt.After(time.Now()) {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("expires_at must be in the future"))
+		return
+	}
+
 	if err := ctx.Repo.Repository.AddCollaborator(collaborator); err != nil {
 		ctx.Error(http.StatusInternalServerError, "AddCollaborator", err)
 		return
@@ -170,6 +177,13 @@ func AddCollaborator(ctx *context.APIContext, form api.AddCollaboratorOption) {
 		}
 	}
 
+	if form.ExpiresAt != nil {
+		if err := ctx.Repo.Repository.ChangeCollaborationExpiry(collaborator.ID, timeutil.TimeStamp(form.ExpiresAt.Unix())); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ChangeCollaborationExpiry", err)
+			return
+		}
+	}
+
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -0,0 +1,88 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/repo"
+)
+
+// CompareDiff compare two branches, tags or commits of a repository or its forks
+func CompareDiff(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/compare/{basehead} repository repoCompareDiff
+	// ---
+	// summary: Compare two branches, tags or commits
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: basehead
+	//   in: path
+	//   description: compare the head with the merge base (base...head) or the base itself (base..head), the head may be prefixed by the owner or the full name of a fork (owner/repo:branch)
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Compare"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if ctx.Repo.GitRepo == nil {
+		ctx.NotFound()
+		return
+	}
+	if base := strings.SplitN(ctx.Params("*"), "..", 2)[0]; strings.Contains(base, ":") {
+		ctx.Error(http.StatusUnprocessableEntity, "",
+			errors.New("the base must be a reference of this repository, compare from the repository of the base instead"))
+		return
+	}
+
+	// Comparing branches only requires to read the code, not to be able to open pull requests
+	ctx.Data["PageIsComparePull"] = false
+	_, headRepo, headGitRepo, compareInfo, _, _ := repo.ParseCompareInfo(ctx.Context)
+	if ctx.Written() {
+		return
+	}
+	defer headGitRepo.Close()
+
+	userCache := make(map[string]*models.User)
+	apiCommits := make([]*api.Commit, 0, compareInfo.Commits.Len())
+	for e := compareInfo.Commits.Front(); e != nil; e = e.Next() {
+		apiCommit, err := convert.ToCommit(headRepo, e.Value.(*git.Commit), userCache)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToCommit", err)
+			return
+		}
+		apiCommits = append(apiCommits, apiCommit)
+	}
+
+	ctx.JSON(http.StatusOK, &api.Compare{
+		TotalCommits:     len(apiCommits),
+		Commits:          apiCommits,
+		NumFiles:         compareInfo.NumFiles,
+		MergeBase:        compareInfo.MergeBase,
+		BaseCommit:       compareInfo.BaseCommitID,
+		DirectComparison: compareInfo.DirectComparison,
+	})
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/events"
+)
+
+// StreamEvents streams the events of a repository
+func StreamEvents(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/events repository repoStreamEvents
+	// ---
+	// summary: Stream the events of a repository as server-sent events
+	// description: Each event is named after its type and its data is a RepoEvent. Only the events about the parts of the repository the user can read are sent. A `ping` event is sent every 30 seconds to keep the connection open.
+	// produces:
+	// - text/event-stream
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoEvent"
+
+	events.RepoEvents(ctx.Context)
+}
@@ -8,15 +8,23 @@ package repo
 import (
 	"encoding/base64"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"path"
 	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/repofiles"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/repo"
+	archiver_service "code.gitea.io/gitea/services/archiver"
 )
 
 // GetRawFile get a file by path on a repository
@@ -108,6 +116,131 @@ func GetArchive(ctx *context.APIContext) {
 	repo.Download(ctx.Context)
 }
 
+// getArchiveRequest derives the archive request of the path parameters
+func getArchiveRequest(ctx *context.APIContext) *archiver_service.ArchiveRequest {
+	aReq := archiver_service.DeriveRequestFrom(ctx.Context, ctx.Params("*"))
+	if aReq == nil && !ctx.Written() {
+		ctx.NotFound()
+	}
+	return aReq
+}
+
+func archiveDoerID(ctx *context.APIContext) int64 {
+	if ctx.User == nil {
+		return 0
+	}
+	return ctx.User.ID
+}
+
+func toRepoArchiveStatus(aReq *archiver_service.ArchiveRequest) *api.RepoArchiveStatus {
+	return &api.RepoArchiveStatus{
+		Status:        aReq.Status().String(),
+		Complete:      aReq.IsComplete(),
+		Size:          aReq.Progress(),
+		QueuePosition: aReq.QueuePosition(),
+	}
+}
+
+// CreateArchive queue the generation of an archive of a repository
+func CreateArchive(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/archive/{archive} repository repoCreateArchive
+	// ---
+	// summary: Queue the generation of an archive of a repository and get its status
+	// description: Poll this endpoint until the archive is complete, then download it. Requesting an archive which is
+	//              already queued or generated does not queue it again.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: archive
+	//   in: path
+	//   description: the git reference for download with attached archive format (e.g. master.zip)
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoArchiveStatus"
+	//   "202":
+	//     "$ref": "#/responses/RepoArchiveStatus"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "429":
+	//     description: too many archives of the user are queued
+
+	aReq := getArchiveRequest(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !aReq.IsComplete() {
+		var err error
+		aReq, err = archiver_service.ArchiveRepository(aReq, archiveDoerID(ctx))
+		if err != nil {
+			if archiver_service.IsErrTooManyArchives(err) {
+				ctx.Error(http.StatusTooManyRequests, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "ArchiveRepository", err)
+			}
+			return
+		}
+	}
+
+	status := http.StatusOK
+	if !aReq.IsComplete() {
+		status = http.StatusAccepted
+	}
+	ctx.JSON(status, toRepoArchiveStatus(aReq))
+}
+
+// CancelArchive cancel the generation of an archive of a repository
+func CancelArchive(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/archive/{archive} repository repoCancelArchive
+	// ---
+	// summary: Cancel the generation of an archive of a repository
+	// description: The generation is only cancelled if no other user requested the archive as well.
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: archive
+	//   in: path
+	//   description: the git reference for download with attached archive format (e.g. master.zip)
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	aReq := getArchiveRequest(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !archiver_service.CancelArchive(aReq, archiveDoerID(ctx)) {
+		ctx.NotFound()
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
 // GetEditorconfig get editor config of a repository
 func GetEditorconfig(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/editorconfig/{filepath} repository repoGetEditorConfig
@@ -531,6 +664,10 @@ func GetContents(ctx *context.APIContext) {
 		}
 		ctx.Error(http.StatusInternalServerError, "GetContentsOrList", err)
 	} else {
+		if err := repofiles.ResolveSubmoduleTargets(ctx.Repo.Repository, ctx.User, fileList); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ResolveSubmoduleTargets", err)
+			return
+		}
 		ctx.JSON(http.StatusOK, fileList)
 	}
 }
@@ -567,3 +704,112 @@ func GetContentsList(ctx *context.APIContext) {
 	// same as GetContents(), this function is here because swagger fails if path is empty in GetContents() interface
 	GetContents(ctx)
 }
+
+// GetRenderedFile renders a markup file of a repository to HTML
+func GetRenderedFile(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/rendered/{filepath} repository repoGetRenderedFile
+	// ---
+	// summary: Render a markup file of a repository to HTML and return its table of contents
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: filepath
+	//   in: path
+	//   description: path of the markup file in the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RenderedFileResponse"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.NotFound()
+		return
+	}
+
+	treePath := ctx.Params("*")
+	ref := ctx.QueryTrim("ref")
+	if ref == "" {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+
+	commit, err := ctx.Repo.GitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return
+	}
+
+	blob, err := commit.GetBlobByPath(treePath)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetBlobByPath", err)
+		}
+		return
+	}
+
+	markupType := markup.Type(blob.Name())
+	if markupType == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("%s is not a markup file", treePath))
+		return
+	}
+	if blob.Size() >= setting.UI.MaxDisplayFileSize {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("%s is too large to be rendered", treePath))
+		return
+	}
+
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "DataAsync", err)
+		return
+	}
+	defer dataRc.Close()
+	buf, err := ioutil.ReadAll(dataRc)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReadAll", err)
+		return
+	}
+
+	treeDir := path.Dir(treePath)
+	if treeDir == "." {
+		treeDir = ""
+	}
+	urlPrefix := util.URLJoin(ctx.Repo.Repository.HTMLURL(), "src/commit", commit.ID.String(), treeDir)
+
+	result := &api.RenderedFileResponse{
+		Name: blob.Name(),
+		Path: treePath,
+		SHA:  blob.ID.String(),
+		Type: markupType,
+		HTML: string(markup.Render(blob.Name(), buf, urlPrefix, ctx.Repo.Repository.ComposeDocumentMetas())),
+		TOC:  []*api.MarkupHeading{},
+	}
+	if markupType == markdown.MarkupName {
+		result.TOC = convert.ToMarkupHeadings(markdown.ExtractTOC(buf))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
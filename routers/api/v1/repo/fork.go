@@ -127,3 +127,135 @@ func CreateFork(ctx *context.APIContext, form api.CreateForkOption) {
 	//TODO change back to 201
 	ctx.JSON(http.StatusAccepted, fork.APIFormat(models.AccessModeOwner))
 }
+
+// ListForkNetwork list the fork network of a repository
+func ListForkNetwork(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/forks/network repository repoListForkNetwork
+	// ---
+	// summary: List the fork network of a repository, including forks of forks
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ForkNetworkRepositoryList"
+
+	nodes, err := ctx.Repo.Repository.GetForkNetwork(ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetForkNetwork", err)
+		return
+	}
+	apiNodes := make([]*api.ForkNetworkRepository, len(nodes))
+	for i, node := range nodes {
+		access, err := models.AccessLevel(ctx.User, node.Repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		apiNodes[i] = &api.ForkNetworkRepository{
+			Repository: node.Repo.APIFormat(access),
+			ParentID:   node.ParentID,
+			Depth:      node.Depth,
+		}
+	}
+	ctx.JSON(http.StatusOK, apiNodes)
+}
+
+// DetachFork make a fork a standalone repository
+func DetachFork(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/forks/detach repository repoDetachFork
+	// ---
+	// summary: Make a fork a standalone repository, requires site admin permissions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the fork
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the fork
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Repository"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	repo := ctx.Repo.Repository
+	if err := models.DetachFork(repo); err != nil {
+		if models.IsErrRepoNotFork(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DetachFork", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, repo.APIFormat(models.AccessModeAdmin))
+}
+
+// ReRootForkNetwork replace a repository in its fork network by one of its forks
+func ReRootForkNetwork(ctx *context.APIContext, form api.ReRootForkNetworkOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/forks/reroot repository repoReRootForkNetwork
+	// ---
+	// summary: Replace a repository in its fork network by one of its forks, requires site admin permissions
+	// description: The other forks of the repository become forks of the new root and the repository becomes a standalone repository.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ReRootForkNetworkOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Repository"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	newRoot, err := models.GetRepositoryByID(form.NewRootID)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByID", err)
+		}
+		return
+	}
+	if err := models.ReRootForkNetwork(ctx.Repo.Repository, newRoot); err != nil {
+		if models.IsErrRepoNotFork(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ReRootForkNetwork", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, newRoot.APIFormat(models.AccessModeAdmin))
+}
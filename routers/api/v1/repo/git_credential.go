@@ -0,0 +1,79 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// CreateGitCredential creates a short-lived git credential for a repository
+func CreateGitCredential(ctx *context.APIContext, form api.CreateGitCredentialOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/git_credentials repository repoCreateGitCredential
+	// ---
+	// summary: Create a short-lived credential to clone, fetch or push the repository over HTTP
+	// description: The credential is only valid for the repository and the operation it was created for, e.g. to be used by a git credential helper in CI jobs instead of a personal access token.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateGitCredentialOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/GitCredential"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	op := models.GitCredentialOperation(form.Operation)
+	if len(op) == 0 {
+		op = models.GitCredentialOperationRead
+	}
+	if !op.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown operation %q", op))
+		return
+	}
+	if !ctx.Repo.Permission.CanAccess(op.AccessMode(), models.UnitTypeCode) {
+		ctx.Error(http.StatusForbidden, "", "user does not have the permission to perform the operation on the repository")
+		return
+	}
+
+	minutes := form.ExpiresIn
+	if minutes == 0 {
+		minutes = setting.GitCredentialDefaultExpiry
+	}
+	if minutes < 0 || minutes > setting.GitCredentialMaxExpiry {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("expires_in must be between 1 and %d minutes", setting.GitCredentialMaxExpiry))
+		return
+	}
+
+	password, expires := models.CreateGitCredential(ctx.User, ctx.Repo.Repository, op, minutes)
+	ctx.JSON(http.StatusCreated, &api.GitCredential{
+		Username:  ctx.User.Name,
+		Password:  password,
+		Operation: string(op),
+		Expires:   expires,
+	})
+}
@@ -191,3 +191,216 @@ func DeleteGitHook(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+func getGitHookScript(ctx *context.APIContext) *git.HookScript {
+	script, err := git.GetHookScript(ctx.Repo.Repository.RepoPath(), ctx.Params(":id"), ctx.Params(":name"))
+	if err != nil {
+		if err == git.ErrNotValidHook || err == git.ErrNotValidHookScript {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetHookScript", err)
+		}
+		return nil
+	}
+	return script
+}
+
+// ListGitHookScripts list all scripts of a Git hook chain
+func ListGitHookScripts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/hooks/git/{id}/scripts repository repoListGitHookScripts
+	// ---
+	// summary: List the scripts of a Git hook chain
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GitHookScriptList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	scripts, err := git.ListHookScripts(ctx.Repo.Repository.RepoPath(), ctx.Params(":id"))
+	if err != nil {
+		if err == git.ErrNotValidHook {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ListHookScripts", err)
+		}
+		return
+	}
+
+	apiScripts := make([]*api.GitHookScript, len(scripts))
+	for i := range scripts {
+		apiScripts[i] = convert.ToGitHookScript(scripts[i])
+	}
+	ctx.JSON(http.StatusOK, &apiScripts)
+}
+
+// GetGitHookScript get a script of a Git hook chain
+func GetGitHookScript(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/hooks/git/{id}/scripts/{name} repository repoGetGitHookScript
+	// ---
+	// summary: Get a script of a Git hook chain
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the script
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GitHookScript"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	script := getGitHookScript(ctx)
+	if ctx.Written() {
+		return
+	}
+	if len(script.Content) == 0 {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToGitHookScript(script))
+}
+
+// EditGitHookScript create or modify a script of a Git hook chain
+func EditGitHookScript(ctx *context.APIContext, form api.EditGitHookScriptOption) {
+	// swagger:operation PUT /repos/{owner}/{repo}/hooks/git/{id}/scripts/{name} repository repoEditGitHookScript
+	// ---
+	// summary: Create or update a script of a Git hook chain
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the script
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditGitHookScriptOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GitHookScript"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	script := getGitHookScript(ctx)
+	if ctx.Written() {
+		return
+	}
+	if script.IsManaged() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "managed hook scripts can only be changed in the hooks source repository")
+		return
+	}
+
+	script.Content = form.Content
+	if err := script.Update(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "script.Update", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToGitHookScript(script))
+}
+
+// DeleteGitHookScript delete a script of a Git hook chain
+func DeleteGitHookScript(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/hooks/git/{id}/scripts/{name} repository repoDeleteGitHookScript
+	// ---
+	// summary: Delete a script of a Git hook chain
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the script
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	script := getGitHookScript(ctx)
+	if ctx.Written() {
+		return
+	}
+	if script.IsManaged() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "managed hook scripts can only be changed in the hooks source repository")
+		return
+	}
+
+	if err := script.Delete(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "script.Delete", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -11,10 +11,9 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
-	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
-	"code.gitea.io/gitea/modules/webhook"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	webhook_service "code.gitea.io/gitea/services/webhook"
 )
 
 // ListHooks list all hooks of a repository
@@ -103,7 +102,8 @@ func GetHook(ctx *context.APIContext) {
 func TestHook(ctx *context.APIContext) {
 	// swagger:operation POST /repos/{owner}/{repo}/hooks/{id}/tests repository repoTestHook
 	// ---
-	// summary: Test a push webhook
+	// summary: Test a webhook with a sample payload of an event
+	// description: The test delivery is sent even if the webhook is not subscribed to the event.
 	// produces:
 	// - application/json
 	// parameters:
@@ -123,12 +123,28 @@ func TestHook(ctx *context.APIContext) {
 	//   type: integer
 	//   format: int64
 	//   required: true
+	// - name: event
+	//   in: query
+	//   description: event of the sample payload, defaults to push
+	//   type: string
+	//   enum: [push, create, delete, fork, issues, issue_assign, issue_label, issue_milestone, issue_comment, pull_request, pull_request_assign, pull_request_label, pull_request_milestone, pull_request_comment, pull_request_review_approved, pull_request_review_rejected, pull_request_review_comment, pull_request_sync, repository, release]
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	event := models.HookEventType(ctx.QueryTrim("event"))
+	if len(event) == 0 {
+		event = models.HookEventPush
+	}
+	if !webhook_service.IsSampleEvent(event) {
+		ctx.Error(http.StatusUnprocessableEntity, "", webhook_service.ErrUnknownSampleEvent{Event: event})
+		return
+	}
 
-	if ctx.Repo.Commit == nil {
-		// if repo does not have any commits, then don't send a webhook
+	if event == models.HookEventPush && ctx.Repo.Commit == nil {
+		// if repo does not have any commits, then don't send a push webhook
 		ctx.Status(http.StatusNoContent)
 		return
 	}
@@ -139,18 +155,8 @@ func TestHook(ctx *context.APIContext) {
 		return
 	}
 
-	if err := webhook.PrepareWebhook(hook, ctx.Repo.Repository, models.HookEventPush, &api.PushPayload{
-		Ref:    git.BranchPrefix + ctx.Repo.Repository.DefaultBranch,
-		Before: ctx.Repo.Commit.ID.String(),
-		After:  ctx.Repo.Commit.ID.String(),
-		Commits: []*api.PayloadCommit{
-			convert.ToPayloadCommit(ctx.Repo.Repository, ctx.Repo.Commit),
-		},
-		Repo:   ctx.Repo.Repository.APIFormat(models.AccessModeNone),
-		Pusher: convert.ToUser(ctx.User, ctx.IsSigned, false),
-		Sender: convert.ToUser(ctx.User, ctx.IsSigned, false),
-	}); err != nil {
-		ctx.Error(http.StatusInternalServerError, "PrepareWebhook: ", err)
+	if err := webhook_service.SendTestDelivery(hook, event, ctx.Repo.Repository, ctx.User, ctx.Repo.Commit); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SendTestDelivery", err)
 		return
 	}
 
@@ -0,0 +1,67 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// SuggestIssueReferences suggests issues and pull requests to reference from the repository
+func SuggestIssueReferences(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/suggestions issue issueSuggestReferences
+	// ---
+	// summary: Suggest issues and pull requests to reference from a repository
+	// description: Issues and pull requests of other repositories the user can read are
+	//   searched if the query is prefixed by "owner/repo#"
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: keyword or number, optionally prefixed by "owner/repo#"
+	//   type: string
+	// - name: limit
+	//   in: query
+	//   description: max number of suggestions
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueReferenceSuggestionList"
+
+	limit := ctx.QueryInt("limit")
+	if limit <= 0 {
+		limit = 10
+	} else if limit > setting.API.MaxResponseItems {
+		limit = setting.API.MaxResponseItems
+	}
+
+	suggestions, err := issue_service.SuggestReferences(ctx.User, ctx.Repo.Repository, ctx.Query("q"), limit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SuggestReferences", err)
+		return
+	}
+
+	apiSuggestions := make([]*api.IssueReferenceSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		apiSuggestions = append(apiSuggestions, convert.ToIssueReferenceSuggestion(suggestion.Issue, suggestion.Ref))
+	}
+	ctx.JSON(http.StatusOK, apiSuggestions)
+}
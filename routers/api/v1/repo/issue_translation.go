@@ -0,0 +1,163 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	translation_service "code.gitea.io/gitea/services/translation"
+)
+
+// GetIssueTranslation get the machine translation of the body of an issue
+func GetIssueTranslation(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/translation issue issueGetTranslation
+	// ---
+	// summary: Get the machine translation of the body of an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: lang
+	//   in: query
+	//   description: language to translate to, the language of the user by default
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ContentTranslation"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	if !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound()
+		return
+	}
+	if issue.IsHidden && !ctx.User.IsAdmin {
+		ctx.Error(http.StatusForbidden, "GetIssueTranslation", errors.New("the issue has been hidden by a site administrator"))
+		return
+	}
+
+	t, err := translation_service.TranslateIssue(ctx.Req.Context(), issue, translationLanguage(ctx))
+	respondTranslation(ctx, t, err)
+}
+
+// GetIssueCommentTranslation get the machine translation of the body of a comment
+func GetIssueCommentTranslation(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/comments/{id}/translation issue issueGetCommentTranslation
+	// ---
+	// summary: Get the machine translation of the body of a comment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: lang
+	//   in: query
+	//   description: language to translate to, the language of the user by default
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ContentTranslation"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	comment, err := models.GetCommentByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrCommentNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommentByID", err)
+		}
+		return
+	}
+	if err := comment.LoadIssue(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return
+	}
+	if comment.Issue.RepoID != ctx.Repo.Repository.ID || !ctx.Repo.CanReadIssuesOrPulls(comment.Issue.IsPull) ||
+		(comment.Type != models.CommentTypeComment && comment.Type != models.CommentTypeCode) {
+		ctx.NotFound()
+		return
+	}
+	if comment.IsHidden && !ctx.User.IsAdmin {
+		ctx.Error(http.StatusForbidden, "GetIssueCommentTranslation", errors.New("the comment has been hidden by a site administrator"))
+		return
+	}
+
+	t, err := translation_service.TranslateComment(ctx.Req.Context(), comment, translationLanguage(ctx))
+	respondTranslation(ctx, t, err)
+}
+
+// translationLanguage returns the requested target language, the language of the user by default
+func translationLanguage(ctx *context.APIContext) string {
+	if lang := ctx.Query("lang"); len(lang) > 0 {
+		return lang
+	} else if len(ctx.User.Language) > 0 {
+		return ctx.User.Language
+	}
+	return setting.Langs[0]
+}
+
+func respondTranslation(ctx *context.APIContext, t *models.IssueContentTranslation, err error) {
+	if err != nil {
+		if translation_service.IsErrInvalidLanguage(err) || translation_service.IsErrContentTooLong(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "Translate", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "Translate", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToContentTranslation(t))
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+)
+
+// GetProjectAnalytics returns the time issues spend on the boards of a project
+func GetProjectAnalytics(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/projects/{id}/analytics repository repoGetProjectAnalytics
+	// ---
+	// summary: Get the time issues spend on the boards of a project and its lead and cycle times
+	// description: Durations are in seconds and computed from the recorded moves of issues between boards.
+	// produces:
+	//   - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectAnalytics"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if models.UnitTypeProjects.UnitGlobalDisabled() {
+		ctx.NotFound()
+		return
+	}
+
+	project, err := models.GetProjectByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProjectNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetProjectByID", err)
+		}
+		return
+	}
+	if project.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return
+	}
+
+	analytics, err := models.GetProjectAnalytics(project)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjectAnalytics", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToProjectAnalytics(analytics))
+}
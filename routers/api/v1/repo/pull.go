@@ -873,12 +873,14 @@ func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption)
 
 	var (
 		headUser   *models.User
+		headRepo   *models.Repository
 		headBranch string
 		isSameRepo bool
 		err        error
 	)
 
 	// If there is no head repository, it means pull request between same repository.
+	// The head repository may be given by its owner or its full name ({:owner}/{:repoName}:{:branch}).
 	headInfos := strings.Split(form.Head, ":")
 	if len(headInfos) == 1 {
 		isSameRepo = true
@@ -886,14 +888,32 @@ func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption)
 		headBranch = headInfos[0]
 
 	} else if len(headInfos) == 2 {
-		headUser, err = models.GetUserByName(headInfos[0])
-		if err != nil {
-			if models.IsErrUserNotExist(err) {
-				ctx.NotFound("GetUserByName")
-			} else {
-				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		if ownerAndName := strings.SplitN(headInfos[0], "/", 2); len(ownerAndName) == 2 {
+			headRepo, err = models.GetRepositoryByOwnerAndName(ownerAndName[0], ownerAndName[1])
+			if err != nil {
+				if models.IsErrRepoNotExist(err) {
+					ctx.NotFound("GetRepositoryByOwnerAndName")
+				} else {
+					ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+				}
+				return nil, nil, nil, nil, "", ""
+			}
+			if err = headRepo.GetOwner(); err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetOwner", err)
+				return nil, nil, nil, nil, "", ""
+			}
+			headUser = headRepo.Owner
+			isSameRepo = headRepo.ID == baseRepo.ID
+		} else {
+			headUser, err = models.GetUserByName(headInfos[0])
+			if err != nil {
+				if models.IsErrUserNotExist(err) {
+					ctx.NotFound("GetUserByName")
+				} else {
+					ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+				}
+				return nil, nil, nil, nil, "", ""
 			}
-			return nil, nil, nil, nil, "", ""
 		}
 		headBranch = headInfos[1]
 
@@ -911,8 +931,29 @@ func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption)
 		return nil, nil, nil, nil, "", ""
 	}
 
-	// Check if current user has fork of repository or in the same repository.
-	headRepo, has := models.HasForkedRepo(headUser.ID, baseRepo.ID)
+	// Find the head repository within the fork network of the base repository: a fork of it,
+	// the repository it was forked from or another fork of that repository.
+	has := headRepo != nil
+	if has && !isSameRepo && headRepo.ForkID != baseRepo.ID && baseRepo.ForkID != headRepo.ID &&
+		(!baseRepo.IsFork || headRepo.ForkID != baseRepo.ForkID) {
+		log.Trace("parseCompareInfo[%d]: head repository %d is not in the fork network", baseRepo.ID, headRepo.ID)
+		ctx.NotFound("HasForkedRepo")
+		return nil, nil, nil, nil, "", ""
+	}
+	if !has {
+		headRepo, has = models.HasForkedRepo(headUser.ID, baseRepo.ID)
+	}
+	if !has && baseRepo.IsFork {
+		if err = baseRepo.GetBaseRepo(); err != nil && !models.IsErrRepoNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "GetBaseRepo", err)
+			return nil, nil, nil, nil, "", ""
+		}
+		if baseRepo.BaseRepo != nil && baseRepo.BaseRepo.OwnerID == headUser.ID {
+			headRepo, has = baseRepo.BaseRepo, true
+		} else {
+			headRepo, has = models.HasForkedRepo(headUser.ID, baseRepo.ForkID)
+		}
+	}
 	if !has && !isSameRepo {
 		log.Trace("parseCompareInfo[%d]: does not have fork or in same repository", baseRepo.ID)
 		ctx.NotFound("HasForkedRepo")
@@ -976,7 +1017,7 @@ func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption)
 		return nil, nil, nil, nil, "", ""
 	}
 
-	compareInfo, err := headGitRepo.GetCompareInfo(models.RepoPath(baseRepo.Owner.Name, baseRepo.Name), baseBranch, headBranch)
+	compareInfo, err := headGitRepo.GetCompareInfo(models.RepoPath(baseRepo.Owner.Name, baseRepo.Name), baseBranch, headBranch, false)
 	if err != nil {
 		headGitRepo.Close()
 		ctx.Error(http.StatusInternalServerError, "GetCompareInfo", err)
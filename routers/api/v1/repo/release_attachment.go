@@ -5,6 +5,7 @@
 package repo
 
 import (
+	"fmt"
 	"net/http"
 
 	"code.gitea.io/gitea/models"
@@ -196,6 +197,7 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	// Create a new attachment and save the file
 	attach, err := models.NewAttachment(&models.Attachment{
 		UploaderID: ctx.User.ID,
+		RepoID:     release.RepoID,
 		Name:       filename,
 		ReleaseID:  release.ID,
 	}, buf, file)
@@ -207,6 +209,98 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, convert.ToReleaseAttachment(attach))
 }
 
+// CreateReleaseAttachmentSignedURL creates a download URL of a release attachment which is valid without signing in
+func CreateReleaseAttachmentSignedURL(ctx *context.APIContext, form api.CreateSignedURLOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/releases/{id}/assets/{attachment_id}/signed_url repository repoCreateReleaseAttachmentSignedURL
+	// ---
+	// summary: Create a short-lived download URL of a release attachment
+	// description: The URL allows to download the attachment without signing in until it expires, e.g. to pass it to other steps of a CI/CD pipeline.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the release
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: attachment_id
+	//   in: path
+	//   description: id of the attachment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateSignedURLOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/SignedURL"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	releaseID := ctx.ParamsInt64(":id")
+	release, err := models.GetReleaseByID(releaseID)
+	if err != nil {
+		if models.IsErrReleaseNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetReleaseByID", err)
+		}
+		return
+	}
+	if release.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return
+	}
+
+	attachID := ctx.ParamsInt64(":asset")
+	attach, err := models.GetAttachmentByID(attachID)
+	if err != nil {
+		if models.IsErrAttachmentNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetAttachmentByID", err)
+		}
+		return
+	}
+	if attach.ReleaseID != releaseID {
+		log.Info("User requested attachment is not in release, release_id %v, attachment_id: %v", releaseID, attachID)
+		ctx.NotFound()
+		return
+	}
+
+	minutes := form.ExpiresIn
+	if minutes == 0 {
+		minutes = setting.Attachment.SignedURLDefaultExpiry
+	}
+	if minutes < 0 || minutes > setting.Attachment.SignedURLMaxExpiry {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("expires_in must be between 1 and %d minutes", setting.Attachment.SignedURLMaxExpiry))
+		return
+	}
+
+	token, expires := attach.CreateDownloadToken(minutes)
+	ctx.JSON(http.StatusCreated, &api.SignedURL{
+		URL:     attach.SignedDownloadURL(token),
+		Expires: expires,
+	})
+}
+
 // EditReleaseAttachment updates the given attachment
 func EditReleaseAttachment(ctx *context.APIContext, form api.EditAttachmentOptions) {
 	// swagger:operation PATCH /repos/{owner}/{repo}/releases/{id}/assets/{attachment_id} repository repoEditReleaseAttachment
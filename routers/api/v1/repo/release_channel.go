@@ -0,0 +1,304 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getReleaseChannelByParams returns the release channel of the request, it writes the error response on failure
+func getReleaseChannelByParams(ctx *context.APIContext) *models.ReleaseChannel {
+	channel, err := models.GetReleaseChannelByName(ctx.Repo.Repository.ID, ctx.Params(":channel"))
+	if err != nil {
+		if models.IsErrReleaseChannelNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetReleaseChannelByName", err)
+		}
+		return nil
+	}
+	return channel
+}
+
+// ListReleaseChannels list a repository's release channels
+func ListReleaseChannels(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/releases/channels repository repoListReleaseChannels
+	// ---
+	// summary: List a repo's release channels
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReleaseChannelList"
+
+	channels, err := models.GetReleaseChannels(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetReleaseChannels", err)
+		return
+	}
+	apiChannels := make([]*api.ReleaseChannel, len(channels))
+	for i := range channels {
+		apiChannels[i] = convert.ToReleaseChannel(channels[i])
+	}
+	ctx.JSON(http.StatusOK, apiChannels)
+}
+
+// GetReleaseChannel get a release channel of a repository
+func GetReleaseChannel(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/releases/channels/{channel} repository repoGetReleaseChannel
+	// ---
+	// summary: Get a release channel
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: channel
+	//   in: path
+	//   description: name of the release channel
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReleaseChannel"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	channel := getReleaseChannelByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToReleaseChannel(channel))
+}
+
+// GetReleaseChannelLatest get the latest release of a release channel
+func GetReleaseChannelLatest(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/releases/channels/{channel}/latest repository repoGetReleaseChannelLatest
+	// ---
+	// summary: Get the latest published release of a release channel
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: channel
+	//   in: path
+	//   description: name of the release channel
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Release"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	channel := getReleaseChannelByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	release, err := channel.GetLatestRelease()
+	if err != nil {
+		if models.IsErrReleaseNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetLatestRelease", err)
+		}
+		return
+	}
+	if err := release.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRelease(release))
+}
+
+// CreateReleaseChannel create a release channel
+func CreateReleaseChannel(ctx *context.APIContext, form api.CreateReleaseChannelOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/releases/channels repository repoCreateReleaseChannel
+	// ---
+	// summary: Create a release channel
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateReleaseChannelOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ReleaseChannel"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	channel := &models.ReleaseChannel{
+		RepoID:     ctx.Repo.Repository.ID,
+		Name:       form.Name,
+		TagPattern: form.TagPattern,
+		KeepLast:   form.KeepLast,
+	}
+	if err := models.CreateReleaseChannel(channel); err != nil {
+		if models.IsErrReleaseChannelAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "CreateReleaseChannel", err)
+		} else if models.IsErrInvalidReleaseChannel(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "CreateReleaseChannel", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateReleaseChannel", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToReleaseChannel(channel))
+}
+
+// EditReleaseChannel edit a release channel
+func EditReleaseChannel(ctx *context.APIContext, form api.EditReleaseChannelOption) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/releases/channels/{channel} repository repoEditReleaseChannel
+	// ---
+	// summary: Update a release channel
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: channel
+	//   in: path
+	//   description: name of the release channel to edit
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReleaseChannelOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReleaseChannel"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	channel := getReleaseChannelByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil {
+		channel.Name = *form.Name
+	}
+	if form.TagPattern != nil {
+		channel.TagPattern = *form.TagPattern
+	}
+	if form.KeepLast != nil {
+		channel.KeepLast = *form.KeepLast
+	}
+	if err := models.UpdateReleaseChannel(channel); err != nil {
+		if models.IsErrReleaseChannelAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "UpdateReleaseChannel", err)
+		} else if models.IsErrInvalidReleaseChannel(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "UpdateReleaseChannel", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateReleaseChannel", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToReleaseChannel(channel))
+}
+
+// DeleteReleaseChannel delete a release channel
+func DeleteReleaseChannel(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/releases/channels/{channel} repository repoDeleteReleaseChannel
+	// ---
+	// summary: Delete a release channel, its releases are kept
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: channel
+	//   in: path
+	//   description: name of the release channel to delete
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	channel := getReleaseChannelByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteReleaseChannel(channel.RepoID, channel.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteReleaseChannel", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
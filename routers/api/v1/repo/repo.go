@@ -9,13 +9,16 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/references"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/validation"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -90,6 +93,10 @@ func Search(ctx *context.APIContext) {
 	//   in: query
 	//   description: show only archived, non-archived or all repositories (defaults to all)
 	//   type: boolean
+	// - name: license
+	//   in: query
+	//   description: show only repositories with this detected SPDX license identifier
+	//   type: string
 	// - name: mode
 	//   in: query
 	//   description: type of repository to search for. Supported values are
@@ -136,6 +143,7 @@ func Search(ctx *context.APIContext) {
 		Template:           util.OptionalBoolNone,
 		StarredByID:        ctx.QueryInt64("starredBy"),
 		IncludeDescription: ctx.QueryBool("includeDesc"),
+		License:            ctx.QueryTrim("license"),
 	}
 
 	if ctx.Query("template") != "" {
@@ -234,7 +242,7 @@ func CreateUserRepo(ctx *context.APIContext, owner *models.User, opt api.CreateR
 	if opt.AutoInit && opt.Readme == "" {
 		opt.Readme = "Default"
 	}
-	repo, err := repo_service.CreateRepository(ctx.User, owner, models.CreateRepoOptions{
+	opts := models.CreateRepoOptions{
 		Name:          opt.Name,
 		Description:   opt.Description,
 		IssueLabels:   opt.IssueLabels,
@@ -246,12 +254,20 @@ func CreateUserRepo(ctx *context.APIContext, owner *models.User, opt api.CreateR
 		DefaultBranch: opt.DefaultBranch,
 		TrustModel:    models.ToTrustModel(opt.TrustModel),
 		IsTemplate:    opt.Template,
-	})
+	}
+	var repo *models.Repository
+	var err error
+	if opt.PresetID > 0 {
+		repo, err = repo_service.CreateRepositoryFromPreset(ctx.User, owner, opt.PresetID, opts)
+	} else {
+		repo, err = repo_service.CreateRepository(ctx.User, owner, opts)
+	}
 	if err != nil {
 		if models.IsErrRepoAlreadyExist(err) {
 			ctx.Error(http.StatusConflict, "", "The repository with the same name already exists.")
 		} else if models.IsErrNameReserved(err) ||
-			models.IsErrNamePatternNotAllowed(err) {
+			models.IsErrNamePatternNotAllowed(err) ||
+			models.IsErrRepoPresetNotExist(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "CreateRepository", err)
@@ -453,6 +469,7 @@ func Edit(ctx *context.APIContext, opts api.EditRepoOption) {
 	// swagger:operation PATCH /repos/{owner}/{repo} repository repoEdit
 	// ---
 	// summary: Edit a repository's properties. Only fields that are set will be changed.
+	// description: All fields are validated before any change is made, the errors of all invalid fields are returned together.
 	// produces:
 	// - application/json
 	// parameters:
@@ -478,7 +495,21 @@ func Edit(ctx *context.APIContext, opts api.EditRepoOption) {
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
 	//   "422":
-	//     "$ref": "#/responses/validationError"
+	//     "$ref": "#/responses/fieldValidationError"
+
+	if (opts.EnableHealthCheck != nil || opts.CloseIssuesViaCommitInAnyBranch != nil) && !ctx.User.IsAdmin {
+		ctx.Error(http.StatusForbidden, "", "Only site administrators can change enable_health_check and close_issues_via_commit_in_any_branch.")
+		return
+	}
+
+	errs, err := validateEditRepoOption(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "validateEditRepoOption", err)
+		return
+	} else if len(errs) > 0 {
+		ctx.FieldValidationError(errs)
+		return
+	}
 
 	if err := updateBasicProperties(ctx, opts); err != nil {
 		return
@@ -488,6 +519,10 @@ func Edit(ctx *context.APIContext, opts api.EditRepoOption) {
 		return
 	}
 
+	if err := updateMirrorSettings(ctx, opts); err != nil {
+		return
+	}
+
 	if opts.Archived != nil {
 		if err := updateRepoArchivedState(ctx, opts); err != nil {
 			return
@@ -497,10 +532,111 @@ func Edit(ctx *context.APIContext, opts api.EditRepoOption) {
 	ctx.JSON(http.StatusOK, ctx.Repo.Repository.APIFormat(ctx.Repo.AccessMode))
 }
 
-// updateBasicProperties updates the basic properties of a repo: Name, Description, Website and Visibility
+// invalidKeywords returns the quoted keywords which are not valid issue reference keywords
+func invalidKeywords(keywords []string) []string {
+	var invalid []string
+	for _, keyword := range keywords {
+		if !references.IsValidKeyword(keyword) {
+			invalid = append(invalid, fmt.Sprintf("%q", keyword))
+		}
+	}
+	return invalid
+}
+
+// validateEditRepoOption returns the errors of the invalid fields of the options keyed by their JSON names,
+// the same checks as the settings page of the repository
+func validateEditRepoOption(ctx *context.APIContext, opts api.EditRepoOption) (map[string]string, error) {
+	repo := ctx.Repo.Repository
+	errs := make(map[string]string)
+
+	if opts.Name != nil && repo.LowerName != strings.ToLower(*opts.Name) {
+		if err := models.IsUsableRepoName(*opts.Name); err != nil {
+			errs["name"] = err.Error()
+		} else if exist, err := models.IsRepositoryExist(ctx.Repo.Owner, *opts.Name); err != nil {
+			return nil, err
+		} else if exist {
+			errs["name"] = fmt.Sprintf("repo name is already taken [name: %s]", *opts.Name)
+		}
+	}
+
+	if opts.Website != nil && len(*opts.Website) > 0 && !validation.IsValidURL(*opts.Website) {
+		errs["website"] = "website must be a valid URL"
+	}
+
+	// when ForcePrivate enabled, you could change public repo to private, but only admin users can change private to public
+	if opts.Private != nil && !repo.IsFork && repo.IsPrivate && !*opts.Private && setting.Repository.ForcePrivate && !ctx.User.IsAdmin {
+		errs["private"] = "cannot change private repository to public"
+	}
+
+	if opts.AttachmentRetentionDays != nil && (*opts.AttachmentRetentionDays < 0 || *opts.AttachmentRetentionDays > 36500) {
+		errs["attachment_retention_days"] = "attachment retention days must be between 0 and 36500"
+	}
+
+	if opts.TrustModel != nil && models.ToTrustModel(*opts.TrustModel).String() != strings.ToLower(strings.TrimSpace(*opts.TrustModel)) {
+		errs["trust_model"] = "trust model must be one of default, collaborator, committer and collaboratorcommitter"
+	}
+
+	// the trackers and wikis are only validated where they are used by the update
+	if opts.HasIssues != nil && *opts.HasIssues {
+		if opts.ExternalTracker != nil && !models.UnitTypeExternalTracker.UnitGlobalDisabled() {
+			if !validation.IsValidExternalURL(opts.ExternalTracker.ExternalTrackerURL) {
+				errs["external_tracker.external_tracker_url"] = "External tracker URL not valid"
+			}
+			if len(opts.ExternalTracker.ExternalTrackerFormat) != 0 && !validation.IsValidExternalTrackerURLFormat(opts.ExternalTracker.ExternalTrackerFormat) {
+				errs["external_tracker.external_tracker_format"] = "External tracker URL format not valid"
+			}
+		} else if opts.ExternalTracker == nil && opts.InternalTracker != nil && !models.UnitTypeIssues.UnitGlobalDisabled() {
+			if !models.IssueCloseTrigger(opts.InternalTracker.CloseTrigger).IsValid() {
+				errs["internal_tracker.close_trigger"] = "Close trigger not valid"
+			}
+			if invalid := invalidKeywords(opts.InternalTracker.CloseKeywords); len(invalid) > 0 {
+				errs["internal_tracker.close_keywords"] = fmt.Sprintf("Keywords %s not valid", strings.Join(invalid, ", "))
+			}
+			if invalid := invalidKeywords(opts.InternalTracker.ReopenKeywords); len(invalid) > 0 {
+				errs["internal_tracker.reopen_keywords"] = fmt.Sprintf("Keywords %s not valid", strings.Join(invalid, ", "))
+			}
+		}
+	}
+
+	if opts.HasWiki != nil && *opts.HasWiki && opts.ExternalWiki != nil && !models.UnitTypeExternalWiki.UnitGlobalDisabled() &&
+		!validation.IsValidExternalURL(opts.ExternalWiki.ExternalWikiURL) {
+		errs["external_wiki.external_wiki_url"] = "External wiki URL not valid"
+	}
+
+	if !repo.IsMirror {
+		for field, set := range map[string]bool{
+			"mirror_interval":       opts.MirrorInterval != nil,
+			"mirror_enable_prune":   opts.MirrorEnablePrune != nil,
+			"mirror_release_assets": opts.MirrorReleaseAssets != nil,
+		} {
+			if set {
+				errs[field] = "repo is not a mirror"
+			}
+		}
+	} else {
+		if opts.MirrorInterval != nil {
+			interval, err := time.ParseDuration(*opts.MirrorInterval)
+			if err != nil || interval < 0 || (interval != 0 && interval < setting.Mirror.MinInterval) {
+				errs["mirror_interval"] = fmt.Sprintf("mirror interval must be 0 or a duration of at least %s", setting.Mirror.MinInterval)
+			}
+		}
+		if opts.MirrorReleaseAssets != nil && *opts.MirrorReleaseAssets && !setting.Mirror.EnableReleaseAssets {
+			errs["mirror_release_assets"] = "mirroring release assets is disabled"
+		}
+		if opts.Archived != nil {
+			errs["archived"] = "repo is a mirror, cannot archive/un-archive"
+		}
+	}
+
+	return errs, nil
+}
+
+// updateBasicProperties updates the basic properties of a repo: Name, Description, Website, Visibility,
+// signing and admin settings
 func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) error {
 	owner := ctx.Repo.Owner
 	repo := ctx.Repo.Repository
+
 	newRepoName := repo.Name
 	if opts.Name != nil {
 		newRepoName = *opts.Name
@@ -543,13 +679,6 @@ func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) err
 		}
 
 		visibilityChanged = repo.IsPrivate != *opts.Private
-		// when ForcePrivate enabled, you could change public repo to private, but only admin users can change private to public
-		if visibilityChanged && setting.Repository.ForcePrivate && !*opts.Private && !ctx.User.IsAdmin {
-			err := fmt.Errorf("cannot change private repository to public")
-			ctx.Error(http.StatusUnprocessableEntity, "Force Private enabled", err)
-			return err
-		}
-
 		repo.IsPrivate = *opts.Private
 	}
 
@@ -557,6 +686,22 @@ func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) err
 		repo.IsTemplate = *opts.Template
 	}
 
+	if opts.AttachmentRetentionDays != nil {
+		repo.AttachmentRetentionDays = *opts.AttachmentRetentionDays
+	}
+
+	if opts.TrustModel != nil {
+		repo.TrustModel = models.ToTrustModel(*opts.TrustModel)
+	}
+
+	if opts.EnableHealthCheck != nil {
+		repo.IsFsckEnabled = *opts.EnableHealthCheck
+	}
+
+	if opts.CloseIssuesViaCommitInAnyBranch != nil {
+		repo.CloseIssuesViaCommitInAnyBranch = *opts.CloseIssuesViaCommitInAnyBranch
+	}
+
 	// Default branch only updated if changed and exist
 	if opts.DefaultBranch != nil && repo.DefaultBranch != *opts.DefaultBranch && ctx.Repo.GitRepo.IsBranchExist(*opts.DefaultBranch) {
 		if err := ctx.Repo.GitRepo.SetDefaultBranch(*opts.DefaultBranch); err != nil {
@@ -587,18 +732,6 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 
 	if opts.HasIssues != nil {
 		if *opts.HasIssues && opts.ExternalTracker != nil && !models.UnitTypeExternalTracker.UnitGlobalDisabled() {
-			// Check that values are valid
-			if !validation.IsValidExternalURL(opts.ExternalTracker.ExternalTrackerURL) {
-				err := fmt.Errorf("External tracker URL not valid")
-				ctx.Error(http.StatusUnprocessableEntity, "Invalid external tracker URL", err)
-				return err
-			}
-			if len(opts.ExternalTracker.ExternalTrackerFormat) != 0 && !validation.IsValidExternalTrackerURLFormat(opts.ExternalTracker.ExternalTrackerFormat) {
-				err := fmt.Errorf("External tracker URL format not valid")
-				ctx.Error(http.StatusUnprocessableEntity, "Invalid external tracker URL format", err)
-				return err
-			}
-
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypeExternalTracker,
@@ -618,6 +751,10 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 					EnableTimetracker:                opts.InternalTracker.EnableTimeTracker,
 					AllowOnlyContributorsToTrackTime: opts.InternalTracker.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               opts.InternalTracker.EnableIssueDependencies,
+					DisableCloseKeywords:             opts.InternalTracker.DisableCloseKeywords,
+					CloseKeywords:                    opts.InternalTracker.CloseKeywords,
+					ReopenKeywords:                   opts.InternalTracker.ReopenKeywords,
+					CloseTrigger:                     models.IssueCloseTrigger(opts.InternalTracker.CloseTrigger),
 				}
 			} else if unit, err := repo.GetUnit(models.UnitTypeIssues); err != nil {
 				// Unit type doesn't exist so we make a new config file with default values
@@ -648,13 +785,6 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 
 	if opts.HasWiki != nil {
 		if *opts.HasWiki && opts.ExternalWiki != nil && !models.UnitTypeExternalWiki.UnitGlobalDisabled() {
-			// Check that values are valid
-			if !validation.IsValidExternalURL(opts.ExternalWiki.ExternalWikiURL) {
-				err := fmt.Errorf("External wiki URL not valid")
-				ctx.Error(http.StatusUnprocessableEntity, "", "Invalid external wiki URL")
-				return err
-			}
-
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypeExternalWiki,
@@ -747,16 +877,47 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 	return nil
 }
 
+// updateMirrorSettings updates the interval, pruning and release assets settings of a mirror
+func updateMirrorSettings(ctx *context.APIContext, opts api.EditRepoOption) error {
+	repo := ctx.Repo.Repository
+	if !repo.IsMirror || (opts.MirrorInterval == nil && opts.MirrorEnablePrune == nil && opts.MirrorReleaseAssets == nil) {
+		return nil
+	}
+
+	mirror, err := models.GetMirrorByRepoID(repo.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMirrorByRepoID", err)
+		return err
+	}
+	if opts.MirrorInterval != nil {
+		// already validated
+		mirror.Interval, _ = time.ParseDuration(*opts.MirrorInterval)
+		if mirror.Interval != 0 {
+			mirror.NextUpdateUnix = timeutil.TimeStampNow().AddDuration(mirror.Interval)
+		} else {
+			mirror.NextUpdateUnix = 0
+		}
+	}
+	if opts.MirrorEnablePrune != nil {
+		mirror.EnablePrune = *opts.MirrorEnablePrune
+	}
+	if opts.MirrorReleaseAssets != nil {
+		mirror.EnableReleaseAssets = *opts.MirrorReleaseAssets
+	}
+	if err := models.UpdateMirror(mirror); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateMirror", err)
+		return err
+	}
+
+	log.Trace("Repository mirror settings updated: %s/%s", ctx.Repo.Owner.Name, repo.Name)
+	return nil
+}
+
 // updateRepoArchivedState updates repo's archive state
 func updateRepoArchivedState(ctx *context.APIContext, opts api.EditRepoOption) error {
 	repo := ctx.Repo.Repository
 	// archive / un-archive
 	if opts.Archived != nil {
-		if repo.IsMirror {
-			err := fmt.Errorf("repo is a mirror, cannot archive/un-archive")
-			ctx.Error(http.StatusUnprocessableEntity, err.Error(), err)
-			return err
-		}
 		if *opts.Archived {
 			if err := repo.SetArchiveRepoState(*opts.Archived); err != nil {
 				log.Error("Tried to archive a repo: %s", err)
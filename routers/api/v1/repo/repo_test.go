@@ -80,3 +80,82 @@ func TestRepoEditNameChange(t *testing.T) {
 		ID: 1,
 	}, models.Cond("name = ?", opts.Name))
 }
+
+func TestRepoEditInvalidFields(t *testing.T) {
+	models.PrepareTestEnv(t)
+
+	ctx := test.MockContext(t, "user2/repo1")
+	test.LoadRepo(t, ctx, 1)
+	test.LoadUser(t, ctx, 2)
+	ctx.Repo.Owner = ctx.User
+	description := "not saved"
+	name := "repo2"
+	trustModel := "nobody"
+	interval := "1h"
+	hasWiki := true
+	opts := api.EditRepoOption{
+		Name:           &name,
+		Description:    &description,
+		TrustModel:     &trustModel,
+		MirrorInterval: &interval,
+		HasWiki:        &hasWiki,
+		ExternalWiki:   &api.ExternalWiki{ExternalWikiURL: "htp://wiki"},
+	}
+
+	Edit(&context.APIContext{Context: ctx, Org: nil}, opts)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, ctx.Resp.Status())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.EqualValues(t, "repo1", repo.Name)
+	assert.NotEqual(t, description, repo.Description)
+}
+
+func TestRepoEditSettings(t *testing.T) {
+	models.PrepareTestEnv(t)
+
+	ctx := test.MockContext(t, "user2/repo1")
+	test.LoadRepo(t, ctx, 1)
+	test.LoadUser(t, ctx, 2)
+	ctx.Repo.Owner = ctx.User
+	trustModel := "committer"
+	enableHealthCheck := false
+	opts := api.EditRepoOption{
+		TrustModel:        &trustModel,
+		EnableHealthCheck: &enableHealthCheck,
+	}
+
+	// only site administrators can change the admin settings
+	Edit(&context.APIContext{Context: ctx, Org: nil}, opts)
+	assert.EqualValues(t, http.StatusForbidden, ctx.Resp.Status())
+
+	ctx = test.MockContext(t, "user2/repo1")
+	test.LoadRepo(t, ctx, 1)
+	test.LoadUser(t, ctx, 1)
+	ctx.Repo.Owner = models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	Edit(&context.APIContext{Context: ctx, Org: nil}, opts)
+	assert.EqualValues(t, http.StatusOK, ctx.Resp.Status())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.EqualValues(t, models.CommitterTrustModel, repo.TrustModel)
+	assert.False(t, repo.IsFsckEnabled)
+}
+
+func TestValidateEditRepoOptionKeywords(t *testing.T) {
+	models.PrepareTestEnv(t)
+
+	ctx := test.MockContext(t, "user2/repo1")
+	test.LoadRepo(t, ctx, 1)
+	test.LoadUser(t, ctx, 2)
+	hasIssues := true
+	errs, err := validateEditRepoOption(&context.APIContext{Context: ctx, Org: nil}, api.EditRepoOption{
+		HasIssues: &hasIssues,
+		InternalTracker: &api.InternalTracker{
+			CloseKeywords:  []string{"closes", "bad keyword", "also bad!"},
+			ReopenKeywords: []string{"reopens"},
+		},
+	})
+	assert.NoError(t, err)
+	// all the invalid keywords are reported
+	assert.Equal(t, `Keywords "bad keyword", "also bad!" not valid`, errs["internal_tracker.close_keywords"])
+	assert.NotContains(t, errs, "internal_tracker.reopen_keywords")
+}
@@ -0,0 +1,30 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// FeatureFlag
+// swagger:response FeatureFlag
+type swaggerResponseFeatureFlag struct {
+	// in:body
+	Body api.FeatureFlag `json:"body"`
+}
+
+// FeatureFlagList
+// swagger:response FeatureFlagList
+type swaggerResponseFeatureFlagList struct {
+	// in:body
+	Body []api.FeatureFlag `json:"body"`
+}
+
+// FeatureFlagNameList
+// swagger:response FeatureFlagNameList
+type swaggerResponseFeatureFlagNameList struct {
+	// in:body
+	Body []string `json:"body"`
+}
@@ -22,6 +22,13 @@ type swaggerResponseIssueList struct {
 	Body []api.Issue `json:"body"`
 }
 
+// IssueReferenceSuggestionList
+// swagger:response IssueReferenceSuggestionList
+type swaggerResponseIssueReferenceSuggestionList struct {
+	// in:body
+	Body []api.IssueReferenceSuggestion `json:"body"`
+}
+
 // Comment
 // swagger:response Comment
 type swaggerResponseComment struct {
@@ -119,3 +126,10 @@ type swaggerReactionList struct {
 	// in:body
 	Body []api.Reaction `json:"body"`
 }
+
+// ContentTranslation
+// swagger:response ContentTranslation
+type swaggerContentTranslation struct {
+	// in:body
+	Body api.ContentTranslation `json:"body"`
+}
@@ -21,3 +21,10 @@ type swaggerResponseStringSlice struct {
 	// in:body
 	Body []string `json:"body"`
 }
+
+// FieldValidationError
+// swagger:response fieldValidationError
+type swaggerResponseFieldValidationError struct {
+	// in:body
+	Body api.APIFieldValidationError `json:"body"`
+}
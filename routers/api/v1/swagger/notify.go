@@ -28,3 +28,17 @@ type swaggerNotificationCount struct {
 	// in:body
 	Body api.NotificationCount `json:"body"`
 }
+
+// NotificationRule
+// swagger:response NotificationRule
+type swaggerNotificationRule struct {
+	// in:body
+	Body api.NotificationRule `json:"body"`
+}
+
+// NotificationRuleList
+// swagger:response NotificationRuleList
+type swaggerNotificationRuleList struct {
+	// in:body
+	Body []api.NotificationRule `json:"body"`
+}
@@ -30,6 +30,8 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	EditGitHookOption api.EditGitHookOption
+	// in:body
+	EditGitHookScriptOption api.EditGitHookScriptOption
 
 	// in:body
 	CreateIssueOption api.CreateIssueOption
@@ -78,6 +80,10 @@ type swaggerParameterBodies struct {
 	CreateReleaseOption api.CreateReleaseOption
 	// in:body
 	EditReleaseOption api.EditReleaseOption
+	// in:body
+	CreateReleaseChannelOption api.CreateReleaseChannelOption
+	// in:body
+	EditReleaseChannelOption api.EditReleaseChannelOption
 
 	// in:body
 	CreateRepoOption api.CreateRepoOption
@@ -87,6 +93,8 @@ type swaggerParameterBodies struct {
 	TransferRepoOption api.TransferRepoOption
 	// in:body
 	CreateForkOption api.CreateForkOption
+	// in:body
+	ReRootForkNetworkOption api.ReRootForkNetworkOption
 
 	// in:body
 	CreateStatusOption api.CreateStatusOption
@@ -111,6 +119,15 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditAttachmentOptions api.EditAttachmentOptions
 
+	// in:body
+	DeleteAttachmentsOption api.DeleteAttachmentsOption
+
+	// in:body
+	CreateSignedURLOption api.CreateSignedURLOption
+
+	// in:body
+	CreateGitCredentialOption api.CreateGitCredentialOption
+
 	// in:body
 	CreateFileOptions api.CreateFileOptions
 
@@ -138,6 +155,9 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditBranchProtectionOption api.EditBranchProtectionOption
 
+	// in:body
+	StatusCheckContextsOption api.StatusCheckContextsOption
+
 	// in:body
 	CreateOAuth2ApplicationOptions api.CreateOAuth2ApplicationOptions
 
@@ -155,4 +175,19 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	PullReviewRequestOptions api.PullReviewRequestOptions
+
+	// in:body
+	CreateRepoPresetOption api.CreateRepoPresetOption
+
+	// in:body
+	EditRepoPresetOption api.EditRepoPresetOption
+
+	// in:body
+	CreateNotificationRuleOption api.CreateNotificationRuleOption
+
+	// in:body
+	CreateFeatureFlagOption api.CreateFeatureFlagOption
+
+	// in:body
+	EditFeatureFlagOption api.EditFeatureFlagOption
 }
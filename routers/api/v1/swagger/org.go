@@ -22,6 +22,27 @@ type swaggerResponseOrganizationList struct {
 	Body []api.Organization `json:"body"`
 }
 
+// OrgMemberTwoFactorStatusList
+// swagger:response OrgMemberTwoFactorStatusList
+type swaggerResponseOrgMemberTwoFactorStatusList struct {
+	// in:body
+	Body []api.OrgMemberTwoFactorStatus `json:"body"`
+}
+
+// OutsideCollaboratorList
+// swagger:response OutsideCollaboratorList
+type swaggerResponseOutsideCollaboratorList struct {
+	// in:body
+	Body []api.OutsideCollaborator `json:"body"`
+}
+
+// TokenUsageList
+// swagger:response TokenUsageList
+type swaggerResponseTokenUsageList struct {
+	// in:body
+	Body []api.TokenUsage `json:"body"`
+}
+
 // Team
 // swagger:response Team
 type swaggerResponseTeam struct {
@@ -35,3 +56,17 @@ type swaggerResponseTeamList struct {
 	// in:body
 	Body []api.Team `json:"body"`
 }
+
+// RepoPreset
+// swagger:response RepoPreset
+type swaggerResponseRepoPreset struct {
+	// in:body
+	Body api.RepoPreset `json:"body"`
+}
+
+// RepoPresetList
+// swagger:response RepoPresetList
+type swaggerResponseRepoPresetList struct {
+	// in:body
+	Body []api.RepoPreset `json:"body"`
+}
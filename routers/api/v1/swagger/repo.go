@@ -22,6 +22,13 @@ type swaggerResponseRepositoryList struct {
 	Body []api.Repository `json:"body"`
 }
 
+// ForkNetworkRepositoryList
+// swagger:response ForkNetworkRepositoryList
+type swaggerResponseForkNetworkRepositoryList struct {
+	// in:body
+	Body []api.ForkNetworkRepository `json:"body"`
+}
+
 // Branch
 // swagger:response Branch
 type swaggerResponseBranch struct {
@@ -50,6 +57,13 @@ type swaggerResponseBranchProtectionList struct {
 	Body []api.BranchProtection `json:"body"`
 }
 
+// StatusCheckContextChangeList
+// swagger:response StatusCheckContextChangeList
+type swaggerResponseStatusCheckContextChangeList struct {
+	// in:body
+	Body []api.StatusCheckContextChange `json:"body"`
+}
+
 // TagList
 // swagger:response TagList
 type swaggerResponseTagList struct {
@@ -113,6 +127,20 @@ type swaggerResponseGitHookList struct {
 	Body []api.GitHook `json:"body"`
 }
 
+// GitHookScript
+// swagger:response GitHookScript
+type swaggerResponseGitHookScript struct {
+	// in:body
+	Body api.GitHookScript `json:"body"`
+}
+
+// GitHookScriptList
+// swagger:response GitHookScriptList
+type swaggerResponseGitHookScriptList struct {
+	// in:body
+	Body []api.GitHookScript `json:"body"`
+}
+
 // Release
 // swagger:response Release
 type swaggerResponseRelease struct {
@@ -127,6 +155,20 @@ type swaggerResponseReleaseList struct {
 	Body []api.Release `json:"body"`
 }
 
+// ReleaseChannel
+// swagger:response ReleaseChannel
+type swaggerResponseReleaseChannel struct {
+	// in:body
+	Body api.ReleaseChannel `json:"body"`
+}
+
+// ReleaseChannelList
+// swagger:response ReleaseChannelList
+type swaggerResponseReleaseChannelList struct {
+	// in:body
+	Body []api.ReleaseChannel `json:"body"`
+}
+
 // PullRequest
 // swagger:response PullRequest
 type swaggerResponsePullRequest struct {
@@ -204,6 +246,13 @@ type swaggerResponseAttachmentList struct {
 	Body []api.Attachment `json:"body"`
 }
 
+// RepoAttachmentList
+// swagger:response RepoAttachmentList
+type swaggerResponseRepoAttachmentList struct {
+	// in:body
+	Body []api.RepoAttachment `json:"body"`
+}
+
 // Attachment
 // swagger:response Attachment
 type swaggerResponseAttachment struct {
@@ -211,6 +260,27 @@ type swaggerResponseAttachment struct {
 	Body api.Attachment `json:"body"`
 }
 
+// SignedURL
+// swagger:response SignedURL
+type swaggerResponseSignedURL struct {
+	// in: body
+	Body api.SignedURL `json:"body"`
+}
+
+// RepoEvent
+// swagger:response RepoEvent
+type swaggerResponseRepoEvent struct {
+	// in: body
+	Body api.RepoEvent `json:"body"`
+}
+
+// GitCredential
+// swagger:response GitCredential
+type swaggerResponseGitCredential struct {
+	// in: body
+	Body api.GitCredential `json:"body"`
+}
+
 // GitTreeResponse
 // swagger:response GitTreeResponse
 type swaggerGitTreeResponse struct {
@@ -232,6 +302,13 @@ type swaggerCommit struct {
 	Body api.Commit `json:"body"`
 }
 
+// Compare
+// swagger:response Compare
+type swaggerCompare struct {
+	// in: body
+	Body api.Compare `json:"body"`
+}
+
 // CommitList
 // swagger:response CommitList
 type swaggerCommitList struct {
@@ -275,6 +352,13 @@ type swaggerContentsResponse struct {
 	Body api.ContentsResponse `json:"body"`
 }
 
+// RenderedFileResponse
+// swagger:response RenderedFileResponse
+type swaggerRenderedFileResponse struct {
+	// in: body
+	Body api.RenderedFileResponse `json:"body"`
+}
+
 // ContentsListResponse
 // swagger:response ContentsListResponse
 type swaggerContentsListResponse struct {
@@ -309,3 +393,24 @@ type swaggerLanguageStatistics struct {
 	// in: body
 	Body map[string]int64 `json:"body"`
 }
+
+// DirectoryCodeFrequencyList
+// swagger:response DirectoryCodeFrequencyList
+type swaggerDirectoryCodeFrequencyList struct {
+	// in: body
+	Body []api.DirectoryCodeFrequency `json:"body"`
+}
+
+// ProjectAnalytics
+// swagger:response ProjectAnalytics
+type swaggerProjectAnalytics struct {
+	// in: body
+	Body api.ProjectAnalytics `json:"body"`
+}
+
+// RepoArchiveStatus
+// swagger:response RepoArchiveStatus
+type swaggerRepoArchiveStatus struct {
+	// in: body
+	Body api.RepoArchiveStatus `json:"body"`
+}
@@ -23,6 +23,13 @@ type swaggerResponseUserList struct {
 	Body []api.User `json:"body"`
 }
 
+// DormantUserList
+// swagger:response DormantUserList
+type swaggerResponseDormantUserList struct {
+	// in:body
+	Body []api.DormantUser `json:"body"`
+}
+
 // EmailList
 // swagger:response EmailList
 type swaggerResponseEmailList struct {
@@ -0,0 +1,105 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListNotificationRules list the notification rules of the authenticated user
+func ListNotificationRules(ctx *context.APIContext) {
+	// swagger:operation GET /user/notification_rules user userListNotificationRules
+	// ---
+	// summary: List the notification rules of the authenticated user in the order they are evaluated
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NotificationRuleList"
+
+	rules, err := ctx.User.GetNotificationRules()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetNotificationRules", err)
+		return
+	}
+
+	apiRules := make([]*api.NotificationRule, len(rules))
+	for i := range rules {
+		apiRules[i] = convert.ToNotificationRule(rules[i])
+	}
+	ctx.JSON(http.StatusOK, apiRules)
+}
+
+// CreateNotificationRule create a notification rule for the authenticated user
+func CreateNotificationRule(ctx *context.APIContext, form api.CreateNotificationRuleOption) {
+	// swagger:operation POST /user/notification_rules user userCreateNotificationRule
+	// ---
+	// summary: Create a notification rule for the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateNotificationRuleOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/NotificationRule"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	rule := &models.NotificationRule{
+		OwnerID:     ctx.User.ID,
+		RepoPattern: form.RepoPattern,
+		Event:       models.NotificationRuleEvent(form.Event),
+		Action:      models.NotificationRuleAction(form.Action),
+		Priority:    form.Priority,
+	}
+	if err := models.CreateNotificationRule(rule); err != nil {
+		if models.IsErrInvalidNotificationRule(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateNotificationRule", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToNotificationRule(rule))
+}
+
+// DeleteNotificationRule delete a notification rule of the authenticated user
+func DeleteNotificationRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/notification_rules/{id} user userDeleteNotificationRule
+	// ---
+	// summary: Delete a notification rule of the authenticated user
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the rule to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteNotificationRule(ctx.User.ID, ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrNotificationRuleNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteNotificationRule", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -23,6 +23,7 @@ func listUserRepos(ctx *context.APIContext, u *models.User, private bool) {
 		Private:     private,
 		ListOptions: opts,
 		OrderBy:     "id ASC",
+		License:     ctx.QueryTrim("license"),
 	})
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "GetUserRepositories", err)
@@ -60,6 +61,10 @@ func ListUserRepos(ctx *context.APIContext) {
 	//   description: username of user
 	//   type: string
 	//   required: true
+	// - name: license
+	//   in: query
+	//   description: show only repositories with this detected SPDX license identifier
+	//   type: string
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -147,6 +152,10 @@ func ListOrgRepos(ctx *context.APIContext) {
 	//   description: name of the organization
 	//   type: string
 	//   required: true
+	// - name: license
+	//   in: query
+	//   description: show only repositories with this detected SPDX license identifier
+	//   type: string
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
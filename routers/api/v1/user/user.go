@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/featureflag"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 
@@ -136,6 +137,20 @@ func GetAuthenticatedUser(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, convert.ToUser(ctx.User, ctx.IsSigned, ctx.User != nil))
 }
 
+// GetMyFeatureFlags list the feature flags which are on for the authenticated user
+func GetMyFeatureFlags(ctx *context.APIContext) {
+	// swagger:operation GET /user/feature_flags user userListFeatureFlags
+	// ---
+	// summary: List the names of the feature flags which are on for the authenticated user
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FeatureFlagNameList"
+
+	ctx.JSON(http.StatusOK, featureflag.EnabledFlags(ctx.User))
+}
+
 // GetUserHeatmapData is the handler to get a users heatmap
 func GetUserHeatmapData(ctx *context.APIContext) {
 	// swagger:operation GET /users/{username}/heatmap user userGetHeatmapData
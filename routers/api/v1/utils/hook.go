@@ -6,6 +6,7 @@ package utils
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -95,12 +96,36 @@ func pullHook(events []string, event string) bool {
 	return com.IsSliceContainsStr(events, event) || com.IsSliceContainsStr(events, string(models.HookEventPullRequest))
 }
 
+// toTopicFilter sanitizes the topics of a webhook topic filter. If the topics are
+// invalid or the webhook does not belong to an organization, write to `ctx`
+// accordingly. Return (topics, ok)
+func toTopicFilter(ctx *context.APIContext, topics []string, orgID int64) ([]string, bool) {
+	if len(topics) == 0 {
+		return nil, true
+	}
+	if orgID == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "Topic filter is only supported for organization webhooks")
+		return nil, false
+	}
+
+	validTopics, invalidTopics := models.SanitizeAndValidateTopics(topics)
+	if len(invalidTopics) > 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("Invalid topics: %s", strings.Join(invalidTopics, ", ")))
+		return nil, false
+	}
+	return validTopics, true
+}
+
 // addHook add the hook specified by `form`, `orgID` and `repoID`. If there is
 // an error, write to `ctx` accordingly. Return (webhook, ok)
 func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID int64) (*models.Webhook, bool) {
 	if len(form.Events) == 0 {
 		form.Events = []string{"push"}
 	}
+	topicFilter, ok := toTopicFilter(ctx, form.TopicFilter, orgID)
+	if !ok {
+		return nil, false
+	}
 	w := &models.Webhook{
 		OrgID:       orgID,
 		RepoID:      repoID,
@@ -131,6 +156,7 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 				Release:              com.IsSliceContainsStr(form.Events, string(models.HookEventRelease)),
 			},
 			BranchFilter: form.BranchFilter,
+			TopicFilter:  topicFilter,
 		},
 		IsActive:     form.Active,
 		HookTaskType: models.ToHookTaskType(form.Type),
@@ -256,6 +282,13 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 	w.Repository = com.IsSliceContainsStr(form.Events, string(models.HookEventRepository))
 	w.Release = com.IsSliceContainsStr(form.Events, string(models.HookEventRelease))
 	w.BranchFilter = form.BranchFilter
+	if form.TopicFilter != nil {
+		topicFilter, ok := toTopicFilter(ctx, form.TopicFilter, w.OrgID)
+		if !ok {
+			return false
+		}
+		w.TopicFilter = topicFilter
+	}
 
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateEvent", err)
@@ -0,0 +1,139 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package events
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/eventsource"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// repoEventUnitType returns the unit of the repository a user needs to be able to read to receive the event
+func repoEventUnitType(event *api.RepoEvent) models.UnitType {
+	switch {
+	case event.IssueIndex > 0 && event.IsPull:
+		return models.UnitTypePullRequests
+	case event.IssueIndex > 0:
+		return models.UnitTypeIssues
+	case event.ReleaseID > 0:
+		return models.UnitTypeReleases
+	}
+	return models.UnitTypeCode
+}
+
+// reloadRepoPermission looks up the current permission of the user on the repository,
+// access may have been revoked or the repository made private since the stream was opened
+func reloadRepoPermission(repoID int64, user *models.User) (models.Permission, error) {
+	repo, err := models.GetRepositoryByID(repoID)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return models.Permission{}, nil
+		}
+		return models.Permission{}, err
+	}
+	return models.GetUserRepoPermission(repo, user)
+}
+
+// RepoEvents streams the events of the repository of the context as server-sent events,
+// only the events about the units of the repository the user can read are sent.
+// The permission is checked again for every event and ping, the stream is closed once
+// the user has lost access to the repository.
+func RepoEvents(ctx *context.Context) {
+	ctx.Resp.Header().Set("Content-Type", "text/event-stream")
+	ctx.Resp.Header().Set("Cache-Control", "no-cache")
+	ctx.Resp.Header().Set("Connection", "keep-alive")
+	ctx.Resp.Header().Set("X-Accel-Buffering", "no")
+	ctx.Resp.WriteHeader(http.StatusOK)
+
+	notify := ctx.Req.Context().Done()
+	ctx.Resp.Flush()
+
+	shutdownCtx := graceful.GetManager().ShutdownContext()
+
+	repoID := ctx.Repo.Repository.ID
+	messageChan := eventsource.GetManager().RegisterRepo(repoID)
+
+	unregister := func() {
+		eventsource.GetManager().UnregisterRepo(repoID, messageChan)
+		// ensure the messageChan is closed
+		for {
+			_, ok := <-messageChan
+			if !ok {
+				break
+			}
+		}
+	}
+
+	if _, err := ctx.Resp.Write([]byte("\n")); err != nil {
+		log.Error("Unable to write to EventStream: %v", err)
+		unregister()
+		return
+	}
+
+	timer := time.NewTicker(30 * time.Second)
+
+	perm := ctx.Repo.Permission
+	hasAccess := func() bool {
+		var err error
+		perm, err = reloadRepoPermission(repoID, ctx.User)
+		if err != nil {
+			log.Error("Unable to reload permission on repository %s: %v", ctx.Repo.Repository.FullName(), err)
+			return false
+		}
+		return perm.HasAccess()
+	}
+
+loop:
+	for {
+		select {
+		case <-timer.C:
+			if !hasAccess() {
+				go unregister()
+				break loop
+			}
+			event := &eventsource.Event{
+				Name: "ping",
+			}
+			if _, err := event.WriteTo(ctx.Resp); err != nil {
+				log.Error("Unable to write to EventStream of repository %s: %v", ctx.Repo.Repository.FullName(), err)
+				go unregister()
+				break loop
+			}
+			ctx.Resp.Flush()
+		case <-notify:
+			go unregister()
+			break loop
+		case <-shutdownCtx.Done():
+			go unregister()
+			break loop
+		case event, ok := <-messageChan:
+			if !ok {
+				break loop
+			}
+
+			if !hasAccess() {
+				go unregister()
+				break loop
+			}
+			if repoEvent, ok := event.Data.(*api.RepoEvent); ok && !perm.CanRead(repoEventUnitType(repoEvent)) {
+				continue
+			}
+
+			if _, err := event.WriteTo(ctx.Resp); err != nil {
+				log.Error("Unable to write to EventStream of repository %s: %v", ctx.Repo.Repository.FullName(), err)
+				go unregister()
+				break loop
+			}
+			ctx.Resp.Flush()
+		}
+	}
+	timer.Stop()
+}
@@ -0,0 +1,37 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package events
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadRepoPermission(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 4}).(*models.Repository)
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	repo.IsPrivate = true
+	assert.NoError(t, models.UpdateRepository(repo, true))
+
+	perm, err := reloadRepoPermission(repo.ID, user)
+	assert.NoError(t, err)
+	assert.True(t, perm.HasAccess())
+
+	// the stream notices the collaborator was removed
+	assert.NoError(t, repo.DeleteCollaboration(user.ID))
+	perm, err = reloadRepoPermission(repo.ID, user)
+	assert.NoError(t, err)
+	assert.False(t, perm.HasAccess())
+
+	// and that the repository is gone
+	perm, err = reloadRepoPermission(1000, user)
+	assert.NoError(t, err)
+	assert.False(t, perm.HasAccess())
+}
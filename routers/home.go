@@ -135,6 +135,8 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 	keyword := strings.Trim(ctx.Query("q"), " ")
 	topicOnly := ctx.QueryBool("topic")
 	ctx.Data["TopicOnly"] = topicOnly
+	license := ctx.QueryTrim("license")
+	ctx.Data["License"] = license
 
 	repos, count, err = models.SearchRepository(&models.SearchRepoOptions{
 		ListOptions: models.ListOptions{
@@ -149,6 +151,7 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 		AllPublic:          true,
 		AllLimited:         true,
 		TopicOnly:          topicOnly,
+		License:            license,
 		IncludeDescription: setting.UI.SearchRepoDescription,
 	})
 	if err != nil {
@@ -163,6 +166,7 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 	pager := context.NewPagination(int(count), opts.PageSize, page, 5)
 	pager.SetDefaultParams(ctx)
 	pager.AddParam(ctx, "topic", "TopicOnly")
+	pager.AddParam(ctx, "license", "License")
 	ctx.Data["Page"] = pager
 
 	ctx.HTML(200, opts.TplName)
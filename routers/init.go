@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/auth/sso"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/cron"
+	"code.gitea.io/gitea/modules/eventarchive"
 	"code.gitea.io/gitea/modules/eventsource"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/highlight"
@@ -33,10 +34,14 @@ import (
 	"code.gitea.io/gitea/modules/svg"
 	"code.gitea.io/gitea/modules/task"
 	"code.gitea.io/gitea/modules/webhook"
+	archiver_service "code.gitea.io/gitea/services/archiver"
+	issuesync_service "code.gitea.io/gitea/services/issuesync"
 	"code.gitea.io/gitea/services/mailer"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	pull_service "code.gitea.io/gitea/services/pull"
+	"code.gitea.io/gitea/services/replication"
 	"code.gitea.io/gitea/services/repository"
+	"code.gitea.io/gitea/services/tokenusage"
 
 	"gitea.com/macaron/i18n"
 	"gitea.com/macaron/macaron"
@@ -188,16 +193,32 @@ func GlobalInit(ctx context.Context) {
 	models.NewRepoContext()
 
 	// Booting long running goroutines.
+	if err := replication.Init(); err != nil {
+		log.Fatal("Failed to initialize replica sync queue: %v", err)
+	}
 	cron.NewContext()
 	issue_indexer.InitIssueIndexer(false)
 	code_indexer.Init()
 	if err := stats_indexer.Init(); err != nil {
 		log.Fatal("Failed to initialize repository stats indexer queue: %v", err)
 	}
-	mirror_service.InitSyncMirrors()
-	webhook.InitDeliverHooks()
-	if err := pull_service.Init(); err != nil {
-		log.Fatal("Failed to initialize test pull requests queue: %v", err)
+	// Read replicas leave mirrors, webhook deliveries, pull request checks and issue syncs to the primary
+	if !setting.Replication.IsReplica {
+		mirror_service.InitSyncMirrors()
+		webhook.InitDeliverHooks()
+		if err := pull_service.Init(); err != nil {
+			log.Fatal("Failed to initialize test pull requests queue: %v", err)
+		}
+		if err := issuesync_service.Init(); err != nil {
+			log.Fatal("Failed to initialize issue sync queue: %v", err)
+		}
+	}
+	if err := eventarchive.Init(); err != nil {
+		log.Fatal("Failed to initialize event archive queue: %v", err)
+	}
+	tokenusage.Init()
+	if err := archiver_service.Init(); err != nil {
+		log.Fatal("Failed to initialize repository archive queue: %v", err)
 	}
 	if err := task.Init(); err != nil {
 		log.Fatal("Failed to initialize task scheduler: %v", err)
@@ -18,6 +18,8 @@ import (
 const (
 	// tplMembers template for organization members page
 	tplMembers base.TplName = "org/member/members"
+	// tplOutsideCollaborators template for organization outside collaborators page
+	tplOutsideCollaborators base.TplName = "org/member/outside_collaborators"
 )
 
 // Members render organization users page
@@ -68,6 +70,40 @@ func Members(ctx *context.Context) {
 	ctx.HTML(200, tplMembers)
 }
 
+// OutsideCollaborators render the page listing the access of users who are collaborators
+// on repositories of the organization without being a member of it
+func OutsideCollaborators(ctx *context.Context) {
+	org := ctx.Org.Organization
+	ctx.Data["Title"] = org.FullName
+	ctx.Data["PageIsOrgMembers"] = true
+	ctx.Data["PageIsOrgOutsideCollaborators"] = true
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	total, err := org.CountOutsideCollaborations()
+	if err != nil {
+		ctx.ServerError("CountOutsideCollaborations", err)
+		return
+	}
+
+	collaborations, err := org.GetOutsideCollaborations(models.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.MembersPagingNum,
+	})
+	if err != nil {
+		ctx.ServerError("GetOutsideCollaborations", err)
+		return
+	}
+	ctx.Data["Collaborations"] = collaborations
+	ctx.Data["Total"] = total
+	ctx.Data["Page"] = context.NewPagination(int(total), setting.UI.MembersPagingNum, page, 5)
+
+	ctx.HTML(200, tplOutsideCollaborators)
+}
+
 // MembersAction response for operation to a member of organization
 func MembersAction(ctx *context.Context) {
 	uid := com.StrTo(ctx.Query("uid")).MustInt64()
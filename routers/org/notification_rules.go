@@ -0,0 +1,88 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+)
+
+const (
+	// tplSettingsNotificationRules template path for render the notification rules of an organization
+	tplSettingsNotificationRules base.TplName = "org/settings/notification_rules"
+)
+
+func loadNotificationRulesData(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings.notification_rules")
+	ctx.Data["PageIsSettingsNotificationRules"] = true
+	ctx.Data["IsOrgNotificationRules"] = true
+	ctx.Data["NotificationRulesLink"] = ctx.Org.OrgLink + "/settings/notification_rules"
+	ctx.Data["NotificationRuleEvents"] = models.NotificationRuleEvents
+	ctx.Data["NotificationRuleActions"] = models.NotificationRuleActions
+
+	rules, err := ctx.Org.Organization.GetNotificationRules()
+	if err != nil {
+		ctx.ServerError("GetNotificationRules", err)
+		return
+	}
+	ctx.Data["NotificationRules"] = rules
+}
+
+// NotificationRules render the notification rules of an organization
+func NotificationRules(ctx *context.Context) {
+	loadNotificationRulesData(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplSettingsNotificationRules)
+}
+
+// NotificationRulesPost response for adding a notification rule of an organization
+func NotificationRulesPost(ctx *context.Context, form auth.NotificationRuleForm) {
+	loadNotificationRulesData(ctx)
+	if ctx.Written() {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsNotificationRules)
+		return
+	}
+
+	if err := models.CreateNotificationRule(&models.NotificationRule{
+		OwnerID:     ctx.Org.Organization.ID,
+		RepoPattern: form.RepoPattern,
+		Event:       models.NotificationRuleEvent(form.Event),
+		Action:      models.NotificationRuleAction(form.Action),
+		Priority:    form.Priority,
+	}); err != nil {
+		if models.IsErrInvalidNotificationRule(err) {
+			ctx.Data["Err_RepoPattern"] = true
+			ctx.RenderWithErr(ctx.Tr("settings.notification_rules.invalid", err.(models.ErrInvalidNotificationRule).Reason), tplSettingsNotificationRules, &form)
+		} else {
+			ctx.ServerError("CreateNotificationRule", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.notification_rules.add_success"))
+	ctx.Redirect(ctx.Org.OrgLink + "/settings/notification_rules")
+}
+
+// DeleteNotificationRule response for deleting a notification rule of an organization
+func DeleteNotificationRule(ctx *context.Context) {
+	if err := models.DeleteNotificationRule(ctx.Org.Organization.ID, ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteNotificationRule: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.notification_rules.deletion_success"))
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": ctx.Org.OrgLink + "/settings/notification_rules",
+	})
+}
@@ -0,0 +1,206 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+)
+
+const (
+	// tplSettingsRepoPresets template path for render the repository presets of an organization
+	tplSettingsRepoPresets base.TplName = "org/settings/repo_presets"
+	// tplSettingsRepoPresetNew template path for render creating or editing a repository preset
+	tplSettingsRepoPresetNew base.TplName = "org/settings/repo_preset_new"
+)
+
+// splitPresetList splits a list of values separated by commas or new lines
+func splitPresetList(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+	values := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); len(field) > 0 {
+			values = append(values, field)
+		}
+	}
+	return values
+}
+
+func prepareRepoPresetForm(ctx *context.Context, preset *models.RepoPreset) {
+	ctx.Data["Title"] = ctx.Tr("org.settings.repo_presets")
+	ctx.Data["PageIsSettingsRepoPresets"] = true
+	ctx.Data["Preset"] = preset
+	ctx.Data["LabelTemplates"] = models.LabelTemplates
+
+	units := make([]models.Unit, 0, len(models.DefaultRepoUnits))
+	for _, tp := range models.DefaultRepoUnits {
+		if tp.CanDisable() && !tp.UnitGlobalDisabled() {
+			units = append(units, models.Units[tp])
+		}
+	}
+	ctx.Data["PresetUnits"] = units
+
+	if err := ctx.Org.Organization.GetTeams(&models.SearchTeamOptions{}); err != nil {
+		ctx.ServerError("GetTeams", err)
+		return
+	}
+	ctx.Data["Teams"] = ctx.Org.Organization.Teams
+
+	webhookURLs := make([]string, 0, len(preset.Webhooks))
+	for _, w := range preset.Webhooks {
+		webhookURLs = append(webhookURLs, w.URL)
+	}
+	ctx.Data["topics"] = strings.Join(preset.Topics, ",")
+	ctx.Data["status_check_contexts"] = strings.Join(preset.StatusCheckContexts, "\n")
+	ctx.Data["webhooks"] = strings.Join(webhookURLs, "\n")
+}
+
+func saveRepoPresetForm(ctx *context.Context, preset *models.RepoPreset, form auth.RepoPresetForm) {
+	preset.Name = form.Name
+	preset.Description = form.Description
+	preset.TeamID = form.TeamID
+	preset.Units = form.Units
+	preset.IssueLabels = form.IssueLabels
+	preset.Topics = splitPresetList(form.Topics)
+	preset.ProtectDefaultBranch = form.ProtectDefaultBranch
+	preset.RequiredApprovals = form.RequiredApprovals
+	preset.StatusCheckContexts = splitPresetList(form.StatusCheckContexts)
+
+	// keep the secrets and events of the webhooks whose URL did not change, they are only managed by the API
+	existing := make(map[string]*models.RepoPresetWebhook, len(preset.Webhooks))
+	for _, w := range preset.Webhooks {
+		existing[w.URL] = w
+	}
+	webhookURLs := splitPresetList(form.Webhooks)
+	preset.Webhooks = make([]*models.RepoPresetWebhook, 0, len(webhookURLs))
+	for _, url := range webhookURLs {
+		w, ok := existing[url]
+		if !ok {
+			w = &models.RepoPresetWebhook{URL: url, ContentType: models.ContentTypeJSON}
+		}
+		preset.Webhooks = append(preset.Webhooks, w)
+	}
+
+	prepareRepoPresetForm(ctx, preset)
+	if ctx.Written() {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsRepoPresetNew)
+		return
+	}
+
+	var err error
+	if preset.ID == 0 {
+		err = models.CreateRepoPreset(preset)
+	} else {
+		err = models.UpdateRepoPreset(preset)
+	}
+	if err != nil {
+		switch {
+		case models.IsErrRepoPresetAlreadyExist(err):
+			ctx.Data["Err_Name"] = true
+			ctx.RenderWithErr(ctx.Tr("org.settings.repo_presets.name_been_taken"), tplSettingsRepoPresetNew, &form)
+		case models.IsErrInvalidRepoPreset(err):
+			ctx.RenderWithErr(ctx.Tr("org.settings.repo_presets.invalid", err.(models.ErrInvalidRepoPreset).Reason), tplSettingsRepoPresetNew, &form)
+		default:
+			ctx.ServerError("SaveRepoPreset", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("org.settings.repo_presets.save_success"))
+	ctx.Redirect(ctx.Org.OrgLink + "/settings/repo_presets")
+}
+
+// RepoPresets render the repository presets of an organization
+func RepoPresets(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings.repo_presets")
+	ctx.Data["PageIsSettingsRepoPresets"] = true
+
+	presets, err := ctx.Org.Organization.GetRepoPresets()
+	if err != nil {
+		ctx.ServerError("GetRepoPresets", err)
+		return
+	}
+	for _, preset := range presets {
+		if err := preset.LoadTeam(); err != nil {
+			ctx.ServerError("LoadTeam", err)
+			return
+		}
+	}
+	ctx.Data["Presets"] = presets
+
+	ctx.HTML(http.StatusOK, tplSettingsRepoPresets)
+}
+
+// NewRepoPreset render creating a repository preset
+func NewRepoPreset(ctx *context.Context) {
+	prepareRepoPresetForm(ctx, &models.RepoPreset{OrgID: ctx.Org.Organization.ID})
+	if ctx.Written() {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplSettingsRepoPresetNew)
+}
+
+// NewRepoPresetPost response for creating a repository preset
+func NewRepoPresetPost(ctx *context.Context, form auth.RepoPresetForm) {
+	saveRepoPresetForm(ctx, &models.RepoPreset{OrgID: ctx.Org.Organization.ID}, form)
+}
+
+func getRepoPreset(ctx *context.Context) *models.RepoPreset {
+	preset, err := models.GetRepoPresetByID(ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrRepoPresetNotExist(err) {
+			ctx.NotFound("GetRepoPresetByID", err)
+		} else {
+			ctx.ServerError("GetRepoPresetByID", err)
+		}
+		return nil
+	}
+	return preset
+}
+
+// EditRepoPreset render editing a repository preset
+func EditRepoPreset(ctx *context.Context) {
+	preset := getRepoPreset(ctx)
+	if ctx.Written() {
+		return
+	}
+	prepareRepoPresetForm(ctx, preset)
+	if ctx.Written() {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplSettingsRepoPresetNew)
+}
+
+// EditRepoPresetPost response for editing a repository preset
+func EditRepoPresetPost(ctx *context.Context, form auth.RepoPresetForm) {
+	preset := getRepoPreset(ctx)
+	if ctx.Written() {
+		return
+	}
+	saveRepoPresetForm(ctx, preset, form)
+}
+
+// DeleteRepoPreset response for deleting a repository preset
+func DeleteRepoPreset(ctx *context.Context) {
+	if err := models.DeleteRepoPreset(ctx.Org.Organization.ID, ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteRepoPreset: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("org.settings.repo_presets.deletion_success"))
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": ctx.Org.OrgLink + "/settings/repo_presets",
+	})
+}
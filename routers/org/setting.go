@@ -6,6 +6,7 @@
 package org
 
 import (
+	"net/http"
 	"strings"
 
 	"code.gitea.io/gitea/models"
@@ -26,6 +27,10 @@ const (
 	tplSettingsHooks base.TplName = "org/settings/hooks"
 	// tplSettingsLabels template path for render labels settings
 	tplSettingsLabels base.TplName = "org/settings/labels"
+	// tplSettingsSecurity template path for render security settings
+	tplSettingsSecurity base.TplName = "org/settings/security"
+	// tplSettingsTokenUsage template path for render the token usage report
+	tplSettingsTokenUsage base.TplName = "org/settings/token_usage"
 )
 
 // Settings render the main settings page
@@ -206,3 +211,104 @@ func Labels(ctx *context.Context) {
 	ctx.Data["LabelTemplates"] = models.LabelTemplates
 	ctx.HTML(200, tplSettingsLabels)
 }
+
+// SettingsSecurity render the two-factor authentication requirement and the compliance of the members
+func SettingsSecurity(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsSecurity"] = true
+
+	statuses, err := ctx.Org.Organization.GetMembersTwoFactorStatus()
+	if err != nil {
+		ctx.ServerError("GetMembersTwoFactorStatus", err)
+		return
+	}
+	var numCompliant int
+	for _, status := range statuses {
+		if status.HasTwoFactor {
+			numCompliant++
+		}
+	}
+	ctx.Data["MemberStatuses"] = statuses
+	ctx.Data["NumCompliant"] = numCompliant
+	ctx.Data["NumNonCompliant"] = len(statuses) - numCompliant
+
+	ctx.HTML(200, tplSettingsSecurity)
+}
+
+// SettingsSecurityPost response for updating the two-factor authentication requirement
+func SettingsSecurityPost(ctx *context.Context, form auth.OrgTwoFactorForm) {
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(ctx.Org.OrgLink + "/settings/security")
+		return
+	}
+
+	org := ctx.Org.Organization
+	if err := org.SetTwoFactorRequirement(ctx.User, form.RequireTwoFactor, form.TwoFactorGraceDays); err != nil {
+		if models.IsErrTwoFactorNotEnrolled(err) {
+			ctx.Flash.Error(ctx.Tr("org.settings.two_factor.doer_not_enrolled"))
+			ctx.Redirect(ctx.Org.OrgLink + "/settings/security")
+			return
+		}
+		ctx.ServerError("SetTwoFactorRequirement", err)
+		return
+	}
+
+	log.Trace("Two-factor authentication requirement of organization %s updated: %v", org.Name, org.RequireTwoFactor)
+	ctx.Flash.Success(ctx.Tr("org.settings.update_setting_success"))
+	ctx.Redirect(ctx.Org.OrgLink + "/settings/security")
+}
+
+// SettingsTokenUsage render the API calls made with access tokens to the resources of the organization
+func SettingsTokenUsage(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings.token_usage")
+	ctx.Data["PageIsSettingsTokenUsage"] = true
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	opts := models.FindTokenUsagesOptions{
+		ListOptions: models.ListOptions{
+			Page:     page,
+			PageSize: setting.UI.MembersPagingNum,
+		},
+		OrgID: ctx.Org.Organization.ID,
+	}
+
+	userName := ctx.Query("user")
+	if len(userName) > 0 {
+		u, err := models.GetUserByName(userName)
+		if err != nil && !models.IsErrUserNotExist(err) {
+			ctx.ServerError("GetUserByName", err)
+			return
+		}
+		// an unknown user has no token usage
+		opts.UID = -1
+		if u != nil {
+			opts.UID = u.ID
+		}
+	}
+	ctx.Data["UserName"] = userName
+
+	var usages []*models.TokenUsage
+	var total int64
+	if opts.UID >= 0 {
+		var err error
+		if usages, total, err = models.FindTokenUsages(opts); err != nil {
+			ctx.ServerError("FindTokenUsages", err)
+			return
+		}
+		if err := models.TokenUsageList(usages).LoadAttributes(); err != nil {
+			ctx.ServerError("LoadAttributes", err)
+			return
+		}
+	}
+	ctx.Data["TokenUsages"] = usages
+
+	pager := context.NewPagination(int(total), setting.UI.MembersPagingNum, page, 5)
+	pager.AddParamString("user", userName)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplSettingsTokenUsage)
+}
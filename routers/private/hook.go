@@ -236,6 +236,11 @@ func HookPreReceive(ctx *macaron.Context, opts private.HookOptions) {
 			}
 		}
 
+		// Pushes which are turned into pull requests are checked by the pull request instead
+		if isPushToPullRequest(repo, protectBranch, &opts, oldCommitID, newCommitID) {
+			continue
+		}
+
 		// Now there are several tests which can be overridden:
 		//
 		// 4. Check protected file patterns - this is overridable from the UI
@@ -370,6 +375,18 @@ func HookPreReceive(ctx *macaron.Context, opts private.HookOptions) {
 	ctx.PlainText(http.StatusOK, []byte("ok"))
 }
 
+// isPushToPullRequest returns true if the push to the protected branch has to be turned into a pull request
+func isPushToPullRequest(repo *models.Repository, protectBranch *models.ProtectedBranch, opts *private.HookOptions, oldCommitID, newCommitID string) bool {
+	if protectBranch == nil || !protectBranch.IsProtected() || opts.IsDeployKey || opts.ProtectedBranchID > 0 {
+		return false
+	}
+	// Creating or deleting the branch can't be done by a pull request
+	if oldCommitID == git.EmptySHA || newCommitID == git.EmptySHA {
+		return false
+	}
+	return repo.AllowsPulls() && protectBranch.IsPushToPullRequest(opts.GitPushOptions.Bool(private.GitPushOptionPullRequest, false))
+}
+
 // HookPostReceive updates services and users
 func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 	ownerName := ctx.Params(":owner")
@@ -378,6 +395,8 @@ func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 	var repo *models.Repository
 	updates := make([]*repo_module.PushUpdateOptions, 0, len(opts.OldCommitIDs))
 	wasEmpty := false
+	results := make([]private.HookPostReceiveBranchResult, 0, len(opts.OldCommitIDs))
+	pushedToPullRequest := make(map[int]bool)
 
 	for i := range opts.OldCommitIDs {
 		refFullName := opts.RefFullNames[i]
@@ -412,6 +431,39 @@ func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 				RepoUserName: ownerName,
 				RepoName:     repoName,
 			}
+
+			if option.IsBranch() {
+				protectBranch, err := models.GetProtectedBranchBy(repo.ID, option.BranchName())
+				if err != nil {
+					log.Error("Unable to get protected branch: %s in %-v Error: %v", option.BranchName(), repo, err)
+					ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{
+						Err: fmt.Sprintf("Unable to get protected branch: %s in %-v Error: %v", option.BranchName(), repo, err),
+					})
+					return
+				}
+				if isPushToPullRequest(repo, protectBranch, &opts, option.OldCommitID, option.NewCommitID) {
+					pr, err := pushToPullRequest(repo, &opts, &option)
+					if err != nil {
+						log.Error("Failed to turn push to %s in %-v into a pull request: %v", option.BranchName(), repo, err)
+						ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{
+							Err: fmt.Sprintf("Failed to turn push to %s into a pull request: %v", option.BranchName(), err),
+						})
+						return
+					}
+					results = append(results, private.HookPostReceiveBranchResult{
+						Message:           true,
+						PushToPullRequest: true,
+						Branch:            option.BranchName(),
+						URL:               fmt.Sprintf("%s/pulls/%d", repo.HTMLURL(), pr.Index),
+					})
+					pushedToPullRequest[i] = true
+
+					// From now on this is the push of a new branch
+					option.RefFullName = git.BranchPrefix + pr.HeadBranch
+					option.OldCommitID = git.EmptySHA
+				}
+			}
+
 			updates = append(updates, &option)
 			if repo.IsEmpty && option.IsBranch() && option.BranchName() == "master" {
 				// put the master branch first
@@ -448,8 +500,6 @@ func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 		}
 	}
 
-	results := make([]private.HookPostReceiveBranchResult, 0, len(opts.OldCommitIDs))
-
 	// We have to reload the repo in case its state is changed above
 	repo = nil
 	var baseRepo *models.Repository
@@ -458,6 +508,10 @@ func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 		refFullName := opts.RefFullNames[i]
 		newCommitID := opts.NewCommitIDs[i]
 
+		if pushedToPullRequest[i] {
+			continue
+		}
+
 		branch := git.RefEndName(opts.RefFullNames[i])
 
 		if newCommitID != git.EmptySHA && strings.HasPrefix(refFullName, git.BranchPrefix) {
@@ -479,6 +533,7 @@ func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 				if !repo.AllowsPulls() {
 					// We can stop there's no need to go any further
 					ctx.JSON(http.StatusOK, private.HookPostReceiveResult{
+						Results:      results,
 						RepoWasEmpty: wasEmpty,
 					})
 					return
@@ -540,6 +595,22 @@ func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 	})
 }
 
+func pushToPullRequest(repo *models.Repository, opts *private.HookOptions, update *repo_module.PushUpdateOptions) (*models.PullRequest, error) {
+	pusher, err := models.GetUserByID(opts.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return pull_service.PushToPullRequest(&pull_service.PushToPullRequestOptions{
+		Repo:        repo,
+		Doer:        pusher,
+		BaseBranch:  update.BranchName(),
+		OldCommitID: update.OldCommitID,
+		NewCommitID: update.NewCommitID,
+		Title:       opts.GitPushOptions[private.GitPushOptionPullRequestTitle],
+		Description: opts.GitPushOptions[private.GitPushOptionPullRequestDescription],
+	})
+}
+
 // SetDefaultBranch updates the default branch
 func SetDefaultBranch(ctx *macaron.Context) {
 	ownerName := ctx.Params(":owner")
@@ -48,5 +48,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Post("/manager/add-logger", bind(private.LoggerOptions{}), AddLogger)
 		m.Post("/manager/remove-logger/:group/:name", RemoveLogger)
 		m.Post("/mail/send", SendEmail)
+		m.Post("/pull/check/claim", ClaimPullCheck)
+		m.Post("/pull/check/:id", bind(private.PullCheckResult{}), SubmitPullCheck)
+		m.Post("/replica/sync", bind(private.ReplicaSyncOptions{}), SyncReplica)
 	}, CheckInternalToken)
 }
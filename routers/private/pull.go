@@ -0,0 +1,59 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"gitea.com/macaron/macaron"
+)
+
+// ClaimPullCheck hands out the next pull request of a shard to an external merge checker
+func ClaimPullCheck(ctx *macaron.Context) {
+	if !setting.Repository.PullRequest.ExternalMergeChecker {
+		ctx.JSON(http.StatusConflict, map[string]interface{}{
+			"err": "External merge checkers are not enabled",
+		})
+		return
+	}
+
+	shard := ctx.QueryInt64("shard")
+	shards := ctx.QueryInt64("shards")
+	if shards < 1 || shard < 0 || shard >= shards {
+		ctx.JSON(http.StatusBadRequest, map[string]interface{}{
+			"err": fmt.Sprintf("Invalid shard %d of %d shards", shard, shards),
+		})
+		return
+	}
+
+	task, err := pull_service.ClaimExternalCheck(shard, shards)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"err": fmt.Sprintf("Unable to claim a pull request: %v", err),
+		})
+		return
+	}
+	if task == nil {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+	ctx.JSON(http.StatusOK, task)
+}
+
+// SubmitPullCheck stores the result of an external merge checker for a pull request
+func SubmitPullCheck(ctx *macaron.Context, result private.PullCheckResult) {
+	if err := pull_service.SubmitExternalCheck(ctx.ParamsInt64(":id"), &result); err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"err": fmt.Sprintf("Unable to store the result: %v", err),
+		})
+		return
+	}
+	ctx.PlainText(http.StatusOK, []byte("success"))
+}
@@ -0,0 +1,34 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/replication"
+
+	"gitea.com/macaron/macaron"
+)
+
+// SyncReplica applies a repository change the primary notified this read replica of
+func SyncReplica(ctx *macaron.Context, opts private.ReplicaSyncOptions) {
+	if !setting.Replication.IsReplica {
+		ctx.JSON(http.StatusConflict, map[string]interface{}{
+			"err": "This instance is not a read replica",
+		})
+		return
+	}
+
+	if err := replication.HandleSyncRequest(&opts); err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"err": fmt.Sprintf("Unable to sync repository %s/%s: %v", opts.OwnerName, opts.RepoName, err),
+		})
+		return
+	}
+	ctx.PlainText(http.StatusOK, []byte("success"))
+}
@@ -5,15 +5,18 @@
 package repo
 
 import (
+	"net/http"
 	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/indexer/stats"
 )
 
 const (
-	tplActivity base.TplName = "repo/activity"
+	tplActivity              base.TplName = "repo/activity"
+	tplActivityCodeFrequency base.TplName = "repo/activity_code_frequency"
 )
 
 // Activity render the page to show repository latest changes
@@ -100,3 +103,38 @@ func ActivityAuthors(ctx *context.Context) {
 
 	ctx.JSON(200, authors)
 }
+
+// codeFrequencyBar represents a directory in the code frequency chart
+type codeFrequencyBar struct {
+	*models.DirectoryCodeFrequency
+	// AdditionsPerc and DeletionsPerc are relative to the directory with the most changed lines
+	AdditionsPerc int
+	DeletionsPerc int
+}
+
+// ActivityCodeFrequency renders the lines added and deleted per top level directory of the last year
+func ActivityCodeFrequency(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.activity.code_frequency")
+	ctx.Data["PageIsActivity"] = true
+	ctx.Data["CodeFrequencyWeeks"] = models.CodeFrequencyWeeks
+
+	directories, ready, err := stats.GetCodeFrequency(ctx.Repo.Repository, ctx.Repo.CommitID)
+	if err != nil {
+		ctx.ServerError("GetCodeFrequency", err)
+		return
+	}
+	ctx.Data["CodeFrequencyReady"] = ready
+
+	bars := make([]*codeFrequencyBar, len(directories))
+	for i, d := range directories {
+		bars[i] = &codeFrequencyBar{DirectoryCodeFrequency: d}
+		// the directories are sorted by the changed lines, binary files have none
+		if maxChurn := directories[0].Churn(); maxChurn > 0 {
+			bars[i].AdditionsPerc = int(d.Additions * 100 / maxChurn)
+			bars[i].DeletionsPerc = int(d.Deletions * 100 / maxChurn)
+		}
+	}
+	ctx.Data["CodeFrequencies"] = bars
+
+	ctx.HTML(http.StatusOK, tplActivityCodeFrequency)
+}
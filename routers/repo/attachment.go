@@ -54,6 +54,7 @@ func uploadAttachment(ctx *context.Context, allowedTypes string) {
 
 	attach, err := models.NewAttachment(&models.Attachment{
 		UploaderID: ctx.User.ID,
+		RepoID:     ctx.Repo.Repository.ID,
 		Name:       header.Filename,
 	}, buf, file)
 	if err != nil {
@@ -124,6 +125,35 @@ func GetAttachment(ctx *context.Context) {
 		}
 	}
 
+	serveAttachment(ctx, attach)
+}
+
+// GetSignedAttachment serves the attachment without checking the access of the user
+// if the request carries a download token of the attachment
+func GetSignedAttachment(ctx *context.Context) {
+	token := ctx.Query("token")
+	if len(token) == 0 {
+		return
+	}
+
+	attach, err := models.GetAttachmentByUUID(ctx.Params(":uuid"))
+	if err != nil {
+		if models.IsErrAttachmentNotExist(err) {
+			ctx.Error(http.StatusNotFound)
+		} else {
+			ctx.ServerError("GetAttachmentByUUID", err)
+		}
+		return
+	}
+	if !attach.VerifyDownloadToken(token) {
+		ctx.Error(http.StatusForbidden, "invalid or expired download token")
+		return
+	}
+
+	serveAttachment(ctx, attach)
+}
+
+func serveAttachment(ctx *context.Context, attach *models.Attachment) {
 	if setting.Attachment.ServeDirect {
 		//If we have a signed url (S3, object storage), redirect to this directly.
 		u, err := storage.Attachments.URL(attach.RelativePath(), attach.Name)
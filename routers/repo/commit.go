@@ -335,6 +335,7 @@ func Diff(ctx *context.Context) {
 		}
 	}
 	setImageCompareContext(ctx, parentCommit, commit)
+	setPreviewCompareContext(ctx, parentCommit, commit)
 	headTarget := path.Join(userName, repoName)
 	setPathsCompareContext(ctx, parentCommit, commit, headTarget)
 	ctx.Data["Title"] = commit.Summary() + " · " + base.ShortSha(commitID)
@@ -342,6 +343,10 @@ func Diff(ctx *context.Context) {
 	verification := models.ParseCommitWithSignature(commit)
 	ctx.Data["Verification"] = verification
 	ctx.Data["Author"] = models.ValidateCommitWithEmail(commit)
+	ctx.Data["CoAuthors"] = models.GetCommitCoAuthors(commit)
+	ctx.Data["CommitTrailers"] = commit.Trailers()
+	// the trailers are listed separately, so they are not rendered as part of the body
+	ctx.Data["CommitMessage"] = git.StripCommitTrailers(commit.Message())
 	ctx.Data["Diff"] = diff
 	ctx.Data["Parents"] = parents
 	ctx.Data["DiffNotAvailable"] = diff.NumFiles == 0
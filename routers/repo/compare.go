@@ -8,6 +8,7 @@ import (
 	"bufio"
 	"fmt"
 	"html"
+	"html/template"
 	"path"
 	"strings"
 
@@ -16,8 +17,10 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/upload"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/gitdiff"
 )
 
@@ -65,6 +68,131 @@ func setImageCompareContext(ctx *context.Context, base *git.Commit, head *git.Co
 	}
 }
 
+// renderPreview renders the preview of a binary file at a commit, or an empty string if it cannot be previewed
+func renderPreview(commit *git.Commit, name, rawLink string) template.HTML {
+	if commit == nil || markup.GetPreviewerByFileName(name) == nil {
+		return ""
+	}
+	blob, err := commit.GetBlobByPath(name)
+	if err != nil {
+		return ""
+	}
+	if blob.Size() >= setting.UI.MaxDisplayFileSize {
+		return ""
+	}
+	dataRc, err := blob.DataAsync()
+	if err != nil {
+		log.Error("DataAsync failed: %v", err)
+		return ""
+	}
+	defer dataRc.Close()
+	result, err := markup.Preview(name, dataRc, rawLink)
+	if err != nil {
+		log.Error("Preview failed: %v", err)
+		return ""
+	}
+	return template.HTML(result)
+}
+
+// previewType returns the type of the preview of a binary file in a diff. PDF documents are shown
+// with pdf.js like in the file view, which loads the raw file itself.
+func previewType(name string) string {
+	if strings.EqualFold(path.Ext(name), ".pdf") {
+		return "pdf"
+	}
+	return markup.PreviewType(name)
+}
+
+// setPreviewCompareContext sets context data that is required by binary file preview template
+func setPreviewCompareContext(ctx *context.Context, base *git.Commit, head *git.Commit) {
+	ctx.Data["PreviewType"] = previewType
+	ctx.Data["PreviewBase"] = func(name, rawLink string) template.HTML {
+		return renderPreview(base, name, rawLink)
+	}
+	ctx.Data["Preview"] = func(name, rawLink string) template.HTML {
+		return renderPreview(head, name, rawLink)
+	}
+}
+
+// isSameForkNetwork returns whether both repositories belong to the same fork network,
+// including forks of forks
+func isSameForkNetwork(repo, other *models.Repository) (bool, error) {
+	if repo.ID == other.ID {
+		return true, nil
+	}
+	root, err := repo.GetForkNetworkRoot()
+	if err != nil {
+		return false, err
+	}
+	otherRoot, err := other.GetForkNetworkRoot()
+	if err != nil {
+		return false, err
+	}
+	return root.ID == otherRoot.ID, nil
+}
+
+// getCompareBaseRepo returns the repository of the fork network of baseRepo identified by
+// the owner name or the full name given as prefix of the base branch of a comparison
+func getCompareBaseRepo(ctx *context.Context, baseRepo *models.Repository, name string) *models.Repository {
+	if ownerAndName := strings.SplitN(name, "/", 2); len(ownerAndName) == 2 {
+		repo, err := models.GetRepositoryByOwnerAndName(ownerAndName[0], ownerAndName[1])
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				ctx.NotFound("GetRepositoryByOwnerAndName", nil)
+			} else {
+				ctx.ServerError("GetRepositoryByOwnerAndName", err)
+			}
+			return nil
+		}
+		if same, err := isSameForkNetwork(baseRepo, repo); err != nil {
+			ctx.ServerError("isSameForkNetwork", err)
+			return nil
+		} else if !same {
+			ctx.NotFound("isSameForkNetwork", nil)
+			return nil
+		}
+		return repo
+	}
+
+	if strings.EqualFold(name, baseRepo.OwnerName) {
+		return baseRepo
+	}
+	owner, err := models.GetUserByName(name)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound("GetUserByName", nil)
+		} else {
+			ctx.ServerError("GetUserByName", err)
+		}
+		return nil
+	}
+	if owner.ID == baseRepo.OwnerID {
+		return baseRepo
+	}
+
+	// The base repository may be the repository baseRepo was forked from, a fork of
+	// baseRepo or another fork of the repository baseRepo was forked from.
+	if baseRepo.IsFork {
+		if err := baseRepo.GetBaseRepo(); err != nil && !models.IsErrRepoNotExist(err) {
+			ctx.ServerError("GetBaseRepo", err)
+			return nil
+		}
+		if baseRepo.BaseRepo != nil && baseRepo.BaseRepo.OwnerID == owner.ID {
+			return baseRepo.BaseRepo
+		}
+	}
+	if repo, has := models.HasForkedRepo(owner.ID, baseRepo.ID); has {
+		return repo
+	}
+	if baseRepo.IsFork {
+		if repo, has := models.HasForkedRepo(owner.ID, baseRepo.ForkID); has {
+			return repo
+		}
+	}
+	ctx.NotFound("HasForkedRepo", nil)
+	return nil
+}
+
 // ParseCompareInfo parse compare info between two commit for preparing comparing references
 func ParseCompareInfo(ctx *context.Context) (*models.User, *models.Repository, *git.Repository, *git.CompareInfo, string, string) {
 	baseRepo := ctx.Repo.Repository
@@ -91,25 +219,63 @@ func ParseCompareInfo(ctx *context.Context) (*models.User, *models.Repository, *
 	// format: <base branch>...[<head repo>:]<head branch>
 	// base<-head: master...head:feature
 	// same repo: master...feature
+	//
+	// The base branch may be prefixed by the owner or the full name of another repository of the
+	// fork network as well ({:baseOwner}[/{:baseRepoName}]:{:baseBranch}), we then redirect to the
+	// compare page of that repository.
+	//
+	// With three dots the head is compared with the merge base of base and head, with two dots
+	// (base..head) it is compared with the base directly.
 
 	var (
-		headUser   *models.User
-		headRepo   *models.Repository
-		headBranch string
-		isSameRepo bool
-		infoPath   string
-		err        error
+		headUser         *models.User
+		headRepo         *models.Repository
+		headBranch       string
+		isSameRepo       bool
+		directComparison bool
+		infoPath         string
+		err              error
 	)
 	infoPath = ctx.Params("*")
-	infos := strings.SplitN(infoPath, "...", 2)
+	separator := "..."
+	if !strings.Contains(infoPath, separator) {
+		// Branch names can not contain "..", so this can only be a two dots comparison
+		separator = ".."
+		directComparison = true
+	}
+	infos := strings.SplitN(infoPath, separator, 2)
 	if len(infos) != 2 {
 		log.Trace("ParseCompareInfo[%d]: not enough compared branches information %s", baseRepo.ID, infos)
 		ctx.NotFound("CompareAndPullRequest", nil)
 		return nil, nil, nil, nil, "", ""
 	}
+	ctx.Data["DirectComparison"] = directComparison
+	ctx.Data["CompareSeparator"] = separator
 
-	ctx.Data["BaseName"] = baseRepo.OwnerName
 	baseBranch := infos[0]
+	if baseInfos := strings.SplitN(baseBranch, ":", 2); len(baseInfos) == 2 {
+		compareRepo := getCompareBaseRepo(ctx, baseRepo, baseInfos[0])
+		if ctx.Written() {
+			return nil, nil, nil, nil, "", ""
+		}
+		baseBranch = baseInfos[1]
+		if compareRepo.ID != baseRepo.ID {
+			redirect := compareRepo.Link() + "/compare/" + util.PathEscapeSegments(baseBranch) + separator + util.PathEscapeSegments(infos[1])
+			if len(ctx.Req.URL.RawQuery) > 0 {
+				redirect += "?" + ctx.Req.URL.RawQuery
+			}
+			ctx.Redirect(redirect)
+			return nil, nil, nil, nil, "", ""
+		}
+	}
+
+	otherSeparator := ".."
+	if directComparison {
+		otherSeparator = "..."
+	}
+	ctx.Data["CompareToggleLink"] = baseRepo.Link() + "/compare/" + util.PathEscapeSegments(infos[0]) + otherSeparator + util.PathEscapeSegments(infos[1])
+
+	ctx.Data["BaseName"] = baseRepo.OwnerName
 	ctx.Data["BaseBranch"] = baseBranch
 
 	// If there is no head repository, it means compare between same repository.
@@ -394,12 +560,12 @@ func ParseCompareInfo(ctx *context.Context) (*models.User, *models.Repository, *
 		headBranchRef = git.TagPrefix + headBranch
 	}
 
-	compareInfo, err := headGitRepo.GetCompareInfo(baseRepo.RepoPath(), baseBranchRef, headBranchRef)
+	compareInfo, err := headGitRepo.GetCompareInfo(baseRepo.RepoPath(), baseBranchRef, headBranchRef, directComparison)
 	if err != nil {
 		ctx.ServerError("GetCompareInfo", err)
 		return nil, nil, nil, nil, "", ""
 	}
-	ctx.Data["BeforeCommitID"] = compareInfo.MergeBase
+	ctx.Data["BeforeCommitID"] = compareInfo.CompareStartCommitID()
 
 	return headUser, headRepo, headGitRepo, compareInfo, baseBranch, headBranch
 }
@@ -437,13 +603,13 @@ func PrepareCompareDiff(
 
 	ctx.Data["AfterCommitID"] = headCommitID
 
-	if headCommitID == compareInfo.MergeBase {
+	if headCommitID == compareInfo.CompareStartCommitID() {
 		ctx.Data["IsNothingToCompare"] = true
 		return true
 	}
 
 	diff, err := gitdiff.GetDiffRange(models.RepoPath(headUser.Name, headRepo.Name),
-		compareInfo.MergeBase, headCommitID, setting.Git.MaxGitDiffLines,
+		compareInfo.CompareStartCommitID(), headCommitID, setting.Git.MaxGitDiffLines,
 		setting.Git.MaxGitDiffLineCharacters, setting.Git.MaxGitDiffFiles)
 	if err != nil {
 		ctx.ServerError("GetDiffRange", err)
@@ -500,6 +666,7 @@ func PrepareCompareDiff(
 	ctx.Data["Reponame"] = headRepo.Name
 
 	setImageCompareContext(ctx, baseCommit, headCommit)
+	setPreviewCompareContext(ctx, baseCommit, headCommit)
 	headTarget := path.Join(headUser.Name, repo.Name)
 	setPathsCompareContext(ctx, baseCommit, headCommit, headTarget)
 
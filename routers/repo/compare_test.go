@@ -0,0 +1,65 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCompareBaseRepo(t *testing.T) {
+	models.PrepareTestDatabase()
+	repo27 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 27}).(*models.Repository)
+	repo29 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 29}).(*models.Repository)
+
+	ctx := test.MockContext(t, "user19/big_test_public_mirror_6/compare")
+	test.LoadRepo(t, ctx, 27)
+	assert.Equal(t, repo27.ID, getCompareBaseRepo(ctx, repo27, "user19").ID)
+	assert.Equal(t, repo29.ID, getCompareBaseRepo(ctx, repo27, "user20").ID)
+	assert.Equal(t, repo29.ID, getCompareBaseRepo(ctx, repo27, "user20/big_test_public_fork_7").ID)
+	assert.Equal(t, repo27.ID, getCompareBaseRepo(ctx, repo29, "user19").ID)
+	assert.False(t, ctx.Written())
+
+	// repo1 is not part of the fork network
+	assert.Nil(t, getCompareBaseRepo(ctx, repo27, "user2/repo1"))
+	assert.EqualValues(t, http.StatusNotFound, ctx.Resp.Status())
+}
+
+func TestIsSameForkNetwork(t *testing.T) {
+	models.PrepareTestDatabase()
+	repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	repo27 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 27}).(*models.Repository)
+	repo29 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 29}).(*models.Repository)
+	// a fork of repo29, which is a fork of repo27
+	forkOfFork := &models.Repository{ID: 1000, IsFork: true, ForkID: repo29.ID}
+
+	for _, c := range []struct {
+		repo, other *models.Repository
+		expected    bool
+	}{
+		{repo27, repo27, true},
+		{repo27, repo29, true},
+		{repo29, repo27, true},
+		{forkOfFork, repo27, true},
+		{repo27, forkOfFork, true},
+		{forkOfFork, repo29, true},
+		{forkOfFork, repo1, false},
+		{repo1, repo27, false},
+	} {
+		same, err := isSameForkNetwork(c.repo, c.other)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, same, "%d and %d", c.repo.ID, c.other.ID)
+	}
+}
+
+func TestPreviewType(t *testing.T) {
+	assert.Equal(t, "pdf", previewType("docs/Manual.PDF"))
+	assert.Equal(t, "", previewType("docs/manual.bin"))
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+)
+
+const (
+	tplForkNetwork base.TplName = "repo/fork_network"
+)
+
+// ForkNetwork render the fork network of a repository including forks of forks
+func ForkNetwork(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.fork_network")
+
+	nodes, err := ctx.Repo.Repository.GetForkNetwork(ctx.User)
+	if err != nil {
+		ctx.ServerError("GetForkNetwork", err)
+		return
+	}
+	ctx.Data["ForkNetwork"] = nodes
+
+	ctx.HTML(http.StatusOK, tplForkNetwork)
+}
+
+// ForkNetworkPost response for the administration of the fork network of a repository by site administrators
+func ForkNetworkPost(ctx *context.Context) {
+	redirectTo := ctx.Repo.RepoLink + "/forks/network"
+
+	nodes, err := ctx.Repo.Repository.GetForkNetwork(ctx.User)
+	if err != nil {
+		ctx.ServerError("GetForkNetwork", err)
+		return
+	}
+	var node *models.ForkNetworkNode
+	for _, n := range nodes {
+		if n.Repo.ID == ctx.QueryInt64("repo_id") {
+			node = n
+		}
+	}
+	if node == nil || node.ParentID == 0 {
+		ctx.NotFound("ForkNetworkPost", nil)
+		return
+	}
+
+	switch ctx.Query("action") {
+	case "detach":
+		if err := models.DetachFork(node.Repo); err != nil {
+			ctx.ServerError("DetachFork", err)
+			return
+		}
+		log.Trace("Fork detached by %s: %s", ctx.User.Name, node.Repo.FullName())
+		ctx.Flash.Success(ctx.Tr("repo.fork_network.detach_success", node.Repo.FullName()))
+	case "reroot":
+		parent, err := models.GetRepositoryByID(node.ParentID)
+		if err != nil {
+			ctx.ServerError("GetRepositoryByID", err)
+			return
+		}
+		if err := models.ReRootForkNetwork(parent, node.Repo); err != nil {
+			ctx.ServerError("ReRootForkNetwork", err)
+			return
+		}
+		log.Trace("Fork network re-rooted by %s: %s replaces %s", ctx.User.Name, node.Repo.FullName(), parent.FullName())
+		ctx.Flash.Success(ctx.Tr("repo.fork_network.reroot_success", node.Repo.FullName(), parent.FullName()))
+	default:
+		ctx.NotFound("", nil)
+		return
+	}
+
+	ctx.Redirect(redirectTo)
+}
@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	gocontext "context"
+	"crypto/subtle"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -35,6 +36,20 @@ import (
 	repo_service "code.gitea.io/gitea/services/repository"
 )
 
+// isReplicaFetch returns whether the request is a read replica fetching a repository,
+// replicas authenticate with the replication secret.
+func isReplicaFetch(ctx *context.Context) bool {
+	if len(setting.Replication.ReplicaURLs) == 0 || len(setting.Replication.Secret) == 0 {
+		return false
+	}
+
+	auths := strings.Fields(ctx.Req.Header.Get("Authorization"))
+	if len(auths) != 2 || auths[0] != "Bearer" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(auths[1]), []byte(setting.Replication.Secret)) == 1
+}
+
 // HTTP implmentation git smart HTTP protocol
 func HTTP(ctx *context.Context) {
 	if len(setting.Repository.AccessControlAllowOrigin) > 0 {
@@ -151,6 +166,11 @@ func HTTP(ctx *context.Context) {
 		askAuth = askAuth || (repo.Owner.Visibility != structs.VisibleTypePublic)
 	}
 
+	// read replicas sync all repositories
+	if askAuth && isPull && isReplicaFetch(ctx) {
+		askAuth = false
+	}
+
 	// check access
 	if askAuth {
 		authUsername = ctx.Req.Header.Get(setting.ReverseProxyAuthUser)
@@ -218,6 +238,24 @@ func HTTP(ctx *context.Context) {
 				log.Error("GetAccessTokenBySha: %v", err)
 			}
 
+			if authUser == nil && repoExist && strings.HasPrefix(authPasswd, models.GitCredentialPrefix) {
+				// Check short-lived git credential, it is only valid for the operation on the repository it was created for
+				credentialOp := models.GitCredentialOperationWrite
+				if isPull {
+					credentialOp = models.GitCredentialOperationRead
+				}
+				credentialUser, err := models.GetUserByName(authUsername)
+				if err != nil && !models.IsErrUserNotExist(err) {
+					ctx.ServerError("GetUserByName", err)
+					return
+				}
+				if credentialUser == nil || !models.VerifyGitCredential(credentialUser, repo, credentialOp, authPasswd) {
+					ctx.HandleText(http.StatusUnauthorized, fmt.Sprintf("invalid credentials from %s", ctx.RemoteAddr()))
+					return
+				}
+				authUser = credentialUser
+			}
+
 			if authUser == nil {
 				// Check username and password
 				authUser, err = models.UserSignIn(authUsername, authPasswd)
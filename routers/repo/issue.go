@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"html"
 	"io/ioutil"
 	"net/http"
 	"path"
@@ -381,6 +382,17 @@ func Issues(ctx *context.Context) {
 
 	ctx.Data["CanWriteIssuesOrPulls"] = ctx.Repo.CanWriteIssuesOrPulls(isPullList)
 
+	if ctx.IsSigned {
+		ctx.Data["ScheduledPostCount"], err = models.CountScheduledPosts(models.FindScheduledPostsOptions{
+			RepoID:   ctx.Repo.Repository.ID,
+			PosterID: ctx.User.ID,
+		})
+		if err != nil {
+			ctx.ServerError("CountScheduledPosts", err)
+			return
+		}
+	}
+
 	ctx.HTML(200, tplIssues)
 }
 
@@ -943,6 +955,36 @@ func NewIssuePost(ctx *context.Context, form auth.CreateIssueForm) {
 		return
 	}
 
+	if len(form.PostAt) > 0 {
+		postUnix, ok := parseScheduledPostTime(form.PostAt, form.PostAtOffset)
+		if !ok {
+			ctx.RenderWithErr(ctx.Tr("repo.issues.scheduled.time_error"), tplIssueNew, form)
+			return
+		}
+		if len(attachments) > 0 {
+			ctx.RenderWithErr(ctx.Tr("repo.issues.scheduled.attachments_error"), tplIssueNew, form)
+			return
+		}
+		if err := models.CreateScheduledPost(&models.ScheduledPost{
+			RepoID:      repo.ID,
+			PosterID:    ctx.User.ID,
+			Title:       form.Title,
+			Content:     form.Content,
+			Ref:         form.Ref,
+			MilestoneID: milestoneID,
+			ProjectID:   projectID,
+			LabelIDs:    labelIDs,
+			AssigneeIDs: assigneeIDs,
+			PostUnix:    postUnix,
+		}); err != nil {
+			ctx.ServerError("CreateScheduledPost", err)
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("repo.issues.scheduled.success", postUnix.FormatLong()))
+		ctx.Redirect(ctx.Repo.RepoLink + "/issues/scheduled")
+		return
+	}
+
 	issue := &models.Issue{
 		RepoID:      repo.ID,
 		Title:       form.Title,
@@ -1114,6 +1156,10 @@ func ViewIssue(ctx *context.Context) {
 
 	issue.RenderedContent = string(markdown.Render([]byte(issue.Content), ctx.Repo.RepoLink,
 		ctx.Repo.Repository.ComposeMetas()))
+	if issue.IsHidden && !canSeeHiddenContent(ctx) {
+		issue.Content = ""
+		issue.RenderedContent = hiddenContentPlaceholder(ctx)
+	}
 
 	repo := ctx.Repo.Repository
 
@@ -1272,6 +1318,10 @@ func ViewIssue(ctx *context.Context) {
 
 			comment.RenderedContent = string(markdown.Render([]byte(comment.Content), ctx.Repo.RepoLink,
 				ctx.Repo.Repository.ComposeMetas()))
+			if comment.IsHidden && !canSeeHiddenContent(ctx) {
+				comment.Content = ""
+				comment.RenderedContent = hiddenContentPlaceholder(ctx)
+			}
 
 			// Check tag.
 			tag, ok = marked[comment.PosterID]
@@ -1342,6 +1392,10 @@ func ViewIssue(ctx *context.Context) {
 		} else if comment.Type == models.CommentTypeCode || comment.Type == models.CommentTypeReview {
 			comment.RenderedContent = string(markdown.Render([]byte(comment.Content), ctx.Repo.RepoLink,
 				ctx.Repo.Repository.ComposeMetas()))
+			if comment.IsHidden && !canSeeHiddenContent(ctx) {
+				comment.Content = ""
+				comment.RenderedContent = hiddenContentPlaceholder(ctx)
+			}
 			if err = comment.LoadReview(); err != nil && !models.IsErrReviewNotExist(err) {
 				ctx.ServerError("LoadReview", err)
 				return
@@ -1508,6 +1562,16 @@ func ViewIssue(ctx *context.Context) {
 	ctx.Data["IsRepoAdmin"] = ctx.IsSigned && (ctx.Repo.IsAdmin() || ctx.User.IsAdmin)
 	ctx.Data["LockReasons"] = setting.Repository.Issue.LockReasons
 	ctx.Data["RefEndName"] = git.RefEndName(issue.Ref)
+	if ctx.IsSigned {
+		ctx.Data["ScheduledPostCount"], err = models.CountScheduledPosts(models.FindScheduledPostsOptions{
+			IssueID:  issue.ID,
+			PosterID: ctx.User.ID,
+		})
+		if err != nil {
+			ctx.ServerError("CountScheduledPosts", err)
+			return
+		}
+	}
 	ctx.HTML(200, tplIssueView)
 }
 
@@ -1631,7 +1695,8 @@ func UpdateIssueContent(ctx *context.Context) {
 		return
 	}
 
-	if !ctx.IsSigned || (ctx.User.ID != issue.PosterID && !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull)) {
+	if !ctx.IsSigned || (ctx.User.ID != issue.PosterID && !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull)) ||
+		(issue.IsHidden && !canSeeHiddenContent(ctx)) {
 		ctx.Error(403)
 		return
 	}
@@ -1936,6 +2001,32 @@ func NewComment(ctx *context.Context, form auth.CreateCommentForm) {
 		return
 	}
 
+	if len(form.PostAt) > 0 {
+		postUnix, ok := parseScheduledPostTime(form.PostAt, form.PostAtOffset)
+		switch {
+		case !ok:
+			ctx.Flash.Error(ctx.Tr("repo.issues.scheduled.time_error"))
+		case len(attachments) > 0:
+			ctx.Flash.Error(ctx.Tr("repo.issues.scheduled.attachments_error"))
+		case len(form.Status) > 0 || len(form.Content) == 0:
+			ctx.Flash.Error(ctx.Tr("repo.issues.scheduled.comment_error"))
+		default:
+			if err := models.CreateScheduledPost(&models.ScheduledPost{
+				RepoID:   ctx.Repo.Repository.ID,
+				PosterID: ctx.User.ID,
+				IssueID:  issue.ID,
+				Content:  form.Content,
+				PostUnix: postUnix,
+			}); err != nil {
+				ctx.ServerError("CreateScheduledPost", err)
+				return
+			}
+			ctx.Flash.Success(ctx.Tr("repo.issues.scheduled.success", postUnix.FormatLong()))
+		}
+		ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+		return
+	}
+
 	var comment *models.Comment
 	defer func() {
 		// Check if issue admin/poster changes the status of issue.
@@ -2037,7 +2128,8 @@ func UpdateCommentContent(ctx *context.Context) {
 		}
 	}
 
-	if !ctx.IsSigned || (ctx.User.ID != comment.PosterID && !ctx.Repo.CanWriteIssuesOrPulls(comment.Issue.IsPull)) {
+	if !ctx.IsSigned || (ctx.User.ID != comment.PosterID && !ctx.Repo.CanWriteIssuesOrPulls(comment.Issue.IsPull)) ||
+		(comment.IsHidden && !canSeeHiddenContent(ctx)) {
 		ctx.Error(403)
 		return
 	} else if comment.Type != models.CommentTypeComment && comment.Type != models.CommentTypeCode {
@@ -2443,3 +2535,14 @@ func combineLabelComments(issue *models.Issue) {
 		i--
 	}
 }
+
+// canSeeHiddenContent returns whether the user can see the content of issues and comments
+// hidden by the site administrators
+func canSeeHiddenContent(ctx *context.Context) bool {
+	return ctx.IsSigned && ctx.User.IsAdmin
+}
+
+// hiddenContentPlaceholder returns the content rendered instead of the content of hidden issues and comments
+func hiddenContentPlaceholder(ctx *context.Context) string {
+	return `<p><i>` + html.EscapeString(ctx.Tr("repo.issues.content_hidden")) + `</i></p>`
+}
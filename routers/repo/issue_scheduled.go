@@ -0,0 +1,98 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const (
+	tplScheduledPosts base.TplName = "repo/issue/scheduled"
+
+	scheduledPostTimeLayout = "2006-01-02T15:04"
+)
+
+// parseScheduledPostTime parses the time of the post_at field, which must be in the future.
+// The time is either a RFC3339 time or a local time, which is in the time zone of the browser
+// given by the offset in minutes east of UTC or in the default UI time zone without an offset.
+func parseScheduledPostTime(postAt, offset string) (timeutil.TimeStamp, bool) {
+	t, err := time.Parse(time.RFC3339, postAt)
+	if err != nil {
+		loc := setting.DefaultUILocation
+		if len(offset) > 0 {
+			minutes, err := strconv.Atoi(offset)
+			if err != nil || minutes < -14*60 || minutes > 14*60 {
+				return 0, false
+			}
+			loc = time.FixedZone("", minutes*60)
+		}
+		t, err = time.ParseInLocation(scheduledPostTimeLayout, postAt, loc)
+	}
+	if err != nil || !t.After(time.Now()) {
+		return 0, false
+	}
+	return timeutil.TimeStamp(t.Unix()), true
+}
+
+// ScheduledPosts lists the issues and comments the signed in user scheduled in the repository
+func ScheduledPosts(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.issues.scheduled")
+	ctx.Data["PageIsIssueList"] = true
+
+	posts, err := models.FindScheduledPosts(models.FindScheduledPostsOptions{
+		RepoID:   ctx.Repo.Repository.ID,
+		PosterID: ctx.User.ID,
+	})
+	if err != nil {
+		ctx.ServerError("FindScheduledPosts", err)
+		return
+	}
+	for _, p := range posts {
+		p.Repo = ctx.Repo.Repository
+		p.Poster = ctx.User
+		if err := p.LoadAttributes(); err != nil {
+			ctx.ServerError("LoadAttributes", err)
+			return
+		}
+	}
+	ctx.Data["ScheduledPosts"] = posts
+
+	ctx.HTML(http.StatusOK, tplScheduledPosts)
+}
+
+// DeleteScheduledPost cancels a scheduled issue or comment of the signed in user
+func DeleteScheduledPost(ctx *context.Context) {
+	p, err := models.GetScheduledPostByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrScheduledPostNotExist(err) {
+			ctx.NotFound("GetScheduledPostByID", err)
+		} else {
+			ctx.ServerError("GetScheduledPostByID", err)
+		}
+		return
+	}
+	if p.RepoID != ctx.Repo.Repository.ID || p.PosterID != ctx.User.ID {
+		ctx.NotFound("DeleteScheduledPost", nil)
+		return
+	}
+
+	if err := models.DeleteScheduledPost(p); err != nil {
+		ctx.ServerError("DeleteScheduledPost", err)
+		return
+	}
+	log.Trace("Scheduled post %d of user %d canceled", p.ID, p.PosterID)
+
+	ctx.Flash.Success(ctx.Tr("repo.issues.scheduled.cancel_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/issues/scheduled")
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScheduledPostTime(t *testing.T) {
+	future := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Minute)
+
+	postUnix, ok := parseScheduledPostTime(future.Format(time.RFC3339), "")
+	assert.True(t, ok)
+	assert.EqualValues(t, future.Unix(), postUnix)
+
+	// the local time is entered in the time zone of the browser, two hours east of UTC
+	postUnix, ok = parseScheduledPostTime(future.Add(2*time.Hour).Format(scheduledPostTimeLayout), "120")
+	assert.True(t, ok)
+	assert.EqualValues(t, future.Unix(), postUnix)
+
+	_, ok = parseScheduledPostTime(future.Format(scheduledPostTimeLayout), "east")
+	assert.False(t, ok)
+	_, ok = parseScheduledPostTime(future.Format(scheduledPostTimeLayout), "6000")
+	assert.False(t, ok)
+	_, ok = parseScheduledPostTime(time.Now().Add(-time.Hour).Format(time.RFC3339), "")
+	assert.False(t, ok)
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+	translation_service "code.gitea.io/gitea/services/translation"
+)
+
+// MustEnableTranslation check if machine translation of issues and comments is enabled
+func MustEnableTranslation(ctx *context.Context) {
+	if !setting.Translation.Enabled {
+		ctx.NotFound("MustEnableTranslation", nil)
+	}
+}
+
+// IssueTranslation returns the machine translation of the content of an issue
+func IssueTranslation(ctx *context.Context) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.IsHidden && !canSeeHiddenContent(ctx) {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	t, err := translation_service.TranslateIssue(ctx.Req.Context(), issue, translationLanguage(ctx))
+	renderTranslation(ctx, t, err)
+}
+
+// CommentTranslation returns the machine translation of the content of a comment
+func CommentTranslation(ctx *context.Context) {
+	comment, err := models.GetCommentByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetCommentByID", models.IsErrCommentNotExist, err)
+		return
+	}
+	if err := comment.LoadIssue(); err != nil {
+		ctx.NotFoundOrServerError("LoadIssue", models.IsErrIssueNotExist, err)
+		return
+	}
+	if comment.Issue.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound("CommentTranslation", nil)
+		return
+	}
+	checkIssueRights(ctx, comment.Issue)
+	if ctx.Written() {
+		return
+	}
+	if comment.IsHidden && !canSeeHiddenContent(ctx) {
+		ctx.Error(http.StatusForbidden)
+		return
+	} else if comment.Type != models.CommentTypeComment && comment.Type != models.CommentTypeCode {
+		ctx.Error(http.StatusNoContent)
+		return
+	}
+
+	t, err := translation_service.TranslateComment(ctx.Req.Context(), comment, translationLanguage(ctx))
+	renderTranslation(ctx, t, err)
+}
+
+// translationLanguage returns the requested target language, the language of the interface by default
+func translationLanguage(ctx *context.Context) string {
+	if lang := ctx.Query("lang"); len(lang) > 0 {
+		return lang
+	}
+	return ctx.Locale.Language()
+}
+
+func renderTranslation(ctx *context.Context, t *models.IssueContentTranslation, err error) {
+	if err != nil {
+		switch {
+		case translation_service.IsErrInvalidLanguage(err):
+			ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+				"error": ctx.Tr("repo.issues.translation.invalid_language"),
+			})
+		case translation_service.IsErrContentTooLong(err):
+			ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+				"error": ctx.Tr("repo.issues.translation.too_long"),
+			})
+		default:
+			log.Error("Translate: %v", err)
+			ctx.JSON(http.StatusBadGateway, map[string]interface{}{
+				"error": ctx.Tr("repo.issues.translation.failed"),
+			})
+		}
+		return
+	}
+
+	note := ctx.Tr("repo.issues.translation.note")
+	if len(t.SourceLanguage) > 0 {
+		note = ctx.Tr("repo.issues.translation.note_from", t.SourceLanguage)
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"content":         string(markdown.Render([]byte(t.Content), ctx.Repo.RepoLink, ctx.Repo.Repository.ComposeMetas())),
+		"language":        t.Language,
+		"source_language": t.SourceLanguage,
+		"note":            note,
+	})
+}
@@ -21,6 +21,7 @@ const (
 	tplProjects           base.TplName = "repo/projects/list"
 	tplProjectsNew        base.TplName = "repo/projects/new"
 	tplProjectsView       base.TplName = "repo/projects/view"
+	tplProjectsAnalytics  base.TplName = "repo/projects/analytics"
 	tplGenericProjectsNew base.TplName = "user/project"
 )
 
@@ -302,6 +303,60 @@ func ViewProject(ctx *context.Context) {
 	ctx.HTML(200, tplProjectsView)
 }
 
+// projectBoardBar represents the time-in-board statistics of a board with its bar width in the chart
+type projectBoardBar struct {
+	*models.ProjectBoardStats
+	Perc int
+}
+
+// ProjectAnalytics renders the time issues spend on the boards of a project
+func ProjectAnalytics(ctx *context.Context) {
+	project, err := models.GetProjectByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProjectNotExist(err) {
+			ctx.NotFound("", nil)
+		} else {
+			ctx.ServerError("GetProjectByID", err)
+		}
+		return
+	}
+	if project.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound("", nil)
+		return
+	}
+
+	analytics, err := models.GetProjectAnalytics(project)
+	if err != nil {
+		ctx.ServerError("GetProjectAnalytics", err)
+		return
+	}
+
+	var maxDuration int64
+	for _, board := range analytics.Boards {
+		if board.AverageDuration > maxDuration {
+			maxDuration = board.AverageDuration
+		}
+	}
+	bars := make([]*projectBoardBar, len(analytics.Boards))
+	for i, board := range analytics.Boards {
+		if board.Board.ID == 0 {
+			board.Board.Title = ctx.Tr("repo.projects.type.uncategorized")
+		}
+		bars[i] = &projectBoardBar{ProjectBoardStats: board}
+		if maxDuration > 0 {
+			bars[i].Perc = int(board.AverageDuration * 100 / maxDuration)
+		}
+	}
+
+	ctx.Data["Title"] = ctx.Tr("repo.projects.analytics")
+	ctx.Data["Project"] = project
+	ctx.Data["Analytics"] = analytics
+	ctx.Data["Boards"] = bars
+	ctx.Data["PageIsProjects"] = true
+
+	ctx.HTML(200, tplProjectsAnalytics)
+}
+
 // UpdateIssueProject change an issue's project
 func UpdateIssueProject(ctx *context.Context) {
 	issues := getActionIssues(ctx)
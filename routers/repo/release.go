@@ -135,6 +135,15 @@ func releasesOrTags(ctx *context.Context, isTagList bool) {
 	ctx.Data["Releases"] = releases
 	ctx.Data["ReleasesNum"] = len(releases)
 
+	if !isTagList {
+		channels, err := models.GetReleaseChannels(ctx.Repo.Repository.ID)
+		if err != nil {
+			ctx.ServerError("GetReleaseChannels", err)
+			return
+		}
+		ctx.Data["ReleaseChannels"] = channels
+	}
+
 	pager := context.NewPagination(int(count), opts.PageSize, opts.Page, 5)
 	pager.SetDefaultParams(ctx)
 	ctx.Data["Page"] = pager
@@ -0,0 +1,125 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup/markdown"
+)
+
+// releaseChannelFeedItems is the number of releases in the RSS feed of a release channel
+const releaseChannelFeedItems = 20
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string     `xml:"title"`
+	Link        string     `xml:"link"`
+	Description string     `xml:"description"`
+	Items       []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+}
+
+// getReleaseChannel returns the release channel of the request, it writes the error response on failure
+func getReleaseChannel(ctx *context.Context) *models.ReleaseChannel {
+	channel, err := models.GetReleaseChannelByName(ctx.Repo.Repository.ID, ctx.Params(":channel"))
+	if err != nil {
+		if models.IsErrReleaseChannelNotExist(err) {
+			ctx.NotFound("GetReleaseChannelByName", err)
+		} else {
+			ctx.ServerError("GetReleaseChannelByName", err)
+		}
+		return nil
+	}
+	return channel
+}
+
+// LatestChannelRelease redirects to the latest release of a release channel
+func LatestChannelRelease(ctx *context.Context) {
+	channel := getReleaseChannel(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	release, err := channel.GetLatestRelease()
+	if err != nil {
+		if models.IsErrReleaseNotExist(err) {
+			ctx.NotFound("GetLatestRelease", err)
+			return
+		}
+		ctx.ServerError("GetLatestRelease", err)
+		return
+	}
+
+	release.Repo = ctx.Repo.Repository
+	ctx.Redirect(release.HTMLURL())
+}
+
+// ChannelReleasesFeed renders the RSS feed of the latest releases of a release channel
+func ChannelReleasesFeed(ctx *context.Context) {
+	channel := getReleaseChannel(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	releases, err := channel.GetReleases(releaseChannelFeedItems)
+	if err != nil {
+		ctx.ServerError("GetReleases", err)
+		return
+	}
+
+	repo := ctx.Repo.Repository
+	feed := &rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       repo.FullName() + " " + channel.Name,
+			Link:        repo.HTMLURL() + "/releases",
+			Description: ctx.Tr("repo.release.channel_feed_desc", channel.Name, repo.FullName()),
+			Items:       make([]*rssItem, 0, len(releases)),
+		},
+	}
+	for _, rel := range releases {
+		rel.Repo = repo
+
+		title := rel.Title
+		if len(title) == 0 {
+			title = rel.TagName
+		}
+		feed.Channel.Items = append(feed.Channel.Items, &rssItem{
+			Title:       title,
+			Link:        rel.HTMLURL(),
+			Description: markdown.RenderString(rel.Note, repo.HTMLURL(), repo.ComposeMetas()),
+			GUID:        rel.HTMLURL(),
+			PubDate:     rel.CreatedUnix.AsTime().Format(time.RFC1123Z),
+		})
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "application/rss+xml;charset=utf-8")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write([]byte(xml.Header)); err != nil {
+		log.Error("Write: %v", err)
+		return
+	}
+	if err := xml.NewEncoder(ctx.Resp).Encode(feed); err != nil {
+		log.Error("Encode: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ package repo
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -101,6 +102,22 @@ func checkContextUser(ctx *context.Context, uid int64) *models.User {
 	return org
 }
 
+// loadRepoPresets loads the repository presets of the organizations the user can create repositories in,
+// which the user may use
+func loadRepoPresets(ctx *context.Context) {
+	orgs, _ := ctx.Data["Orgs"].([]*models.User)
+	presets := make([]*models.RepoPreset, 0, len(orgs))
+	for _, org := range orgs {
+		orgPresets, err := org.GetUsableRepoPresets(ctx.User)
+		if err != nil {
+			ctx.ServerError("GetUsableRepoPresets", err)
+			return
+		}
+		presets = append(presets, orgPresets...)
+	}
+	ctx.Data["RepoPresets"] = presets
+}
+
 func getRepoPrivate(ctx *context.Context) bool {
 	switch strings.ToLower(setting.Repository.DefaultPrivate) {
 	case setting.RepoCreatingLastUserVisibility:
@@ -134,6 +151,11 @@ func Create(ctx *context.Context) {
 	}
 	ctx.Data["ContextUser"] = ctxUser
 
+	loadRepoPresets(ctx)
+	if ctx.Written() {
+		return
+	}
+
 	ctx.Data["repo_template_name"] = ctx.Tr("repo.template_select")
 	templateID := ctx.QueryInt64("template_id")
 	if templateID > 0 {
@@ -176,6 +198,8 @@ func handleCreateError(ctx *context.Context, owner *models.User, err error, name
 	case models.IsErrNamePatternNotAllowed(err):
 		ctx.Data["Err_RepoName"] = true
 		ctx.RenderWithErr(ctx.Tr("repo.form.name_pattern_not_allowed", err.(models.ErrNamePatternNotAllowed).Pattern), tpl, form)
+	case models.IsErrRepoPresetNotExist(err):
+		ctx.RenderWithErr(ctx.Tr("repo.preset.invalid"), tpl, form)
 	default:
 		ctx.ServerError(name, err)
 	}
@@ -196,6 +220,11 @@ func CreatePost(ctx *context.Context, form auth.CreateRepoForm) {
 	}
 	ctx.Data["ContextUser"] = ctxUser
 
+	loadRepoPresets(ctx)
+	if ctx.Written() {
+		return
+	}
+
 	if ctx.HasError() {
 		ctx.HTML(200, tplCreate)
 		return
@@ -238,7 +267,7 @@ func CreatePost(ctx *context.Context, form auth.CreateRepoForm) {
 			return
 		}
 	} else {
-		repo, err = repo_service.CreateRepository(ctx.User, ctxUser, models.CreateRepoOptions{
+		opts := models.CreateRepoOptions{
 			Name:          form.RepoName,
 			Description:   form.Description,
 			Gitignores:    form.Gitignores,
@@ -250,7 +279,12 @@ func CreatePost(ctx *context.Context, form auth.CreateRepoForm) {
 			AutoInit:      form.AutoInit,
 			IsTemplate:    form.Template,
 			TrustModel:    models.ToTrustModel(form.TrustModel),
-		})
+		}
+		if form.RepoPreset > 0 {
+			repo, err = repo_service.CreateRepositoryFromPreset(ctx.User, ctxUser, form.RepoPreset, opts)
+		} else {
+			repo, err = repo_service.CreateRepository(ctx.User, ctxUser, opts)
+		}
 		if err == nil {
 			log.Trace("Repository created [%d]: %s/%s", repo.ID, ctxUser.Name, repo.Name)
 			ctx.Redirect(setting.AppSubURL + "/" + ctxUser.Name + "/" + repo.Name)
@@ -337,7 +371,16 @@ func Download(ctx *context.Context) {
 	downloadName := ctx.Repo.Repository.Name + "-" + aReq.GetArchiveName()
 	complete := aReq.IsComplete()
 	if !complete {
-		aReq = archiver_service.ArchiveRepository(aReq)
+		var err error
+		aReq, err = archiver_service.ArchiveRepository(aReq, archiveDoerID(ctx))
+		if err != nil {
+			if archiver_service.IsErrTooManyArchives(err) {
+				ctx.Error(http.StatusTooManyRequests, err.Error())
+			} else {
+				ctx.ServerError("ArchiveRepository", err)
+			}
+			return
+		}
 		complete = aReq.WaitForCompletion(ctx)
 	}
 
@@ -348,9 +391,17 @@ func Download(ctx *context.Context) {
 	}
 }
 
+func archiveDoerID(ctx *context.Context) int64 {
+	if ctx.User == nil {
+		return 0
+	}
+	return ctx.User.ID
+}
+
 // InitiateDownload will enqueue an archival request, as needed.  It may submit
 // a request that's already in-progress, but the archiver service will just
-// kind of drop it on the floor if this is the case.
+// kind of drop it on the floor if this is the case.  It responds with the
+// status of the request, which the UI polls until the archive is complete.
 func InitiateDownload(ctx *context.Context) {
 	uri := ctx.Params("*")
 	aReq := archiver_service.DeriveRequestFrom(ctx, uri)
@@ -362,11 +413,53 @@ func InitiateDownload(ctx *context.Context) {
 
 	complete := aReq.IsComplete()
 	if !complete {
-		aReq = archiver_service.ArchiveRepository(aReq)
+		var err error
+		aReq, err = archiver_service.ArchiveRepository(aReq, archiveDoerID(ctx))
+		if err != nil {
+			if archiver_service.IsErrTooManyArchives(err) {
+				ctx.JSON(http.StatusTooManyRequests, map[string]interface{}{
+					"complete": false,
+					"message":  ctx.Tr("repo.download_archive.too_many", err.(archiver_service.ErrTooManyArchives).Limit),
+				})
+			} else {
+				ctx.ServerError("ArchiveRepository", err)
+			}
+			return
+		}
 		complete, _ = aReq.TimedWaitForCompletion(ctx, 2*time.Second)
 	}
 
+	status := aReq.Status()
+	var message string
+	switch status {
+	case archiver_service.ArchiveQueued:
+		message = ctx.Tr("repo.download_archive.queued", aReq.QueuePosition()+1)
+	case archiver_service.ArchiveRunning:
+		message = ctx.Tr("repo.download_archive.running", base.FileSize(aReq.Progress()))
+	case archiver_service.ArchiveFailed, archiver_service.ArchiveCancelled:
+		message = ctx.Tr("repo.download_archive." + status.String())
+	}
+
 	ctx.JSON(200, map[string]interface{}{
 		"complete": complete,
+		"status":   status.String(),
+		"progress": aReq.Progress(),
+		"message":  message,
+	})
+}
+
+// CancelDownload withdraws the archival request of the user, the generation of
+// the archive is cancelled if nobody else requested it.
+func CancelDownload(ctx *context.Context) {
+	uri := ctx.Params("*")
+	aReq := archiver_service.DeriveRequestFrom(ctx, uri)
+
+	if aReq == nil {
+		ctx.Error(404)
+		return
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"cancelled": archiver_service.CancelArchive(aReq, archiveDoerID(ctx)),
 	})
 }
@@ -0,0 +1,45 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePost_RepoPreset(t *testing.T) {
+	models.PrepareTestEnv(t)
+
+	preset := &models.RepoPreset{
+		OrgID:  3,
+		TeamID: 12,
+		Name:   "Service",
+		Units:  []models.UnitType{models.UnitTypeIssues},
+		Topics: []string{"backend"},
+	}
+	assert.NoError(t, models.CreateRepoPreset(preset))
+
+	// the preset of an organization can not be used for repositories of users
+	ctx := test.MockContext(t, "repo/create")
+	test.LoadUser(t, ctx, 28)
+	CreatePost(ctx, auth.CreateRepoForm{UID: 28, RepoName: "preset-repo", RepoPreset: preset.ID})
+	assert.EqualValues(t, http.StatusOK, ctx.Resp.Status())
+	assert.NotEmpty(t, ctx.Flash.ErrorMsg)
+	models.AssertNotExistsBean(t, &models.Repository{OwnerID: 28, LowerName: "preset-repo"})
+
+	ctx = test.MockContext(t, "repo/create")
+	test.LoadUser(t, ctx, 28)
+	CreatePost(ctx, auth.CreateRepoForm{UID: 3, RepoName: "preset-repo", RepoPreset: preset.ID})
+	assert.EqualValues(t, http.StatusFound, ctx.Resp.Status())
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: 3, LowerName: "preset-repo"}).(*models.Repository)
+	assert.EqualValues(t, []string{"backend"}, repo.Topics)
+	assert.False(t, repo.UnitEnabled(models.UnitTypePullRequests))
+}
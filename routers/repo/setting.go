@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/references"
 	"code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
@@ -54,6 +55,10 @@ func Settings(ctx *context.Context) {
 	signing, _ := models.SigningKey(ctx.Repo.Repository.RepoPath())
 	ctx.Data["SigningKeyAvailable"] = len(signing) > 0
 	ctx.Data["SigningSettings"] = setting.Repository.Signing
+	ctx.Data["MirrorReleaseAssetsEnabled"] = setting.Mirror.EnableReleaseAssets
+	ctx.Data["MirrorReleaseAssetMaxSize"] = setting.Mirror.ReleaseAssetMaxSize
+	ctx.Data["DefaultCloseKeywords"] = setting.Repository.PullRequest.CloseKeywords
+	ctx.Data["DefaultReopenKeywords"] = setting.Repository.PullRequest.ReopenKeywords
 
 	ctx.HTML(200, tplSettingsOptions)
 }
@@ -62,6 +67,8 @@ func Settings(ctx *context.Context) {
 func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings")
 	ctx.Data["PageIsSettingsOptions"] = true
+	ctx.Data["MirrorReleaseAssetsEnabled"] = setting.Mirror.EnableReleaseAssets
+	ctx.Data["MirrorReleaseAssetMaxSize"] = setting.Mirror.ReleaseAssetMaxSize
 
 	repo := ctx.Repo.Repository
 
@@ -154,6 +161,9 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 			ctx.RenderWithErr(ctx.Tr("repo.mirror_interval_invalid"), tplSettingsOptions, &form)
 		} else {
 			ctx.Repo.Mirror.EnablePrune = form.EnablePrune
+			if setting.Mirror.EnableReleaseAssets {
+				ctx.Repo.Mirror.EnableReleaseAssets = form.EnableMirrorReleaseAssets
+			}
 			ctx.Repo.Mirror.Interval = interval
 			if interval != 0 {
 				ctx.Repo.Mirror.NextUpdateUnix = timeutil.TimeStampNow().AddDuration(interval)
@@ -281,6 +291,20 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeIssues)
 		} else if form.EnableIssues && !form.EnableExternalTracker && !models.UnitTypeIssues.UnitGlobalDisabled() {
+			closeTrigger := models.IssueCloseTrigger(form.IssueCloseTrigger)
+			if !closeTrigger.IsValid() {
+				ctx.Flash.Error(ctx.Tr("repo.settings.issue_close_trigger_error"))
+				ctx.Redirect(repo.Link() + "/settings")
+				return
+			}
+			closeKeywords, reopenKeywords := splitKeywords(form.CloseKeywords), splitKeywords(form.ReopenKeywords)
+			for _, keyword := range append(closeKeywords, reopenKeywords...) {
+				if !references.IsValidKeyword(keyword) {
+					ctx.Flash.Error(ctx.Tr("repo.settings.close_keywords_error", keyword))
+					ctx.Redirect(repo.Link() + "/settings")
+					return
+				}
+			}
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypeIssues,
@@ -288,6 +312,10 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 					EnableTimetracker:                form.EnableTimetracker,
 					AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               form.EnableIssueDependencies,
+					DisableCloseKeywords:             form.DisableCloseKeywords,
+					CloseKeywords:                    closeKeywords,
+					ReopenKeywords:                   reopenKeywords,
+					CloseTrigger:                     closeTrigger,
 				},
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeExternalTracker)
@@ -572,6 +600,17 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 	}
 }
 
+// splitKeywords returns the comma separated keywords
+func splitKeywords(keywords string) []string {
+	list := make([]string, 0, 5)
+	for _, keyword := range strings.Split(keywords, ",") {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			list = append(list, keyword)
+		}
+	}
+	return list
+}
+
 // Collaboration render a repository's collaboration page
 func Collaboration(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings")
@@ -631,6 +670,13 @@ func CollaborationPost(ctx *context.Context) {
 		return
 	}
 
+	expires, err := parseCollaborationExpiry(ctx.Query("expires"))
+	if err != nil {
+		ctx.Flash.Error(ctx.Tr("repo.settings.collaboration.invalid_expiry"))
+		ctx.Redirect(setting.AppSubURL + ctx.Req.URL.Path)
+		return
+	}
+
 	if got, err := ctx.Repo.Repository.IsCollaborator(u.ID); err == nil && got {
 		ctx.Flash.Error(ctx.Tr("repo.settings.add_collaborator_duplicate"))
 		ctx.Redirect(ctx.Repo.RepoLink + "/settings/collaboration")
@@ -641,6 +687,12 @@ func CollaborationPost(ctx *context.Context) {
 		ctx.ServerError("AddCollaborator", err)
 		return
 	}
+	if expires > 0 {
+		if err = ctx.Repo.Repository.ChangeCollaborationExpiry(u.ID, expires); err != nil {
+			ctx.ServerError("ChangeCollaborationExpiry", err)
+			return
+		}
+	}
 
 	if setting.Service.EnableNotifyMail {
 		mailer.SendCollaboratorMail(u, ctx.User, ctx.Repo.Repository)
@@ -659,6 +711,41 @@ func ChangeCollaborationAccessMode(ctx *context.Context) {
 	}
 }
 
+// parseCollaborationExpiry parses an expiry date of a collaboration, the collaboration
+// expires at the end of that day. An empty date means the collaboration does not expire.
+func parseCollaborationExpiry(date string) (timeutil.TimeStamp, error) {
+	if len(date) == 0 {
+		return 0, nil
+	}
+	expires, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return 0, err
+	}
+	expires = time.Date(expires.Year(), expires.Month(), expires.Day(), 23, 59, 59, 0, expires.Location())
+	if expires.Before(time.Now()) {
+		return 0, fmt.Errorf("expiry date %s has passed", date)
+	}
+	return timeutil.TimeStamp(expires.Unix()), nil
+}
+
+// ChangeCollaborationExpiry response for changing the expiry date of a collaboration
+func ChangeCollaborationExpiry(ctx *context.Context) {
+	expires, err := parseCollaborationExpiry(ctx.Query("expires"))
+	if err != nil {
+		ctx.Flash.Error(ctx.Tr("repo.settings.collaboration.invalid_expiry"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings/collaboration")
+		return
+	}
+
+	if err := ctx.Repo.Repository.ChangeCollaborationExpiry(ctx.QueryInt64("uid"), expires); err != nil {
+		ctx.ServerError("ChangeCollaborationExpiry", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.collaboration.expiry_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/collaboration")
+}
+
 // DeleteCollaboration delete a collaboration for a repository
 func DeleteCollaboration(ctx *context.Context) {
 	if err := ctx.Repo.Repository.DeleteCollaboration(ctx.QueryInt64("id")); err != nil {
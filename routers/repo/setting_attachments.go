@@ -0,0 +1,123 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/url"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/unknwon/com"
+)
+
+const (
+	tplSettingsAttachments base.TplName = "repo/settings/attachments"
+)
+
+// SettingsAttachments lists the attachments of the issues, comments and releases of a repository
+func SettingsAttachments(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.attachments")
+	ctx.Data["PageIsSettingsAttachments"] = true
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	state := ctx.Query("state")
+	opts := &models.FindRepoAttachmentsOptions{
+		ListOptions: models.ListOptions{
+			Page:     page,
+			PageSize: setting.UI.ExplorePagingNum,
+		},
+		RepoID: ctx.Repo.Repository.ID,
+	}
+	switch state {
+	case "orphaned":
+		opts.Orphaned = util.OptionalBoolTrue
+	case "linked":
+		opts.Orphaned = util.OptionalBoolFalse
+	default:
+		state = "all"
+	}
+	ctx.Data["State"] = state
+
+	total, totalSize, err := models.CountRepoAttachments(opts)
+	if err != nil {
+		ctx.ServerError("CountRepoAttachments", err)
+		return
+	}
+	ctx.Data["Total"] = total
+	ctx.Data["TotalSize"] = totalSize
+
+	attachments, err := models.FindRepoAttachments(opts)
+	if err != nil {
+		ctx.ServerError("FindRepoAttachments", err)
+		return
+	}
+	if err := attachments.LoadAttributes(); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return
+	}
+	ctx.Data["Attachments"] = attachments
+
+	pager := context.NewPagination(int(total), setting.UI.ExplorePagingNum, page, 5)
+	pager.AddParam(ctx, "state", "State")
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(200, tplSettingsAttachments)
+}
+
+// SettingsAttachmentsDeletePost deletes the selected attachments of a repository
+func SettingsAttachmentsDeletePost(ctx *context.Context) {
+	strs := ctx.QueryStrings("ids[]")
+	ids := make([]int64, 0, len(strs))
+	for i := range strs {
+		id := com.StrTo(strs[i]).MustInt64()
+		if id > 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	attachments, err := models.GetRepoAttachmentsByIDs(ctx.Repo.Repository.ID, ids)
+	if err != nil {
+		ctx.ServerError("GetRepoAttachmentsByIDs", err)
+		return
+	}
+	if _, err := models.DeleteAttachments(attachments, true); err != nil {
+		ctx.ServerError("DeleteAttachments", err)
+		return
+	}
+
+	log.Trace("%d attachments of repository %s deleted by %s", len(attachments), ctx.Repo.Repository.FullName(), ctx.User.Name)
+	ctx.Flash.Success(ctx.Tr("repo.settings.attachments.delete_success", len(attachments)))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/attachments?state=" + url.QueryEscape(ctx.Query("state")))
+}
+
+// SettingsAttachmentsRetentionPost updates the retention of orphaned attachments of a repository
+func SettingsAttachmentsRetentionPost(ctx *context.Context, form auth.AttachmentRetentionForm) {
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(ctx.Repo.RepoLink + "/settings/attachments")
+		return
+	}
+
+	repo := ctx.Repo.Repository
+	repo.AttachmentRetentionDays = form.RetentionDays
+	if err := models.UpdateRepositoryCols(repo, "attachment_retention_days"); err != nil {
+		ctx.ServerError("UpdateRepositoryCols", err)
+		return
+	}
+
+	log.Trace("Attachment retention of repository %s set to %d days", repo.FullName(), repo.AttachmentRetentionDays)
+	ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/attachments")
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	issuesync_service "code.gitea.io/gitea/services/issuesync"
+)
+
+const (
+	tplSettingsIssueSync base.TplName = "repo/settings/issue_sync"
+)
+
+// MustEnableIssueSync check if the issues of the repository can be synced with its upstream repository
+func MustEnableIssueSync(ctx *context.Context) {
+	if !issuesync_service.IsAvailable(ctx.Repo.Repository) || !ctx.Repo.Repository.UnitEnabled(models.UnitTypeIssues) {
+		ctx.NotFound("MustEnableIssueSync", nil)
+	}
+}
+
+// getIssueSync returns the issue sync of the repository, nil if it has not been set up
+func getIssueSync(ctx *context.Context) *models.IssueSync {
+	s, err := models.GetIssueSyncByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		if !models.IsErrIssueSyncNotExist(err) {
+			ctx.ServerError("GetIssueSyncByRepoID", err)
+		}
+		return nil
+	}
+	return s
+}
+
+// SettingsIssueSync shows the issue sync of a repository migrated from GitHub
+func SettingsIssueSync(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.issue_sync")
+	ctx.Data["PageIsSettingsIssueSync"] = true
+
+	s := getIssueSync(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["IssueSync"] = s
+
+	ctx.HTML(200, tplSettingsIssueSync)
+}
+
+// SettingsIssueSyncPost sets up or updates the issue sync of a repository migrated from GitHub
+func SettingsIssueSyncPost(ctx *context.Context, form auth.IssueSyncForm) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.issue_sync")
+	ctx.Data["PageIsSettingsIssueSync"] = true
+
+	s := getIssueSync(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["IssueSync"] = s
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplSettingsIssueSync)
+		return
+	}
+
+	if s == nil {
+		s = &models.IssueSync{
+			RepoID: ctx.Repo.Repository.ID,
+			DoerID: ctx.User.ID,
+		}
+	}
+	s.PullEnabled = form.PullEnabled
+	s.PushEnabled = form.PushEnabled
+	// an empty token keeps the current token
+	if len(form.Token) > 0 {
+		if err := s.SetToken(form.Token); err != nil {
+			ctx.ServerError("SetToken", err)
+			return
+		}
+	}
+	if s.PushEnabled && len(s.EncryptedToken) == 0 {
+		ctx.Data["Err_Token"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.settings.issue_sync.token_required"), tplSettingsIssueSync, &form)
+		return
+	}
+
+	if err := models.SaveIssueSync(s); err != nil {
+		ctx.ServerError("SaveIssueSync", err)
+		return
+	}
+
+	log.Trace("Issue sync of repository %s updated by %s: pull %t, push %t", ctx.Repo.Repository.FullName(), ctx.User.Name, s.PullEnabled, s.PushEnabled)
+	ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/issue_sync")
+}
+
+// DeleteIssueSync stops the issue sync of a repository
+func DeleteIssueSync(ctx *context.Context) {
+	if err := models.DeleteIssueSync(ctx.Repo.Repository.ID); err != nil {
+		ctx.Flash.Error("DeleteIssueSync: " + err.Error())
+	} else {
+		log.Trace("Issue sync of repository %s removed by %s", ctx.Repo.Repository.FullName(), ctx.User.Name)
+		ctx.Flash.Success(ctx.Tr("repo.settings.issue_sync.deletion_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": ctx.Repo.RepoLink + "/settings/issue_sync",
+	})
+}
@@ -163,6 +163,15 @@ func SettingsProtectedBranch(c *context.Context) {
 		c.Data["approvals_whitelist_teams"] = strings.Join(base.Int64sToStrings(protectBranch.ApprovalsWhitelistTeamIDs), ",")
 	}
 
+	if protectBranch.ID > 0 {
+		changes, err := protectBranch.GetStatusCheckContextChanges(models.ListOptions{Page: 1, PageSize: 10})
+		if err != nil {
+			c.ServerError("GetStatusCheckContextChanges", err)
+			return
+		}
+		c.Data["StatusCheckContextChanges"] = changes
+	}
+
 	c.Data["Branch"] = protectBranch
 	c.HTML(200, tplProtectedBranch)
 }
@@ -251,6 +260,7 @@ func SettingsProtectedBranchPost(ctx *context.Context, f auth.ProtectBranchForm)
 		protectBranch.RequireSignedCommits = f.RequireSignedCommits
 		protectBranch.ProtectedFilePatterns = f.ProtectedFilePatterns
 		protectBranch.BlockOnOutdatedBranch = f.BlockOnOutdatedBranch
+		protectBranch.PushToPullRequest = models.ToPushToPullRequestMode(f.PushToPullRequest)
 
 		err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
 			UserIDs:          whitelistUsers,
@@ -0,0 +1,88 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+)
+
+const (
+	tplSettingsReleaseChannels base.TplName = "repo/settings/release_channels"
+)
+
+// SettingsReleaseChannels lists the release channels of a repository
+func SettingsReleaseChannels(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.release_channels")
+	ctx.Data["PageIsSettingsReleaseChannels"] = true
+
+	channels, err := models.GetReleaseChannels(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetReleaseChannels", err)
+		return
+	}
+	ctx.Data["ReleaseChannels"] = channels
+
+	ctx.HTML(200, tplSettingsReleaseChannels)
+}
+
+// SettingsReleaseChannelsPost adds a release channel to a repository
+func SettingsReleaseChannelsPost(ctx *context.Context, form auth.ReleaseChannelForm) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.release_channels")
+	ctx.Data["PageIsSettingsReleaseChannels"] = true
+
+	channels, err := models.GetReleaseChannels(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetReleaseChannels", err)
+		return
+	}
+	ctx.Data["ReleaseChannels"] = channels
+
+	if ctx.HasError() {
+		ctx.Data["HasError"] = true
+		ctx.HTML(200, tplSettingsReleaseChannels)
+		return
+	}
+
+	channel := &models.ReleaseChannel{
+		RepoID:     ctx.Repo.Repository.ID,
+		Name:       form.Name,
+		TagPattern: form.TagPattern,
+		KeepLast:   form.KeepLast,
+	}
+	if err := models.CreateReleaseChannel(channel); err != nil {
+		ctx.Data["HasError"] = true
+		switch {
+		case models.IsErrReleaseChannelAlreadyExist(err):
+			ctx.Data["Err_Name"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.release_channels.name_been_used"), tplSettingsReleaseChannels, &form)
+		case models.IsErrInvalidReleaseChannel(err):
+			ctx.RenderWithErr(ctx.Tr("repo.settings.release_channels.invalid", err.(models.ErrInvalidReleaseChannel).Reason), tplSettingsReleaseChannels, &form)
+		default:
+			ctx.ServerError("CreateReleaseChannel", err)
+		}
+		return
+	}
+
+	log.Trace("Release channel %s added to repository %s", channel.Name, ctx.Repo.Repository.FullName())
+	ctx.Flash.Success(ctx.Tr("repo.settings.release_channels.add_success", channel.Name))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/release_channels")
+}
+
+// DeleteReleaseChannel response for deleting a release channel
+func DeleteReleaseChannel(ctx *context.Context) {
+	if err := models.DeleteReleaseChannel(ctx.Repo.Repository.ID, ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteReleaseChannel: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.settings.release_channels.deletion_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": ctx.Repo.RepoLink + "/settings/release_channels",
+	})
+}
@@ -26,6 +26,7 @@ import (
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/repofiles"
 	"code.gitea.io/gitea/modules/setting"
 )
 
@@ -35,6 +36,8 @@ const (
 	tplWatchers  base.TplName = "repo/watchers"
 	tplForks     base.TplName = "repo/forks"
 	tplMigrating base.TplName = "repo/migrate/migrating"
+
+	tplSubmoduleTree base.TplName = "repo/submodule_tree"
 )
 
 type namedBlob struct {
@@ -143,11 +146,42 @@ func renderDirectory(ctx *context.Context, treeLink string) {
 	}
 
 	var latestCommit *git.Commit
-	ctx.Data["Files"], latestCommit, err = entries.GetCommitsInfo(ctx.Repo.Commit, ctx.Repo.TreePath, c)
+	var files [][]interface{}
+	files, latestCommit, err = entries.GetCommitsInfo(ctx.Repo.Commit, ctx.Repo.TreePath, c)
 	if err != nil {
 		ctx.ServerError("GetCommitsInfo", err)
 		return
 	}
+	ctx.Data["Files"] = files
+
+	// Submodules hosted on this instance can be expanded inline
+	submoduleTargets := make(map[string]*repofiles.SubmoduleTarget)
+	for _, file := range files {
+		entry := file[0].(*git.TreeEntry)
+		if !entry.IsSubModule() {
+			continue
+		}
+		sm, err := ctx.Repo.Commit.GetSubModule(path.Join(ctx.Repo.TreePath, entry.Name()))
+		if err != nil {
+			ctx.ServerError("GetSubModule", err)
+			return
+		} else if sm == nil {
+			continue
+		}
+		target, err := repofiles.GetSubmoduleTarget(ctx.Repo.Repository, ctx.User, sm.URL, entry.ID.String())
+		if err != nil {
+			ctx.ServerError("GetSubmoduleTarget", err)
+			return
+		} else if target != nil {
+			submoduleTargets[entry.Name()] = target
+		}
+	}
+	ctx.Data["SubmoduleTargets"] = submoduleTargets
+	submoduleLink := ctx.Repo.RepoLink + "/submodule/" + ctx.Repo.BranchNameSubURL()
+	if len(ctx.Repo.TreePath) > 0 {
+		submoduleLink += "/" + ctx.Repo.TreePath
+	}
+	ctx.Data["SubmoduleLink"] = submoduleLink
 
 	// 3 for the extensions in exts[] in order
 	// the last one is for a readme that doesn't
@@ -228,12 +262,13 @@ func renderDirectory(ctx *context.Context, treeLink string) {
 		}
 	}
 
-	if ctx.Repo.TreePath == "" && readmeFile == nil {
-		for _, entry := range docsEntries {
-			if entry == nil {
+	if readmeFile == nil {
+		for i, entry := range docsEntries {
+			// .gitea and .github only hold the README of the whole repository
+			if entry == nil || (i > 0 && ctx.Repo.TreePath != "") {
 				continue
 			}
-			readmeFile, err = getReadmeFileFromPath(ctx.Repo.Commit, entry.GetSubJumpablePathName())
+			readmeFile, err = getReadmeFileFromPath(ctx.Repo.Commit, path.Join(ctx.Repo.TreePath, entry.GetSubJumpablePathName()))
 			if err != nil {
 				ctx.ServerError("getReadmeFileFromPath", err)
 				return
@@ -454,7 +489,23 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 		ctx.Data["EditFileTooltip"] = ctx.Tr("repo.editor.cannot_edit_non_text_files")
 	}
 
+	isPreview := false
+	if markup.PreviewType(blob.Name()) != "" && fileSize < setting.UI.MaxDisplayFileSize {
+		d, _ := ioutil.ReadAll(dataRc)
+		buf = append(buf, d...)
+		preview, err := markup.Preview(blob.Name(), bytes.NewReader(buf), ctx.Data["RawFileLink"].(string))
+		if err != nil {
+			log.Error("Preview failed: %v", err)
+		} else {
+			isPreview = true
+			ctx.Data["IsPreview"] = true
+			ctx.Data["PreviewType"] = markup.PreviewType(blob.Name())
+			ctx.Data["FileContent"] = string(preview)
+		}
+	}
+
 	switch {
+	case isPreview:
 	case isTextFile:
 		if fileSize >= setting.UI.MaxDisplayFileSize {
 			ctx.Data["IsFileTooLarge"] = true
@@ -736,3 +787,72 @@ func Forks(ctx *context.Context) {
 
 	ctx.HTML(200, tplForks)
 }
+
+// SubmoduleTree renders the entries of a submodule hosted on this instance at the commit it
+// points to, used to expand the submodule inline in the file browser
+func SubmoduleTree(ctx *context.Context) {
+	entry, err := ctx.Repo.Commit.GetTreeEntryByPath(ctx.Repo.TreePath)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetTreeEntryByPath", git.IsErrNotExist, err)
+		return
+	}
+	if !entry.IsSubModule() {
+		ctx.NotFound("IsSubModule", nil)
+		return
+	}
+	sm, err := ctx.Repo.Commit.GetSubModule(ctx.Repo.TreePath)
+	if err != nil {
+		ctx.ServerError("GetSubModule", err)
+		return
+	} else if sm == nil {
+		ctx.NotFound("GetSubModule", nil)
+		return
+	}
+
+	target, err := repofiles.GetSubmoduleTarget(ctx.Repo.Repository, ctx.User, sm.URL, entry.ID.String())
+	if err != nil {
+		ctx.ServerError("GetSubmoduleTarget", err)
+		return
+	} else if target == nil {
+		ctx.NotFound("GetSubmoduleTarget", nil)
+		return
+	}
+
+	gitRepo, err := git.OpenRepository(target.Repo.RepoPath())
+	if err != nil {
+		ctx.ServerError("OpenRepository", err)
+		return
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(target.CommitID)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetCommit", git.IsErrNotExist, err)
+		return
+	}
+
+	dir := strings.TrimPrefix(path.Clean("/"+ctx.Query("dir")), "/")
+	tree, err := commit.SubTree(dir)
+	if err != nil {
+		ctx.NotFoundOrServerError("SubTree", git.IsErrNotExist, err)
+		return
+	}
+	entries, err := tree.ListEntries()
+	if err != nil {
+		ctx.ServerError("ListEntries", err)
+		return
+	}
+	entries.CustomSort(base.NaturalSortLess)
+
+	indent := 1
+	if len(dir) > 0 {
+		indent += strings.Count(dir, "/") + 1
+	}
+	ctx.Data["SubmoduleIndent"] = indent
+	ctx.Data["SubmoduleDir"] = dir
+	ctx.Data["SubmoduleLink"] = ctx.Repo.RepoLink + "/submodule/" + ctx.Repo.BranchNameSubURL() + "/" + ctx.Repo.TreePath
+	ctx.Data["SubmoduleName"] = entry.Name()
+	ctx.Data["SubmoduleTarget"] = target
+	ctx.Data["SubmoduleEntries"] = entries
+	ctx.HTML(200, tplSubmoduleTree)
+}
@@ -11,16 +11,15 @@ import (
 	"fmt"
 	"path"
 	"strings"
+	"unicode"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
-	"code.gitea.io/gitea/modules/convert"
-	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/setting"
-	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/webhook"
+	webhook_service "code.gitea.io/gitea/services/webhook"
 
 	"github.com/unknwon/com"
 )
@@ -172,9 +171,22 @@ func ParseHookEvent(form auth.WebhookForm) *models.HookEvent {
 			Repository:           form.Repository,
 		},
 		BranchFilter: form.BranchFilter,
+		TopicFilter:  parseTopicFilter(form.TopicFilter),
 	}
 }
 
+// parseTopicFilter splits the comma or space separated topic filter of a webhook form.
+// Invalid topics are dropped as no repository can carry them.
+func parseTopicFilter(filter string) []string {
+	topics, _ := models.SanitizeAndValidateTopics(strings.FieldsFunc(filter, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}))
+	if len(topics) == 0 {
+		return nil
+	}
+	return topics
+}
+
 // GiteaHooksNewPost response for creating Gitea webhook
 func GiteaHooksNewPost(ctx *context.Context, form auth.NewWebhookForm) {
 	ctx.Data["Title"] = ctx.Tr("repo.settings.add_webhook")
@@ -677,6 +689,7 @@ func WebHooksEdit(ctx *context.Context) {
 		return
 	}
 	ctx.Data["Webhook"] = w
+	ctx.Data["SampleEvents"] = webhook_service.SampleEvents
 
 	ctx.HTML(200, orCtx.NewTemplate)
 }
@@ -1031,7 +1044,7 @@ func FeishuHooksEditPost(ctx *context.Context, form auth.NewFeishuHookForm) {
 	ctx.Redirect(fmt.Sprintf("%s/%d", orCtx.Link, w.ID))
 }
 
-// TestWebhook test if web hook is work fine
+// TestWebhook sends a test delivery with a sample payload of the selected event to the webhook
 func TestWebhook(ctx *context.Context) {
 	hookID := ctx.ParamsInt64(":id")
 	w, err := models.GetWebhookByRepoID(ctx.Repo.Repository.ID, hookID)
@@ -1041,44 +1054,18 @@ func TestWebhook(ctx *context.Context) {
 		return
 	}
 
-	// Grab latest commit or fake one if it's empty repository.
-	commit := ctx.Repo.Commit
-	if commit == nil {
-		ghost := models.NewGhostUser()
-		commit = &git.Commit{
-			ID:            git.MustIDFromString(git.EmptySHA),
-			Author:        ghost.NewGitSig(),
-			Committer:     ghost.NewGitSig(),
-			CommitMessage: "This is a fake commit",
-		}
+	event := models.HookEventType(ctx.QueryTrim("event"))
+	if len(event) == 0 {
+		event = models.HookEventPush
 	}
 
-	apiUser := convert.ToUser(ctx.User, true, true)
-	p := &api.PushPayload{
-		Ref:    git.BranchPrefix + ctx.Repo.Repository.DefaultBranch,
-		Before: commit.ID.String(),
-		After:  commit.ID.String(),
-		Commits: []*api.PayloadCommit{
-			{
-				ID:      commit.ID.String(),
-				Message: commit.Message(),
-				URL:     ctx.Repo.Repository.HTMLURL() + "/commit/" + commit.ID.String(),
-				Author: &api.PayloadUser{
-					Name:  commit.Author.Name,
-					Email: commit.Author.Email,
-				},
-				Committer: &api.PayloadUser{
-					Name:  commit.Committer.Name,
-					Email: commit.Committer.Email,
-				},
-			},
-		},
-		Repo:   ctx.Repo.Repository.APIFormat(models.AccessModeNone),
-		Pusher: apiUser,
-		Sender: apiUser,
-	}
-	if err := webhook.PrepareWebhook(w, ctx.Repo.Repository, models.HookEventPush, p); err != nil {
-		ctx.Flash.Error("PrepareWebhook: " + err.Error())
+	if err := webhook_service.SendTestDelivery(w, event, ctx.Repo.Repository, ctx.User, ctx.Repo.Commit); err != nil {
+		if webhook_service.IsErrUnknownSampleEvent(err) {
+			ctx.Flash.Error(ctx.Tr("repo.settings.webhook.test_delivery_unknown_event", event))
+			ctx.Status(422)
+			return
+		}
+		ctx.Flash.Error("SendTestDelivery: " + err.Error())
 		ctx.Status(500)
 	} else {
 		ctx.Flash.Info(ctx.Tr("repo.settings.webhook.test_delivery_success"))
@@ -23,6 +23,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/routers"
+	"code.gitea.io/gitea/services/replication"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
@@ -193,6 +194,24 @@ func storageHandler(storageSetting setting.Storage, prefix string, objStore stor
 	}
 }
 
+// replicaProxyHandler forwards the requests a read replica cannot serve itself to the primary
+func replicaProxyHandler() func(next http.Handler) http.Handler {
+	proxy, err := replication.NewPrimaryProxy()
+	if err != nil {
+		log.Fatal("Failed to create proxy to the primary: %v", err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if replication.ServedByReplica(req) {
+				next.ServeHTTP(w, req)
+				return
+			}
+			proxy.ServeHTTP(w, req)
+		})
+	}
+}
+
 // NewChi creates a chi Router
 func NewChi() chi.Router {
 	c := chi.NewRouter()
@@ -206,6 +225,9 @@ func NewChi() chi.Router {
 	if setting.EnableAccessLog {
 		setupAccessLogger(c)
 	}
+	if setting.Replication.IsReplica {
+		c.Use(replicaProxyHandler())
+	}
 
 	c.Use(public.Custom(
 		&public.Options{
@@ -302,6 +302,9 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Combo("/keys").Get(userSetting.Keys).
 			Post(bindIgnErr(auth.AddKeyForm{}), userSetting.KeysPost)
 		m.Post("/keys/delete", userSetting.DeleteKey)
+		m.Combo("/notifications").Get(userSetting.Notifications).
+			Post(bindIgnErr(auth.NotificationRuleForm{}), userSetting.NotificationRulesPost)
+		m.Post("/notifications/delete", userSetting.DeleteNotificationRule)
 		m.Get("/organization", userSetting.Organization)
 		m.Get("/repos", userSetting.Repos)
 		m.Post("/repos/unadopted", userSetting.AdoptOrDeleteRepository)
@@ -322,6 +325,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Post("/forgot_password", user.ForgotPasswdPost)
 		m.Post("/logout", user.SignOut)
 		m.Get("/task/:task", user.TaskStatus)
+		m.Combo("/report_abuse", reqSignIn, user.MustEnableAbuseReports).Get(user.ReportAbuse).
+			Post(bindIgnErr(auth.ReportAbuseForm{}), user.ReportAbusePost)
 	})
 	// ***** END: User *****
 
@@ -350,6 +355,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Group("/users", func() {
 			m.Get("", admin.Users)
 			m.Combo("/new").Get(admin.NewUser).Post(bindIgnErr(auth.AdminCreateUserForm{}), admin.NewUserPost)
+			m.Get("/dormant", admin.DormantUsers)
 			m.Combo("/:userid").Get(admin.EditUser).Post(bindIgnErr(auth.AdminEditUserForm{}), admin.EditUserPost)
 			m.Post("/:userid/delete", admin.DeleteUser)
 		})
@@ -407,13 +413,30 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Post("/delete", admin.DeleteNotices)
 			m.Post("/empty", admin.EmptyNotices)
 		})
+
+		m.Group("/abuse_reports", func() {
+			m.Get("", admin.AbuseReports)
+			m.Combo("/:id").Get(admin.ViewAbuseReport).Post(admin.AbuseReportPost)
+		}, func(ctx *context.Context) {
+			if !setting.Moderation.Enabled {
+				ctx.NotFound("AbuseReports", nil)
+			}
+		})
+
+		m.Group("/feature_flags", func() {
+			m.Get("", admin.FeatureFlags)
+			m.Combo("/new").Get(admin.NewFeatureFlag).Post(bindIgnErr(auth.AdminFeatureFlagForm{}), admin.NewFeatureFlagPost)
+			m.Post("/delete", admin.DeleteFeatureFlag)
+			m.Combo("/:id").Get(admin.EditFeatureFlag).Post(bindIgnErr(auth.AdminFeatureFlagForm{}), admin.EditFeatureFlagPost)
+		})
 	}, adminReq)
 	// ***** END: Admin *****
 
 	m.Group("", func() {
 		m.Get("/:username", user.Profile)
-		m.Get("/attachments/:uuid", repo.GetAttachment)
 	}, ignSignIn)
+	// signed attachment urls can be used without signing in
+	m.Get("/attachments/:uuid", repo.GetSignedAttachment, ignSignIn, repo.GetAttachment)
 
 	m.Group("/:username", func() {
 		m.Post("/action/:action", user.Action)
@@ -468,6 +491,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Post("/teams/:team/edit", bindIgnErr(auth.CreateTeamForm{}), org.EditTeamPost)
 			m.Post("/teams/:team/delete", org.DeleteTeam)
 
+			m.Get("/outside_collaborators", org.OutsideCollaborators)
+
 			m.Group("/settings", func() {
 				m.Combo("").Get(org.Settings).
 					Post(bindIgnErr(auth.UpdateOrgSettingForm{}), org.SettingsPost)
@@ -507,6 +532,23 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 					m.Post("/initialize", bindIgnErr(auth.InitializeLabelsForm{}), org.InitializeLabels)
 				})
 
+				m.Group("/repo_presets", func() {
+					m.Get("", org.RepoPresets)
+					m.Combo("/new").Get(org.NewRepoPreset).
+						Post(bindIgnErr(auth.RepoPresetForm{}), org.NewRepoPresetPost)
+					m.Post("/delete", org.DeleteRepoPreset)
+					m.Combo("/:id").Get(org.EditRepoPreset).
+						Post(bindIgnErr(auth.RepoPresetForm{}), org.EditRepoPresetPost)
+				})
+
+				m.Combo("/notification_rules").Get(org.NotificationRules).
+					Post(bindIgnErr(auth.NotificationRuleForm{}), org.NotificationRulesPost)
+				m.Post("/notification_rules/delete", org.DeleteNotificationRule)
+
+				m.Combo("/security").Get(org.SettingsSecurity).
+					Post(bindIgnErr(auth.OrgTwoFactorForm{}), org.SettingsSecurityPost)
+				m.Get("/token_usage", org.SettingsTokenUsage)
+
 				m.Route("/delete", "GET,POST", org.SettingsDelete)
 			})
 		}, context.OrgAssignment(true, true))
@@ -538,6 +580,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Group("/collaboration", func() {
 				m.Combo("").Get(repo.Collaboration).Post(repo.CollaborationPost)
 				m.Post("/access_mode", repo.ChangeCollaborationAccessMode)
+				m.Post("/expiry", repo.ChangeCollaborationExpiry)
 				m.Post("/delete", repo.DeleteCollaboration)
 				m.Group("/team", func() {
 					m.Post("", repo.AddTeamPost)
@@ -588,6 +631,24 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 				m.Post("/delete", repo.DeleteDeployKey)
 			})
 
+			m.Group("/attachments", func() {
+				m.Get("", repo.SettingsAttachments)
+				m.Post("/delete", repo.SettingsAttachmentsDeletePost)
+				m.Post("/retention", bindIgnErr(auth.AttachmentRetentionForm{}), repo.SettingsAttachmentsRetentionPost)
+			})
+
+			m.Group("/release_channels", func() {
+				m.Combo("").Get(repo.SettingsReleaseChannels).
+					Post(bindIgnErr(auth.ReleaseChannelForm{}), repo.SettingsReleaseChannelsPost)
+				m.Post("/delete", repo.DeleteReleaseChannel)
+			})
+
+			m.Group("/issue_sync", func() {
+				m.Combo("").Get(repo.SettingsIssueSync).
+					Post(bindIgnErr(auth.IssueSyncForm{}), repo.SettingsIssueSyncPost)
+				m.Post("/delete", repo.DeleteIssueSync)
+			}, repo.MustEnableIssueSync)
+
 			m.Group("/lfs", func() {
 				m.Get("", repo.LFSFiles)
 				m.Get("/show/:oid", repo.LFSFileGet)
@@ -629,6 +690,10 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 				m.Get("/choose", context.RepoRef(), repo.NewIssueChooseTemplate)
 			})
 		}, context.RepoMustNotBeArchived(), reqRepoIssueReader)
+		m.Group("/issues/scheduled", func() {
+			m.Get("", repo.ScheduledPosts)
+			m.Post("/:id/delete", repo.DeleteScheduledPost)
+		}, context.RepoMustNotBeArchived(), reqRepoIssuesOrPullsReader)
 		// FIXME: should use different URLs but mostly same logic for comments of issue and pull reuqest.
 		// So they can apply their own enable/disable logic on routers.
 		m.Group("/issues", func() {
@@ -656,6 +721,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Group("/:index", func() {
 				m.Get("/attachments", repo.GetIssueAttachments)
 				m.Get("/attachments/:uuid", repo.GetAttachment)
+				m.Get("/translation", repo.MustEnableTranslation, repo.IssueTranslation)
 			})
 
 			m.Post("/labels", reqRepoIssuesOrPullsWriter, repo.UpdateIssueLabel)
@@ -675,6 +741,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		}, context.RepoMustNotBeArchived())
 		m.Group("/comments/:id", func() {
 			m.Get("/attachments", repo.GetCommentAttachments)
+			m.Get("/translation", repo.MustEnableTranslation, repo.CommentTranslation)
 		})
 		m.Group("/labels", func() {
 			m.Post("/new", bindIgnErr(auth.CreateLabelForm{}), repo.NewLabel)
@@ -733,6 +800,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Get("/", repo.Releases)
 			m.Get("/tag/*", repo.SingleRelease)
 			m.Get("/latest", repo.LatestRelease)
+			m.Get("/channels/:channel/latest", repo.LatestChannelRelease)
+			m.Get("/channels/:channel/rss", repo.ChannelReleasesFeed)
 			m.Get("/attachments/:uuid", repo.GetAttachment)
 		}, repo.MustBeNotEmpty, reqRepoReleaseReader, context.RepoRefByType(context.RepoRefTag))
 		m.Group("/releases", func() {
@@ -778,6 +847,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Group("/projects", func() {
 			m.Get("", repo.Projects)
 			m.Get("/:id", repo.ViewProject)
+			m.Get("/:id/analytics", repo.ProjectAnalytics)
 			m.Group("", func() {
 				m.Get("/new", repo.NewProject)
 				m.Post("/new", bindIgnErr(auth.CreateProjectForm{}), repo.NewProjectPost)
@@ -826,6 +896,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Get("/:period", repo.Activity)
 		}, context.RepoRef(), repo.MustBeNotEmpty, context.RequireRepoReaderOr(models.UnitTypePullRequests, models.UnitTypeIssues, models.UnitTypeReleases))
 
+		m.Get("/activity/code_frequency", context.RepoRef(), repo.MustBeNotEmpty, reqRepoCodeReader, repo.ActivityCodeFrequency)
+
 		m.Group("/activity_author_data", func() {
 			m.Get("", repo.ActivityAuthors)
 			m.Get("/:period", repo.ActivityAuthors)
@@ -834,6 +906,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Group("/archive", func() {
 			m.Get("/*", repo.Download)
 			m.Post("/*", repo.InitiateDownload)
+			m.Delete("/*", reqSignIn, repo.CancelDownload)
 		}, repo.MustBeNotEmpty, reqRepoCodeReader)
 
 		m.Group("/branches", func() {
@@ -897,6 +970,12 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 			m.Get("/commit/:sha([a-f0-9]{7,40})$", repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.Diff)
 		}, repo.MustBeNotEmpty, context.RepoRef(), reqRepoCodeReader)
 
+		m.Group("/submodule", func() {
+			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.SubmoduleTree)
+			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.SubmoduleTree)
+			m.Get("/commit/*", context.RepoRefByType(context.RepoRefCommit), repo.SubmoduleTree)
+		}, repo.MustBeNotEmpty, reqRepoCodeReader)
+
 		m.Group("/src", func() {
 			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.Home)
 			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.Home)
@@ -907,6 +986,8 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 
 		m.Group("", func() {
 			m.Get("/forks", repo.Forks)
+			m.Get("/forks/network", repo.ForkNetwork)
+			m.Post("/forks/network", adminReq, repo.ForkNetworkPost)
 		}, context.RepoRef(), reqRepoCodeReader)
 		m.Get("/commit/:sha([a-f0-9]{7,40})\\.:ext(patch|diff)",
 			repo.MustBeNotEmpty, reqRepoCodeReader, repo.RawDiff)
@@ -915,6 +996,7 @@ func RegisterMacaronRoutes(m *macaron.Macaron) {
 		m.Get("/stars", repo.Stars)
 		m.Get("/watchers", repo.Watchers)
 		m.Get("/search", reqRepoCodeReader, repo.Search)
+		m.Get("/events", reqSignIn, events.RepoEvents)
 	}, ignSignIn, context.RepoAssignment(), context.RepoRef(), context.UnitTypes())
 
 	m.Group("/:username", func() {
@@ -0,0 +1,108 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/moderation"
+)
+
+const (
+	tplReportAbuse base.TplName = "user/report_abuse"
+)
+
+// MustEnableAbuseReports check if users are allowed to report abuse
+func MustEnableAbuseReports(ctx *context.Context) {
+	if !setting.Moderation.Enabled {
+		ctx.NotFound("MustEnableAbuseReports", nil)
+	}
+}
+
+// prepareAbuseReport loads the content to report and checks the user is allowed to see it
+func prepareAbuseReport(ctx *context.Context, contentType string, contentID int64) *models.AbuseReport {
+	r := &models.AbuseReport{
+		ReporterID:  ctx.User.ID,
+		Reporter:    ctx.User,
+		ContentType: models.ParseAbuseReportContentType(contentType),
+		ContentID:   contentID,
+	}
+	if err := r.LoadContent(); err != nil {
+		if models.IsErrAbuseReportContentNotExist(err) {
+			ctx.NotFound("LoadContent", err)
+		} else {
+			ctx.ServerError("LoadContent", err)
+		}
+		return nil
+	}
+
+	if r.ContentOwnerID == ctx.User.ID {
+		ctx.NotFound("ReportAbuse", nil)
+		return nil
+	}
+	if r.Repo != nil {
+		perm, err := models.GetUserRepoPermission(r.Repo, ctx.User)
+		if err != nil {
+			ctx.ServerError("GetUserRepoPermission", err)
+			return nil
+		}
+		if !perm.HasAccess() || (r.Issue != nil && !perm.CanReadIssuesOrPulls(r.Issue.IsPull)) {
+			ctx.NotFound("ReportAbuse", nil)
+			return nil
+		}
+	} else if r.ContentOwner.IsOrganization() && !models.HasOrgVisible(r.ContentOwner, ctx.User) {
+		ctx.NotFound("ReportAbuse", nil)
+		return nil
+	}
+
+	ctx.Data["Title"] = ctx.Tr("report_abuse")
+	ctx.Data["Report"] = r
+	ctx.Data["Reasons"] = models.AbuseReportReasons
+	return r
+}
+
+// ReportAbuse render reporting an issue, a comment, a repository or a user to the site administrators
+func ReportAbuse(ctx *context.Context) {
+	prepareAbuseReport(ctx, ctx.Query("type"), ctx.QueryInt64("id"))
+	if ctx.Written() {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplReportAbuse)
+}
+
+// ReportAbusePost response for reporting abuse
+func ReportAbusePost(ctx *context.Context, form auth.ReportAbuseForm) {
+	r := prepareAbuseReport(ctx, form.Type, form.ID)
+	if ctx.Written() {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplReportAbuse)
+		return
+	}
+
+	r.Reason = models.AbuseReportReason(form.Reason)
+	r.Remark = form.Remark
+
+	if err := moderation.ReportAbuse(r); err != nil {
+		if models.IsErrAbuseReportReasonInvalid(err) {
+			ctx.Data["Err_Reason"] = true
+			ctx.RenderWithErr(ctx.Tr("report_abuse.invalid_reason"), tplReportAbuse, &form)
+			return
+		} else if !models.IsErrAbuseReportAlreadyExist(err) {
+			ctx.ServerError("ReportAbuse", err)
+			return
+		}
+		ctx.Flash.Info(ctx.Tr("report_abuse.already_reported"))
+	} else {
+		ctx.Flash.Success(ctx.Tr("report_abuse.success"))
+	}
+	ctx.Redirect(r.HTMLURL())
+}
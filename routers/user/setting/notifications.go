@@ -0,0 +1,95 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplSettingsNotifications base.TplName = "user/settings/notifications"
+)
+
+func loadNotificationRulesData(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings.notifications")
+	ctx.Data["PageIsSettingsNotifications"] = true
+	ctx.Data["NotificationRulesLink"] = setting.AppSubURL + "/user/settings/notifications"
+	ctx.Data["NotificationRuleEvents"] = models.NotificationRuleEvents
+	ctx.Data["NotificationRuleActions"] = models.NotificationRuleActions
+	switch ctx.User.EmailNotifications() {
+	case models.EmailNotificationsEnabled:
+		ctx.Data["EmailNotificationsPreferenceDesc"] = ctx.Tr("settings.email_notifications.enable")
+	case models.EmailNotificationsOnMention:
+		ctx.Data["EmailNotificationsPreferenceDesc"] = ctx.Tr("settings.email_notifications.onmention")
+	default:
+		ctx.Data["EmailNotificationsPreferenceDesc"] = ctx.Tr("settings.email_notifications.disable")
+	}
+
+	rules, err := ctx.User.GetNotificationRules()
+	if err != nil {
+		ctx.ServerError("GetNotificationRules", err)
+		return
+	}
+	ctx.Data["NotificationRules"] = rules
+}
+
+// Notifications render the notification rules of the user
+func Notifications(ctx *context.Context) {
+	loadNotificationRulesData(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.HTML(http.StatusOK, tplSettingsNotifications)
+}
+
+// NotificationRulesPost response for adding a notification rule of the user
+func NotificationRulesPost(ctx *context.Context, form auth.NotificationRuleForm) {
+	loadNotificationRulesData(ctx)
+	if ctx.Written() {
+		return
+	}
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplSettingsNotifications)
+		return
+	}
+
+	if err := models.CreateNotificationRule(&models.NotificationRule{
+		OwnerID:     ctx.User.ID,
+		RepoPattern: form.RepoPattern,
+		Event:       models.NotificationRuleEvent(form.Event),
+		Action:      models.NotificationRuleAction(form.Action),
+		Priority:    form.Priority,
+	}); err != nil {
+		if models.IsErrInvalidNotificationRule(err) {
+			ctx.Data["Err_RepoPattern"] = true
+			ctx.RenderWithErr(ctx.Tr("settings.notification_rules.invalid", err.(models.ErrInvalidNotificationRule).Reason), tplSettingsNotifications, &form)
+		} else {
+			ctx.ServerError("CreateNotificationRule", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.notification_rules.add_success"))
+	ctx.Redirect(setting.AppSubURL + "/user/settings/notifications")
+}
+
+// DeleteNotificationRule response for deleting a notification rule of the user
+func DeleteNotificationRule(ctx *context.Context) {
+	if err := models.DeleteNotificationRule(ctx.User.ID, ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteNotificationRule: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.notification_rules.deletion_success"))
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/user/settings/notifications",
+	})
+}
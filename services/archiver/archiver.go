@@ -6,6 +6,8 @@
 package archiver
 
 import (
+	gocontext "context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -20,10 +22,55 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 )
 
+// ArchiveStatus represents the state of an archive request
+type ArchiveStatus int
+
+// enumerates all the states of an archive request
+const (
+	ArchiveQueued ArchiveStatus = iota
+	ArchiveRunning
+	ArchiveComplete
+	ArchiveFailed
+	ArchiveCancelled
+)
+
+// String returns the name of the status
+func (s ArchiveStatus) String() string {
+	switch s {
+	case ArchiveQueued:
+		return "queued"
+	case ArchiveRunning:
+		return "running"
+	case ArchiveComplete:
+		return "complete"
+	case ArchiveFailed:
+		return "failed"
+	case ArchiveCancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
+
+// ErrTooManyArchives represents an error that a user has too many archive requests in progress
+type ErrTooManyArchives struct {
+	Limit int
+}
+
+// IsErrTooManyArchives checks if an error is a ErrTooManyArchives.
+func IsErrTooManyArchives(err error) bool {
+	_, ok := err.(ErrTooManyArchives)
+	return ok
+}
+
+func (err ErrTooManyArchives) Error() string {
+	return fmt.Sprintf("too many archive requests in progress [limit: %d]", err.Limit)
+}
+
 // ArchiveRequest defines the parameters of an archive request, which notably
 // includes the specific repository being archived as well as the commit, the
 // name by which it was requested, and the kind of archive being requested.
@@ -39,11 +86,22 @@ type ArchiveRequest struct {
 	archiveComplete bool
 	commit          *git.Commit
 	cchan           chan struct{}
+
+	// The following fields are protected by archiveMutex.
+	status     ArchiveStatus
+	tmpPath    string
+	cancel     gocontext.CancelFunc
+	doerID     int64
+	requesters map[int64]struct{}
 }
 
 var archiveInProgress []*ArchiveRequest
 var archiveMutex sync.Mutex
 
+// archiveQueue represents a queue to handle archive requests. Only the key of the
+// requests is queued, the requests themselves are kept in archiveInProgress.
+var archiveQueue queue.UniqueQueue
+
 // SHA1 hashes will only go up to 40 characters, but SHA256 hashes will go all
 // the way to 64.
 var shaRegex = regexp.MustCompile(`^[0-9a-f]{4,64}$`)
@@ -70,6 +128,59 @@ func (aReq *ArchiveRequest) IsComplete() bool {
 	return aReq.archiveComplete
 }
 
+// key returns the key identifying the request in the queue
+func (aReq *ArchiveRequest) key() string {
+	return fmt.Sprintf("%s:%s:%d", aReq.repo.Path, aReq.commit.ID.String(), aReq.archiveType)
+}
+
+// Status returns the state of this request.
+func (aReq *ArchiveRequest) Status() ArchiveStatus {
+	if aReq.IsComplete() {
+		return ArchiveComplete
+	}
+	archiveMutex.Lock()
+	defer archiveMutex.Unlock()
+	return aReq.status
+}
+
+// Progress returns the number of bytes of the archive written so far.
+func (aReq *ArchiveRequest) Progress() int64 {
+	p := aReq.archivePath
+	if !aReq.IsComplete() {
+		archiveMutex.Lock()
+		p = aReq.tmpPath
+		archiveMutex.Unlock()
+	}
+	if len(p) == 0 {
+		return 0
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		return 0
+	}
+	return fi.Size()
+}
+
+// QueuePosition returns the number of queued requests ahead of this request,
+// or -1 if the request is not queued.
+func (aReq *ArchiveRequest) QueuePosition() int {
+	archiveMutex.Lock()
+	defer archiveMutex.Unlock()
+	if aReq.status != ArchiveQueued || aReq.archiveComplete {
+		return -1
+	}
+	pos := 0
+	for _, r := range archiveInProgress {
+		if r == aReq {
+			return pos
+		}
+		if r.status == ArchiveQueued {
+			pos++
+		}
+	}
+	return -1
+}
+
 // WaitForCompletion will wait for this request to complete, with no timeout.
 // It returns whether the archive was actually completed, as the channel could
 // have also been closed due to an error.
@@ -101,6 +212,11 @@ func (aReq *ArchiveRequest) TimedWaitForCompletion(ctx *context.Context, dur tim
 // The caller must hold the archiveMutex across calls to getArchiveRequest.
 func getArchiveRequest(repo *git.Repository, commit *git.Commit, archiveType git.ArchiveType) *ArchiveRequest {
 	for _, r := range archiveInProgress {
+		// Cancelled or failed requests are about to be purged, they must not
+		// be handed out anymore.
+		if r.status == ArchiveCancelled || r.status == ArchiveFailed {
+			continue
+		}
 		// Need to be referring to the same repository.
 		if r.repo.Path == repo.Path && r.commit.ID == commit.ID && r.archiveType == archiveType {
 			return r
@@ -188,7 +304,7 @@ func DeriveRequestFrom(ctx *context.Context, uri string) *ArchiveRequest {
 	return r
 }
 
-func doArchive(r *ArchiveRequest) {
+func doArchive(ctx gocontext.Context, r *ArchiveRequest) {
 	var (
 		err         error
 		tmpArchive  *os.File
@@ -225,7 +341,16 @@ func doArchive(r *ArchiveRequest) {
 		os.Remove(tmpArchive.Name())
 	}()
 
-	if err = r.commit.CreateArchive(graceful.GetManager().ShutdownContext(), tmpArchive.Name(), git.CreateArchiveOpts{
+	archiveMutex.Lock()
+	r.tmpPath = tmpArchive.Name()
+	archiveMutex.Unlock()
+	defer func() {
+		archiveMutex.Lock()
+		r.tmpPath = ""
+		archiveMutex.Unlock()
+	}()
+
+	if err = r.commit.CreateArchive(ctx, tmpArchive.Name(), git.CreateArchiveOpts{
 		Format: r.archiveType,
 		Prefix: setting.Repository.PrefixArchiveFiles,
 	}); err != nil {
@@ -249,71 +374,185 @@ func doArchive(r *ArchiveRequest) {
 	r.archiveComplete = true
 }
 
+// removeArchiveRequest removes the request from archiveInProgress.
+// The caller must hold the archiveMutex.
+func removeArchiveRequest(request *ArchiveRequest) {
+	// To do so, we'll just take the index at which we ended up at and drop it.
+	// The slice may have changed since the request was added and we may have
+	// moved, so we search for it here.
+	idx := -1
+	for _idx, req := range archiveInProgress {
+		if req == request {
+			idx = _idx
+			break
+		}
+	}
+	if idx == -1 {
+		log.Error("ArchiveRepository: Failed to find request for removal.")
+		return
+	}
+	archiveInProgress = append(archiveInProgress[:idx], archiveInProgress[idx+1:]...)
+}
+
+// handle processes the queued archive requests
+func handle(data ...queue.Data) {
+	for _, datum := range data {
+		key := datum.(string)
+
+		archiveMutex.Lock()
+		var request *ArchiveRequest
+		for _, r := range archiveInProgress {
+			if r.status == ArchiveQueued && r.key() == key {
+				request = r
+				break
+			}
+		}
+		if request == nil {
+			// The request was cancelled or belongs to a previous run of Gitea.
+			archiveMutex.Unlock()
+			log.Trace("archive request %s is not in progress anymore", key)
+			continue
+		}
+		ctx, cancel := gocontext.WithCancel(graceful.GetManager().ShutdownContext())
+		request.status = ArchiveRunning
+		request.cancel = cancel
+		archiveMutex.Unlock()
+
+		processArchiveRequest(ctx, request)
+		cancel()
+	}
+}
+
+func processArchiveRequest(ctx gocontext.Context, request *ArchiveRequest) {
+	// Wait to start, if we have the Cond for it.  This is currently only
+	// useful for testing, so that the start and release of queued entries
+	// can be controlled to examine the queue.
+	if archiveQueueStartCond != nil {
+		archiveQueueMutex.Lock()
+		archiveQueueStartCond.Wait()
+		archiveQueueMutex.Unlock()
+	}
+
+	// Drop the mutex while we process the request.  This may take a long
+	// time, and it's not necessary now that we've added the request to
+	// archiveInProgress.
+	doArchive(ctx, request)
+
+	archiveMutex.Lock()
+	switch {
+	case request.archiveComplete:
+		request.status = ArchiveComplete
+	case ctx.Err() != nil || request.status == ArchiveCancelled:
+		request.status = ArchiveCancelled
+	default:
+		request.status = ArchiveFailed
+	}
+	archiveMutex.Unlock()
+
+	if archiveQueueReleaseCond != nil {
+		archiveQueueMutex.Lock()
+		archiveQueueReleaseCond.Wait()
+		archiveQueueMutex.Unlock()
+	}
+
+	// Purge this request from the list.
+	archiveMutex.Lock()
+	defer archiveMutex.Unlock()
+	removeArchiveRequest(request)
+}
+
 // ArchiveRepository satisfies the ArchiveRequest being passed in.  Processing
-// will occur in a separate goroutine, as this phase may take a while to
+// will occur on the archive queue, as this phase may take a while to
 // complete.  If the archive already exists, ArchiveRepository will not do
 // anything.  In all cases, the caller should be examining the *ArchiveRequest
 // being returned for completion, as it may be different than the one they passed
-// in.
-func ArchiveRepository(request *ArchiveRequest) *ArchiveRequest {
+// in.  The doer may only have a limited number of new requests in progress,
+// ErrTooManyArchives is returned if the limit has been reached.  Anonymous
+// requests (doerID 0) are not limited, they would all share one limit and
+// lock each other out.
+func ArchiveRepository(request *ArchiveRequest, doerID int64) (*ArchiveRequest, error) {
 	// We'll return the request that's already been enqueued if it has been
 	// enqueued, or we'll immediately enqueue it if it has not been enqueued
 	// and it is not marked complete.
 	archiveMutex.Lock()
 	defer archiveMutex.Unlock()
 	if rExisting := getArchiveRequest(request.repo, request.commit, request.archiveType); rExisting != nil {
-		return rExisting
+		rExisting.requesters[doerID] = struct{}{}
+		return rExisting, nil
 	}
 	if request.archiveComplete {
-		return request
+		return request, nil
+	}
+
+	if limit := setting.Repository.MaxConcurrentArchivesPerUser; limit > 0 && doerID > 0 {
+		count := 0
+		for _, r := range archiveInProgress {
+			if r.doerID == doerID {
+				count++
+			}
+		}
+		if count >= limit {
+			return nil, ErrTooManyArchives{Limit: limit}
+		}
 	}
 
 	request.cchan = make(chan struct{})
+	request.status = ArchiveQueued
+	request.doerID = doerID
+	request.requesters = map[int64]struct{}{doerID: {}}
 	archiveInProgress = append(archiveInProgress, request)
-	go func() {
-		// Wait to start, if we have the Cond for it.  This is currently only
-		// useful for testing, so that the start and release of queued entries
-		// can be controlled to examine the queue.
-		if archiveQueueStartCond != nil {
-			archiveQueueMutex.Lock()
-			archiveQueueStartCond.Wait()
-			archiveQueueMutex.Unlock()
-		}
+	if err := archiveQueue.Push(request.key()); err != nil && err != queue.ErrAlreadyInQueue {
+		removeArchiveRequest(request)
+		return nil, err
+	}
 
-		// Drop the mutex while we process the request.  This may take a long
-		// time, and it's not necessary now that we've added the reequest to
-		// archiveInProgress.
-		doArchive(request)
+	return request, nil
+}
 
-		if archiveQueueReleaseCond != nil {
-			archiveQueueMutex.Lock()
-			archiveQueueReleaseCond.Wait()
-			archiveQueueMutex.Unlock()
-		}
+// CancelArchive withdraws the interest of the doer in the archive request.  The
+// generation of the archive is cancelled if nobody else requested it as well.
+// It returns whether the request has been cancelled.  Anonymous requests can
+// not be told apart and are never withdrawn.
+func CancelArchive(request *ArchiveRequest, doerID int64) bool {
+	if doerID <= 0 {
+		return false
+	}
 
-		// Purge this request from the list.  To do so, we'll just take the
-		// index at which we ended up at and swap the final element into that
-		// position, then chop off the now-redundant final element.  The slice
-		// may have change in between these two segments and we may have moved,
-		// so we search for it here.  We could perhaps avoid this search
-		// entirely if len(archiveInProgress) == 1, but we should verify
-		// correctness.
-		archiveMutex.Lock()
-		defer archiveMutex.Unlock()
+	archiveMutex.Lock()
+	defer archiveMutex.Unlock()
+	if request.archiveComplete || request.requesters == nil {
+		return false
+	}
+	if _, ok := request.requesters[doerID]; !ok {
+		return false
+	}
+	delete(request.requesters, doerID)
+	if len(request.requesters) > 0 {
+		return false
+	}
 
-		idx := -1
-		for _idx, req := range archiveInProgress {
-			if req == request {
-				idx = _idx
-				break
-			}
-		}
-		if idx == -1 {
-			log.Error("ArchiveRepository: Failed to find request for removal.")
-			return
-		}
-		archiveInProgress = append(archiveInProgress[:idx], archiveInProgress[idx+1:]...)
-	}()
+	switch request.status {
+	case ArchiveQueued:
+		// The handler will skip the queued key as the request is gone.
+		request.status = ArchiveCancelled
+		removeArchiveRequest(request)
+		close(request.cchan)
+	case ArchiveRunning:
+		request.status = ArchiveCancelled
+		request.cancel()
+	default:
+		return false
+	}
+	return true
+}
 
-	return request
+// Init starts the archive queue
+func Init() error {
+	archiveQueue = queue.CreateUniqueQueue("repo-archive", handle, "").(queue.UniqueQueue)
+	if archiveQueue == nil {
+		return fmt.Errorf("Unable to create repo-archive Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(archiveQueue.Run)
+	return nil
 }
@@ -5,12 +5,16 @@
 package archiver
 
 import (
+	"context"
+	"os"
 	"path/filepath"
 	"sync"
 	"testing"
 	"time"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/test"
 
 	"github.com/stretchr/testify/assert"
@@ -23,6 +27,20 @@ func TestMain(m *testing.M) {
 	models.MainTest(m, filepath.Join("..", ".."))
 }
 
+func initTestQueue(t *testing.T) {
+	q, err := queue.NewChannelUniqueQueue(handle, queue.ChannelUniqueQueueConfiguration{
+		WorkerPoolConfiguration: queue.WorkerPoolConfiguration{
+			QueueLength: 10,
+			BatchLength: 1,
+		},
+		Workers: 4,
+		Name:    "test-repo-archive",
+	}, "")
+	assert.NoError(t, err)
+	archiveQueue = q.(queue.UniqueQueue)
+	archiveQueue.Run(func(_ context.Context, _ func()) {}, func(_ context.Context, _ func()) {})
+}
+
 func waitForCount(t *testing.T, num int) {
 	var numQueued int
 
@@ -62,8 +80,14 @@ func releaseOneEntry(t *testing.T, inFlight []*ArchiveRequest) {
 	assert.Equal(t, numQueued-1, nowQueued)
 }
 
+func assertArchiveRepository(t *testing.T, request *ArchiveRequest) {
+	_, err := ArchiveRepository(request, 0)
+	assert.NoError(t, err)
+}
+
 func TestArchive_Basic(t *testing.T) {
 	assert.NoError(t, models.PrepareTestDatabase())
+	initTestQueue(t)
 
 	archiveQueueMutex = &queueMutex
 	archiveQueueStartCond = sync.NewCond(&queueMutex)
@@ -115,16 +139,16 @@ func TestArchive_Basic(t *testing.T) {
 	inFlight[1] = tgzReq
 	inFlight[2] = secondReq
 
-	ArchiveRepository(zipReq)
+	assertArchiveRepository(t, zipReq)
 	waitForCount(t, 1)
-	ArchiveRepository(tgzReq)
+	assertArchiveRepository(t, tgzReq)
 	waitForCount(t, 2)
-	ArchiveRepository(secondReq)
+	assertArchiveRepository(t, secondReq)
 	waitForCount(t, 3)
 
 	// Make sure sending an unprocessed request through doesn't affect the queue
 	// count.
-	ArchiveRepository(zipReq)
+	assertArchiveRepository(t, zipReq)
 
 	// Sleep two seconds to make sure the queue doesn't change.
 	time.Sleep(2 * time.Second)
@@ -153,7 +177,7 @@ func TestArchive_Basic(t *testing.T) {
 	// complete again.
 	arbitraryReq.cchan = make(chan struct{})
 	arbitraryReq.archiveComplete = false
-	doArchive(arbitraryReq)
+	doArchive(context.Background(), arbitraryReq)
 	assert.True(t, arbitraryReq.IsComplete())
 
 	// Queues should not have drained yet, because we haven't released them.
@@ -169,7 +193,7 @@ func TestArchive_Basic(t *testing.T) {
 	// We still have the other three stalled at completion, waiting to remove
 	// from archiveInProgress.  Try to submit this new one before its
 	// predecessor has cleared out of the queue.
-	ArchiveRepository(zipReq2)
+	assertArchiveRepository(t, zipReq2)
 
 	// Make sure the queue hasn't grown any.
 	assert.Equal(t, 3, len(archiveInProgress))
@@ -188,7 +212,7 @@ func TestArchive_Basic(t *testing.T) {
 	var completed, timedout bool
 	timedReq := DeriveRequestFrom(ctx, secondCommit+".tar.gz")
 	assert.NotNil(t, timedReq)
-	ArchiveRepository(timedReq)
+	assertArchiveRepository(t, timedReq)
 
 	// Guaranteed to timeout; we haven't signalled the request to start..
 	completed, timedout = timedReq.TimedWaitForCompletion(ctx, 2*time.Second)
@@ -211,6 +235,9 @@ func TestArchive_Basic(t *testing.T) {
 	// is zipReq.cchan, which will be non-nil because it's a completed request.
 	// It's fine to go ahead and set it to nil now.
 	zipReq.cchan = nil
+	zipReq.status = ArchiveQueued
+	zipReq.cancel = nil
+	zipReq.requesters = nil
 	assert.Equal(t, zipReq, zipReq2)
 	assert.False(t, zipReq == zipReq2)
 
@@ -219,3 +246,89 @@ func TestArchive_Basic(t *testing.T) {
 	assert.NotEqual(t, zipReq.GetArchiveName(), tgzReq.GetArchiveName())
 	assert.NotEqual(t, zipReq.GetArchiveName(), secondReq.GetArchiveName())
 }
+
+func TestArchive_LimitAndCancel(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	initTestQueue(t)
+
+	archiveQueueMutex = &queueMutex
+	archiveQueueStartCond = sync.NewCond(&queueMutex)
+	archiveQueueReleaseCond = sync.NewCond(&queueMutex)
+	defer func() {
+		archiveQueueMutex = nil
+		archiveQueueStartCond = nil
+		archiveQueueReleaseCond = nil
+	}()
+
+	defer func(limit int) {
+		setting.Repository.MaxConcurrentArchivesPerUser = limit
+	}(setting.Repository.MaxConcurrentArchivesPerUser)
+	setting.Repository.MaxConcurrentArchivesPerUser = 1
+
+	// Forget the requests of the previous test stalled at their release.
+	archiveMutex.Lock()
+	archiveInProgress = nil
+	archiveMutex.Unlock()
+
+	ctx := test.MockContext(t, "user27/repo49")
+	test.LoadRepo(t, ctx, 49)
+	test.LoadGitRepo(t, ctx)
+	defer ctx.Repo.GitRepo.Close()
+	assert.NoError(t, os.RemoveAll(filepath.Join(ctx.Repo.GitRepo.Path, "archives")))
+	firstCommit, secondCommit := "51f84af23134", "aacbdfe9e1c4"
+
+	firstReq, err := ArchiveRepository(DeriveRequestFrom(ctx, firstCommit+".zip"), 1)
+	assert.NoError(t, err)
+	waitForCount(t, 1)
+
+	// user1 may only have one archive in progress, user2 may still request one
+	_, err = ArchiveRepository(DeriveRequestFrom(ctx, secondCommit+".zip"), 1)
+	assert.True(t, IsErrTooManyArchives(err))
+	secondReq, err := ArchiveRepository(DeriveRequestFrom(ctx, secondCommit+".zip"), 2)
+	assert.NoError(t, err)
+	waitForCount(t, 2)
+
+	// requesting an archive in progress does not count towards the limit
+	sameReq, err := ArchiveRepository(DeriveRequestFrom(ctx, firstCommit+".zip"), 2)
+	assert.NoError(t, err)
+	assert.True(t, firstReq == sameReq)
+
+	// anonymous requests are not limited, but may not cancel anything
+	anonReq, err := ArchiveRepository(DeriveRequestFrom(ctx, secondCommit+".zip"), 0)
+	assert.NoError(t, err)
+	assert.True(t, secondReq == anonReq)
+	thirdReq, err := ArchiveRepository(DeriveRequestFrom(ctx, firstCommit+".tar.gz"), 0)
+	assert.NoError(t, err)
+	waitForCount(t, 3)
+	assert.False(t, CancelArchive(thirdReq, 0))
+
+	assert.Eventually(t, func() bool {
+		return firstReq.Status() == ArchiveRunning && secondReq.Status() == ArchiveRunning
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.Equal(t, -1, firstReq.QueuePosition())
+
+	// the archive is only cancelled once nobody is interested in it anymore
+	assert.False(t, CancelArchive(firstReq, 1))
+	assert.False(t, CancelArchive(firstReq, 3))
+	assert.True(t, CancelArchive(firstReq, 2))
+	assert.Equal(t, ArchiveCancelled, firstReq.Status())
+	assert.False(t, firstReq == DeriveRequestFrom(ctx, firstCommit+".zip"))
+
+	queueMutex.Lock()
+	archiveQueueStartCond.Broadcast()
+	queueMutex.Unlock()
+
+	assert.False(t, firstReq.WaitForCompletion(ctx))
+	assert.True(t, secondReq.WaitForCompletion(ctx))
+	assert.Eventually(t, func() bool {
+		return firstReq.Status() == ArchiveCancelled && secondReq.Status() == ArchiveComplete
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.False(t, com.IsExist(firstReq.GetArchivePath()))
+	assert.True(t, com.IsExist(secondReq.GetArchivePath()))
+	assert.True(t, thirdReq.WaitForCompletion(ctx))
+
+	releaseOneEntry(t, nil)
+	releaseOneEntry(t, nil)
+	releaseOneEntry(t, nil)
+	assert.Equal(t, 0, len(archiveInProgress))
+}
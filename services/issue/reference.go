@@ -0,0 +1,116 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ReferenceSuggestion is an issue or pull request which can be referenced by Ref
+type ReferenceSuggestion struct {
+	*models.Issue
+	Ref string
+}
+
+// SuggestReferences returns the issues and pull requests the doer can read which match the query,
+// to reference them from repo. The query is a keyword or an index of an issue of repo,
+// or of another repository if it is prefixed by "owner/name#".
+func SuggestReferences(doer *models.User, repo *models.Repository, query string, limit int) ([]*ReferenceSuggestion, error) {
+	target := repo
+	if pos := strings.IndexByte(query, '#'); pos >= 0 {
+		fields := strings.Split(query[:pos], "/")
+		query = query[pos+1:]
+		if len(fields) != 2 {
+			return []*ReferenceSuggestion{}, nil
+		}
+		if !strings.EqualFold(fields[0], repo.OwnerName) || !strings.EqualFold(fields[1], repo.Name) {
+			var err error
+			target, err = models.GetRepositoryByOwnerAndName(fields[0], fields[1])
+			if err != nil {
+				if models.IsErrRepoNotExist(err) {
+					return []*ReferenceSuggestion{}, nil
+				}
+				return nil, err
+			}
+		}
+	}
+
+	perm, err := models.GetUserRepoPermission(target, doer)
+	if err != nil {
+		return nil, err
+	}
+	canReadIssues, canReadPulls := perm.CanRead(models.UnitTypeIssues), perm.CanRead(models.UnitTypePullRequests)
+	if !canReadIssues && !canReadPulls {
+		return []*ReferenceSuggestion{}, nil
+	}
+
+	opts := &models.IssuesOptions{
+		ListOptions: models.ListOptions{
+			Page:     1,
+			PageSize: limit,
+		},
+		RepoIDs:  []int64{target.ID},
+		SortType: "recentupdate",
+	}
+	if !canReadIssues {
+		opts.IsPull = util.OptionalBoolTrue
+	} else if !canReadPulls {
+		opts.IsPull = util.OptionalBoolFalse
+	}
+
+	var byIndex *models.Issue
+	keyword := strings.TrimSpace(query)
+	if len(keyword) > 0 {
+		if index, err := strconv.ParseInt(keyword, 10, 64); err == nil {
+			byIndex, err = models.GetIssueByIndex(target.ID, index)
+			if err != nil && !models.IsErrIssueNotExist(err) {
+				return nil, err
+			}
+			if byIndex != nil && !opts.IsPull.IsNone() && byIndex.IsPull != opts.IsPull.IsTrue() {
+				byIndex = nil
+			}
+		}
+
+		if opts.IssueIDs, err = issue_indexer.SearchIssuesByKeyword(opts.RepoIDs, keyword); err != nil {
+			return nil, err
+		}
+	}
+
+	// the issue with the index the user typed comes first
+	issues := make([]*models.Issue, 0, limit)
+	if byIndex != nil {
+		issues = append(issues, byIndex)
+	}
+	if len(keyword) == 0 || len(opts.IssueIDs) > 0 {
+		found, err := models.Issues(opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range found {
+			if len(issues) < limit && (byIndex == nil || issue.ID != byIndex.ID) {
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	refPrefix := "#"
+	if target.ID != repo.ID {
+		refPrefix = target.FullName() + "#"
+	}
+	suggestions := make([]*ReferenceSuggestion, 0, len(issues))
+	for _, issue := range issues {
+		issue.Repo = target
+		suggestions = append(suggestions, &ReferenceSuggestion{
+			Issue: issue,
+			Ref:   refPrefix + strconv.FormatInt(issue.Index, 10),
+		})
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestReferences(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	setting.Indexer.IssueType = "db"
+	issue_indexer.InitIssueIndexer(true)
+
+	user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	repo1 := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+	refs := func(suggestions []*ReferenceSuggestion) []string {
+		result := make([]string, 0, len(suggestions))
+		for _, s := range suggestions {
+			result = append(result, s.Ref)
+		}
+		return result
+	}
+
+	suggestions, err := SuggestReferences(user2, repo1, "", 10)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, suggestions)
+	for _, s := range suggestions {
+		assert.EqualValues(t, repo1.ID, s.RepoID)
+		assert.EqualValues(t, repo1.ID, s.Repo.ID)
+	}
+
+	suggestions, err = SuggestReferences(user2, repo1, "issue2", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"#2"}, refs(suggestions))
+
+	// the issue with the typed index comes first
+	suggestions, err = SuggestReferences(user2, repo1, "4", 10)
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, suggestions) {
+		assert.Equal(t, "#4", suggestions[0].Ref)
+	}
+
+	// issues of other repositories are referenced with their full name
+	suggestions, err = SuggestReferences(user2, repo1, "user2/repo2#", 10)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user2/repo2#1", "user2/repo2#2"}, refs(suggestions))
+
+	suggestions, err = SuggestReferences(user2, repo1, "user2/repo1#issue2", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"#2"}, refs(suggestions))
+
+	// private and unknown repositories are not searched
+	for _, query := range []string{"user2/repo2#", "user2/does-not-exist#1", "repo2#1"} {
+		suggestions, err = SuggestReferences(nil, repo1, query, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, suggestions, query)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	comment_service "code.gitea.io/gitea/services/comments"
+)
+
+// PublishScheduledPosts posts the scheduled issues and comments which are due. The permissions of
+// the posters are checked again, posts which cannot be published anymore are dropped. Posts which
+// fail to be published are kept with the error for their posters to see.
+func PublishScheduledPosts(ctx context.Context) error {
+	posts, err := models.GetDueScheduledPosts()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range posts {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before publishing scheduled post %d", p.ID)
+		default:
+		}
+
+		// claim the post first, so it is never published twice
+		if ok, err := models.ClaimScheduledPost(p); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		if err := publishScheduledPost(p); err != nil {
+			log.Error("Unable to publish scheduled post %d of user %d in repository %d: %v", p.ID, p.PosterID, p.RepoID, err)
+			if err := models.SetScheduledPostPublishError(p, err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := models.DeleteScheduledPost(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func publishScheduledPost(p *models.ScheduledPost) error {
+	if err := p.LoadAttributes(); err != nil {
+		if models.IsErrRepoNotExist(err) || models.IsErrUserNotExist(err) || models.IsErrIssueNotExist(err) {
+			log.Warn("Dropped scheduled post %d: %v", p.ID, err)
+			return nil
+		}
+		return err
+	}
+	if !p.Poster.IsActive || p.Poster.ProhibitLogin || p.Repo.IsArchived {
+		log.Warn("Dropped scheduled post %d: the poster or the repository is disabled", p.ID)
+		return nil
+	}
+
+	perm, err := models.GetUserRepoPermission(p.Repo, p.Poster)
+	if err != nil {
+		return err
+	}
+
+	if p.IsComment() {
+		issue := p.Issue
+		if !perm.CanReadIssuesOrPulls(issue.IsPull) && p.PosterID != issue.PosterID ||
+			issue.IsLocked && !perm.CanWriteIssuesOrPulls(issue.IsPull) && !p.Poster.IsAdmin {
+			log.Warn("Dropped scheduled post %d: the poster cannot comment on issue %d anymore", p.ID, issue.ID)
+			return nil
+		}
+		_, err := comment_service.CreateIssueComment(p.Poster, p.Repo, issue, p.Content, nil)
+		return err
+	}
+
+	if !perm.CanRead(models.UnitTypeIssues) {
+		log.Warn("Dropped scheduled post %d: the poster cannot create issues in repository %d anymore", p.ID, p.RepoID)
+		return nil
+	}
+	// only writers can set the metadata of new issues
+	if !perm.CanWrite(models.UnitTypeIssues) {
+		p.LabelIDs, p.AssigneeIDs, p.MilestoneID, p.ProjectID = nil, nil, 0, 0
+	}
+
+	issue := &models.Issue{
+		RepoID:      p.RepoID,
+		Repo:        p.Repo,
+		Title:       p.Title,
+		PosterID:    p.PosterID,
+		Poster:      p.Poster,
+		MilestoneID: p.MilestoneID,
+		Content:     p.Content,
+		Ref:         p.Ref,
+	}
+	if err := NewIssue(p.Repo, issue, p.LabelIDs, nil, p.AssigneeIDs); err != nil {
+		return err
+	}
+	if p.ProjectID > 0 {
+		return models.ChangeProjectAssign(issue, p.Poster, p.ProjectID)
+	}
+	return nil
+}
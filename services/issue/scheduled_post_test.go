@@ -0,0 +1,35 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishScheduledPosts(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	assert.NoError(t, PublishScheduledPosts(context.Background()))
+
+	// the due posts are removed, the future one is kept
+	models.AssertNotExistsBean(t, &models.ScheduledPost{ID: 1})
+	models.AssertNotExistsBean(t, &models.ScheduledPost{ID: 2})
+	models.AssertNotExistsBean(t, &models.ScheduledPost{ID: 4})
+	models.AssertExistsAndLoadBean(t, &models.ScheduledPost{ID: 3})
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: 1, PosterID: 2, Title: "scheduled issue"}).(*models.Issue)
+	assert.Equal(t, "content of the scheduled issue", issue.Content)
+	models.AssertExistsAndLoadBean(t, &models.IssueLabel{IssueID: issue.ID, LabelID: 1})
+
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: 1, PosterID: 2, Type: models.CommentTypeComment, Content: "scheduled comment"})
+
+	// user4 has no access to the private repository anymore
+	models.AssertNotExistsBean(t, &models.Issue{RepoID: 2, Title: "issue without access"})
+}
@@ -0,0 +1,473 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package issuesync mirrors new issues and comments between repositories migrated from GitHub
+// and their upstream repositories in both directions.
+//
+// Loops are prevented in three ways: issues and comments mirrored from upstream are inserted
+// without notifications, so they are never pushed back; issues and comments pushed upstream are
+// linked to their local counterparts and carry a hidden marker, so they are never pulled back;
+// and the pulls and pushes of a repository never run at the same time.
+package issuesync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// syncMarker is appended to the issues and comments pushed upstream to recognize them
+// even if their link is missing, e.g. because the server stopped right after pushing
+const syncMarker = "<!-- gitea-issue-sync -->"
+
+// sinceMargin is subtracted from the time of the last pull to cover clock differences with upstream,
+// what was mirrored already is skipped thanks to the links
+const sinceMargin = 5 * time.Minute
+
+var (
+	// repoWorkingPool serializes the pulls and pushes of a repository
+	repoWorkingPool = sync.NewExclusivePool()
+	pushQueue       queue.Queue
+)
+
+// pushTask is a new issue, or comment if CommentID is not 0, to push upstream
+type pushTask struct {
+	RepoID    int64
+	IssueID   int64
+	CommentID int64
+}
+
+// Init starts the queue pushing new issues and comments upstream
+func Init() error {
+	if !setting.IssueSync.Enabled {
+		return nil
+	}
+
+	pushQueue = queue.CreateQueue("issue_sync", handle, &pushTask{})
+	if pushQueue == nil {
+		return fmt.Errorf("Unable to create issue_sync Queue")
+	}
+	go graceful.GetManager().RunWithShutdownFns(pushQueue.Run)
+
+	notification.RegisterNotifier(&issueSyncNotifier{})
+	return nil
+}
+
+func handle(data ...queue.Data) {
+	for _, datum := range data {
+		task := datum.(*pushTask)
+		if err := push(graceful.GetManager().ShutdownContext(), task); err != nil {
+			log.Error("Failed to push issue %d (comment %d) of repository %d upstream: %v", task.IssueID, task.CommentID, task.RepoID, err)
+		}
+	}
+}
+
+// syncer syncs the issues of a repository with its upstream repository
+type syncer struct {
+	sync     *models.IssueSync
+	repo     *models.Repository
+	upstream upstream
+	doer     *models.User
+	// users caches the local users linked to upstream users by upstream user ID
+	users map[int64]*models.User
+}
+
+func newSyncer(ctx context.Context, s *models.IssueSync) (*syncer, error) {
+	if err := s.LoadRepo(); err != nil {
+		return nil, err
+	}
+	token, err := s.Token()
+	if err != nil {
+		return nil, fmt.Errorf("Token: %v", err)
+	}
+	up, err := newUpstream(ctx, s.Repo, token)
+	if err != nil {
+		return nil, err
+	}
+
+	doer, err := models.GetUserByID(s.DoerID)
+	if err != nil {
+		if !models.IsErrUserNotExist(err) {
+			return nil, err
+		}
+		doer = models.NewGhostUser()
+	}
+
+	return &syncer{
+		sync:     s,
+		repo:     s.Repo,
+		upstream: up,
+		doer:     doer,
+		users:    make(map[int64]*models.User),
+	}, nil
+}
+
+// localUser returns the local user who linked the upstream user's account, nil if there is none
+func (sy *syncer) localUser(upstreamUserID int64) (*models.User, error) {
+	if u, ok := sy.users[upstreamUserID]; ok {
+		return u, nil
+	}
+
+	var u *models.User
+	userID, err := models.GetUserIDByExternalUserID(structs.GithubService.Name(), strconv.FormatInt(upstreamUserID, 10))
+	if err != nil {
+		return nil, err
+	}
+	if userID > 0 {
+		if u, err = models.GetUserByID(userID); err != nil && !models.IsErrUserNotExist(err) {
+			return nil, err
+		}
+	}
+	sy.users[upstreamUserID] = u
+	return u, nil
+}
+
+// linkMigratedIssue links an issue migrated from upstream which has not been linked yet to its upstream issue.
+// Migrated issues keep the number and the creation time of their upstream issue.
+func (sy *syncer) linkMigratedIssue(issue *models.Issue) (*models.IssueSyncLink, error) {
+	if issue.IsPull || issue.CreatedUnix >= sy.sync.CreatedUnix {
+		return nil, nil
+	}
+	if link, err := models.GetIssueSyncLinkByUpstream(sy.repo.ID, issue.Index, 0); err != nil || link != nil {
+		// the upstream issue with the same number is linked to another issue
+		return nil, err
+	}
+
+	up, err := sy.upstream.GetIssue(issue.Index)
+	if err != nil {
+		return nil, fmt.Errorf("GetIssue: %v", err)
+	}
+	if up.IsPull || up.Created.Unix() != int64(issue.CreatedUnix) {
+		return nil, nil
+	}
+
+	link := &models.IssueSyncLink{
+		RepoID:         sy.repo.ID,
+		IssueID:        issue.ID,
+		UpstreamNumber: issue.Index,
+	}
+	return link, models.InsertIssueSyncLink(link)
+}
+
+// localIssue returns the issue linked to the upstream issue, nil if there is none
+func (sy *syncer) localIssue(number int64) (*models.Issue, error) {
+	link, err := models.GetIssueSyncLinkByUpstream(sy.repo.ID, number, 0)
+	if err != nil {
+		return nil, err
+	} else if link != nil {
+		return models.GetIssueByID(link.IssueID)
+	}
+
+	issue, err := models.GetIssueByIndex(sy.repo.ID, number)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if link, err = models.GetIssueSyncLink(issue.ID, 0); err != nil || link != nil {
+		// the issue is linked to another upstream issue
+		return nil, err
+	}
+	if link, err = sy.linkMigratedIssue(issue); err != nil || link == nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// upstreamNumber returns the number of the upstream issue linked to the issue, 0 if there is none
+func (sy *syncer) upstreamNumber(issue *models.Issue) (int64, error) {
+	link, err := models.GetIssueSyncLink(issue.ID, 0)
+	if err != nil {
+		return 0, err
+	} else if link == nil {
+		if link, err = sy.linkMigratedIssue(issue); err != nil || link == nil {
+			return 0, err
+		}
+	}
+	return link.UpstreamNumber, nil
+}
+
+// Pull mirrors the issues and comments created upstream since the last pull to the repository
+func Pull(ctx context.Context, s *models.IssueSync) error {
+	repoWorkingPool.CheckIn(strconv.FormatInt(s.RepoID, 10))
+	defer repoWorkingPool.CheckOut(strconv.FormatInt(s.RepoID, 10))
+
+	started := timeutil.TimeStampNow()
+	err := pull(ctx, s)
+	if err != nil {
+		s.LastError = err.Error()
+	} else {
+		s.LastError = ""
+		s.PulledUnix = started
+	}
+	if err := models.UpdateIssueSyncCols(s, "pulled_unix", "last_error"); err != nil {
+		log.Error("UpdateIssueSyncCols: %v", err)
+	}
+	return err
+}
+
+func pull(ctx context.Context, s *models.IssueSync) error {
+	sy, err := newSyncer(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	since := s.PulledUnix
+	if since < s.CreatedUnix {
+		since = s.CreatedUnix
+	}
+	sinceTime := since.AsTime().Add(-sinceMargin)
+	// only what is created upstream after the sync was set up is mirrored
+	setUp := s.CreatedUnix.AsTime()
+
+	issues, err := sy.upstream.ListIssues(sinceTime)
+	if err != nil {
+		return fmt.Errorf("ListIssues: %v", err)
+	}
+	for _, issue := range issues {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("during issue sync of %s", sy.repo.FullName())
+		default:
+		}
+		if issue.IsPull || issue.Created.Before(setUp) || strings.Contains(issue.Body, syncMarker) {
+			continue
+		}
+		if link, err := models.GetIssueSyncLinkByUpstream(sy.repo.ID, issue.Number, 0); err != nil {
+			return err
+		} else if link != nil {
+			continue
+		}
+		if err := sy.pullIssue(issue); err != nil {
+			return fmt.Errorf("pullIssue #%d: %v", issue.Number, err)
+		}
+	}
+
+	comments, err := sy.upstream.ListComments(sinceTime)
+	if err != nil {
+		return fmt.Errorf("ListComments: %v", err)
+	}
+	for _, comment := range comments {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("during issue sync of %s", sy.repo.FullName())
+		default:
+		}
+		if comment.Created.Before(setUp) || strings.Contains(comment.Body, syncMarker) {
+			continue
+		}
+		if link, err := models.GetIssueSyncLinkByUpstream(sy.repo.ID, comment.IssueNumber, comment.ID); err != nil {
+			return err
+		} else if link != nil {
+			continue
+		}
+		issue, err := sy.localIssue(comment.IssueNumber)
+		if err != nil {
+			return fmt.Errorf("localIssue #%d: %v", comment.IssueNumber, err)
+		} else if issue == nil {
+			// comments of pull requests and of issues which were not mirrored
+			continue
+		}
+		if err := sy.pullComment(issue, comment); err != nil {
+			return fmt.Errorf("pullComment %d: %v", comment.ID, err)
+		}
+	}
+	return nil
+}
+
+func (sy *syncer) pullIssue(up *upstreamIssue) error {
+	issue := &models.Issue{
+		RepoID:  sy.repo.ID,
+		Repo:    sy.repo,
+		Title:   up.Title,
+		Content: up.Body,
+	}
+	u, err := sy.localUser(up.UserID)
+	if err != nil {
+		return err
+	}
+	if u != nil {
+		issue.Poster = u
+	} else {
+		issue.Poster = sy.doer
+		issue.OriginalAuthor = up.UserName
+		issue.OriginalAuthorID = up.UserID
+	}
+	issue.PosterID = issue.Poster.ID
+
+	if err := models.InsertUpstreamIssue(sy.repo, issue, &models.IssueSyncLink{UpstreamNumber: up.Number}); err != nil {
+		return err
+	}
+	log.Trace("Issue #%d of %s mirrored from upstream issue #%d", issue.Index, sy.repo.FullName(), up.Number)
+	return nil
+}
+
+func (sy *syncer) pullComment(issue *models.Issue, up *upstreamComment) error {
+	comment := &models.Comment{
+		IssueID: issue.ID,
+		Content: up.Body,
+	}
+	u, err := sy.localUser(up.UserID)
+	if err != nil {
+		return err
+	}
+	if u != nil {
+		comment.PosterID = u.ID
+	} else {
+		comment.PosterID = sy.doer.ID
+		comment.OriginalAuthor = up.UserName
+		comment.OriginalAuthorID = up.UserID
+	}
+
+	return models.InsertUpstreamComment(comment, &models.IssueSyncLink{
+		RepoID:            sy.repo.ID,
+		UpstreamNumber:    up.IssueNumber,
+		UpstreamCommentID: up.ID,
+	})
+}
+
+// PullAll mirrors the new upstream issues and comments of all repositories which pull them
+func PullAll(ctx context.Context) error {
+	if !setting.IssueSync.Enabled {
+		return nil
+	}
+
+	syncs, err := models.FindPullIssueSyncs()
+	if err != nil {
+		return err
+	}
+	for _, s := range syncs {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before issue sync of repository %d", s.RepoID)
+		default:
+		}
+		if err := Pull(ctx, s); err != nil {
+			log.Error("Failed to pull upstream issues of repository %d: %v", s.RepoID, err)
+		}
+	}
+	return nil
+}
+
+// mirroredContent returns the content of an issue or comment pushed upstream, it names the poster
+// by the account the poster linked upstream if there is one
+func mirroredContent(content string, poster *models.User, link string) (string, error) {
+	author := fmt.Sprintf("[%s](%s)", poster.Name, poster.HTMLURL())
+	accounts, err := models.ListAccountLinks(poster)
+	if err != nil {
+		return "", err
+	}
+	for _, account := range accounts {
+		if account.Provider == structs.GithubService.Name() && len(account.NickName) > 0 {
+			author = "@" + account.NickName
+			break
+		}
+	}
+	return fmt.Sprintf("%s\n\n---\n_Posted by %s on [%s](%s)_\n%s", content, author, setting.AppName, link, syncMarker), nil
+}
+
+func push(ctx context.Context, task *pushTask) error {
+	s, err := models.GetIssueSyncByRepoID(task.RepoID)
+	if err != nil {
+		if models.IsErrIssueSyncNotExist(err) {
+			return nil
+		}
+		return err
+	} else if !s.PushEnabled {
+		return nil
+	}
+
+	repoWorkingPool.CheckIn(strconv.FormatInt(s.RepoID, 10))
+	defer repoWorkingPool.CheckOut(strconv.FormatInt(s.RepoID, 10))
+
+	sy, err := newSyncer(ctx, s)
+	if err != nil {
+		return err
+	}
+	issue, err := models.GetIssueByID(task.IssueID)
+	if err != nil {
+		return err
+	}
+	issue.Repo = sy.repo
+	if task.CommentID == 0 {
+		return sy.pushIssue(issue)
+	}
+	comment, err := models.GetCommentByID(task.CommentID)
+	if err != nil {
+		return err
+	}
+	comment.Issue = issue
+	return sy.pushComment(issue, comment)
+}
+
+func (sy *syncer) pushIssue(issue *models.Issue) error {
+	if link, err := models.GetIssueSyncLink(issue.ID, 0); err != nil || link != nil {
+		return err
+	}
+	if err := issue.LoadPoster(); err != nil {
+		return err
+	}
+
+	content, err := mirroredContent(issue.Content, issue.Poster, issue.HTMLURL())
+	if err != nil {
+		return err
+	}
+	number, err := sy.upstream.CreateIssue(issue.Title, content)
+	if err != nil {
+		return fmt.Errorf("CreateIssue: %v", err)
+	}
+	log.Trace("Issue #%d of %s mirrored to upstream issue #%d", issue.Index, sy.repo.FullName(), number)
+
+	return models.InsertIssueSyncLink(&models.IssueSyncLink{
+		RepoID:         sy.repo.ID,
+		IssueID:        issue.ID,
+		UpstreamNumber: number,
+		IsPushed:       true,
+	})
+}
+
+func (sy *syncer) pushComment(issue *models.Issue, comment *models.Comment) error {
+	if link, err := models.GetIssueSyncLink(issue.ID, comment.ID); err != nil || link != nil {
+		return err
+	}
+	number, err := sy.upstreamNumber(issue)
+	if err != nil || number == 0 {
+		// comments of issues which were not mirrored stay local
+		return err
+	}
+	if err := comment.LoadPoster(); err != nil {
+		return err
+	}
+
+	content, err := mirroredContent(comment.Content, comment.Poster, comment.HTMLURL())
+	if err != nil {
+		return err
+	}
+	id, err := sy.upstream.CreateComment(number, content)
+	if err != nil {
+		return fmt.Errorf("CreateComment: %v", err)
+	}
+
+	return models.InsertIssueSyncLink(&models.IssueSyncLink{
+		RepoID:            sy.repo.ID,
+		IssueID:           issue.ID,
+		CommentID:         comment.ID,
+		UpstreamNumber:    number,
+		UpstreamCommentID: id,
+		IsPushed:          true,
+	})
+}
@@ -0,0 +1,147 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issuesync
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUpstream struct {
+	issues   []*upstreamIssue
+	comments []*upstreamComment
+}
+
+func (f *fakeUpstream) ListIssues(since time.Time) ([]*upstreamIssue, error) {
+	return f.issues, nil
+}
+
+func (f *fakeUpstream) ListComments(since time.Time) ([]*upstreamComment, error) {
+	return f.comments, nil
+}
+
+func (f *fakeUpstream) GetIssue(number int64) (*upstreamIssue, error) {
+	for _, issue := range f.issues {
+		if issue.Number == number {
+			return issue, nil
+		}
+	}
+	return nil, models.ErrIssueNotExist{Index: number}
+}
+
+func (f *fakeUpstream) CreateIssue(title, body string) (int64, error) {
+	number := int64(len(f.issues) + 1)
+	f.issues = append(f.issues, &upstreamIssue{Number: number, Title: title, Body: body, Created: time.Now()})
+	return number, nil
+}
+
+func (f *fakeUpstream) CreateComment(number int64, body string) (int64, error) {
+	id := int64(len(f.comments) + 1)
+	f.comments = append(f.comments, &upstreamComment{ID: id, IssueNumber: number, Body: body, Created: time.Now()})
+	return id, nil
+}
+
+func prepareIssueSync(t *testing.T, f *fakeUpstream) *models.IssueSync {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	old := newUpstream
+	newUpstream = func(context.Context, *models.Repository, string) (upstream, error) {
+		return f, nil
+	}
+	t.Cleanup(func() { newUpstream = old })
+
+	s := &models.IssueSync{RepoID: 1, DoerID: 2, PullEnabled: true, PushEnabled: true}
+	assert.NoError(t, models.SaveIssueSync(s))
+	return s
+}
+
+func TestPull(t *testing.T) {
+	f := &fakeUpstream{}
+	s := prepareIssueSync(t, f)
+
+	now := time.Now().Add(time.Minute)
+	f.issues = []*upstreamIssue{
+		{Number: 1, Title: "created before the sync", Created: now.Add(-time.Hour)},
+		{Number: 20, Title: "new upstream issue", Body: "upstream body", UserID: 42, UserName: "octocat", Created: now},
+		{Number: 21, Title: "new upstream pull request", IsPull: true, Created: now},
+		{Number: 22, Title: "pushed issue", Body: "pushed body\n" + syncMarker, Created: now},
+	}
+	f.comments = []*upstreamComment{
+		{ID: 100, IssueNumber: 20, Body: "upstream comment", UserID: 42, UserName: "octocat", Created: now},
+		{ID: 101, IssueNumber: 21, Body: "pull request comment", Created: now},
+		{ID: 102, IssueNumber: 20, Body: "pushed comment\n" + syncMarker, Created: now},
+	}
+
+	assert.NoError(t, Pull(context.Background(), s))
+	s = models.AssertExistsAndLoadBean(t, &models.IssueSync{RepoID: 1}).(*models.IssueSync)
+	assert.NotZero(t, s.PulledUnix)
+	assert.Empty(t, s.LastError)
+
+	link := models.AssertExistsAndLoadBean(t, &models.IssueSyncLink{RepoID: 1, UpstreamNumber: 20}).(*models.IssueSyncLink)
+	assert.False(t, link.IsPushed)
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: link.IssueID}).(*models.Issue)
+	assert.EqualValues(t, "new upstream issue", issue.Title)
+	assert.EqualValues(t, 2, issue.PosterID)
+	assert.EqualValues(t, "octocat", issue.OriginalAuthor)
+	assert.EqualValues(t, 42, issue.OriginalAuthorID)
+
+	comment := models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: issue.ID, Content: "upstream comment"}).(*models.Comment)
+	assert.EqualValues(t, "octocat", comment.OriginalAuthor)
+	models.AssertExistsAndLoadBean(t, &models.IssueSyncLink{CommentID: comment.ID, UpstreamNumber: 20, UpstreamCommentID: 100})
+
+	// issues created before the sync, pull requests and everything pushed from here are skipped
+	models.AssertCount(t, &models.IssueSyncLink{RepoID: 1}, 2)
+	models.AssertNotExistsBean(t, &models.Comment{Content: "pull request comment"})
+
+	// pulling again mirrors nothing twice
+	assert.NoError(t, Pull(context.Background(), s))
+	models.AssertCount(t, &models.IssueSyncLink{RepoID: 1}, 2)
+	models.AssertCount(t, &models.Comment{IssueID: issue.ID}, 1)
+}
+
+func TestPush(t *testing.T) {
+	f := &fakeUpstream{}
+	prepareIssueSync(t, f)
+	ctx := context.Background()
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	// issue #1 was migrated from upstream issue #1
+	f.issues = []*upstreamIssue{{Number: 1, Title: issue.Title, Created: issue.CreatedUnix.AsTime()}}
+
+	assert.NoError(t, push(ctx, &pushTask{RepoID: 1, IssueID: 1, CommentID: 2}))
+	if assert.Len(t, f.comments, 1) {
+		assert.EqualValues(t, 1, f.comments[0].IssueNumber)
+		assert.True(t, strings.HasPrefix(f.comments[0].Body, "good work!"))
+		assert.Contains(t, f.comments[0].Body, syncMarker)
+	}
+	models.AssertExistsAndLoadBean(t, &models.IssueSyncLink{RepoID: 1, IssueID: 1, CommentID: 0, UpstreamNumber: 1})
+	link := models.AssertExistsAndLoadBean(t, &models.IssueSyncLink{IssueID: 1, CommentID: 2}).(*models.IssueSyncLink)
+	assert.True(t, link.IsPushed)
+
+	// a comment is pushed only once
+	assert.NoError(t, push(ctx, &pushTask{RepoID: 1, IssueID: 1, CommentID: 2}))
+	assert.Len(t, f.comments, 1)
+
+	assert.NoError(t, push(ctx, &pushTask{RepoID: 1, IssueID: 2}))
+	if assert.Len(t, f.issues, 2) {
+		assert.Contains(t, f.issues[1].Body, syncMarker)
+	}
+	link = models.AssertExistsAndLoadBean(t, &models.IssueSyncLink{IssueID: 2, CommentID: 0}).(*models.IssueSyncLink)
+	assert.EqualValues(t, 2, link.UpstreamNumber)
+	assert.True(t, link.IsPushed)
+
+	// what was pushed is not pulled back
+	s := models.AssertExistsAndLoadBean(t, &models.IssueSync{RepoID: 1}).(*models.IssueSync)
+	numIssues := models.GetCount(t, &models.Issue{RepoID: 1})
+	assert.NoError(t, Pull(ctx, s))
+	models.AssertCount(t, &models.Issue{RepoID: 1}, numIssues)
+	models.AssertCount(t, &models.IssueSyncLink{RepoID: 1}, 3)
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issuesync
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/structs"
+)
+
+type issueSyncNotifier struct {
+	base.NullNotifier
+}
+
+var (
+	_ base.Notifier = &issueSyncNotifier{}
+)
+
+// queuePush queues a new issue or comment of a repository migrated from GitHub to be pushed upstream,
+// the queue finds out whether the repository pushes its issues
+func queuePush(repo *models.Repository, task *pushTask) {
+	if repo.OriginalServiceType != structs.GithubService {
+		return
+	}
+	if err := pushQueue.Push(task); err != nil {
+		log.Error("Unable to queue issue %d (comment %d) of %s to be pushed upstream: %v", task.IssueID, task.CommentID, repo.FullName(), err)
+	}
+}
+
+func (n *issueSyncNotifier) NotifyNewIssue(issue *models.Issue) {
+	if issue.IsPull {
+		return
+	}
+	if err := issue.LoadRepo(); err != nil {
+		log.Error("LoadRepo: %v", err)
+		return
+	}
+	queuePush(issue.Repo, &pushTask{RepoID: issue.RepoID, IssueID: issue.ID})
+}
+
+func (n *issueSyncNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
+	issue *models.Issue, comment *models.Comment) {
+	if issue.IsPull || comment.Type != models.CommentTypeComment {
+		return
+	}
+	queuePush(repo, &pushTask{RepoID: repo.ID, IssueID: issue.ID, CommentID: comment.ID})
+}
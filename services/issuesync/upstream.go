@@ -0,0 +1,227 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issuesync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+
+	"github.com/google/go-github/v32/github"
+	"golang.org/x/oauth2"
+)
+
+// upstreamIssue is an issue or pull request of the upstream repository
+type upstreamIssue struct {
+	Number   int64
+	Title    string
+	Body     string
+	IsPull   bool
+	UserID   int64
+	UserName string
+	Created  time.Time
+}
+
+// upstreamComment is a comment on an issue or pull request of the upstream repository
+type upstreamComment struct {
+	ID          int64
+	IssueNumber int64
+	Body        string
+	UserID      int64
+	UserName    string
+	Created     time.Time
+}
+
+// upstream is the upstream repository a repository was migrated from
+type upstream interface {
+	// ListIssues returns the issues and pull requests updated since the time, oldest first
+	ListIssues(since time.Time) ([]*upstreamIssue, error)
+	// ListComments returns the comments updated since the time, oldest first
+	ListComments(since time.Time) ([]*upstreamComment, error)
+	GetIssue(number int64) (*upstreamIssue, error)
+	CreateIssue(title, body string) (number int64, err error)
+	CreateComment(number int64, body string) (id int64, err error)
+}
+
+// newUpstream returns the upstream repository of the repository, it is replaced in tests
+var newUpstream = newGithubUpstream
+
+// ErrNoUpstream represents an error of a repository which can not sync its issues
+type ErrNoUpstream struct {
+	Reason string
+}
+
+// IsErrNoUpstream checks if an error is a ErrNoUpstream.
+func IsErrNoUpstream(err error) bool {
+	_, ok := err.(ErrNoUpstream)
+	return ok
+}
+
+func (err ErrNoUpstream) Error() string {
+	return fmt.Sprintf("no upstream repository: %s", err.Reason)
+}
+
+// parseUpstreamURL returns the base URL, owner and name of the upstream repository of a repository migrated from GitHub
+func parseUpstreamURL(repo *models.Repository) (baseURL, owner, name string, err error) {
+	if repo.OriginalServiceType != structs.GithubService || len(repo.OriginalURL) == 0 {
+		return "", "", "", ErrNoUpstream{"the repository was not migrated from GitHub"}
+	}
+	u, err := url.Parse(repo.OriginalURL)
+	if err != nil {
+		return "", "", "", ErrNoUpstream{err.Error()}
+	}
+	fields := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(fields) != 2 || len(fields[0]) == 0 || len(fields[1]) == 0 {
+		return "", "", "", ErrNoUpstream{"invalid original URL " + repo.SanitizedOriginalURL()}
+	}
+	return u.Scheme + "://" + u.Host, fields[0], strings.TrimSuffix(fields[1], ".git"), nil
+}
+
+// IsAvailable returns whether the issues of the repository can be synced with its upstream repository
+func IsAvailable(repo *models.Repository) bool {
+	_, _, _, err := parseUpstreamURL(repo)
+	return setting.IssueSync.Enabled && err == nil
+}
+
+type githubUpstream struct {
+	ctx    context.Context
+	client *github.Client
+	owner  string
+	name   string
+}
+
+func newGithubUpstream(ctx context.Context, repo *models.Repository, token string) (upstream, error) {
+	baseURL, owner, name, err := parseUpstreamURL(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	client := http.DefaultClient
+	if len(token) > 0 {
+		client = oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	}
+	g := &githubUpstream{
+		ctx:    ctx,
+		client: github.NewClient(client),
+		owner:  owner,
+		name:   name,
+	}
+	if baseURL != "https://github.com" {
+		if g.client, err = github.NewEnterpriseClient(baseURL, baseURL, client); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func convertGithubIssue(issue *github.Issue) *upstreamIssue {
+	return &upstreamIssue{
+		Number:   int64(issue.GetNumber()),
+		Title:    issue.GetTitle(),
+		Body:     issue.GetBody(),
+		IsPull:   issue.IsPullRequest(),
+		UserID:   issue.GetUser().GetID(),
+		UserName: issue.GetUser().GetLogin(),
+		Created:  issue.GetCreatedAt(),
+	}
+}
+
+func (g *githubUpstream) ListIssues(since time.Time) ([]*upstreamIssue, error) {
+	opts := &github.IssueListByRepoOptions{
+		State:       "all",
+		Sort:        "created",
+		Direction:   "asc",
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	issues := make([]*upstreamIssue, 0, 10)
+	for {
+		list, resp, err := g.client.Issues.ListByRepo(g.ctx, g.owner, g.name, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range list {
+			issues = append(issues, convertGithubIssue(issue))
+		}
+		if resp.NextPage == 0 {
+			return issues, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (g *githubUpstream) ListComments(since time.Time) ([]*upstreamComment, error) {
+	created, asc := "created", "asc"
+	opts := &github.IssueListCommentsOptions{
+		Sort:        &created,
+		Direction:   &asc,
+		Since:       &since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	comments := make([]*upstreamComment, 0, 10)
+	for {
+		// number 0 lists the comments of all issues and pull requests
+		list, resp, err := g.client.Issues.ListComments(g.ctx, g.owner, g.name, 0, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range list {
+			number, err := strconv.ParseInt(path.Base(comment.GetIssueURL()), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid issue URL of comment %d: %s", comment.GetID(), comment.GetIssueURL())
+			}
+			comments = append(comments, &upstreamComment{
+				ID:          comment.GetID(),
+				IssueNumber: number,
+				Body:        comment.GetBody(),
+				UserID:      comment.GetUser().GetID(),
+				UserName:    comment.GetUser().GetLogin(),
+				Created:     comment.GetCreatedAt(),
+			})
+		}
+		if resp.NextPage == 0 {
+			return comments, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func (g *githubUpstream) GetIssue(number int64) (*upstreamIssue, error) {
+	issue, _, err := g.client.Issues.Get(g.ctx, g.owner, g.name, int(number))
+	if err != nil {
+		return nil, err
+	}
+	return convertGithubIssue(issue), nil
+}
+
+func (g *githubUpstream) CreateIssue(title, body string) (int64, error) {
+	issue, _, err := g.client.Issues.Create(g.ctx, g.owner, g.name, &github.IssueRequest{
+		Title: &title,
+		Body:  &body,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(issue.GetNumber()), nil
+}
+
+func (g *githubUpstream) CreateComment(number int64, body string) (int64, error) {
+	comment, _, err := g.client.Issues.CreateComment(g.ctx, g.owner, g.name, int(number), &github.IssueComment{
+		Body: &body,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return comment.GetID(), nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	mailNotifyAbuseReport base.TplName = "notify/abuse_report"
+)
+
+// SendAbuseReportMail notifies the moderators about a new abuse report
+func SendAbuseReportMail(r *models.AbuseReport, moderators []*models.User) {
+	if setting.MailService == nil || len(moderators) == 0 {
+		return
+	}
+	if err := r.LoadAttributes(); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+
+	subject := fmt.Sprintf("[%s] New abuse report #%d", setting.AppName, r.ID)
+
+	data := map[string]interface{}{
+		"Subject":     subject,
+		"Reporter":    r.Reporter.Name,
+		"ContentType": r.ContentType.Name(),
+		"ContentURL":  r.HTMLURL(),
+		"Reason":      string(r.Reason),
+		"Remark":      r.Remark,
+		"Link":        fmt.Sprintf("%sadmin/abuse_reports/%d", setting.AppURL, r.ID),
+	}
+
+	var content bytes.Buffer
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyAbuseReport), data); err != nil {
+		log.Error("Template: %v", err)
+		return
+	}
+
+	msgs := make([]*Message, 0, len(moderators))
+	for _, moderator := range moderators {
+		msg := NewMessage([]string{moderator.Email}, subject, content.String())
+		msg.Info = fmt.Sprintf("UID: %d, abuse report notification for report %d", moderator.ID, r.ID)
+		msgs = append(msgs, msg)
+	}
+	SendAsyncs(msgs)
+}
@@ -118,7 +118,7 @@ func mailIssueCommentBatch(ctx *mailCommentContext, ids []int64, visited map[int
 				visited[id] = true
 			}
 		}
-		recipients, err := models.GetMaileableUsersByIDs(unique, fromMention)
+		recipients, err := models.GetMailableNotificationRecipients(unique, ctx.Issue.Repo, models.NotificationRuleEventOfIssue(ctx.Issue), fromMention)
 		if err != nil {
 			return err
 		}
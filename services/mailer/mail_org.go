@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const (
+	mailNotifyOrgTwoFactor base.TplName = "notify/org_two_factor"
+)
+
+// SendOrgTwoFactorReminderMail reminds a member of an organization requiring two-factor authentication to enroll
+func SendOrgTwoFactorReminderMail(u, org *models.User) {
+	subject := fmt.Sprintf("%s requires two-factor authentication", org.DisplayName())
+
+	data := map[string]interface{}{
+		"Subject":  subject,
+		"OrgName":  org.DisplayName(),
+		"Deadline": org.TwoFactorDeadline().FormatLong(),
+		"Link":     setting.AppURL + "user/settings/security",
+	}
+
+	var content bytes.Buffer
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyOrgTwoFactor), data); err != nil {
+		log.Error("Template: %v", err)
+		return
+	}
+
+	msg := NewMessage([]string{u.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, organization two-factor authentication reminder", u.ID)
+
+	SendAsync(msg)
+}
+
+// SendOrgTwoFactorReminderMails reminds all members of organizations requiring two-factor authentication
+// who have not enrolled yet, as long as the grace period of their organization has not ended
+func SendOrgTwoFactorReminderMails(ctx context.Context) error {
+	if setting.MailService == nil {
+		return nil
+	}
+
+	orgs, err := models.GetOrgsRequiringTwoFactor()
+	if err != nil {
+		return err
+	}
+	now := timeutil.TimeStampNow()
+	for _, org := range orgs {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before sending two-factor authentication reminders of %s", org.Name)
+		default:
+		}
+
+		if org.TwoFactorDeadline() <= now {
+			continue
+		}
+		members, err := org.GetMembersWithoutTwoFactor()
+		if err != nil {
+			return err
+		}
+		for _, u := range members {
+			if u.IsActive && !u.ProhibitLogin {
+				SendOrgTwoFactorReminderMail(u, org)
+			}
+		}
+	}
+	return nil
+}
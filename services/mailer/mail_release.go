@@ -27,9 +27,9 @@ func MailNewRelease(rel *models.Release) {
 		return
 	}
 
-	recipients, err := models.GetMaileableUsersByIDs(watcherIDList, false)
+	recipients, err := models.GetMailableNotificationRecipients(watcherIDList, rel.Repo, models.NotificationRuleEventRelease, false)
 	if err != nil {
-		log.Error("models.GetMaileableUsersByIDs: %v", err)
+		log.Error("models.GetMailableNotificationRecipients: %v", err)
 		return
 	}
 
@@ -0,0 +1,48 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	mailNotifyDormantAccount base.TplName = "notify/dormant_account"
+)
+
+// SendDormantAccountMail notifies a user that their account is dormant and will be deactivated
+func SendDormantAccountMail(u *models.User) {
+	if setting.MailService == nil {
+		return
+	}
+
+	subject := fmt.Sprintf("Your %s account will be deactivated", setting.AppName)
+
+	data := map[string]interface{}{
+		"Subject":           subject,
+		"DisplayName":       u.DisplayName(),
+		"Deadline":          u.DormantDeadline().FormatLong(),
+		"AllowReactivation": setting.Service.DormantUserAllowReactivation,
+		"Link":              setting.AppURL + "user/login",
+	}
+
+	var content bytes.Buffer
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyDormantAccount), data); err != nil {
+		log.Error("Template: %v", err)
+		return
+	}
+
+	msg := NewMessage([]string{u.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, dormant account notification", u.ID)
+
+	SendAsync(msg)
+}
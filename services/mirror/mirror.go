@@ -257,6 +257,13 @@ func runSync(m *models.Mirror) ([]*mirrorSyncResult, bool) {
 	}
 	gitRepo.Close()
 
+	if setting.Mirror.EnableReleaseAssets && m.EnableReleaseAssets {
+		log.Trace("SyncMirrors [repo: %-v]: mirroring release assets...", m.Repo)
+		if err := syncReleaseAssets(graceful.GetManager().ShutdownContext(), m); err != nil {
+			log.Error("Failed to mirror release assets for repository: %v", err)
+		}
+	}
+
 	log.Trace("SyncMirrors [repo: %-v]: updating size of repository", m.Repo)
 	if err := m.Repo.UpdateSize(models.DefaultDBContext()); err != nil {
 		log.Error("Failed to update size for mirror repository: %v", err)
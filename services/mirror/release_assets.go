@@ -0,0 +1,164 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations"
+	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// syncReleaseAssets mirrors the releases and release assets of the upstream repository of a mirror
+func syncReleaseAssets(ctx context.Context, m *models.Mirror) error {
+	downloader, err := migrations.NewDownloader(ctx, base.MigrateOptions{
+		CloneAddr:      Address(m),
+		AuthUsername:   Username(m),
+		AuthPassword:   Password(m),
+		OriginalURL:    m.Repo.OriginalURL,
+		GitServiceType: m.Repo.OriginalServiceType,
+	})
+	if err != nil {
+		return fmt.Errorf("NewDownloader: %v", err)
+	} else if downloader == nil {
+		log.Trace("SyncMirrors [repo: %-v]: release assets can not be mirrored from %s", m.Repo, m.Repo.OriginalServiceType.Name())
+		return nil
+	}
+	return mirrorReleases(ctx, m.Repo, downloader)
+}
+
+// mirrorReleases turns the tags of the repository which are releases upstream into releases
+// and downloads the assets of the releases which are not larger than the configured limits
+func mirrorReleases(ctx context.Context, repo *models.Repository, downloader base.Downloader) error {
+	releases, err := downloader.GetReleases()
+	if err != nil {
+		return fmt.Errorf("GetReleases: %v", err)
+	}
+
+	totalSize, err := models.SumRepoReleaseAttachmentsSize(repo.ID)
+	if err != nil {
+		return fmt.Errorf("SumRepoReleaseAttachmentsSize: %v", err)
+	}
+
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+
+		rel, err := models.GetRelease(repo.ID, release.TagName)
+		if err != nil {
+			if models.IsErrReleaseNotExist(err) {
+				// the tag has not been mirrored (yet)
+				continue
+			}
+			return fmt.Errorf("GetRelease: %v", err)
+		}
+
+		if rel.IsTag {
+			rel.IsTag = false
+			rel.Title = release.Name
+			rel.Note = release.Body
+			rel.IsPrerelease = release.Prerelease
+			if len(release.PublisherName) > 0 {
+				rel.OriginalAuthor = release.PublisherName
+				rel.OriginalAuthorID = release.PublisherID
+			}
+			if err := models.UpdateRelease(models.DefaultDBContext(), rel); err != nil {
+				return fmt.Errorf("UpdateRelease: %v", err)
+			}
+		}
+
+		for i := range release.Assets {
+			asset := &release.Assets[i]
+			attach, err := models.GetAttachmentByReleaseIDFileName(rel.ID, asset.Name)
+			if err != nil {
+				return fmt.Errorf("GetAttachmentByReleaseIDFileName: %v", err)
+			} else if attach != nil {
+				continue
+			}
+
+			if asset.Size != nil {
+				size := int64(*asset.Size)
+				if size > setting.Mirror.ReleaseAssetMaxSize {
+					log.Trace("SyncMirrors [repo: %-v]: skipping release asset %s of %s, it is too large", repo, asset.Name, rel.TagName)
+					continue
+				}
+				if setting.Mirror.ReleaseAssetsMaxTotalSize > 0 && totalSize+size > setting.Mirror.ReleaseAssetsMaxTotalSize {
+					log.Trace("SyncMirrors [repo: %-v]: skipping release asset %s of %s, the release assets are too large", repo, asset.Name, rel.TagName)
+					continue
+				}
+			}
+
+			maxSize := setting.Mirror.ReleaseAssetMaxSize
+			if setting.Mirror.ReleaseAssetsMaxTotalSize > 0 && setting.Mirror.ReleaseAssetsMaxTotalSize-totalSize < maxSize {
+				// the size of an asset may be unknown or wrong, so the downloaded bytes are counted
+				maxSize = setting.Mirror.ReleaseAssetsMaxTotalSize - totalSize
+				if maxSize <= 0 {
+					log.Trace("SyncMirrors [repo: %-v]: skipping release asset %s of %s, the release assets are too large", repo, asset.Name, rel.TagName)
+					continue
+				}
+			}
+			attach, err = mirrorReleaseAsset(ctx, downloader, release, rel, asset, maxSize)
+			if err != nil {
+				log.Error("Failed to mirror release asset %s of %s for repository %-v: %v", asset.Name, rel.TagName, repo, err)
+				continue
+			}
+			totalSize += attach.Size
+		}
+	}
+	return nil
+}
+
+// mirrorReleaseAsset downloads a release asset of the upstream release and stores it as attachment of the release
+// if it is not larger than maxSize
+func mirrorReleaseAsset(ctx context.Context, downloader base.AssetDownloader, release *base.Release, rel *models.Release, asset *base.ReleaseAsset, maxSize int64) (*models.Attachment, error) {
+	var rc io.ReadCloser
+	if asset.DownloadURL == nil {
+		var err error
+		rc, err = downloader.GetAsset(release.TagName, release.ID, asset.ID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, *asset.DownloadURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status of %s: %s", *asset.DownloadURL, resp.Status)
+		}
+		rc = resp.Body
+	}
+	defer rc.Close()
+
+	// Read one byte more than allowed to find out whether the asset is larger than its announced size
+	attach, err := models.NewAttachment(&models.Attachment{
+		RepoID:     rel.RepoID,
+		ReleaseID:  rel.ID,
+		UploaderID: rel.PublisherID,
+		Name:       asset.Name,
+	}, nil, io.LimitReader(rc, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if attach.Size > maxSize {
+		if err := models.DeleteAttachment(attach, true); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("release asset is larger than %d bytes", maxSize)
+	}
+	return attach, nil
+}
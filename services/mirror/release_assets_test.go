@@ -0,0 +1,113 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	migration "code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type releaseAssetsDownloader struct {
+	migration.Downloader
+	releases []*migration.Release
+	assets   map[int64]string
+	relIDs   []int64
+}
+
+func (d *releaseAssetsDownloader) GetReleases() ([]*migration.Release, error) {
+	return d.releases, nil
+}
+
+func (d *releaseAssetsDownloader) GetAsset(_ string, relID, id int64) (io.ReadCloser, error) {
+	d.relIDs = append(d.relIDs, relID)
+	return ioutil.NopCloser(strings.NewReader(d.assets[id])), nil
+}
+
+func TestMirrorReleases(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+	defer func(maxSize, maxTotalSize int64) {
+		setting.Mirror.ReleaseAssetMaxSize = maxSize
+		setting.Mirror.ReleaseAssetsMaxTotalSize = maxTotalSize
+	}(setting.Mirror.ReleaseAssetMaxSize, setting.Mirror.ReleaseAssetsMaxTotalSize)
+	setting.Mirror.ReleaseAssetMaxSize = 10
+	setting.Mirror.ReleaseAssetsMaxTotalSize = 0
+
+	size := func(s int) *int { return &s }
+	downloader := &releaseAssetsDownloader{
+		releases: []*migration.Release{
+			{
+				ID:            7,
+				TagName:       "delete-tag",
+				Name:          "Upstream release",
+				Body:          "Upstream notes",
+				Prerelease:    true,
+				PublisherName: "upstream-user",
+				PublisherID:   42,
+				Assets: []migration.ReleaseAsset{
+					{ID: 1, Name: "small.bin", Size: size(5)},
+					{ID: 2, Name: "big.bin", Size: size(11)},
+					{ID: 3, Name: "lying.bin", Size: size(1)},
+				},
+			},
+			{
+				TagName: "not-mirrored-yet",
+				Assets:  []migration.ReleaseAsset{{ID: 4, Name: "other.bin", Size: size(1)}},
+			},
+		},
+		assets: map[int64]string{
+			1: "small",
+			2: "far too big",
+			3: "larger than announced",
+			4: "x",
+		},
+	}
+
+	assert.NoError(t, mirrorReleases(context.Background(), repo, downloader))
+	assert.Equal(t, []int64{7, 7}, downloader.relIDs)
+
+	rel := models.AssertExistsAndLoadBean(t, &models.Release{ID: 3}).(*models.Release)
+	assert.False(t, rel.IsTag)
+	assert.True(t, rel.IsPrerelease)
+	assert.Equal(t, "Upstream release", rel.Title)
+	assert.Equal(t, "Upstream notes", rel.Note)
+	assert.Equal(t, "upstream-user", rel.OriginalAuthor)
+
+	attach := models.AssertExistsAndLoadBean(t, &models.Attachment{ReleaseID: 3, Name: "small.bin"}).(*models.Attachment)
+	assert.EqualValues(t, 5, attach.Size)
+	assert.EqualValues(t, repo.ID, attach.RepoID)
+	models.AssertNotExistsBean(t, &models.Attachment{ReleaseID: 3, Name: "big.bin"})
+	models.AssertNotExistsBean(t, &models.Attachment{ReleaseID: 3, Name: "lying.bin"})
+	models.AssertNotExistsBean(t, &models.Attachment{Name: "other.bin"})
+
+	// assets are only mirrored once
+	assert.NoError(t, mirrorReleases(context.Background(), repo, downloader))
+	models.AssertCount(t, &models.Attachment{ReleaseID: 3}, 1)
+
+	// the total size limit applies to all the release assets of the repository
+	setting.Mirror.ReleaseAssetsMaxTotalSize = 1
+	downloader.releases[0].Assets = []migration.ReleaseAsset{{ID: 4, Name: "other.bin", Size: size(1)}}
+	assert.NoError(t, mirrorReleases(context.Background(), repo, downloader))
+	models.AssertNotExistsBean(t, &models.Attachment{ReleaseID: 3, Name: "other.bin"})
+
+	// assets of unknown size are counted while they are downloaded
+	setting.Mirror.ReleaseAssetsMaxTotalSize = 7
+	downloader.releases[0].Assets = []migration.ReleaseAsset{{ID: 3, Name: "unknown.bin"}}
+	assert.NoError(t, mirrorReleases(context.Background(), repo, downloader))
+	models.AssertNotExistsBean(t, &models.Attachment{ReleaseID: 3, Name: "unknown.bin"})
+	downloader.releases[0].Assets = []migration.ReleaseAsset{{ID: 4, Name: "unknown.bin"}}
+	assert.NoError(t, mirrorReleases(context.Background(), repo, downloader))
+	models.AssertExistsAndLoadBean(t, &models.Attachment{ReleaseID: 3, Name: "unknown.bin"})
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package moderation
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// ReportAbuse creates an abuse report and notifies the site administrators about it
+func ReportAbuse(r *models.AbuseReport) error {
+	if err := models.CreateAbuseReport(r); err != nil {
+		return err
+	}
+
+	if setting.Moderation.NotifyAdmins {
+		moderators, err := models.GetModerators()
+		if err != nil {
+			log.Error("GetModerators: %v", err)
+			return nil
+		}
+		mailer.SendAbuseReportMail(r, moderators)
+	}
+	return nil
+}
+
+// HandleAbuseReport applies the action of a site administrator to the abuse report and the reported
+// content and records it with the comment in the audit trail of the report
+func HandleAbuseReport(doer *models.User, r *models.AbuseReport, action models.AbuseReportAction, comment string) error {
+	notApplicable := models.ErrAbuseReportActionNotApplicable{Action: action, ContentType: r.ContentType}
+	if err := r.LoadContent(); err != nil {
+		// the report of deleted content can still be closed
+		if !models.IsErrAbuseReportContentNotExist(err) {
+			return err
+		}
+		switch action {
+		case models.AbuseReportActionResolve, models.AbuseReportActionDismiss, models.AbuseReportActionReopen, models.AbuseReportActionComment:
+		default:
+			return notApplicable
+		}
+	}
+
+	status := r.Status
+	switch action {
+	case models.AbuseReportActionHide, models.AbuseReportActionShow:
+		hidden := action == models.AbuseReportActionHide
+		switch r.ContentType {
+		case models.AbuseReportContentTypeIssue:
+			if err := models.SetIssueHidden(r.Issue, hidden); err != nil {
+				return err
+			}
+		case models.AbuseReportContentTypeComment:
+			if err := models.SetCommentHidden(r.Comment, hidden); err != nil {
+				return err
+			}
+		case models.AbuseReportContentTypeRepo:
+			// a repository is hidden by making it private, only its owner decides to publish it again
+			if !hidden {
+				return notApplicable
+			}
+			if !r.Repo.IsPrivate {
+				r.Repo.IsPrivate = true
+				if err := models.UpdateRepository(r.Repo, true); err != nil {
+					return err
+				}
+			}
+		default:
+			return notApplicable
+		}
+	case models.AbuseReportActionLock:
+		switch r.ContentType {
+		case models.AbuseReportContentTypeIssue, models.AbuseReportContentTypeComment:
+			if err := models.LockIssue(&models.IssueLockOptions{Doer: doer, Issue: r.Issue}); err != nil {
+				return err
+			}
+		case models.AbuseReportContentTypeRepo:
+			if !r.Repo.IsArchived {
+				if err := r.Repo.SetArchiveRepoState(true); err != nil {
+					return err
+				}
+			}
+		default:
+			return notApplicable
+		}
+	case models.AbuseReportActionBlockUser:
+		u := r.ContentOwner
+		if u.ID <= 0 || u.IsOrganization() || u.IsAdmin {
+			return notApplicable
+		}
+		u.ProhibitLogin = true
+		if err := models.UpdateUserCols(u, "prohibit_login"); err != nil {
+			return err
+		}
+	case models.AbuseReportActionResolve:
+		status = models.AbuseReportStatusResolved
+	case models.AbuseReportActionDismiss:
+		status = models.AbuseReportStatusDismissed
+	case models.AbuseReportActionReopen:
+		status = models.AbuseReportStatusOpen
+	case models.AbuseReportActionComment:
+	default:
+		return notApplicable
+	}
+
+	log.Trace("Abuse report %d handled by %s: %s", r.ID, doer.Name, action)
+	return models.AddAbuseReportLog(r, doer, action, comment, status)
+}
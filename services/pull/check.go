@@ -19,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
@@ -30,6 +31,18 @@ var prQueue queue.UniqueQueue
 
 // AddToTaskQueue adds itself to pull request test task queue.
 func AddToTaskQueue(pr *models.PullRequest) {
+	if setting.Repository.PullRequest.ExternalMergeChecker {
+		// The status is all external merge checkers need to find the pull request.
+		go func() {
+			requeueExternalCheck(pr.ID)
+			pr.Status = models.PullRequestStatusChecking
+			if err := pr.UpdateColsIfNotMerged("status"); err != nil {
+				log.Error("AddToTaskQueue.UpdateCols[%d].(external merge checker): %v", pr.ID, err)
+			}
+		}()
+		return
+	}
+
 	go func() {
 		err := prQueue.PushFunc(strconv.FormatInt(pr.ID, 10), func() error {
 			pr.Status = models.PullRequestStatusChecking
@@ -242,8 +255,14 @@ func CheckPrsForBaseBranch(baseRepo *models.Repository, baseBranchName string) e
 	return nil
 }
 
-// Init runs the task queue to test all the checking status pull requests
+// Init runs the task queue to test all the checking status pull requests,
+// unless they are tested by external merge checkers.
 func Init() error {
+	if setting.Repository.PullRequest.ExternalMergeChecker {
+		log.Info("Pull requests are checked for conflicts by external merge checkers")
+		return nil
+	}
+
 	prQueue = queue.CreateUniqueQueue("pr_patch_checker", handle, "").(queue.UniqueQueue)
 
 	if prQueue == nil {
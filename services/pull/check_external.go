@@ -0,0 +1,254 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// externalCheck represents a pull request handed out to an external merge checker
+type externalCheck struct {
+	claimed time.Time
+	// requeued is set if the pull request needs to be checked again while it is handed out
+	requeued bool
+}
+
+var externalChecks = struct {
+	sync.Mutex
+	checks map[int64]*externalCheck
+}{checks: make(map[int64]*externalCheck)}
+
+// claimExternalCheck marks the pull request as handed out unless it already is
+func claimExternalCheck(prID int64) bool {
+	externalChecks.Lock()
+	defer externalChecks.Unlock()
+
+	if check, ok := externalChecks.checks[prID]; ok && time.Since(check.claimed) < setting.Repository.PullRequest.ExternalMergeCheckerTimeout {
+		return false
+	}
+	externalChecks.checks[prID] = &externalCheck{claimed: time.Now()}
+	return true
+}
+
+// releaseExternalCheck removes the pull request from the handed out ones,
+// it returns false if it has to be checked again since it was handed out.
+func releaseExternalCheck(prID int64) bool {
+	externalChecks.Lock()
+	defer externalChecks.Unlock()
+
+	check, ok := externalChecks.checks[prID]
+	delete(externalChecks.checks, prID)
+	return !ok || !check.requeued
+}
+
+// requeueExternalCheck marks a handed out pull request to be checked again
+func requeueExternalCheck(prID int64) {
+	externalChecks.Lock()
+	defer externalChecks.Unlock()
+
+	if check, ok := externalChecks.checks[prID]; ok {
+		check.requeued = true
+	}
+}
+
+// getCheckCommitIDs returns the current commits of the base and head branch of the pull request
+func getCheckCommitIDs(pr *models.PullRequest) (baseCommitID, headCommitID string, err error) {
+	baseGitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return "", "", fmt.Errorf("OpenRepository: %v", err)
+	}
+	defer baseGitRepo.Close()
+
+	if baseCommitID, err = baseGitRepo.GetBranchCommitID(pr.BaseBranch); err != nil {
+		return "", "", fmt.Errorf("GetBranchCommitID(%s): %v", pr.BaseBranch, err)
+	}
+
+	headGitRepo := baseGitRepo
+	if pr.HeadRepoID != pr.BaseRepoID {
+		headGitRepo, err = git.OpenRepository(pr.HeadRepo.RepoPath())
+		if err != nil {
+			return "", "", fmt.Errorf("OpenRepository: %v", err)
+		}
+		defer headGitRepo.Close()
+	}
+
+	if headCommitID, err = headGitRepo.GetBranchCommitID(pr.HeadBranch); err != nil {
+		return "", "", fmt.Errorf("GetBranchCommitID(%s): %v", pr.HeadBranch, err)
+	}
+	return baseCommitID, headCommitID, nil
+}
+
+func newPullCheckTask(pr *models.PullRequest) (*private.PullCheckTask, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, err
+	} else if err := pr.LoadHeadRepo(); err != nil {
+		return nil, err
+	} else if pr.HeadRepo == nil {
+		return nil, &models.ErrRepoNotExist{ID: pr.HeadRepoID}
+	} else if err := pr.LoadProtectedBranch(); err != nil {
+		return nil, err
+	}
+
+	prUnit, err := pr.BaseRepo.GetUnit(models.UnitTypePullRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	baseCommitID, headCommitID, err := getCheckCommitIDs(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &private.PullCheckTask{
+		PullID:                    pr.ID,
+		BaseRepoOwner:             pr.BaseRepo.OwnerName,
+		BaseRepoName:              pr.BaseRepo.Name,
+		BaseBranch:                pr.BaseBranch,
+		BaseCommitID:              baseCommitID,
+		HeadRepoOwner:             pr.HeadRepo.OwnerName,
+		HeadRepoName:              pr.HeadRepo.Name,
+		HeadBranch:                pr.HeadBranch,
+		HeadCommitID:              headCommitID,
+		IgnoreWhitespaceConflicts: prUnit.PullRequestsConfig().IgnoreWhitespaceConflicts,
+	}
+	if pr.ProtectedBranch != nil {
+		task.ProtectedFilePatterns = pr.ProtectedBranch.ProtectedFilePatterns
+	}
+	return task, nil
+}
+
+// ClaimExternalCheck hands out the next pull request of the shard which needs to be checked for conflicts
+// to an external merge checker. It returns nil if there is none.
+func ClaimExternalCheck(shard, shards int64) (*private.PullCheckTask, error) {
+	prIDs, err := models.GetPullRequestIDsByCheckStatusInShard(models.PullRequestStatusChecking, shard, shards)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prID := range prIDs {
+		if !claimExternalCheck(prID) {
+			continue
+		}
+
+		pr, err := models.GetPullRequestByID(prID)
+		if err != nil {
+			releaseExternalCheck(prID)
+			return nil, err
+		} else if pr.HasMerged || manuallyMerged(pr) {
+			releaseExternalCheck(prID)
+			continue
+		}
+
+		task, err := newPullCheckTask(pr)
+		if err != nil {
+			releaseExternalCheck(prID)
+			log.Error("newPullCheckTask[%d]: %v", pr.ID, err)
+			pr.Status = models.PullRequestStatusError
+			if err := pr.UpdateCols("status"); err != nil {
+				log.Error("update pr [%d] status to PullRequestStatusError failed: %v", pr.ID, err)
+			}
+			continue
+		}
+
+		log.Trace("Handing out PR ID %d of shard %d/%d to an external merge checker", pr.ID, shard, shards)
+		return task, nil
+	}
+	return nil, nil
+}
+
+// SubmitExternalCheck stores the result of checking a pull request by an external merge checker.
+// Results for outdated base or head branches are dropped, the pull request is handed out again.
+func SubmitExternalCheck(prID int64, result *private.PullCheckResult) error {
+	if !releaseExternalCheck(prID) {
+		log.Trace("PR ID %d has been requeued while it was checked, dropping the result", prID)
+		return nil
+	}
+
+	pr, err := models.GetPullRequestByID(prID)
+	if err != nil {
+		return err
+	} else if pr.HasMerged || pr.Status != models.PullRequestStatusChecking {
+		return nil
+	} else if err := pr.LoadBaseRepo(); err != nil {
+		return err
+	} else if err := pr.LoadHeadRepo(); err != nil {
+		return err
+	} else if pr.HeadRepo == nil {
+		return &models.ErrRepoNotExist{ID: pr.HeadRepoID}
+	}
+
+	baseCommitID, headCommitID, err := getCheckCommitIDs(pr)
+	if err != nil {
+		return err
+	}
+	if baseCommitID != result.BaseCommitID || headCommitID != result.HeadCommitID {
+		log.Trace("PR ID %d has changed while it was checked, dropping the result", prID)
+		return nil
+	}
+
+	if len(result.Err) > 0 {
+		log.Error("testPatch[%d]: %s", pr.ID, result.Err)
+		pr.Status = models.PullRequestStatusError
+		return pr.UpdateCols("status")
+	}
+
+	pr.MergeBase = result.MergeBase
+	pr.ConflictedFiles = result.ConflictedFiles
+	pr.ChangedProtectedFiles = result.ChangedProtectedFiles
+	pr.Status = models.PullRequestStatusMergeable
+	if result.Conflicted {
+		pr.Status = models.PullRequestStatusConflict
+	}
+	return pr.UpdateColsIfNotMerged("merge_base", "status", "conflicted_files", "changed_protected_files")
+}
+
+// RunExternalCheck checks a pull request handed out to an external merge checker. Everything but the
+// repositories themselves is taken from the task, so no database access is needed.
+func RunExternalCheck(task *private.PullCheckTask) *private.PullCheckResult {
+	newRepo := func(ownerName, name string) *models.Repository {
+		return &models.Repository{
+			OwnerName: ownerName,
+			Name:      name,
+			Owner:     &models.User{Name: ownerName},
+			Units: []*models.RepoUnit{{
+				Type:   models.UnitTypePullRequests,
+				Config: &models.PullRequestsConfig{IgnoreWhitespaceConflicts: task.IgnoreWhitespaceConflicts},
+			}},
+		}
+	}
+
+	pr := &models.PullRequest{
+		ID:              task.PullID,
+		Status:          models.PullRequestStatusChecking,
+		BaseBranch:      task.BaseBranch,
+		BaseRepo:        newRepo(task.BaseRepoOwner, task.BaseRepoName),
+		HeadBranch:      task.HeadBranch,
+		HeadRepo:        newRepo(task.HeadRepoOwner, task.HeadRepoName),
+		ProtectedBranch: &models.ProtectedBranch{ProtectedFilePatterns: task.ProtectedFilePatterns},
+	}
+
+	result := &private.PullCheckResult{
+		BaseCommitID: task.BaseCommitID,
+		HeadCommitID: task.HeadCommitID,
+	}
+	if err := TestPatch(pr); err != nil {
+		result.Err = err.Error()
+		return result
+	}
+
+	result.MergeBase = pr.MergeBase
+	result.Conflicted = pr.Status == models.PullRequestStatusConflict
+	result.ConflictedFiles = pr.ConflictedFiles
+	result.ChangedProtectedFiles = pr.ChangedProtectedFiles
+	return result
+}
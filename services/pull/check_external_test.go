@@ -0,0 +1,80 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalCheck(t *testing.T) {
+	models.PrepareTestEnv(t)
+
+	defer func(enabled bool) {
+		setting.Repository.PullRequest.ExternalMergeChecker = enabled
+	}(setting.Repository.PullRequest.ExternalMergeChecker)
+	setting.Repository.PullRequest.ExternalMergeChecker = true
+
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+	pr.Status = models.PullRequestStatusChecking
+	assert.NoError(t, pr.UpdateCols("status"))
+
+	// the base repository of pull request 2 is not in shard 0 of 2
+	task, err := ClaimExternalCheck(0, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, task)
+
+	task, err = ClaimExternalCheck(1, 2)
+	assert.NoError(t, err)
+	if !assert.NotNil(t, task) {
+		return
+	}
+	assert.EqualValues(t, 2, task.PullID)
+	assert.Equal(t, "user2", task.BaseRepoOwner)
+	assert.Equal(t, "repo1", task.HeadRepoName)
+	assert.Equal(t, "branch2", task.HeadBranch)
+	assert.NotEmpty(t, task.BaseCommitID)
+	assert.NotEmpty(t, task.HeadCommitID)
+
+	// a pull request is handed out only once
+	again, err := ClaimExternalCheck(1, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, again)
+
+	result := RunExternalCheck(task)
+	assert.Empty(t, result.Err)
+	assert.NotEmpty(t, result.MergeBase)
+
+	// results for pull requests requeued while they were checked are dropped
+	requeueExternalCheck(task.PullID)
+	assert.NoError(t, SubmitExternalCheck(task.PullID, result))
+	models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2, Status: models.PullRequestStatusChecking})
+
+	// so are results for outdated branches
+	task, err = ClaimExternalCheck(1, 2)
+	assert.NoError(t, err)
+	assert.NotNil(t, task)
+	outdated := *result
+	outdated.HeadCommitID = "0000000000000000000000000000000000000000"
+	assert.NoError(t, SubmitExternalCheck(task.PullID, &outdated))
+	models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2, Status: models.PullRequestStatusChecking})
+
+	task, err = ClaimExternalCheck(1, 2)
+	assert.NoError(t, err)
+	assert.NotNil(t, task)
+	assert.NoError(t, SubmitExternalCheck(task.PullID, result))
+	pr = models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+	assert.NotEqual(t, models.PullRequestStatusChecking, pr.Status)
+	assert.Equal(t, result.MergeBase, pr.MergeBase)
+
+	// nothing is left to check
+	task, err = ClaimExternalCheck(1, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, task)
+}
@@ -84,6 +84,11 @@ func Merge(pr *models.PullRequest, doer *models.User, baseGitRepo *git.Repositor
 	// Reset cached commit count
 	cache.Remove(pr.Issue.Repo.GetCommitsCountCacheKey(pr.BaseBranch, true))
 
+	// The repository may only close issues referenced by pushed commits
+	if !pr.Issue.Repo.IssueCloseTrigger().OnMerge() {
+		return nil
+	}
+
 	// Resolve cross references
 	refs, err := pr.ResolveCrossReferences()
 	if err != nil {
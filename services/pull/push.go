@@ -0,0 +1,104 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// PushToPullRequestOptions represents the options to turn a push to a protected branch into a pull request
+type PushToPullRequestOptions struct {
+	Repo        *models.Repository
+	Doer        *models.User
+	BaseBranch  string
+	OldCommitID string
+	NewCommitID string
+	Title       string
+	Description string
+}
+
+// pushToPullRequestBranch returns a name of a branch the pushed commits can be moved to which does not exist yet
+func pushToPullRequestBranch(gitRepo *git.Repository, doer *models.User, newCommitID string) string {
+	name := fmt.Sprintf("pr/%s/%s", doer.LowerName, newCommitID[:10])
+	headBranch := name
+	for i := 2; gitRepo.IsBranchExist(headBranch); i++ {
+		headBranch = fmt.Sprintf("%s-%d", name, i)
+	}
+	return headBranch
+}
+
+// movePushToBranch restores the protected branch and moves the pushed commits onto a new branch, whose name is returned
+func movePushToBranch(gitRepo *git.Repository, opts *PushToPullRequestOptions) (string, error) {
+	repoPath := opts.Repo.RepoPath()
+
+	// Restore the protected branch first, whatever fails afterwards it must not keep the unreviewed commits
+	if _, err := git.NewCommand("update-ref", git.BranchPrefix+opts.BaseBranch, opts.OldCommitID, opts.NewCommitID).RunInDir(repoPath); err != nil {
+		return "", fmt.Errorf("restore branch %s: %v", opts.BaseBranch, err)
+	}
+
+	headBranch := pushToPullRequestBranch(gitRepo, opts.Doer, opts.NewCommitID)
+	if _, err := git.NewCommand("update-ref", git.BranchPrefix+headBranch, opts.NewCommitID, git.EmptySHA).RunInDir(repoPath); err != nil {
+		return "", fmt.Errorf("create branch %s: %v", headBranch, err)
+	}
+	return headBranch, nil
+}
+
+// PushToPullRequest moves an already accepted push to a protected branch onto a new branch,
+// restores the protected branch and opens a pull request from the new branch against it.
+func PushToPullRequest(opts *PushToPullRequestOptions) (*models.PullRequest, error) {
+	gitRepo, err := git.OpenRepository(opts.Repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	headBranch, err := movePushToBranch(gitRepo, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	title := opts.Title
+	if len(title) == 0 {
+		commit, err := gitRepo.GetCommit(opts.NewCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("GetCommit: %v", err)
+		}
+		title = commit.Summary()
+	}
+
+	mergeBase, _, err := gitRepo.GetMergeBase("", git.BranchPrefix+opts.BaseBranch, git.BranchPrefix+headBranch)
+	if err != nil {
+		return nil, fmt.Errorf("GetMergeBase: %v", err)
+	}
+
+	prIssue := &models.Issue{
+		RepoID:   opts.Repo.ID,
+		Title:    title,
+		PosterID: opts.Doer.ID,
+		Poster:   opts.Doer,
+		IsPull:   true,
+		Content:  opts.Description,
+	}
+	pr := &models.PullRequest{
+		HeadRepoID: opts.Repo.ID,
+		BaseRepoID: opts.Repo.ID,
+		HeadBranch: headBranch,
+		BaseBranch: opts.BaseBranch,
+		HeadRepo:   opts.Repo,
+		BaseRepo:   opts.Repo,
+		MergeBase:  mergeBase,
+		Type:       models.PullRequestGitea,
+	}
+	if err := NewPullRequest(opts.Repo, prIssue, nil, nil, pr, nil); err != nil {
+		return nil, err
+	}
+
+	log.Trace("Push to protected branch %s in %-v turned into pull request #%d", opts.BaseBranch, opts.Repo, prIssue.Index)
+	return pr, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMovePushToBranch(t *testing.T) {
+	models.PrepareTestEnv(t)
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	opts := &PushToPullRequestOptions{
+		Repo:        repo,
+		Doer:        models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User),
+		BaseBranch:  "master",
+		OldCommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+		NewCommitID: "5c050d3b6d2db231ab1f64e324f1b6b9a0b181c2",
+	}
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+
+	push := func() string {
+		// the push was already accepted when the commits are moved
+		_, err := git.NewCommand("update-ref", git.BranchPrefix+"master", opts.NewCommitID, opts.OldCommitID).RunInDir(repo.RepoPath())
+		assert.NoError(t, err)
+
+		headBranch, err := movePushToBranch(gitRepo, opts)
+		assert.NoError(t, err)
+
+		commitID, err := gitRepo.GetBranchCommitID("master")
+		assert.NoError(t, err)
+		assert.EqualValues(t, opts.OldCommitID, commitID)
+		commitID, err = gitRepo.GetBranchCommitID(headBranch)
+		assert.NoError(t, err)
+		assert.EqualValues(t, opts.NewCommitID, commitID)
+		return headBranch
+	}
+
+	assert.EqualValues(t, "pr/user2/5c050d3b6d", push())
+	// pushing the same commit again moves it to another branch
+	assert.EqualValues(t, "pr/user2/5c050d3b6d-2", push())
+}
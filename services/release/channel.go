@@ -0,0 +1,49 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package release
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// DeleteExpiredChannelReleases deletes the releases, including their tags, of all release
+// channels beyond the number of releases the channels keep
+func DeleteExpiredChannelReleases(ctx context.Context) error {
+	log.Trace("Doing: DeleteExpiredChannelReleases")
+
+	channels, err := models.GetReleaseChannelsWithRetention()
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		rels, err := channel.GetExpiredReleases()
+		if err != nil {
+			return err
+		}
+
+		for _, rel := range rels {
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before deleting release %s of repo %d", rel.TagName, rel.RepoID)
+			default:
+			}
+
+			if err := rel.LoadAttributes(); err != nil {
+				return err
+			}
+			log.Trace("Deleting release %s of %s, it has expired in release channel %s", rel.TagName, rel.Repo.FullName(), channel.Name)
+			if err := DeleteReleaseByID(rel.ID, rel.Publisher, true); err != nil {
+				log.Error("DeleteReleaseByID[%d]: %v", rel.ID, err)
+			}
+		}
+	}
+
+	log.Trace("Finished: DeleteExpiredChannelReleases")
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package replication
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ServedByReplica returns whether a read replica serves the request itself. Reads and git fetches
+// are served from the local copies, all writes including git pushes are forwarded to the primary.
+func ServedByReplica(req *http.Request) bool {
+	if strings.HasPrefix(req.URL.Path, setting.AppSubURL+"/api/internal/") ||
+		strings.HasSuffix(req.URL.Path, "/git-upload-pack") {
+		return true
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return req.URL.Query().Get("service") != "git-receive-pack"
+	}
+	return false
+}
+
+// NewPrimaryProxy returns a handler forwarding requests to the primary. The primary is expected
+// to be served from the same sub-path as the replica.
+func NewPrimaryProxy() (http.Handler, error) {
+	primaryURL, err := url.Parse(setting.Replication.PrimaryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = primaryURL.Scheme
+			req.URL.Host = primaryURL.Host
+			req.Host = primaryURL.Host
+		},
+		ErrorHandler: func(w http.ResponseWriter, req *http.Request, err error) {
+			log.Error("Unable to forward %s %s to the primary: %v", req.Method, req.URL.Path, err)
+			http.Error(w, "Unable to contact the primary", http.StatusBadGateway)
+		},
+	}, nil
+}
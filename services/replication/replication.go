@@ -0,0 +1,236 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package replication
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// syncQueue represents a queue of repository IDs to sync from the primary
+var syncQueue queue.UniqueQueue
+
+func handle(data ...queue.Data) {
+	for _, datum := range data {
+		repoID := datum.(int64)
+		if err := SyncRepository(repoID); err != nil {
+			log.Error("SyncRepository[%d]: %v", repoID, err)
+		}
+	}
+}
+
+// Init starts the queue syncing the repositories of a read replica from the primary
+func Init() error {
+	if !setting.Replication.IsReplica {
+		return nil
+	}
+
+	// the replication secret is passed to git through the environment
+	if err := git.CheckGitVersionAtLeast("2.31"); err != nil {
+		return fmt.Errorf("read replicas require git >= 2.31: %v", err)
+	}
+
+	syncQueue = queue.CreateUniqueQueue("replica_sync", handle, int64(0)).(queue.UniqueQueue)
+	if syncQueue == nil {
+		return fmt.Errorf("Unable to create replica_sync Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(syncQueue.Run)
+
+	log.Info("Running as read replica of %s", setting.Replication.PrimaryURL)
+	return nil
+}
+
+// AddToSyncQueue queues the repository to be synced from the primary
+func AddToSyncQueue(repoID int64) error {
+	if err := syncQueue.Push(repoID); err != nil {
+		if err != queue.ErrAlreadyInQueue {
+			return err
+		}
+		log.Trace("Repo ID: %d already queued for replica sync", repoID)
+	}
+	return nil
+}
+
+// HandleSyncRequest applies a repository change the primary notified the replica of.
+// Deletions, renames and transfers are applied to the local copies at once, everything
+// else is fetched from the primary in the background.
+func HandleSyncRequest(opts *private.ReplicaSyncOptions) error {
+	repoPath := models.RepoPath(opts.OwnerName, opts.RepoName)
+	wikiPath := models.WikiPath(opts.OwnerName, opts.RepoName)
+
+	if opts.Deleted {
+		log.Trace("Removing local copy of deleted repository %s/%s", opts.OwnerName, opts.RepoName)
+		if err := util.RemoveAll(repoPath); err != nil {
+			return fmt.Errorf("RemoveAll(%s): %v", repoPath, err)
+		}
+		if err := util.RemoveAll(wikiPath); err != nil {
+			return fmt.Errorf("RemoveAll(%s): %v", wikiPath, err)
+		}
+		return nil
+	}
+
+	if len(opts.OldOwnerName) > 0 || len(opts.OldRepoName) > 0 {
+		oldOwnerName, oldRepoName := opts.OwnerName, opts.RepoName
+		if len(opts.OldOwnerName) > 0 {
+			oldOwnerName = opts.OldOwnerName
+		}
+		if len(opts.OldRepoName) > 0 {
+			oldRepoName = opts.OldRepoName
+		}
+
+		log.Trace("Moving local copy of repository %s/%s to %s/%s", oldOwnerName, oldRepoName, opts.OwnerName, opts.RepoName)
+		if err := moveLocalCopy(models.RepoPath(oldOwnerName, oldRepoName), repoPath); err != nil {
+			return err
+		}
+		if err := moveLocalCopy(models.WikiPath(oldOwnerName, oldRepoName), wikiPath); err != nil {
+			return err
+		}
+	}
+
+	return AddToSyncQueue(opts.RepoID)
+}
+
+// moveLocalCopy moves a local repository, if it does not exist it is cloned by the next sync
+func moveLocalCopy(oldPath, newPath string) error {
+	if oldPath == newPath {
+		return nil
+	}
+
+	isExist, err := util.IsExist(oldPath)
+	if err != nil {
+		return err
+	} else if !isExist {
+		return nil
+	}
+
+	if err := util.RemoveAll(newPath); err != nil {
+		return fmt.Errorf("RemoveAll(%s): %v", newPath, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(newPath), os.ModePerm); err != nil {
+		return fmt.Errorf("MkdirAll(%s): %v", filepath.Dir(newPath), err)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return fmt.Errorf("Rename(%s, %s): %v", oldPath, newPath, err)
+	}
+	return nil
+}
+
+// primaryRemoteURL returns the smart HTTP URL of a repository on the primary
+func primaryRemoteURL(ownerName, repoName string) string {
+	return setting.Replication.PrimaryURL + url.PathEscape(ownerName) + "/" + url.PathEscape(repoName) + ".git"
+}
+
+// syncGitRepository makes the local repository at repoPath an exact copy of the remote one,
+// the repository is cloned if it does not exist yet.
+func syncGitRepository(repoPath, remoteURL, description string) error {
+	timeout := time.Duration(setting.Git.Timeout.Mirror) * time.Second
+	// keep the secret off the command line where every local user could read it
+	env := append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Bearer "+setting.Replication.Secret,
+	)
+
+	isExist, err := util.IsExist(repoPath)
+	if err != nil {
+		return err
+	}
+
+	var stderr strings.Builder
+	if !isExist {
+		if err := os.MkdirAll(filepath.Dir(repoPath), os.ModePerm); err != nil {
+			return fmt.Errorf("MkdirAll(%s): %v", filepath.Dir(repoPath), err)
+		}
+		if err := git.NewCommand("clone", "--mirror", "--quiet", "--", remoteURL, repoPath).
+			SetDescription(fmt.Sprintf("Replica.clone: %s", description)).
+			RunInDirTimeoutEnvPipeline(env, timeout, "", nil, &stderr); err != nil {
+			return fmt.Errorf("clone: %v - %s", err, stderr.String())
+		}
+		return nil
+	}
+
+	if err := git.NewCommand("fetch", "--quiet", "--prune", "--force", "--", remoteURL, "+refs/*:refs/*").
+		SetDescription(fmt.Sprintf("Replica.fetch: %s", description)).
+		RunInDirTimeoutEnvPipeline(env, timeout, repoPath, nil, &stderr); err != nil {
+		return fmt.Errorf("fetch: %v - %s", err, stderr.String())
+	}
+	return nil
+}
+
+// SyncRepository updates the local copies of the repository and its wiki from the primary
+func SyncRepository(repoID int64) error {
+	repo, err := models.GetRepositoryByID(repoID)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+
+	log.Trace("Syncing repository %s from the primary", repo.FullName())
+	if err := syncGitRepository(repo.RepoPath(), primaryRemoteURL(repo.OwnerName, repo.Name), repo.FullName()); err != nil {
+		return err
+	}
+	if len(repo.DefaultBranch) > 0 {
+		if _, err := git.NewCommand("symbolic-ref", "HEAD", git.BranchPrefix+repo.DefaultBranch).RunInDir(repo.RepoPath()); err != nil {
+			return fmt.Errorf("symbolic-ref: %v", err)
+		}
+	}
+
+	if repo.UnitEnabled(models.UnitTypeWiki) {
+		// The wiki is only created on the primary with its first page
+		if err := syncGitRepository(repo.WikiPath(), primaryRemoteURL(repo.OwnerName, repo.Name+".wiki"), repo.FullName()+".wiki"); err != nil {
+			log.Debug("Unable to sync wiki of repository %s from the primary: %v", repo.FullName(), err)
+		}
+	}
+	return nil
+}
+
+// SyncAllRepositories queues all repositories to be synced from the primary
+func SyncAllRepositories(ctx context.Context) error {
+	log.Trace("Doing: SyncAllRepositories")
+
+	if err := models.Iterate(
+		models.DefaultDBContext(),
+		new(models.Repository),
+		builder.Gt{"id": 0},
+		func(idx int, bean interface{}) error {
+			repo := bean.(*models.Repository)
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before syncing %s from the primary", repo.FullName())
+			default:
+			}
+			return AddToSyncQueue(repo.ID)
+		},
+	); err != nil {
+		log.Trace("Error: SyncAllRepositories: %v", err)
+		return err
+	}
+
+	log.Trace("Finished: SyncAllRepositories")
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package replication
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServedByReplica(t *testing.T) {
+	kases := []struct {
+		method   string
+		url      string
+		isServed bool
+	}{
+		{"GET", "/user2/repo1", true},
+		{"HEAD", "/user2/repo1/src/branch/master", true},
+		{"POST", "/user2/repo1/issues/new", false},
+		{"DELETE", "/api/v1/repos/user2/repo1", false},
+		{"GET", "/user2/repo1.git/info/refs?service=git-upload-pack", true},
+		{"POST", "/user2/repo1.git/git-upload-pack", true},
+		{"GET", "/user2/repo1.git/info/refs?service=git-receive-pack", false},
+		{"POST", "/user2/repo1.git/git-receive-pack", false},
+		{"POST", "/api/internal/replica/sync", true},
+	}
+	for _, kase := range kases {
+		req := httptest.NewRequest(kase.method, kase.url, nil)
+		assert.Equal(t, kase.isServed, ServedByReplica(req), "%s %s", kase.method, kase.url)
+	}
+}
+
+func TestSyncGitRepository(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	defer func(primaryURL string) {
+		setting.Replication.PrimaryURL = primaryURL
+	}(setting.Replication.PrimaryURL)
+	setting.Replication.PrimaryURL = "file://" + filepath.ToSlash(setting.RepoRootPath) + "/"
+
+	tmpDir, err := ioutil.TempDir("", "replica")
+	assert.NoError(t, err)
+	defer util.RemoveAll(tmpDir)
+	repoPath := filepath.Join(tmpDir, "user2", "repo1.git")
+
+	// the local copy is cloned first
+	assert.NoError(t, syncGitRepository(repoPath, primaryRemoteURL("user2", "repo1"), "user2/repo1"))
+	gitRepo, err := git.OpenRepository(repoPath)
+	assert.NoError(t, err)
+	assert.True(t, gitRepo.IsBranchExist("master"))
+	gitRepo.Close()
+
+	// and updated from then on
+	_, err = git.NewCommand("update-ref", "-d", git.BranchPrefix+"master").RunInDir(repoPath)
+	assert.NoError(t, err)
+	assert.NoError(t, syncGitRepository(repoPath, primaryRemoteURL("user2", "repo1"), "user2/repo1"))
+	gitRepo, err = git.OpenRepository(repoPath)
+	assert.NoError(t, err)
+	assert.True(t, gitRepo.IsBranchExist("master"))
+	gitRepo.Close()
+
+	assert.Error(t, syncGitRepository(filepath.Join(tmpDir, "user2", "missing.git"), primaryRemoteURL("user2", "missing"), "user2/missing"))
+}
+
+func TestMoveLocalCopy(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "replica")
+	assert.NoError(t, err)
+	defer util.RemoveAll(tmpDir)
+
+	oldPath := filepath.Join(tmpDir, "user2", "repo1.git")
+	newPath := filepath.Join(tmpDir, "user3", "repo2.git")
+	assert.NoError(t, os.MkdirAll(oldPath, os.ModePerm))
+
+	assert.NoError(t, moveLocalCopy(oldPath, newPath))
+	isExist, err := util.IsExist(oldPath)
+	assert.NoError(t, err)
+	assert.False(t, isExist)
+	isExist, err = util.IsExist(newPath)
+	assert.NoError(t, err)
+	assert.True(t, isExist)
+
+	// copies not synced yet are left to the next sync
+	assert.NoError(t, moveLocalCopy(oldPath, newPath))
+	isExist, err = util.IsExist(newPath)
+	assert.NoError(t, err)
+	assert.True(t, isExist)
+}
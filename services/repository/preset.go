@@ -0,0 +1,39 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"code.gitea.io/gitea/models"
+)
+
+// getUsableRepoPreset returns the repository preset of the owner which the doer may create repositories from.
+// It returns ErrRepoPresetNotExist if the preset does not belong to the owner or the doer may not use it.
+func getUsableRepoPreset(doer, owner *models.User, presetID int64) (*models.RepoPreset, error) {
+	if !owner.IsOrganization() {
+		return nil, models.ErrRepoPresetNotExist{ID: presetID, OrgID: owner.ID}
+	}
+	preset, err := models.GetRepoPresetByID(owner.ID, presetID)
+	if err != nil {
+		return nil, err
+	}
+	if usable, err := preset.IsUsableBy(doer); err != nil {
+		return nil, err
+	} else if !usable {
+		return nil, models.ErrRepoPresetNotExist{ID: presetID, OrgID: owner.ID}
+	}
+	return preset, nil
+}
+
+// CreateRepositoryFromPreset creates a repository of an organization with the units, labels, topics,
+// branch protection and webhooks of a preset of the organization. The preset is applied within
+// the transaction creating the repository, so either all of it is applied or no repository is created.
+func CreateRepositoryFromPreset(doer, owner *models.User, presetID int64, opts models.CreateRepoOptions) (*models.Repository, error) {
+	preset, err := getUsableRepoPreset(doer, owner, presetID)
+	if err != nil {
+		return nil, err
+	}
+	opts.Preset = preset
+	return CreateRepository(doer, owner, opts)
+}
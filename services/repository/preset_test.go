@@ -0,0 +1,53 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRepositoryFromPreset(t *testing.T) {
+	registerNotifier()
+
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	org := models.AssertExistsAndLoadBean(t, &models.User{ID: 3}).(*models.User)
+	preset := &models.RepoPreset{
+		OrgID:       org.ID,
+		TeamID:      12,
+		Name:        "Service",
+		Units:       []models.UnitType{models.UnitTypeIssues},
+		IssueLabels: "Default",
+		Topics:      []string{"backend"},
+	}
+	assert.NoError(t, models.CreateRepoPreset(preset))
+
+	// user4 is not a member of the team of the preset
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	_, err := CreateRepositoryFromPreset(doer, org, preset.ID, models.CreateRepoOptions{Name: "preset-repo"})
+	assert.True(t, models.IsErrRepoPresetNotExist(err))
+
+	// the preset of an organization can not be used for other owners
+	doer = models.AssertExistsAndLoadBean(t, &models.User{ID: 28}).(*models.User)
+	_, err = CreateRepositoryFromPreset(doer, doer, preset.ID, models.CreateRepoOptions{Name: "preset-repo"})
+	assert.True(t, models.IsErrRepoPresetNotExist(err))
+
+	repo, err := CreateRepositoryFromPreset(doer, org, preset.ID, models.CreateRepoOptions{Name: "preset-repo"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"backend"}, repo.Topics)
+
+	assert.False(t, repo.UnitEnabled(models.UnitTypePullRequests))
+	assert.True(t, repo.UnitEnabled(models.UnitTypeIssues))
+
+	labels, err := models.GetLabelsByRepoID(repo.ID, "", models.ListOptions{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, labels)
+
+	assert.NoError(t, models.DeleteRepository(doer, org.ID, repo.ID))
+}
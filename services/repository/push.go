@@ -118,6 +118,10 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 				log.Trace("TriggerTask '%s/%s' by %s", repo.Name, branch, pusher.Name)
 				go pull_service.AddTestPullRequestTask(pusher, repo.ID, branch, true, opts.OldCommitID, opts.NewCommitID)
 
+				if branch == repo.DefaultBranch && repo_module.IsHooksSourceRepo(repo) {
+					repo_module.TriggerSyncManagedHookScripts(graceful.GetManager().ShutdownContext())
+				}
+
 				newCommit, err := gitRepo.GetCommit(opts.NewCommitID)
 				if err != nil {
 					return fmt.Errorf("gitRepo.GetCommit: %v", err)
@@ -0,0 +1,97 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package tokenusage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// flushInterval is the interval in which the recorded usages are written to the database,
+// so every usage of a token is written at most once per interval
+const flushInterval = time.Minute
+
+type usageKey struct {
+	tokenID  int64
+	uid      int64
+	orgID    int64
+	endpoint string
+	isWrite  bool
+	ip       string
+}
+
+var (
+	pendingLock sync.Mutex
+	pending     = make(map[usageKey]*models.TokenUsage)
+)
+
+// Init starts writing the recorded token usages to the database
+func Init() {
+	go graceful.GetManager().RunWithShutdownContext(run)
+}
+
+func run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			return
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// Record counts an API call made with the access token to the resources of the organization,
+// the calls are written to the database in batches
+func Record(token *models.AccessToken, orgID int64, endpoint string, isWrite bool, ip string) {
+	key := usageKey{
+		tokenID:  token.ID,
+		uid:      token.UID,
+		orgID:    orgID,
+		endpoint: endpoint,
+		isWrite:  isWrite,
+		ip:       ip,
+	}
+
+	pendingLock.Lock()
+	defer pendingLock.Unlock()
+	usage, ok := pending[key]
+	if !ok {
+		usage = &models.TokenUsage{
+			TokenID:  key.tokenID,
+			UID:      key.uid,
+			OrgID:    key.orgID,
+			Endpoint: key.endpoint,
+			IsWrite:  key.isWrite,
+			IP:       key.ip,
+		}
+		pending[key] = usage
+	}
+	usage.Count++
+	usage.LastUsedUnix = timeutil.TimeStampNow()
+}
+
+// flush writes the recorded usages to the database
+func flush() {
+	pendingLock.Lock()
+	usages := pending
+	pending = make(map[usageKey]*models.TokenUsage)
+	pendingLock.Unlock()
+
+	for _, usage := range usages {
+		if err := models.RecordTokenUsage(usage); err != nil {
+			log.Error("RecordTokenUsage: %v", err)
+		}
+	}
+}
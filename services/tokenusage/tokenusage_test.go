@@ -0,0 +1,34 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package tokenusage
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	token := models.AssertExistsAndLoadBean(t, &models.AccessToken{ID: 1}).(*models.AccessToken)
+	Record(token, 3, "repos/issues", false, "127.0.0.1")
+	Record(token, 3, "repos/issues", false, "127.0.0.1")
+	Record(token, 3, "repos/issues", true, "127.0.0.1")
+
+	// nothing is written before the next flush
+	models.AssertNotExistsBean(t, &models.TokenUsage{TokenID: 1})
+
+	flush()
+	models.AssertExistsAndLoadBean(t, &models.TokenUsage{TokenID: 1, UID: token.UID, OrgID: 3, Endpoint: "repos/issues", IP: "127.0.0.1", Count: 2})
+	models.AssertExistsAndLoadBean(t, &models.TokenUsage{TokenID: 1, IsWrite: true, Count: 1})
+	assert.Empty(t, pending)
+
+	Record(token, 3, "repos/issues", false, "127.0.0.1")
+	flush()
+	models.AssertExistsAndLoadBean(t, &models.TokenUsage{TokenID: 1, IsWrite: false, Count: 3})
+}
@@ -0,0 +1,102 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package translation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var languagePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{1,8}){0,2}$`)
+
+// ErrInvalidLanguage represents an error for a target language which is not a language tag
+type ErrInvalidLanguage struct {
+	Language string
+}
+
+// IsErrInvalidLanguage checks if an error is a ErrInvalidLanguage.
+func IsErrInvalidLanguage(err error) bool {
+	_, ok := err.(ErrInvalidLanguage)
+	return ok
+}
+
+func (err ErrInvalidLanguage) Error() string {
+	return fmt.Sprintf("invalid target language: %s", err.Language)
+}
+
+// ErrContentTooLong represents an error for content exceeding the maximum length which can be translated
+type ErrContentTooLong struct {
+	Length    int
+	MaxLength int
+}
+
+// IsErrContentTooLong checks if an error is a ErrContentTooLong.
+func IsErrContentTooLong(err error) bool {
+	_, ok := err.(ErrContentTooLong)
+	return ok
+}
+
+func (err ErrContentTooLong) Error() string {
+	return fmt.Sprintf("content is too long to be translated [length: %d, max: %d]", err.Length, err.MaxLength)
+}
+
+// TranslateIssue returns the translation of the content of an issue to the language
+func TranslateIssue(ctx context.Context, issue *models.Issue, language string) (*models.IssueContentTranslation, error) {
+	return translate(ctx, issue.RepoID, issue.ID, 0, issue.Content, language)
+}
+
+// TranslateComment returns the translation of the content of a comment to the language,
+// the issue of the comment must be loaded
+func TranslateComment(ctx context.Context, comment *models.Comment, language string) (*models.IssueContentTranslation, error) {
+	return translate(ctx, comment.Issue.RepoID, comment.IssueID, comment.ID, comment.Content, language)
+}
+
+// translate returns the cached translation of the current revision of the content
+// or translates the content with the machine translation service
+func translate(ctx context.Context, repoID, issueID, commentID int64, content, language string) (*models.IssueContentTranslation, error) {
+	if !languagePattern.MatchString(language) {
+		return nil, ErrInvalidLanguage{language}
+	}
+
+	contentHash := base.EncodeSha256(content)
+	t, err := models.GetIssueContentTranslation(issueID, commentID, language)
+	if err == nil && t.ContentHash == contentHash {
+		return t, nil
+	} else if err != nil && !models.IsErrIssueContentTranslationNotExist(err) {
+		return nil, err
+	}
+
+	t = &models.IssueContentTranslation{
+		RepoID:      repoID,
+		IssueID:     issueID,
+		CommentID:   commentID,
+		Language:    language,
+		ContentHash: contentHash,
+	}
+	if len(content) == 0 {
+		return t, nil
+	}
+	if setting.Translation.MaxLength > 0 && len(content) > setting.Translation.MaxLength {
+		return nil, ErrContentTooLong{len(content), setting.Translation.MaxLength}
+	}
+
+	if t.Content, t.SourceLanguage, err = newTranslator().Translate(ctx, content, language); err != nil {
+		return nil, err
+	}
+	if len(t.SourceLanguage) > 20 {
+		t.SourceLanguage = ""
+	}
+	if err = models.SaveIssueContentTranslation(t); err != nil {
+		return nil, err
+	}
+	log.Trace("Translated content of issue %d (comment %d) to %s", issueID, commentID, language)
+	return t, nil
+}
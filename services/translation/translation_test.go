@@ -0,0 +1,107 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateIssue(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		assert.EqualValues(t, "Bearer secret", req.Header.Get("Authorization"))
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		assert.EqualValues(t, "de-DE", body["target_language"])
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"text":            strings.ToUpper(body["text"]),
+			"source_language": "en",
+		})
+	}))
+	defer server.Close()
+
+	defer func(typ, url, apiKey string) {
+		setting.Translation.Type = typ
+		setting.Translation.URL = url
+		setting.Translation.APIKey = apiKey
+	}(setting.Translation.Type, setting.Translation.URL, setting.Translation.APIKey)
+	setting.Translation.Type = "generic"
+	setting.Translation.URL = server.URL
+	setting.Translation.APIKey = "secret"
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	translation, err := TranslateIssue(context.Background(), issue, "de-DE")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "CONTENT FOR THE FIRST ISSUE", translation.Content)
+	assert.EqualValues(t, "en", translation.SourceLanguage)
+	assert.EqualValues(t, 1, requests)
+
+	// the translation of the same revision is cached
+	_, err = TranslateIssue(context.Background(), issue, "de-DE")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, requests)
+
+	issue.Content = "changed content"
+	translation, err = TranslateIssue(context.Background(), issue, "de-DE")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "CHANGED CONTENT", translation.Content)
+	assert.EqualValues(t, 2, requests)
+	models.AssertCount(t, &models.IssueContentTranslation{IssueID: 1}, 1)
+
+	comment := models.AssertExistsAndLoadBean(t, &models.Comment{ID: 2}).(*models.Comment)
+	assert.NoError(t, comment.LoadIssue())
+	translation, err = TranslateComment(context.Background(), comment, "de-DE")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "GOOD WORK!", translation.Content)
+	models.AssertExistsAndLoadBean(t, &models.IssueContentTranslation{IssueID: 1, CommentID: 2, Language: "de-DE"})
+
+	_, err = TranslateIssue(context.Background(), issue, "not a language")
+	assert.True(t, IsErrInvalidLanguage(err))
+
+	defer func(old int) { setting.Translation.MaxLength = old }(setting.Translation.MaxLength)
+	setting.Translation.MaxLength = 5
+	_, err = TranslateIssue(context.Background(), issue, "fr")
+	assert.True(t, IsErrContentTooLong(err))
+}
+
+func TestLibreTranslator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.EqualValues(t, "/translate", req.URL.Path)
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		if body["api_key"] != "key" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error": "Invalid API key"}`))
+			return
+		}
+		assert.EqualValues(t, "auto", body["source"])
+		assert.EqualValues(t, "zh", body["target"])
+		_, _ = w.Write([]byte(`{"translatedText": "你好", "detectedLanguage": {"confidence": 90, "language": "en"}}`))
+	}))
+	defer server.Close()
+
+	translator := &libreTranslator{client: http.DefaultClient, url: server.URL + "/", apiKey: "key"}
+	translated, source, err := translator.Translate(context.Background(), "Hello", "zh-CN")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "你好", translated)
+	assert.EqualValues(t, "en", source)
+
+	translator.apiKey = "wrong"
+	_, _, err = translator.Translate(context.Background(), "Hello", "zh-CN")
+	assert.True(t, IsErrTranslationFailed(err))
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Translator translates texts with a machine translation service
+type Translator interface {
+	// Translate translates the markdown text to the target language,
+	// it returns the translated text and the detected language of the text
+	Translate(ctx context.Context, text, targetLanguage string) (translated, sourceLanguage string, err error)
+}
+
+// ErrTranslationFailed represents an error response of the machine translation service
+type ErrTranslationFailed struct {
+	StatusCode int
+	Message    string
+}
+
+// IsErrTranslationFailed checks if an error is a ErrTranslationFailed.
+func IsErrTranslationFailed(err error) bool {
+	_, ok := err.(ErrTranslationFailed)
+	return ok
+}
+
+func (err ErrTranslationFailed) Error() string {
+	return fmt.Sprintf("translation service responded with status %d: %s", err.StatusCode, err.Message)
+}
+
+// newTranslator returns the translator of the configured machine translation service
+func newTranslator() Translator {
+	client := &http.Client{Timeout: setting.Translation.Timeout}
+	if setting.Translation.Type == "libretranslate" {
+		return &libreTranslator{client: client, url: setting.Translation.URL, apiKey: setting.Translation.APIKey}
+	}
+	return &genericTranslator{client: client, url: setting.Translation.URL, apiKey: setting.Translation.APIKey}
+}
+
+// postJSON posts the request to the machine translation service and decodes its response
+func postJSON(ctx context.Context, client *http.Client, url, token string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return ErrTranslationFailed{resp.StatusCode, strings.TrimSpace(string(msg))}
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
+
+// genericTranslator sends the text as JSON to the configured URL:
+//   {"text": "...", "target_language": "de-DE", "format": "markdown"}
+// and expects the translation in the response:
+//   {"text": "...", "source_language": "en"}
+type genericTranslator struct {
+	client *http.Client
+	url    string
+	apiKey string
+}
+
+func (t *genericTranslator) Translate(ctx context.Context, text, targetLanguage string) (string, string, error) {
+	request := struct {
+		Text           string `json:"text"`
+		TargetLanguage string `json:"target_language"`
+		Format         string `json:"format"`
+	}{text, targetLanguage, "markdown"}
+	var response struct {
+		Text           string `json:"text"`
+		SourceLanguage string `json:"source_language"`
+	}
+	if err := postJSON(ctx, t.client, t.url, t.apiKey, &request, &response); err != nil {
+		return "", "", err
+	}
+	return response.Text, response.SourceLanguage, nil
+}
+
+// libreTranslator uses the API of a LibreTranslate server, see https://github.com/LibreTranslate/LibreTranslate
+type libreTranslator struct {
+	client *http.Client
+	url    string
+	apiKey string
+}
+
+func (t *libreTranslator) Translate(ctx context.Context, text, targetLanguage string) (string, string, error) {
+	// LibreTranslate only knows the primary language subtags
+	if i := strings.IndexByte(targetLanguage, '-'); i > 0 {
+		targetLanguage = targetLanguage[:i]
+	}
+	request := struct {
+		Q      string `json:"q"`
+		Source string `json:"source"`
+		Target string `json:"target"`
+		Format string `json:"format"`
+		APIKey string `json:"api_key,omitempty"`
+	}{text, "auto", strings.ToLower(targetLanguage), "text", t.apiKey}
+	var response struct {
+		TranslatedText   string `json:"translatedText"`
+		DetectedLanguage struct {
+			Language string `json:"language"`
+		} `json:"detectedLanguage"`
+	}
+	if err := postJSON(ctx, t.client, strings.TrimSuffix(t.url, "/")+"/translate", "", &request, &response); err != nil {
+		return "", "", err
+	}
+	return response.TranslatedText, response.DetectedLanguage.Language, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// DeactivateDormantUsers flags and notifies the users who have been inactive for the configured number
+// of months, and deactivates the flagged users whose grace period has ended. Flagged users who have been
+// active again are unflagged.
+func DeactivateDormantUsers(ctx context.Context) error {
+	if setting.Service.DormantUserInactiveMonths <= 0 {
+		return nil
+	}
+
+	inactiveSince := timeutil.TimeStamp(time.Now().AddDate(0, -setting.Service.DormantUserInactiveMonths, 0).Unix())
+	dormant, err := models.FindDormantUsers(inactiveSince)
+	if err != nil {
+		return err
+	}
+	isDormant := make(map[int64]bool, len(dormant))
+	for _, u := range dormant {
+		isDormant[u.ID] = true
+	}
+
+	flagged, err := models.GetFlaggedDormantUsers()
+	if err != nil {
+		return err
+	}
+	now := timeutil.TimeStampNow()
+	for _, u := range flagged {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before deactivating dormant user %s", u.Name)
+		default:
+		}
+
+		if !isDormant[u.ID] {
+			if err := models.UnflagDormantUser(u); err != nil {
+				return err
+			}
+			log.Trace("User %s is active again and no longer dormant", u.Name)
+			continue
+		}
+		if u.DormantDeadline() <= now {
+			if err := models.DeactivateDormantUser(u); err != nil {
+				return err
+			}
+			log.Info("Deactivated dormant user %s", u.Name)
+		}
+	}
+
+	for _, u := range dormant {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before flagging dormant user %s", u.Name)
+		default:
+		}
+
+		if u.DormantFlaggedUnix > 0 {
+			continue
+		}
+		if err := models.FlagDormantUser(u); err != nil {
+			return err
+		}
+		mailer.SendDormantAccountMail(u)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeactivateDormantUsers(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	defer func(months int) {
+		setting.Service.DormantUserInactiveMonths = months
+	}(setting.Service.DormantUserInactiveMonths)
+	setting.Service.DormantUserInactiveMonths = 1
+	setting.Service.DormantUserGracePeriodDays = 14
+
+	assert.NoError(t, DeactivateDormantUsers(context.Background()))
+
+	// user2 never signed in, user1 is an administrator and user3 is an organization
+	user2 := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.NotZero(t, user2.DormantFlaggedUnix)
+	assert.False(t, user2.IsDormant)
+	assert.Zero(t, models.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User).DormantFlaggedUnix)
+	assert.Zero(t, models.AssertExistsAndLoadBean(t, &models.User{ID: 3}).(*models.User).DormantFlaggedUnix)
+
+	// user4 signs in again
+	user4 := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	assert.NotZero(t, user4.DormantFlaggedUnix)
+	user4.SetLastLogin()
+	assert.NoError(t, models.UpdateUserCols(user4, "last_login_unix"))
+
+	// the grace period of user2 ends
+	user2.DormantFlaggedUnix = timeutil.TimeStamp(time.Now().AddDate(0, 0, -15).Unix())
+	assert.NoError(t, models.UpdateUserCols(user2, "dormant_flagged_unix"))
+
+	assert.NoError(t, DeactivateDormantUsers(context.Background()))
+
+	user2 = models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.True(t, user2.IsDormant)
+	assert.True(t, user2.ProhibitLogin)
+	user4 = models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	assert.Zero(t, user4.DormantFlaggedUnix)
+	assert.False(t, user4.IsDormant)
+}
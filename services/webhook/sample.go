@@ -0,0 +1,339 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/webhook"
+)
+
+// SampleEvents are the events a test delivery with a sample payload can be sent for
+var SampleEvents = []models.HookEventType{
+	models.HookEventPush,
+	models.HookEventCreate,
+	models.HookEventDelete,
+	models.HookEventFork,
+	models.HookEventIssues,
+	models.HookEventIssueAssign,
+	models.HookEventIssueLabel,
+	models.HookEventIssueMilestone,
+	models.HookEventIssueComment,
+	models.HookEventPullRequest,
+	models.HookEventPullRequestAssign,
+	models.HookEventPullRequestLabel,
+	models.HookEventPullRequestMilestone,
+	models.HookEventPullRequestComment,
+	models.HookEventPullRequestReviewApproved,
+	models.HookEventPullRequestReviewRejected,
+	models.HookEventPullRequestReviewComment,
+	models.HookEventPullRequestSync,
+	models.HookEventRepository,
+	models.HookEventRelease,
+}
+
+// IsSampleEvent returns true if a test delivery can be sent for the event
+func IsSampleEvent(event models.HookEventType) bool {
+	for _, e := range SampleEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrUnknownSampleEvent represents an error for an event without a sample payload
+type ErrUnknownSampleEvent struct {
+	Event models.HookEventType
+}
+
+// IsErrUnknownSampleEvent checks if an error is a ErrUnknownSampleEvent.
+func IsErrUnknownSampleEvent(err error) bool {
+	_, ok := err.(ErrUnknownSampleEvent)
+	return ok
+}
+
+func (err ErrUnknownSampleEvent) Error() string {
+	return fmt.Sprintf("no sample payload for event [event: %s]", err.Event)
+}
+
+// samplePayloads builds sample payloads of a repository
+type samplePayloads struct {
+	repo    *models.Repository
+	apiRepo *api.Repository
+	sender  *api.User
+	commit  *git.Commit
+	now     time.Time
+}
+
+// GetSamplePayload returns a payload for the event in the repository as if it was triggered by doer.
+// It contains the real repository and sender, and made up issues, pull requests, comments and releases.
+// commit is the head commit of the default branch, a fake commit is used if it is nil.
+func GetSamplePayload(event models.HookEventType, repo *models.Repository, doer *models.User, commit *git.Commit) (api.Payloader, error) {
+	if commit == nil {
+		ghost := models.NewGhostUser()
+		commit = &git.Commit{
+			ID:            git.MustIDFromString(git.EmptySHA),
+			Author:        ghost.NewGitSig(),
+			Committer:     ghost.NewGitSig(),
+			CommitMessage: "This is a fake commit",
+		}
+	}
+
+	s := &samplePayloads{
+		repo:    repo,
+		apiRepo: repo.APIFormat(models.AccessModeNone),
+		sender:  convert.ToUser(doer, false, false),
+		commit:  commit,
+		now:     time.Now(),
+	}
+
+	switch event {
+	case models.HookEventPush:
+		return s.push(), nil
+	case models.HookEventCreate:
+		return &api.CreatePayload{
+			Sha:     commit.ID.String(),
+			Ref:     "sample-branch",
+			RefType: "branch",
+			Repo:    s.apiRepo,
+			Sender:  s.sender,
+		}, nil
+	case models.HookEventDelete:
+		return &api.DeletePayload{
+			Ref:        "sample-branch",
+			RefType:    "branch",
+			PusherType: api.PusherTypeUser,
+			Repo:       s.apiRepo,
+			Sender:     s.sender,
+		}, nil
+	case models.HookEventFork:
+		return s.fork(), nil
+	case models.HookEventIssues:
+		return s.issue(api.HookIssueOpened), nil
+	case models.HookEventIssueAssign:
+		return s.issue(api.HookIssueAssigned), nil
+	case models.HookEventIssueLabel:
+		return s.issue(api.HookIssueLabelUpdated), nil
+	case models.HookEventIssueMilestone:
+		return s.issue(api.HookIssueMilestoned), nil
+	case models.HookEventIssueComment:
+		return s.comment(false), nil
+	case models.HookEventPullRequest:
+		return s.pullRequest(api.HookIssueOpened), nil
+	case models.HookEventPullRequestAssign:
+		return s.pullRequest(api.HookIssueAssigned), nil
+	case models.HookEventPullRequestLabel:
+		return s.pullRequest(api.HookIssueLabelUpdated), nil
+	case models.HookEventPullRequestMilestone:
+		return s.pullRequest(api.HookIssueMilestoned), nil
+	case models.HookEventPullRequestComment:
+		return s.comment(true), nil
+	case models.HookEventPullRequestReviewApproved, models.HookEventPullRequestReviewRejected, models.HookEventPullRequestReviewComment:
+		p := s.pullRequest(api.HookIssueReviewed)
+		p.Review = &api.ReviewPayload{
+			Type:    string(event),
+			Content: "This is a sample review.",
+		}
+		return p, nil
+	case models.HookEventPullRequestSync:
+		return s.pullRequest(api.HookIssueSynchronized), nil
+	case models.HookEventRepository:
+		p := &api.RepositoryPayload{
+			Action:     api.HookRepoCreated,
+			Repository: s.apiRepo,
+			Sender:     s.sender,
+		}
+		if repo.Owner != nil && repo.Owner.IsOrganization() {
+			p.Organization = convert.ToUser(repo.Owner, false, false)
+		}
+		return p, nil
+	case models.HookEventRelease:
+		return s.release(), nil
+	}
+	return nil, ErrUnknownSampleEvent{event}
+}
+
+func (s *samplePayloads) push() *api.PushPayload {
+	return &api.PushPayload{
+		Ref:     git.BranchPrefix + s.repo.DefaultBranch,
+		Before:  s.commit.ID.String(),
+		After:   s.commit.ID.String(),
+		Commits: []*api.PayloadCommit{convert.ToPayloadCommit(s.repo, s.commit)},
+		Repo:    s.apiRepo,
+		Pusher:  s.sender,
+		Sender:  s.sender,
+	}
+}
+
+func (s *samplePayloads) fork() *api.ForkPayload {
+	fork := *s.apiRepo
+	fork.ID = 0
+	fork.Owner = s.sender
+	fork.FullName = s.sender.UserName + "/" + s.repo.Name
+	fork.HTMLURL = setting.AppURL + fork.FullName
+	fork.Fork = true
+	fork.Parent = s.apiRepo
+	return &api.ForkPayload{
+		Forkee: s.apiRepo,
+		Repo:   &fork,
+		Sender: s.sender,
+	}
+}
+
+func (s *samplePayloads) apiIssue(isPull bool) *api.Issue {
+	index := int64(s.repo.NumIssues + s.repo.NumPulls + 1)
+	kind := "issues"
+	if isPull {
+		kind = "pulls"
+	}
+	issue := &api.Issue{
+		URL:     fmt.Sprintf("%s/issues/%d", s.repo.APIURL(), index),
+		HTMLURL: fmt.Sprintf("%s/%s/%d", s.repo.HTMLURL(), kind, index),
+		Index:   index,
+		Poster:  s.sender,
+		Title:   "Sample title",
+		Body:    "This is a sample description.",
+		Labels: []*api.Label{{
+			Name:  "sample-label",
+			Color: "00aabb",
+		}},
+		Milestone: &api.Milestone{
+			Title: "Sample milestone",
+			State: api.StateOpen,
+		},
+		Assignee:  s.sender,
+		Assignees: []*api.User{s.sender},
+		State:     api.StateOpen,
+		Created:   s.now,
+		Updated:   s.now,
+		Repo: &api.RepositoryMeta{
+			ID:       s.repo.ID,
+			Name:     s.repo.Name,
+			Owner:    s.repo.OwnerName,
+			FullName: s.repo.FullName(),
+		},
+	}
+	if isPull {
+		issue.PullRequest = &api.PullRequestMeta{}
+	}
+	return issue
+}
+
+func (s *samplePayloads) issue(action api.HookIssueAction) *api.IssuePayload {
+	issue := s.apiIssue(false)
+	return &api.IssuePayload{
+		Action:     action,
+		Index:      issue.Index,
+		Issue:      issue,
+		Repository: s.apiRepo,
+		Sender:     s.sender,
+	}
+}
+
+func (s *samplePayloads) comment(isPull bool) *api.IssueCommentPayload {
+	issue := s.apiIssue(isPull)
+	comment := &api.Comment{
+		HTMLURL:  issue.HTMLURL + "#issuecomment-0",
+		IssueURL: issue.URL,
+		Poster:   s.sender,
+		Body:     "This is a sample comment.",
+		Created:  s.now,
+		Updated:  s.now,
+	}
+	if isPull {
+		comment.PRURL = issue.HTMLURL
+	}
+	return &api.IssueCommentPayload{
+		Action:     api.HookIssueCommentCreated,
+		Issue:      issue,
+		Comment:    comment,
+		Repository: s.apiRepo,
+		Sender:     s.sender,
+		IsPull:     isPull,
+	}
+}
+
+func (s *samplePayloads) pullRequest(action api.HookIssueAction) *api.PullRequestPayload {
+	issue := s.apiIssue(true)
+	sha := s.commit.ID.String()
+	return &api.PullRequestPayload{
+		Action: action,
+		Index:  issue.Index,
+		PullRequest: &api.PullRequest{
+			URL:       issue.HTMLURL,
+			Index:     issue.Index,
+			Poster:    issue.Poster,
+			Title:     issue.Title,
+			Body:      issue.Body,
+			Labels:    issue.Labels,
+			Milestone: issue.Milestone,
+			Assignee:  issue.Assignee,
+			Assignees: issue.Assignees,
+			State:     issue.State,
+			HTMLURL:   issue.HTMLURL,
+			DiffURL:   issue.HTMLURL + ".diff",
+			PatchURL:  issue.HTMLURL + ".patch",
+			Mergeable: true,
+			Base: &api.PRBranchInfo{
+				Name:       s.repo.DefaultBranch,
+				Ref:        s.repo.DefaultBranch,
+				Sha:        sha,
+				RepoID:     s.repo.ID,
+				Repository: s.apiRepo,
+			},
+			Head: &api.PRBranchInfo{
+				Name:       "sample-branch",
+				Ref:        "sample-branch",
+				Sha:        sha,
+				RepoID:     s.repo.ID,
+				Repository: s.apiRepo,
+			},
+			MergeBase: sha,
+			Created:   &s.now,
+			Updated:   &s.now,
+		},
+		Repository: s.apiRepo,
+		Sender:     s.sender,
+	}
+}
+
+func (s *samplePayloads) release() *api.ReleasePayload {
+	tag := "v0.0.0-sample"
+	return &api.ReleasePayload{
+		Action: api.HookReleasePublished,
+		Release: &api.Release{
+			TagName:     tag,
+			Target:      s.repo.DefaultBranch,
+			Title:       "Sample release",
+			Note:        "These are sample release notes.",
+			URL:         s.repo.APIURL() + "/releases/0",
+			HTMLURL:     s.repo.HTMLURL() + "/releases/tag/" + tag,
+			TarURL:      s.repo.HTMLURL() + "/archive/" + tag + ".tar.gz",
+			ZipURL:      s.repo.HTMLURL() + "/archive/" + tag + ".zip",
+			CreatedAt:   s.now,
+			PublishedAt: s.now,
+			Publisher:   s.sender,
+		},
+		Repository: s.apiRepo,
+		Sender:     s.sender,
+	}
+}
+
+// SendTestDelivery sends a sample payload of the event to the webhook, regardless of the events
+// the webhook is subscribed to
+func SendTestDelivery(w *models.Webhook, event models.HookEventType, repo *models.Repository, doer *models.User, commit *git.Commit) error {
+	p, err := GetSamplePayload(event, repo, doer, commit)
+	if err != nil {
+		return err
+	}
+	return webhook.PrepareTestWebhook(w, repo, event, p)
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSamplePayload(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.NoError(t, repo.GetOwner())
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+
+	for _, event := range SampleEvents {
+		p, err := GetSamplePayload(event, repo, doer, nil)
+		assert.NoError(t, err, event)
+		_, err = p.JSONPayload()
+		assert.NoError(t, err, event)
+	}
+
+	p, err := GetSamplePayload(models.HookEventPullRequestReviewApproved, repo, doer, nil)
+	assert.NoError(t, err)
+	if pr, ok := p.(*api.PullRequestPayload); assert.True(t, ok) {
+		assert.Equal(t, api.HookIssueReviewed, pr.Action)
+		assert.Equal(t, "pull_request_review_approved", pr.Review.Type)
+		assert.Equal(t, "user2", pr.Sender.UserName)
+		assert.Equal(t, repo.FullName(), pr.Repository.FullName)
+	}
+
+	_, err = GetSamplePayload("unknown", repo, doer, nil)
+	assert.True(t, IsErrUnknownSampleEvent(err))
+}
+
+func TestSendTestDelivery(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	hook := models.AssertExistsAndLoadBean(t, &models.Webhook{ID: 1}).(*models.Webhook)
+	assert.False(t, hook.HasReleaseEvent())
+
+	// test deliveries are sent even if the webhook is not subscribed to the event
+	assert.NoError(t, SendTestDelivery(hook, models.HookEventRelease, repo, doer, nil))
+	models.AssertExistsAndLoadBean(t, &models.HookTask{HookID: hook.ID, EventType: models.HookEventRelease})
+}
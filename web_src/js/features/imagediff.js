@@ -0,0 +1,27 @@
+export default function initImageDiff() {
+  for (const modes of document.querySelectorAll('.image-diff-modes')) {
+    const tbody = modes.closest('tbody');
+    for (const button of modes.querySelectorAll('.button')) {
+      button.addEventListener('click', () => {
+        for (const other of modes.querySelectorAll('.button')) {
+          other.classList.toggle('active', other === button);
+        }
+        for (const row of tbody.querySelectorAll('tr.image-diff-mode')) {
+          row.classList.toggle('hide', row.dataset.mode !== button.dataset.mode);
+        }
+      });
+    }
+  }
+
+  for (const slider of document.querySelectorAll('.image-diff-slider')) {
+    const cell = slider.closest('td');
+    const update = () => {
+      const after = cell.querySelector('.image-diff-swipe-after');
+      if (after) after.style.width = `${slider.value}%`;
+      const overlay = cell.querySelector('.image-diff-overlay-after');
+      if (overlay) overlay.style.opacity = slider.value / 100;
+    };
+    slider.addEventListener('input', update);
+    update();
+  }
+}
@@ -0,0 +1,42 @@
+const issueEventTypes = [
+  'issue.closed',
+  'issue.reopened',
+  'issue.updated',
+  'issue_comment.created',
+  'issue_comment.updated',
+  'issue_comment.deleted',
+  'pull_request.merged',
+  'pull_request.synchronized',
+  'pull_request.reviewed',
+];
+
+export default function initRepoEvents() {
+  const el = document.getElementById('repo-events');
+  if (!el || !window.EventSource) return;
+
+  const issueIndex = Number(el.dataset.issueIndex);
+  const headSha = el.dataset.headSha;
+  const userId = Number(el.dataset.userId);
+  const source = new EventSource(el.dataset.url);
+
+  const showUpdate = () => {
+    el.classList.remove('hide');
+    source.close();
+  };
+
+  for (const type of issueEventTypes) {
+    source.addEventListener(type, (event) => {
+      const data = JSON.parse(event.data);
+      if (data.issue_index === issueIndex && data.actor_id !== userId) {
+        showUpdate();
+      }
+    });
+  }
+  source.addEventListener('commit_status.created', (event) => {
+    const data = JSON.parse(event.data);
+    if (headSha && data.sha === headSha) {
+      showUpdate();
+    }
+  });
+  window.addEventListener('beforeunload', () => source.close());
+}
@@ -0,0 +1,32 @@
+import {svg} from '../svg.js';
+
+export default function initSubmoduleExpand() {
+  if (!document.querySelector('#repo-files-table .submodule-expand')) return;
+
+  $(document).on('click', '#repo-files-table .submodule-expand', async (e) => {
+    e.preventDefault();
+    const toggle = e.currentTarget;
+    if (toggle.classList.contains('expanded')) {
+      collapse(toggle);
+      return;
+    }
+
+    let $rows = $(toggle).data('rows');
+    if (!$rows) {
+      $rows = $(await $.get(toggle.dataset.url));
+      $rows.insertAfter(toggle.closest('tr'));
+      $(toggle).data('rows', $rows);
+    }
+    $rows.show();
+    toggle.classList.add('expanded');
+    toggle.innerHTML = svg('octicon-chevron-down');
+  });
+}
+
+function collapse(toggle) {
+  const $rows = $(toggle).data('rows');
+  $rows.find('.submodule-expand.expanded').each((_, el) => collapse(el));
+  $rows.hide();
+  toggle.classList.remove('expanded');
+  toggle.innerHTML = svg('octicon-chevron-right');
+}
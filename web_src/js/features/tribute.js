@@ -1,6 +1,17 @@
+import {htmlEscape} from 'escape-goat';
 import {emojiKeys, emojiHTML, emojiString} from './emoji.js';
+import {svg} from '../svg.js';
 import {uniq} from '../utils.js';
 
+// returns "owner/repo#" if the reference being typed into the element is prefixed by a repository
+function issueReferencePrefix(el, query) {
+  if (!el || typeof el.selectionStart !== 'number') return '';
+  const text = el.value.slice(0, el.selectionStart);
+  const before = text.slice(0, text.length - query.length - 1);
+  const match = /(?:^|\s)([-.\w]+\/[-.\w]+)$/.exec(before);
+  return match ? `${match[1]}#` : '';
+}
+
 function makeCollections({mentions, emoji}) {
   const collections = [];
 
@@ -29,6 +40,43 @@ function makeCollections({mentions, emoji}) {
     });
   }
 
+  const {issueSuggestionsUrl} = window.config;
+  if (mentions && issueSuggestionsUrl) {
+    let prefix = '';
+    collections.push({
+      trigger: '#',
+      requireLeadingSpace: false,
+      searchOpts: {skip: true},
+      values: async (query, cb) => {
+        prefix = issueReferencePrefix(document.activeElement, query);
+        try {
+          const res = await fetch(`${issueSuggestionsUrl}?q=${encodeURIComponent(prefix + query)}`);
+          cb(res.ok ? await res.json() : []);
+        } catch {
+          cb([]);
+        }
+      },
+      lookup: (item) => `${item.number} ${item.title}`,
+      noMatchTemplate: () => null,
+      selectTemplate: (item) => {
+        if (typeof item === 'undefined') return null;
+        return prefix ? `#${item.original.number}` : item.original.ref;
+      },
+      menuItemTemplate: (item) => {
+        const {state, ref, title} = item.original;
+        let icon = state === 'closed' ? 'octicon-issue-closed' : 'octicon-issue-opened';
+        if (item.original.is_pull) icon = 'octicon-git-pull-request';
+        return `
+          <div class="tribute-item">
+            ${svg(icon)}
+            <span class="name">${htmlEscape(ref)}</span>
+            <span class="fullname">${htmlEscape(title)}</span>
+          </div>
+        `;
+      }
+    });
+  }
+
   if (emoji) {
     collections.push({
       values: window.config.tributeValues,
@@ -21,6 +21,9 @@ import attachTribute from './features/tribute.js';
 import createColorPicker from './features/colorpicker.js';
 import createDropzone from './features/dropzone.js';
 import initTableSort from './features/tablesort.js';
+import initImageDiff from './features/imagediff.js';
+import initSubmoduleExpand from './features/submodule.js';
+import initRepoEvents from './features/repoevents.js';
 import ActivityTopAuthors from './components/ActivityTopAuthors.vue';
 import {initNotificationsTable, initNotificationCount} from './features/notification.js';
 import {createCodeEditor, createMonaco} from './features/codeeditor.js';
@@ -693,7 +696,25 @@ function initIssueComments() {
   });
 }
 
-function getArchive($target, url, first) {
+function showArchiveStatus($link, message) {
+  if (message) {
+    $link.popup({content: message, on: 'manual', position: 'bottom center'}).popup('show');
+  } else {
+    $link.popup('hide');
+  }
+}
+
+function stopArchive($link) {
+  $link.removeClass('archive-pending');
+  $link.closest('.dropdown').children('i').removeClass('loading');
+}
+
+function getArchive($link, url, first) {
+  if (!first && !$link.hasClass('archive-pending')) {
+    // The request has been cancelled in the meantime.
+    return;
+  }
+  $link.addClass('archive-pending');
   $.ajax({
     url,
     type: 'POST',
@@ -701,30 +722,49 @@ function getArchive($target, url, first) {
       _csrf: csrf,
     },
     complete(xhr) {
-      if (xhr.status === 200) {
-        if (!xhr.responseJSON) {
-          // XXX Shouldn't happen?
-          $target.closest('.dropdown').children('i').removeClass('loading');
-          return;
-        }
+      const data = xhr.responseJSON;
+      if (!data) {
+        // XXX Shouldn't happen?
+        stopArchive($link);
+        return;
+      }
 
-        if (!xhr.responseJSON.complete) {
-          $target.closest('.dropdown').children('i').addClass('loading');
-          // Wait for only three quarters of a second initially, in case it's
-          // quickly archived.
-          setTimeout(() => {
-            getArchive($target, url, false);
-          }, first ? 750 : 2000);
-        } else {
-          // We don't need to continue checking.
-          $target.closest('.dropdown').children('i').removeClass('loading');
-          window.location.href = url;
-        }
+      showArchiveStatus($link, data.message);
+      if (xhr.status !== 200 || data.status === 'failed' || data.status === 'cancelled') {
+        stopArchive($link);
+        return;
+      }
+
+      if (!data.complete) {
+        $link.closest('.dropdown').children('i').addClass('loading');
+        // Wait for only three quarters of a second initially, in case it's
+        // quickly archived.
+        setTimeout(() => {
+          getArchive($link, url, false);
+        }, first ? 750 : 2000);
+      } else {
+        // We don't need to continue checking.
+        stopArchive($link);
+        window.location.href = url;
       }
     }
   });
 }
 
+function cancelArchive($link, url) {
+  stopArchive($link);
+  $.ajax({
+    url,
+    type: 'DELETE',
+    headers: {
+      'X-Csrf-Token': csrf,
+    },
+    complete() {
+      showArchiveStatus($link, '');
+    }
+  });
+}
+
 function initArchiveLinks() {
   if ($('.archive-link').length === 0) {
     return;
@@ -737,7 +777,11 @@ function initArchiveLinks() {
     }
 
     event.preventDefault();
-    getArchive($(event.target), url, true);
+    if ($(this).hasClass('archive-pending')) {
+      cancelArchive($(this), url);
+      return;
+    }
+    getArchive($(this), url, true);
   });
 }
 
@@ -815,6 +859,25 @@ async function initRepository() {
         $('input[name="auto_init"]').prop('checked', true);
       }
     });
+
+    // Only the presets of the selected owner can be chosen
+    const $presetField = $('#repo_preset_field');
+    if ($presetField.length > 0) {
+      const filterPresets = () => {
+        const uid = $('#uid').val();
+        const $items = $presetField.find('.menu .item[data-org-id]');
+        $items.each(function () {
+          $(this).toggle($(this).attr('data-org-id') === uid);
+        });
+        const $selected = $items.filter(`[data-value="${$('#repo_preset').val()}"]`);
+        if ($selected.length > 0 && $selected.attr('data-org-id') !== uid) {
+          $presetField.find('.dropdown').dropdown('clear');
+        }
+        $presetField.toggle($items.filter(`[data-org-id="${uid}"]`).length > 0);
+      };
+      $('#uid').on('change', filterPresets);
+      filterPresets();
+    }
   }
 
   // Issues
@@ -923,6 +986,32 @@ async function initRepository() {
       event.preventDefault();
     });
 
+    // Translate issue or comment content
+    $('.translate-content').on('click', async function (event) {
+      event.preventDefault();
+      $(this).closest('.dropdown').find('.menu').toggle('visible');
+      const $segment = $(this).closest('.header').next();
+      const $renderContent = $segment.find('.render-content');
+      let $translatedContent = $segment.find('.translated-content');
+      if ($translatedContent.length === 0) {
+        $translatedContent = $('<div class="translated-content"><p class="translation-note text grey"></p><div class="markdown"></div></div>');
+        $translatedContent.insertAfter($renderContent);
+        $translatedContent.find('.translation-note').on('click', () => {
+          $renderContent.toggle();
+          $translatedContent.find('.markdown').toggle();
+        });
+      }
+      try {
+        const data = await $.get($(this).data('url'));
+        $translatedContent.find('.translation-note').text(data.note);
+        $translatedContent.find('.markdown').html(data.content).show();
+        $renderContent.hide();
+      } catch (xhr) {
+        const message = xhr.responseJSON && xhr.responseJSON.error ? xhr.responseJSON.error : xhr.statusText;
+        $translatedContent.find('.translation-note').text(message);
+      }
+    });
+
     // Edit issue or comment content
     $('.edit-content').on('click', async function (event) {
       $(this).closest('.dropdown').find('.menu').toggle('visible');
@@ -1760,7 +1849,8 @@ function initWebhook() {
     const $this = $(this);
     $this.addClass('loading disabled');
     $.post($this.data('link'), {
-      _csrf: csrf
+      _csrf: csrf,
+      event: $('#test-delivery-event').val()
     }).done(
       setTimeout(() => {
         window.location.href = $this.data('redirect');
@@ -2507,9 +2597,12 @@ $(document).ready(async () => {
   initWebhook();
   initAdmin();
   initCodeView();
+  initSubmoduleExpand();
+  initRepoEvents();
   initVueApp();
   initTeamSettings();
   initCtrlEnterSubmit();
+  initScheduledPostTime();
   initNavbarContentToggle();
   initTopicbar();
   initU2FAuth();
@@ -2521,6 +2614,7 @@ $(document).ready(async () => {
   initTemplateSearch();
   initContextPopups();
   initTableSort();
+  initImageDiff();
   initNotificationsTable();
 
   const routes = {
@@ -3061,6 +3155,14 @@ function initVueComponents() {
   });
 }
 
+function initScheduledPostTime() {
+  // the server cannot know the time zone the scheduled time was entered in
+  $('input[name="post_at_offset"]').closest('form').on('submit', function () {
+    const postAt = $(this).find('input[name="post_at"]').val();
+    $(this).find('input[name="post_at_offset"]').val(postAt ? -new Date(postAt).getTimezoneOffset() : '');
+  });
+}
+
 function initCtrlEnterSubmit() {
   $('.js-quick-submit').on('keydown', function (e) {
     if (((e.ctrlKey && !e.altKey) || e.metaKey) && (e.keyCode === 13 || e.keyCode === 10)) {